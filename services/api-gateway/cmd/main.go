@@ -6,7 +6,7 @@ import (
 	"log"
 	"net/http"
 	"os"
-	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
@@ -14,8 +14,10 @@ import (
 	"github.com/flexsearch/api-gateway/internal/config"
 	"github.com/flexsearch/api-gateway/internal/handler"
 	"github.com/flexsearch/api-gateway/internal/middleware"
+	"github.com/flexsearch/api-gateway/internal/model"
 	"github.com/flexsearch/api-gateway/internal/util"
 	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
@@ -32,6 +34,7 @@ func main() {
 		log.Fatalf("Failed to create logger: %v", err)
 	}
 	defer logger.Sync()
+	logger = logger.WithRedaction(cfg.Log.RedactFields)
 
 	if cfg.Server.Mode == "release" {
 		gin.SetMode(gin.ReleaseMode)
@@ -39,12 +42,16 @@ func main() {
 
 	tracingConfig := &middleware.TracingConfig{
 		ServiceName: "api-gateway",
-		Enabled:     true,
-		SampleRate:  1.0,
+		Enabled:     cfg.Tracing.Enabled,
+		SampleRate:  cfg.Tracing.SampleRate,
+		Exporter:    cfg.Tracing.Exporter,
+		Endpoint:    cfg.Tracing.Endpoint,
+		Insecure:    cfg.Tracing.Insecure,
 	}
 	shutdownTracing, err := middleware.InitTracing(tracingConfig, logger.Logger)
 	if err != nil {
 		logger.Warn("Failed to initialize tracing", zap.Error(err))
+		shutdownTracing = func(context.Context) error { return nil }
 	}
 	defer func() {
 		if shutdownErr := shutdownTracing(context.Background()); shutdownErr != nil {
@@ -60,7 +67,6 @@ func main() {
 		Password: cfg.Redis.Password,
 		DB:       cfg.Redis.DB,
 	})
-	defer redisClient.Close()
 
 	ctx := context.Background()
 	if pingErr := redisClient.Ping(ctx).Err(); pingErr != nil {
@@ -73,6 +79,9 @@ func main() {
 	rateLimitConfig := util.DefaultRateLimitConfig()
 	rateLimitConfig.Enabled = cfg.RateLimit.Enabled
 	rateLimitConfig.DefaultLimit = cfg.RateLimit.DefaultLimit
+	if cfg.RateLimit.Algorithm != "" {
+		rateLimitConfig.Algorithm = cfg.RateLimit.Algorithm
+	}
 	rateLimiter := util.NewRateLimiter(redisClient, rateLimitConfig)
 
 	coordinatorClient, err := client.NewCircuitBreakerCoordinatorClient(&cfg.Coordinator)
@@ -80,32 +89,59 @@ func main() {
 		logger.Error("Failed to connect to coordinator", zap.Error(err))
 	} else {
 		logger.Info("Connected to coordinator successfully", zap.String("address", cfg.Coordinator.Address))
-		defer coordinatorClient.Close()
 	}
 
-	jwtManager := util.NewJWTManager(cfg.JWT.Secret, cfg.JWT.Issuer, cfg.JWT.Expiration)
+	jwtManager, err := newJWTManager(cfg.JWT)
+	if err != nil {
+		logger.Fatal("Failed to create JWT manager", zap.Error(err))
+	}
+	jwtManager.SetBlacklist(util.NewRedisTokenBlacklist(redisClient, ""))
+
+	apiKeys := util.NewRedisAPIKeyStore(redisClient, cfg.APIKeys.Prefix)
+	authMiddleware := middleware.AuthMiddleware(jwtManager)
+	if cfg.APIKeys.Enabled {
+		authMiddleware = middleware.AuthOrAPIKeyMiddleware(jwtManager, apiKeys)
+	}
+
+	healthHandler := handler.NewHealthHandler(coordinatorClient, cfg, logger.Logger)
+	healthHandler.SetRedis(redisClient)
 
 	router := gin.New()
 
 	router.Use(gin.Recovery())
+	router.Use(healthHandler.TrackInFlight())
+	router.Use(middleware.MetricsMiddleware(metrics))
 	router.Use(middleware.RequestIDMiddleware())
+	router.Use(middleware.TenantMiddleware(cfg.Tenant.Header))
 	router.Use(tracingMiddleware.Middleware())
-	router.Use(middleware.RequestLoggingMiddleware(logger.Logger))
+	router.Use(middleware.RequestLoggingMiddleware(logger.Logger, logger.Redactor()))
 	router.Use(middleware.ErrorHandlerMiddleware(logger.Logger))
-	router.Use(middleware.ResponseValidationMiddleware(logger.Logger, middleware.DefaultResponseValidationConfig()))
+	router.Use(middleware.TimeoutMiddleware(time.Duration(cfg.Server.RequestTimeout) * time.Second))
+	validationConfig := middleware.DefaultResponseValidationConfig()
+	validationConfig.Validators = responseValidators()
+	router.Use(middleware.ResponseValidationMiddleware(logger.Logger, validationConfig))
 
 	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
 
+	router.Use(middleware.BodySizeLimitMiddleware(cfg.Server.MaxBodyBytes))
+
 	if cfg.CORS.Enabled {
 		router.Use(middleware.CORSMiddleware(middleware.CORSConfig{
 			AllowOrigins:     cfg.CORS.AllowOrigins,
 			AllowMethods:     cfg.CORS.AllowMethods,
 			AllowHeaders:     cfg.CORS.AllowHeaders,
 			AllowCredentials: cfg.CORS.AllowCredentials,
+			Logger:           logger.Logger,
 		}))
 	}
 
 	if cfg.RateLimit.Enabled {
+		// Resolve the caller from any bearer token first so limits can be
+		// keyed and tiered per user.
+		router.Use(middleware.OptionalAuthMiddleware(jwtManager))
+		if cfg.APIKeys.Enabled {
+			router.Use(middleware.OptionalAPIKeyMiddleware(apiKeys))
+		}
 		router.Use(middleware.RateLimitMiddleware(rateLimiter, middleware.RateLimitConfig{
 			Enabled:       cfg.RateLimit.Enabled,
 			DefaultLimit:  cfg.RateLimit.DefaultLimit,
@@ -114,37 +150,57 @@ func main() {
 			ByUser:        cfg.RateLimit.ByUser,
 			ByIP:          cfg.RateLimit.ByIP,
 			TierHeader:    "X-RateLimit-Tier",
+			Endpoints:     endpointRateLimits(cfg.RateLimit.Endpoints),
 		}))
 	}
 
 	searchHandler := handler.NewSearchHandler(coordinatorClient.CoordinatorClient, metrics, logger.Logger)
+	searchHandler.SetMaxResultWindow(cfg.Search.MaxResultWindow)
 	documentHandler := handler.NewDocumentHandler(coordinatorClient.CoordinatorClient, metrics, logger.Logger)
 	indexHandler := handler.NewIndexHandler(coordinatorClient.CoordinatorClient, metrics, logger.Logger)
-	healthHandler := handler.NewHealthHandler(coordinatorClient, cfg, logger.Logger)
 
 	v1 := router.Group("/api/v1")
 	{
 		auth := v1.Group("")
-		auth.Use(middleware.AuthMiddleware(jwtManager))
+		auth.Use(authMiddleware)
 		{
 			auth.POST("/search", searchHandler.Search)
 			auth.GET("/search", searchHandler.SearchGet)
+			auth.POST("/msearch", searchHandler.MultiSearch)
+			auth.GET("/suggest", searchHandler.Suggest)
 
 			auth.POST("/documents", documentHandler.Create)
 			auth.GET("/documents/:index_id/:id", documentHandler.Get)
 			auth.PUT("/documents/:index_id/:id", documentHandler.Update)
-			auth.DELETE("/documents/:index_id/:id", documentHandler.Delete)
+			auth.PATCH("/documents/:index_id/:id", documentHandler.Patch)
+			auth.DELETE("/documents/:index_id/:id", middleware.RequireScope(util.ScopeDocumentsDelete), documentHandler.Delete)
 			auth.POST("/documents/batch", documentHandler.Batch)
+			auth.POST("/documents/mget", documentHandler.MultiGet)
+			auth.POST("/documents/bulk", middleware.BodySizeLimitMiddleware(cfg.Server.MaxBulkBodyBytes), documentHandler.BulkStream)
 
 			auth.POST("/indexes", indexHandler.Create)
 			auth.GET("/indexes", indexHandler.List)
 			auth.GET("/indexes/:id", indexHandler.Get)
-			auth.DELETE("/indexes/:id", indexHandler.Delete)
+			auth.DELETE("/indexes/:id", middleware.RequireScope(util.ScopeIndexesDelete), indexHandler.Delete)
+			auth.GET("/indexes/:id/stats", indexHandler.Stats)
 			auth.POST("/indexes/:id/rebuild", indexHandler.Rebuild)
+			auth.GET("/tasks/:id", indexHandler.GetTask)
 		}
 	}
 
+	authHandler := handler.NewAuthHandler(jwtManager, logger.Logger)
+	router.POST("/api/v1/auth/refresh", authHandler.Refresh)
+
+	adminHandler := handler.NewAdminHandler(rateLimiter, logger.Logger)
+	admin := router.Group("/admin")
+	admin.Use(authMiddleware, middleware.RequireRole("admin"))
+	{
+		admin.POST("/ratelimit/reset", adminHandler.ResetRateLimit)
+		admin.POST("/indexes/:id/reconcile", indexHandler.Reconcile)
+	}
+
 	router.GET("/health", healthHandler.Check)
+	router.GET("/healthz", healthHandler.Ready)
 	router.GET("/health/services", healthHandler.CheckServices)
 	router.GET("/health/circuit-breakers", healthHandler.CheckCircuitBreakers)
 
@@ -165,18 +221,106 @@ func main() {
 		}
 	}()
 
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
+	sig := <-healthHandler.DrainOnSignal(syscall.SIGINT, syscall.SIGTERM)
 
-	logger.Info("Shutting down server...")
+	drainDelay := time.Duration(cfg.Server.DrainDelay) * time.Second
+	logger.Info("Draining server",
+		zap.String("signal", sig.String()),
+		zap.Duration("drain_delay", drainDelay),
+		zap.Int64("in_flight", healthHandler.InFlight()))
+	time.Sleep(drainDelay)
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+	logger.Info("Shutting down server...", zap.Int64("in_flight", healthHandler.InFlight()))
 
-	if err := srv.Shutdown(ctx); err != nil {
+	closeCoordinator := func(ctx context.Context) error {
+		if coordinatorClient == nil {
+			return nil
+		}
+		return coordinatorClient.Close()
+	}
+	if err := util.Shutdown(time.Duration(cfg.Server.ShutdownTimeout)*time.Second,
+		srv.Shutdown,
+		util.CloseFunc(redisClient.Close),
+		closeCoordinator,
+	); err != nil {
 		logger.Error("Server forced to shutdown", zap.Error(err))
 	}
 
 	logger.Info("Server exited")
 }
+
+func endpointRateLimits(endpoints []config.EndpointRateLimit) map[string]util.TierConfig {
+	overrides := make(map[string]util.TierConfig, len(endpoints))
+	for _, endpoint := range endpoints {
+		route := endpoint.Path
+		if endpoint.Method != "" {
+			route = strings.ToUpper(endpoint.Method) + " " + endpoint.Path
+		}
+		overrides[route] = util.TierConfig{
+			Limit:  endpoint.Limit,
+			Burst:  endpoint.Burst,
+			Window: endpoint.Window,
+		}
+	}
+	return overrides
+}
+
+// responseValidators lists the routes whose responses are checked against
+// their model before being sent.
+func responseValidators() map[string]middleware.ResponseFactory {
+	return map[string]middleware.ResponseFactory{
+		"POST /api/v1/search": func() middleware.ValidatableResponse {
+			return &model.SearchResponse{}
+		},
+		"GET /api/v1/search": func() middleware.ValidatableResponse {
+			return &model.SearchResponse{}
+		},
+		"GET /api/v1/documents/:index_id/:id": func() middleware.ValidatableResponse {
+			return &model.DocumentResponse{}
+		},
+		"PATCH /api/v1/documents/:index_id/:id": func() middleware.ValidatableResponse {
+			return &model.DocumentResponse{}
+		},
+		"POST /api/v1/documents/batch": func() middleware.ValidatableResponse {
+			return &model.BatchDocumentsResponse{}
+		},
+		"GET /api/v1/indexes": func() middleware.ValidatableResponse {
+			return &model.ListIndexesResponse{}
+		},
+		"GET /api/v1/indexes/:id": func() middleware.ValidatableResponse {
+			return &model.IndexInfo{}
+		},
+	}
+}
+
+func newJWTManager(cfg config.JWTConfig) (*util.JWTManager, error) {
+	managerConfig := util.JWTManagerConfig{
+		Algorithm:         cfg.Algorithm,
+		Secret:            cfg.Secret,
+		Issuer:            cfg.Issuer,
+		Expiration:        cfg.Expiration,
+		RefreshExpiration: cfg.RefreshExpiration,
+	}
+
+	if cfg.PrivateKeyFile != "" {
+		data, err := os.ReadFile(cfg.PrivateKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read JWT private key: %w", err)
+		}
+		if managerConfig.PrivateKey, err = jwt.ParseRSAPrivateKeyFromPEM(data); err != nil {
+			return nil, fmt.Errorf("failed to parse JWT private key: %w", err)
+		}
+	}
+
+	if cfg.PublicKeyFile != "" {
+		data, err := os.ReadFile(cfg.PublicKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read JWT public key: %w", err)
+		}
+		if managerConfig.PublicKey, err = jwt.ParseRSAPublicKeyFromPEM(data); err != nil {
+			return nil, fmt.Errorf("failed to parse JWT public key: %w", err)
+		}
+	}
+
+	return util.NewJWTManagerWithConfig(managerConfig)
+}
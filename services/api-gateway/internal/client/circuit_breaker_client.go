@@ -25,7 +25,16 @@ func NewCircuitBreakerCoordinatorClient(cfg *config.CoordinatorConfig) (*Circuit
 	if err != nil {
 		return nil, err
 	}
+	return newCircuitBreakerClient(baseClient), nil
+}
+
+// NewCircuitBreakerCoordinatorClientWithConn wraps a client built on an
+// existing connection, see NewCoordinatorClientWithConn.
+func NewCircuitBreakerCoordinatorClientWithConn(cc grpc.ClientConnInterface) *CircuitBreakerCoordinatorClient {
+	return newCircuitBreakerClient(NewCoordinatorClientWithConn(cc))
+}
 
+func newCircuitBreakerClient(baseClient *CoordinatorClient) *CircuitBreakerCoordinatorClient {
 	// Create circuit breakers with different configurations for different services
 	searchConfig := util.DefaultCircuitBreakerConfig()
 	searchConfig.FailureThreshold = 3
@@ -49,7 +58,7 @@ func NewCircuitBreakerCoordinatorClient(cfg *config.CoordinatorConfig) (*Circuit
 		documentCircuitBreaker: util.NewCircuitBreaker("document-service", documentConfig),
 		indexCircuitBreaker:    util.NewCircuitBreaker("index-service", indexConfig),
 		healthCircuitBreaker:   util.NewCircuitBreaker("health-service", healthConfig),
-	}, nil
+	}
 }
 
 // Search with circuit breaker
@@ -69,6 +78,40 @@ func (c *CircuitBreakerCoordinatorClient) Search(ctx context.Context, req *pb.Se
 	return resp, err
 }
 
+// MultiSearch with circuit breaker
+func (c *CircuitBreakerCoordinatorClient) MultiSearch(ctx context.Context, req *pb.MultiSearchRequest, opts ...grpc.CallOption) (*pb.MultiSearchResponse, error) {
+	var resp *pb.MultiSearchResponse
+	var err error
+
+	cbErr := c.searchCircuitBreaker.Execute(ctx, func() error {
+		resp, err = c.CoordinatorClient.MultiSearch(ctx, req, opts...)
+		return err
+	})
+
+	if cbErr != nil {
+		return nil, cbErr
+	}
+
+	return resp, err
+}
+
+// Suggest with circuit breaker
+func (c *CircuitBreakerCoordinatorClient) Suggest(ctx context.Context, req *pb.SuggestRequest, opts ...grpc.CallOption) (*pb.SuggestResponse, error) {
+	var resp *pb.SuggestResponse
+	var err error
+
+	cbErr := c.searchCircuitBreaker.Execute(ctx, func() error {
+		resp, err = c.CoordinatorClient.Suggest(ctx, req, opts...)
+		return err
+	})
+
+	if cbErr != nil {
+		return nil, cbErr
+	}
+
+	return resp, err
+}
+
 // GetDocument with circuit breaker
 func (c *CircuitBreakerCoordinatorClient) GetDocument(ctx context.Context, req *pb.GetDocumentRequest, opts ...grpc.CallOption) (*pb.DocumentResponse, error) {
 	var resp *pb.DocumentResponse
@@ -86,6 +129,23 @@ func (c *CircuitBreakerCoordinatorClient) GetDocument(ctx context.Context, req *
 	return resp, err
 }
 
+// GetDocuments with circuit breaker
+func (c *CircuitBreakerCoordinatorClient) GetDocuments(ctx context.Context, req *pb.GetDocumentsRequest, opts ...grpc.CallOption) (*pb.GetDocumentsResponse, error) {
+	var resp *pb.GetDocumentsResponse
+	var err error
+
+	cbErr := c.documentCircuitBreaker.Execute(ctx, func() error {
+		resp, err = c.CoordinatorClient.GetDocuments(ctx, req, opts...)
+		return err
+	})
+
+	if cbErr != nil {
+		return nil, cbErr
+	}
+
+	return resp, err
+}
+
 // AddDocument with circuit breaker
 func (c *CircuitBreakerCoordinatorClient) AddDocument(ctx context.Context, req *pb.AddDocumentRequest, opts ...grpc.CallOption) (*pb.AddDocumentResponse, error) {
 	var resp *pb.AddDocumentResponse
@@ -120,6 +180,41 @@ func (c *CircuitBreakerCoordinatorClient) UpdateDocument(ctx context.Context, re
 	return resp, err
 }
 
+// MergeDocument with circuit breaker
+func (c *CircuitBreakerCoordinatorClient) MergeDocument(ctx context.Context, req *pb.MergeDocumentRequest, opts ...grpc.CallOption) (*pb.DocumentResponse, error) {
+	var resp *pb.DocumentResponse
+	var err error
+
+	cbErr := c.documentCircuitBreaker.Execute(ctx, func() error {
+		resp, err = c.CoordinatorClient.MergeDocument(ctx, req, opts...)
+		return err
+	})
+
+	if cbErr != nil {
+		return nil, cbErr
+	}
+
+	return resp, err
+}
+
+// BatchDocumentsStream with circuit breaker. Only opening the stream counts
+// against the breaker.
+func (c *CircuitBreakerCoordinatorClient) BatchDocumentsStream(ctx context.Context, opts ...grpc.CallOption) (pb.DocumentService_BatchDocumentsStreamClient, error) {
+	var stream pb.DocumentService_BatchDocumentsStreamClient
+	var err error
+
+	cbErr := c.documentCircuitBreaker.Execute(ctx, func() error {
+		stream, err = c.CoordinatorClient.BatchDocumentsStream(ctx, opts...)
+		return err
+	})
+
+	if cbErr != nil {
+		return nil, cbErr
+	}
+
+	return stream, err
+}
+
 // DeleteDocument with circuit breaker
 func (c *CircuitBreakerCoordinatorClient) DeleteDocument(ctx context.Context, req *pb.DeleteDocumentRequest, opts ...grpc.CallOption) (*pb.DeleteDocumentResponse, error) {
 	var resp *pb.DeleteDocumentResponse
@@ -239,6 +334,57 @@ func (c *CircuitBreakerCoordinatorClient) RebuildIndex(ctx context.Context, req
 	return resp, err
 }
 
+// GetTaskStatus with circuit breaker
+func (c *CircuitBreakerCoordinatorClient) GetTaskStatus(ctx context.Context, req *pb.GetTaskStatusRequest, opts ...grpc.CallOption) (*pb.TaskStatusResponse, error) {
+	var resp *pb.TaskStatusResponse
+	var err error
+
+	cbErr := c.indexCircuitBreaker.Execute(ctx, func() error {
+		resp, err = c.CoordinatorClient.GetTaskStatus(ctx, req, opts...)
+		return err
+	})
+
+	if cbErr != nil {
+		return nil, cbErr
+	}
+
+	return resp, err
+}
+
+// GetIndexStats with circuit breaker
+func (c *CircuitBreakerCoordinatorClient) GetIndexStats(ctx context.Context, req *pb.GetIndexStatsRequest, opts ...grpc.CallOption) (*pb.IndexStatsResponse, error) {
+	var resp *pb.IndexStatsResponse
+	var err error
+
+	cbErr := c.indexCircuitBreaker.Execute(ctx, func() error {
+		resp, err = c.CoordinatorClient.GetIndexStats(ctx, req, opts...)
+		return err
+	})
+
+	if cbErr != nil {
+		return nil, cbErr
+	}
+
+	return resp, err
+}
+
+// ReconcileIndex with circuit breaker
+func (c *CircuitBreakerCoordinatorClient) ReconcileIndex(ctx context.Context, req *pb.ReconcileIndexRequest, opts ...grpc.CallOption) (*pb.ReconcileReport, error) {
+	var resp *pb.ReconcileReport
+	var err error
+
+	cbErr := c.indexCircuitBreaker.Execute(ctx, func() error {
+		resp, err = c.CoordinatorClient.ReconcileIndex(ctx, req, opts...)
+		return err
+	})
+
+	if cbErr != nil {
+		return nil, cbErr
+	}
+
+	return resp, err
+}
+
 // HealthCheck with circuit breaker
 func (c *CircuitBreakerCoordinatorClient) HealthCheck(ctx context.Context, req *pb.HealthCheckRequest, opts ...grpc.CallOption) (*pb.HealthCheckResponse, error) {
 	var resp *pb.HealthCheckResponse
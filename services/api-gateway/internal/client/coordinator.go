@@ -25,8 +25,11 @@ type CoordinatorClient struct {
 func NewCoordinatorClient(cfg *config.CoordinatorConfig) (*CoordinatorClient, error) {
 	conn, err := grpc.Dial(cfg.Address,
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		pb.WithJSONCodec(),
 		grpc.WithBlock(),
 		grpc.WithTimeout(time.Duration(cfg.Timeout)*time.Second),
+		grpc.WithChainUnaryInterceptor(tracePropagationUnaryInterceptor(), requestIDUnaryInterceptor(), tenantUnaryInterceptor()),
+		grpc.WithChainStreamInterceptor(tracePropagationStreamInterceptor(), requestIDStreamInterceptor(), tenantStreamInterceptor()),
 	)
 	if err != nil {
 		return nil, err
@@ -42,7 +45,22 @@ func NewCoordinatorClient(cfg *config.CoordinatorConfig) (*CoordinatorClient, er
 	}, nil
 }
 
+// NewCoordinatorClientWithConn builds a client on an existing connection.
+// The caller owns cc; Close is a no-op for such clients.
+func NewCoordinatorClientWithConn(cc grpc.ClientConnInterface) *CoordinatorClient {
+	return &CoordinatorClient{
+		search:   pb.NewSearchServiceClient(cc),
+		document: pb.NewDocumentServiceClient(cc),
+		index:    pb.NewIndexServiceClient(cc),
+		health:   pb.NewHealthClient(cc),
+		tracer:   otel.Tracer("coordinator-client"),
+	}
+}
+
 func (c *CoordinatorClient) Close() error {
+	if c.conn == nil {
+		return nil
+	}
 	return c.conn.Close()
 }
 
@@ -65,6 +83,38 @@ func (c *CoordinatorClient) Search(ctx context.Context, req *pb.SearchRequest, o
 	return resp, nil
 }
 
+func (c *CoordinatorClient) MultiSearch(ctx context.Context, req *pb.MultiSearchRequest, opts ...grpc.CallOption) (*pb.MultiSearchResponse, error) {
+	ctx, span := c.tracer.Start(ctx, "CoordinatorClient.MultiSearch",
+		trace.WithAttributes(attribute.Int("query_count", len(req.Requests))))
+	defer span.End()
+
+	resp, err := c.search.MultiSearch(ctx, req, opts...)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (c *CoordinatorClient) Suggest(ctx context.Context, req *pb.SuggestRequest, opts ...grpc.CallOption) (*pb.SuggestResponse, error) {
+	ctx, span := c.tracer.Start(ctx, "CoordinatorClient.Suggest",
+		trace.WithAttributes(
+			attribute.String("prefix", req.Prefix),
+			attribute.Int("limit", int(req.Limit)),
+		))
+	defer span.End()
+
+	resp, err := c.search.Suggest(ctx, req, opts...)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.Int("suggestion_count", len(resp.Suggestions)))
+	return resp, nil
+}
+
 func (c *CoordinatorClient) GetDocument(ctx context.Context, req *pb.GetDocumentRequest, opts ...grpc.CallOption) (*pb.DocumentResponse, error) {
 	ctx, span := c.tracer.Start(ctx, "CoordinatorClient.GetDocument",
 		trace.WithAttributes(
@@ -76,6 +126,17 @@ func (c *CoordinatorClient) GetDocument(ctx context.Context, req *pb.GetDocument
 	return c.document.GetDocument(ctx, req, opts...)
 }
 
+func (c *CoordinatorClient) GetDocuments(ctx context.Context, req *pb.GetDocumentsRequest, opts ...grpc.CallOption) (*pb.GetDocumentsResponse, error) {
+	ctx, span := c.tracer.Start(ctx, "CoordinatorClient.GetDocuments",
+		trace.WithAttributes(
+			attribute.String("index_id", req.IndexId),
+			attribute.Int("document_count", len(req.DocumentIds)),
+		))
+	defer span.End()
+
+	return c.document.GetDocuments(ctx, req, opts...)
+}
+
 func (c *CoordinatorClient) AddDocument(ctx context.Context, req *pb.AddDocumentRequest, opts ...grpc.CallOption) (*pb.AddDocumentResponse, error) {
 	ctx, span := c.tracer.Start(ctx, "CoordinatorClient.AddDocument",
 		trace.WithAttributes(
@@ -97,6 +158,31 @@ func (c *CoordinatorClient) UpdateDocument(ctx context.Context, req *pb.UpdateDo
 	return c.document.UpdateDocument(ctx, req, opts...)
 }
 
+func (c *CoordinatorClient) MergeDocument(ctx context.Context, req *pb.MergeDocumentRequest, opts ...grpc.CallOption) (*pb.DocumentResponse, error) {
+	ctx, span := c.tracer.Start(ctx, "CoordinatorClient.MergeDocument",
+		trace.WithAttributes(
+			attribute.String("index_id", req.IndexId),
+			attribute.String("document_id", req.DocumentId),
+		))
+	defer span.End()
+
+	return c.document.MergeDocument(ctx, req, opts...)
+}
+
+// BatchDocumentsStream opens a client stream for bulk ingest. The span only
+// covers opening the stream; callers send chunks and call CloseAndRecv.
+func (c *CoordinatorClient) BatchDocumentsStream(ctx context.Context, opts ...grpc.CallOption) (pb.DocumentService_BatchDocumentsStreamClient, error) {
+	ctx, span := c.tracer.Start(ctx, "CoordinatorClient.BatchDocumentsStream")
+	defer span.End()
+
+	stream, err := c.document.BatchDocumentsStream(ctx, opts...)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	return stream, nil
+}
+
 func (c *CoordinatorClient) DeleteDocument(ctx context.Context, req *pb.DeleteDocumentRequest, opts ...grpc.CallOption) (*pb.DeleteDocumentResponse, error) {
 	ctx, span := c.tracer.Start(ctx, "CoordinatorClient.DeleteDocument",
 		trace.WithAttributes(
@@ -189,6 +275,33 @@ func (c *CoordinatorClient) RebuildIndex(ctx context.Context, req *pb.RebuildInd
 	return c.index.RebuildIndex(ctx, req, opts...)
 }
 
+func (c *CoordinatorClient) GetTaskStatus(ctx context.Context, req *pb.GetTaskStatusRequest, opts ...grpc.CallOption) (*pb.TaskStatusResponse, error) {
+	ctx, span := c.tracer.Start(ctx, "CoordinatorClient.GetTaskStatus",
+		trace.WithAttributes(attribute.String("task_id", req.TaskId)))
+	defer span.End()
+
+	return c.index.GetTaskStatus(ctx, req, opts...)
+}
+
+func (c *CoordinatorClient) GetIndexStats(ctx context.Context, req *pb.GetIndexStatsRequest, opts ...grpc.CallOption) (*pb.IndexStatsResponse, error) {
+	ctx, span := c.tracer.Start(ctx, "CoordinatorClient.GetIndexStats",
+		trace.WithAttributes(attribute.String("index_id", req.IndexId)))
+	defer span.End()
+
+	return c.index.GetIndexStats(ctx, req, opts...)
+}
+
+func (c *CoordinatorClient) ReconcileIndex(ctx context.Context, req *pb.ReconcileIndexRequest, opts ...grpc.CallOption) (*pb.ReconcileReport, error) {
+	ctx, span := c.tracer.Start(ctx, "CoordinatorClient.ReconcileIndex",
+		trace.WithAttributes(
+			attribute.String("index_id", req.IndexId),
+			attribute.Bool("rebuild", req.Rebuild),
+		))
+	defer span.End()
+
+	return c.index.ReconcileIndex(ctx, req, opts...)
+}
+
 func (c *CoordinatorClient) HealthCheck(ctx context.Context, req *pb.HealthCheckRequest, opts ...grpc.CallOption) (*pb.HealthCheckResponse, error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
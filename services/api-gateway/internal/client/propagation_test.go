@@ -0,0 +1,70 @@
+package client
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestTracePropagationUnaryInterceptor(t *testing.T) {
+	previous := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer otel.SetTextMapPropagator(previous)
+
+	tp := sdktrace.NewTracerProvider()
+	defer tp.Shutdown(context.Background())
+
+	ctx, span := tp.Tracer("test").Start(context.Background(), "incoming")
+	defer span.End()
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-request-id", "req-1")
+
+	var outgoing metadata.MD
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		outgoing, _ = metadata.FromOutgoingContext(ctx)
+		return nil
+	}
+
+	interceptor := tracePropagationUnaryInterceptor()
+	if err := interceptor(ctx, "/coordinator.SearchService/Search", nil, nil, nil, invoker); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	values := outgoing.Get("traceparent")
+	if len(values) != 1 {
+		t.Fatalf("Expected one traceparent header, got %v", values)
+	}
+	sc := span.SpanContext()
+	parts := strings.Split(values[0], "-")
+	if len(parts) != 4 || parts[1] != sc.TraceID().String() || parts[2] != sc.SpanID().String() {
+		t.Errorf("Expected traceparent for trace %s span %s, got %q", sc.TraceID(), sc.SpanID(), values[0])
+	}
+	if got := outgoing.Get("x-request-id"); len(got) != 1 || got[0] != "req-1" {
+		t.Errorf("Expected existing metadata to be kept, got %v", got)
+	}
+}
+
+func TestTracePropagationUnaryInterceptor_NoSpan(t *testing.T) {
+	previous := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer otel.SetTextMapPropagator(previous)
+
+	var outgoing metadata.MD
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		outgoing, _ = metadata.FromOutgoingContext(ctx)
+		return nil
+	}
+
+	interceptor := tracePropagationUnaryInterceptor()
+	if err := interceptor(context.Background(), "/coordinator.SearchService/Search", nil, nil, nil, invoker); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if values := outgoing.Get("traceparent"); len(values) != 0 {
+		t.Errorf("Expected no traceparent without a span, got %v", values)
+	}
+}
@@ -0,0 +1,35 @@
+package client
+
+import (
+	"context"
+
+	"github.com/flexsearch/api-gateway/internal/util"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// injectRequestID forwards the gateway request ID as x-request-id metadata
+// so coordinator logs can be correlated with the HTTP request. Metadata the
+// caller already set wins.
+func injectRequestID(ctx context.Context) context.Context {
+	requestID := util.RequestIDFromContext(ctx)
+	if requestID == "" {
+		return ctx
+	}
+	if md, ok := metadata.FromOutgoingContext(ctx); ok && len(md.Get(util.RequestIDMetadataKey)) > 0 {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, util.RequestIDMetadataKey, requestID)
+}
+
+func requestIDUnaryInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(injectRequestID(ctx), method, req, reply, cc, opts...)
+	}
+}
+
+func requestIDStreamInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(injectRequestID(ctx), desc, cc, method, opts...)
+	}
+}
@@ -0,0 +1,70 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/flexsearch/api-gateway/internal/util"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func invokeWithRequestID(t *testing.T, ctx context.Context) metadata.MD {
+	t.Helper()
+
+	var outgoing metadata.MD
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		outgoing, _ = metadata.FromOutgoingContext(ctx)
+		return nil
+	}
+	if err := requestIDUnaryInterceptor()(ctx, "/coordinator.SearchService/Search", nil, nil, nil, invoker); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	return outgoing
+}
+
+func TestRequestIDUnaryInterceptor(t *testing.T) {
+	ctx := util.ContextWithRequestID(context.Background(), "req-42")
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer x")
+
+	md := invokeWithRequestID(t, ctx)
+	if got := md.Get(util.RequestIDMetadataKey); len(got) != 1 || got[0] != "req-42" {
+		t.Errorf("Expected x-request-id req-42, got %v", got)
+	}
+	if got := md.Get("authorization"); len(got) != 1 {
+		t.Errorf("Expected existing metadata to be kept, got %v", got)
+	}
+}
+
+func TestRequestIDUnaryInterceptor_KeepsExplicitMetadata(t *testing.T) {
+	ctx := util.ContextWithRequestID(context.Background(), "req-42")
+	ctx = metadata.AppendToOutgoingContext(ctx, util.RequestIDMetadataKey, "explicit")
+
+	md := invokeWithRequestID(t, ctx)
+	if got := md.Get(util.RequestIDMetadataKey); len(got) != 1 || got[0] != "explicit" {
+		t.Errorf("Expected the caller's x-request-id to win, got %v", got)
+	}
+}
+
+func TestRequestIDUnaryInterceptor_NoRequestID(t *testing.T) {
+	md := invokeWithRequestID(t, context.Background())
+	if got := md.Get(util.RequestIDMetadataKey); len(got) != 0 {
+		t.Errorf("Expected no x-request-id without a request ID, got %v", got)
+	}
+}
+
+func TestRequestIDStreamInterceptor(t *testing.T) {
+	ctx := util.ContextWithRequestID(context.Background(), "req-7")
+
+	var outgoing metadata.MD
+	streamer := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		outgoing, _ = metadata.FromOutgoingContext(ctx)
+		return nil, nil
+	}
+	if _, err := requestIDStreamInterceptor()(ctx, &grpc.StreamDesc{}, nil, "/coordinator.DocumentService/BatchDocumentsStream", streamer); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got := outgoing.Get(util.RequestIDMetadataKey); len(got) != 1 || got[0] != "req-7" {
+		t.Errorf("Expected x-request-id req-7 on the stream, got %v", got)
+	}
+}
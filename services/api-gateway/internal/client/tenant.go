@@ -0,0 +1,35 @@
+package client
+
+import (
+	"context"
+
+	"github.com/flexsearch/api-gateway/internal/util"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// injectTenant forwards the caller's tenant as x-tenant-id metadata. Unlike
+// the request ID, a tenant already in the metadata is replaced: it must
+// come from the caller's credentials, never from an earlier hop.
+func injectTenant(ctx context.Context) context.Context {
+	tenant := util.TenantFromContext(ctx)
+	if tenant == "" {
+		return ctx
+	}
+	md, _ := metadata.FromOutgoingContext(ctx)
+	md = md.Copy()
+	md.Set(util.TenantMetadataKey, tenant)
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+func tenantUnaryInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(injectTenant(ctx), method, req, reply, cc, opts...)
+	}
+}
+
+func tenantStreamInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(injectTenant(ctx), desc, cc, method, opts...)
+	}
+}
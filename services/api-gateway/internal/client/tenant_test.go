@@ -0,0 +1,54 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/flexsearch/api-gateway/internal/util"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func invokeWithTenant(t *testing.T, ctx context.Context) metadata.MD {
+	t.Helper()
+
+	var outgoing metadata.MD
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		outgoing, _ = metadata.FromOutgoingContext(ctx)
+		return nil
+	}
+	if err := tenantUnaryInterceptor()(ctx, "/coordinator.SearchService/Search", nil, nil, nil, invoker); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	return outgoing
+}
+
+func TestTenantUnaryInterceptor(t *testing.T) {
+	ctx := util.ContextWithTenant(context.Background(), "acme")
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer x")
+
+	md := invokeWithTenant(t, ctx)
+	if got := md.Get(util.TenantMetadataKey); len(got) != 1 || got[0] != "acme" {
+		t.Errorf("Expected x-tenant-id acme, got %v", got)
+	}
+	if got := md.Get("authorization"); len(got) != 1 {
+		t.Errorf("Expected existing metadata to be kept, got %v", got)
+	}
+}
+
+func TestTenantUnaryInterceptor_ReplacesForwardedTenant(t *testing.T) {
+	ctx := util.ContextWithTenant(context.Background(), "acme")
+	ctx = metadata.AppendToOutgoingContext(ctx, util.TenantMetadataKey, "globex")
+
+	md := invokeWithTenant(t, ctx)
+	if got := md.Get(util.TenantMetadataKey); len(got) != 1 || got[0] != "acme" {
+		t.Errorf("Expected the credential's tenant to replace the metadata, got %v", got)
+	}
+}
+
+func TestTenantUnaryInterceptor_NoTenant(t *testing.T) {
+	md := invokeWithTenant(t, context.Background())
+	if got := md.Get(util.TenantMetadataKey); len(got) != 0 {
+		t.Errorf("Expected no x-tenant-id without a tenant, got %v", got)
+	}
+}
@@ -15,6 +15,10 @@ type Config struct {
 	JWT         JWTConfig         `mapstructure:"jwt"`
 	RateLimit   RateLimitConfig   `mapstructure:"ratelimit"`
 	CORS        CORSConfig        `mapstructure:"cors"`
+	APIKeys     APIKeyConfig      `mapstructure:"apikeys"`
+	Tracing     TracingConfig     `mapstructure:"tracing"`
+	Search      SearchConfig      `mapstructure:"search"`
+	Tenant      TenantConfig      `mapstructure:"tenant"`
 }
 
 type ServerConfig struct {
@@ -22,12 +26,28 @@ type ServerConfig struct {
 	Mode         string `mapstructure:"mode"`
 	ReadTimeout  int    `mapstructure:"read_timeout"`
 	WriteTimeout int    `mapstructure:"write_timeout"`
+	// MaxBodyBytes caps request bodies; MaxBulkBodyBytes overrides it for
+	// the NDJSON bulk ingest route. Zero disables the limit.
+	MaxBodyBytes     int64 `mapstructure:"max_body_bytes"`
+	MaxBulkBodyBytes int64 `mapstructure:"max_bulk_body_bytes"`
+	// RequestTimeout bounds each request, in seconds, including the
+	// coordinator call it makes. Zero disables the timeout.
+	RequestTimeout int `mapstructure:"request_timeout"`
+	// DrainDelay is how long, in seconds, readiness reports draining after
+	// SIGTERM before the server stops accepting connections.
+	DrainDelay int `mapstructure:"drain_delay"`
+	// ShutdownTimeout bounds, in seconds, server shutdown and closing the
+	// Redis and coordinator clients.
+	ShutdownTimeout int `mapstructure:"shutdown_timeout"`
 }
 
 type LogConfig struct {
 	Level  string `mapstructure:"level"`
 	Format string `mapstructure:"format"`
 	Output string `mapstructure:"output"`
+	// RedactFields lists field names, such as email or ssn, whose values
+	// are masked in structured logs and logged query strings.
+	RedactFields []string `mapstructure:"redact_fields"`
 }
 
 type RedisConfig struct {
@@ -51,17 +71,31 @@ type CoordinatorConfig struct {
 }
 
 type JWTConfig struct {
-	Secret     string `mapstructure:"secret"`
-	Expiration int    `mapstructure:"expiration"`
-	Issuer     string `mapstructure:"issuer"`
+	Algorithm         string `mapstructure:"algorithm"`
+	Secret            string `mapstructure:"secret"`
+	PrivateKeyFile    string `mapstructure:"private_key_file"`
+	PublicKeyFile     string `mapstructure:"public_key_file"`
+	Expiration        int    `mapstructure:"expiration"`
+	RefreshExpiration int    `mapstructure:"refresh_expiration"`
+	Issuer            string `mapstructure:"issuer"`
 }
 
 type RateLimitConfig struct {
-	Enabled       bool          `mapstructure:"enabled"`
-	DefaultLimit  int           `mapstructure:"default_limit"`
-	DefaultWindow time.Duration `mapstructure:"default_window"`
-	ByUser        bool          `mapstructure:"by_user"`
-	ByIP          bool          `mapstructure:"by_ip"`
+	Enabled       bool                `mapstructure:"enabled"`
+	Algorithm     string              `mapstructure:"algorithm"`
+	DefaultLimit  int                 `mapstructure:"default_limit"`
+	DefaultWindow time.Duration       `mapstructure:"default_window"`
+	ByUser        bool                `mapstructure:"by_user"`
+	ByIP          bool                `mapstructure:"by_ip"`
+	Endpoints     []EndpointRateLimit `mapstructure:"endpoints"`
+}
+
+type EndpointRateLimit struct {
+	Method string        `mapstructure:"method"`
+	Path   string        `mapstructure:"path"`
+	Limit  int           `mapstructure:"limit"`
+	Burst  int           `mapstructure:"burst"`
+	Window time.Duration `mapstructure:"window"`
 }
 
 type CORSConfig struct {
@@ -72,12 +106,47 @@ type CORSConfig struct {
 	AllowCredentials bool     `mapstructure:"allow_credentials"`
 }
 
+// APIKeyConfig enables X-API-Key authentication next to JWTs. Keys live in
+// Redis under Prefix.
+type APIKeyConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Prefix  string `mapstructure:"prefix"`
+}
+
+// TracingConfig selects where spans are exported: stdout, otlp-grpc,
+// otlp-http or none. Endpoint is the collector address for the OTLP
+// exporters.
+type TracingConfig struct {
+	Enabled    bool    `mapstructure:"enabled"`
+	Exporter   string  `mapstructure:"exporter"`
+	Endpoint   string  `mapstructure:"endpoint"`
+	Insecure   bool    `mapstructure:"insecure"`
+	SampleRate float64 `mapstructure:"sample_rate"`
+}
+
+type SearchConfig struct {
+	// MaxResultWindow caps page * page_size so deep pages don't make the
+	// coordinator materialize huge result sets. Zero disables the check.
+	MaxResultWindow int `mapstructure:"max_result_window"`
+}
+
+// TenantConfig selects where the caller's tenant comes from. A tenant
+// claim in the JWT or API key always wins; Header, when set, names a
+// request header to read it from otherwise. Only set Header behind a proxy
+// that strips it from client requests.
+type TenantConfig struct {
+	Header string `mapstructure:"header"`
+}
+
 func Load() (*Config, error) {
 	viper.SetConfigName("config")
 	viper.SetConfigType("yaml")
 	viper.AddConfigPath("./configs")
 	viper.AddConfigPath(".")
 
+	viper.SetDefault("server.shutdown_timeout", 30)
+	viper.SetDefault("search.max_result_window", 10000)
+
 	viper.SetEnvPrefix("API_GATEWAY")
 	viper.AutomaticEnv()
 
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/flexsearch/api-gateway/internal/model"
+	"github.com/flexsearch/api-gateway/internal/util"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type AdminHandler struct {
+	limiter *util.RateLimiter
+	logger  *zap.Logger
+}
+
+func NewAdminHandler(limiter *util.RateLimiter, logger *zap.Logger) *AdminHandler {
+	return &AdminHandler{
+		limiter: limiter,
+		logger:  logger,
+	}
+}
+
+// redisGlobEscaper escapes the characters SCAN MATCH treats as glob syntax.
+var redisGlobEscaper = strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`, `[`, `\[`, `]`, `\]`)
+
+// ResetRateLimit clears throttling state for a key pattern, or for every
+// bucket belonging to a user when user_id is given.
+func (h *AdminHandler) ResetRateLimit(c *gin.Context) {
+	var req model.RateLimitResetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondError(c, model.ErrCodeInvalidRequest, err)
+		return
+	}
+
+	var patterns []string
+	switch {
+	case req.UserID != "":
+		// A user's buckets are "user:<id>" plus per-route "user:<id>:..."
+		// keys; a single "user:<id>*" glob would also match other IDs
+		// sharing the prefix. The ID is escaped so it only matches itself.
+		userKey := fmt.Sprintf("user:%s", redisGlobEscaper.Replace(req.UserID))
+		patterns = []string{userKey, userKey + ":*"}
+	case req.Pattern != "":
+		patterns = []string{req.Pattern}
+	default:
+		RespondError(c, model.ErrCodeInvalidRequest, errors.New("pattern or user_id is required"))
+		return
+	}
+
+	reset := 0
+	for _, pattern := range patterns {
+		n, err := h.limiter.ResetByPattern(c.Request.Context(), pattern)
+		if err != nil {
+			h.logger.Error("Rate limit reset failed",
+				zap.Error(err),
+				zap.String("pattern", pattern))
+			RespondError(c, model.ErrCodeInternal, err)
+			return
+		}
+		reset += n
+	}
+
+	h.logger.Info("Rate limits reset",
+		zap.Strings("patterns", patterns),
+		zap.Int("reset", reset),
+		zap.String("admin", c.GetString("user_id")))
+
+	c.JSON(http.StatusOK, model.RateLimitResetResponse{
+		Patterns: patterns,
+		Reset:    reset,
+	})
+}
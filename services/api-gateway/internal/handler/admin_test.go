@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/flexsearch/api-gateway/internal/middleware"
+	"github.com/flexsearch/api-gateway/internal/model"
+	"github.com/flexsearch/api-gateway/internal/util"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+func setupAdminRouter(t *testing.T) (*gin.Engine, *util.JWTManager, *util.RateLimiter, *miniredis.Miniredis) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	limiter := util.NewRateLimiter(client, util.DefaultRateLimitConfig())
+	jwtManager := util.NewJWTManager("test-secret", "test-issuer", 24)
+	h := NewAdminHandler(limiter, zap.NewNop())
+
+	router := gin.New()
+	admin := router.Group("/admin")
+	admin.Use(middleware.AuthMiddleware(jwtManager), middleware.RequireRole("admin"))
+	admin.POST("/ratelimit/reset", h.ResetRateLimit)
+
+	return router, jwtManager, limiter, mr
+}
+
+func postReset(t *testing.T, router *gin.Engine, token string, body model.RateLimitResetRequest) *httptest.ResponseRecorder {
+	t.Helper()
+
+	payload, _ := json.Marshal(body)
+	req := httptest.NewRequest(http.MethodPost, "/admin/ratelimit/reset", bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestAdminHandler_ResetRateLimitForUser(t *testing.T) {
+	router, jwtManager, limiter, mr := setupAdminRouter(t)
+	ctx := context.Background()
+
+	for _, key := range []string{"user:42", "user:42:route:POST /api/v1/documents/batch", "user:420", "user:7"} {
+		if _, err := limiter.Allow(ctx, key, util.TierFree); err != nil {
+			t.Fatalf("Allow failed: %v", err)
+		}
+	}
+
+	token, _ := jwtManager.GenerateToken("admin1", "admin", "admin")
+	w := postReset(t, router, token, model.RateLimitResetRequest{UserID: "42"})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var resp model.RateLimitResetResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.Reset != 2 {
+		t.Errorf("Expected 2 buckets reset, got %d", resp.Reset)
+	}
+	if !mr.Exists("ratelimit:bucket:user:7") || !mr.Exists("ratelimit:bucket:user:420") {
+		t.Error("Expected other users' buckets to be kept")
+	}
+}
+
+func TestAdminHandler_ResetRateLimitEscapesUserID(t *testing.T) {
+	router, jwtManager, limiter, mr := setupAdminRouter(t)
+	ctx := context.Background()
+
+	for _, key := range []string{"user:a*b", "user:axb", "user:42", "user:7"} {
+		if _, err := limiter.Allow(ctx, key, util.TierFree); err != nil {
+			t.Fatalf("Allow failed: %v", err)
+		}
+	}
+
+	token, _ := jwtManager.GenerateToken("admin1", "admin", "admin")
+	for _, tt := range []struct {
+		userID string
+		want   int
+	}{
+		{"*", 0},
+		{"4?", 0},
+		{"[47]", 0},
+		{"a*b", 1},
+	} {
+		w := postReset(t, router, token, model.RateLimitResetRequest{UserID: tt.userID})
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		var resp model.RateLimitResetResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		if resp.Reset != tt.want {
+			t.Errorf("Expected user_id %q to reset %d buckets, got %d", tt.userID, tt.want, resp.Reset)
+		}
+	}
+
+	for _, key := range []string{"user:axb", "user:42", "user:7"} {
+		if !mr.Exists("ratelimit:bucket:" + key) {
+			t.Errorf("Expected bucket %s to be kept", key)
+		}
+	}
+	if mr.Exists("ratelimit:bucket:user:a*b") {
+		t.Error("Expected the bucket for user a*b to be reset")
+	}
+}
+
+func TestAdminHandler_ResetRateLimitRequiresAdmin(t *testing.T) {
+	router, jwtManager, _, _ := setupAdminRouter(t)
+
+	token, _ := jwtManager.GenerateToken("user1", "user", "user")
+	w := postReset(t, router, token, model.RateLimitResetRequest{Pattern: "*"})
+	if w.Code != http.StatusForbidden {
+		t.Errorf("Expected status %d, got %d", http.StatusForbidden, w.Code)
+	}
+}
+
+func TestAdminHandler_ResetRateLimitRequiresPattern(t *testing.T) {
+	router, jwtManager, _, _ := setupAdminRouter(t)
+
+	token, _ := jwtManager.GenerateToken("admin1", "admin", "admin")
+	w := postReset(t, router, token, model.RateLimitResetRequest{})
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/flexsearch/api-gateway/internal/model"
+	"github.com/flexsearch/api-gateway/internal/util"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type AuthHandler struct {
+	jwtManager *util.JWTManager
+	logger     *zap.Logger
+}
+
+func NewAuthHandler(jwtManager *util.JWTManager, logger *zap.Logger) *AuthHandler {
+	return &AuthHandler{
+		jwtManager: jwtManager,
+		logger:     logger,
+	}
+}
+
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req model.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondError(c, model.ErrCodeInvalidRequest, err)
+		return
+	}
+
+	accessToken, err := h.jwtManager.ExchangeRefreshToken(req.RefreshToken)
+	if err != nil {
+		h.logger.Warn("Refresh token rejected",
+			zap.Error(err))
+		RespondError(c, model.ErrCodeInvalidRefreshToken, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.TokenResponse{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(h.jwtManager.AccessTokenLifetime().Seconds()),
+	})
+}
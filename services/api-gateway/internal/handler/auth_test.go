@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/flexsearch/api-gateway/internal/model"
+	"github.com/flexsearch/api-gateway/internal/util"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+func postRefresh(t *testing.T, jwtManager *util.JWTManager, refreshToken string) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.POST("/api/v1/auth/refresh", NewAuthHandler(jwtManager, zap.NewNop()).Refresh)
+
+	payload, _ := json.Marshal(model.RefreshTokenRequest{RefreshToken: refreshToken})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/refresh", bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestAuthHandler_Refresh(t *testing.T) {
+	jwtManager := util.NewJWTManager("test-secret", "test-issuer", 24)
+
+	refresh, err := jwtManager.GenerateRefreshToken(util.CustomClaims{UserID: "user123", Role: "user"})
+	if err != nil {
+		t.Fatalf("Failed to generate refresh token: %v", err)
+	}
+
+	w := postRefresh(t, jwtManager, refresh)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var resp model.TokenResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.TokenType != "Bearer" || resp.ExpiresIn != 24*3600 {
+		t.Errorf("Unexpected token response: %+v", resp)
+	}
+
+	claims, err := jwtManager.ValidateToken(resp.AccessToken)
+	if err != nil {
+		t.Fatalf("Expected a valid access token, got %v", err)
+	}
+	if claims.UserID != "user123" {
+		t.Errorf("Expected UserID 'user123', got '%s'", claims.UserID)
+	}
+}
+
+func TestAuthHandler_RefreshRejectsAccessToken(t *testing.T) {
+	jwtManager := util.NewJWTManager("test-secret", "test-issuer", 24)
+
+	access, _ := jwtManager.GenerateToken("user123", "testuser", "user")
+	w := postRefresh(t, jwtManager, access)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/flexsearch/api-gateway/internal/model"
+	"github.com/flexsearch/api-gateway/internal/util"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/status"
+)
+
+// RespondError writes the JSON error body for code with the code's HTTP
+// status, tagged with the request ID so clients can quote it when reporting
+// a failure.
+//
+// A gRPC error keeps the coordinator's message and status details. Any other
+// err becomes the message, except for 5xx codes, where the message stays
+// generic and err goes into the details. A nil err uses the code's default
+// message.
+func RespondError(c *gin.Context, code model.ErrorCode, err error) {
+	resp := newErrorResponse(code, err)
+	resp.RequestID = util.RequestIDFromContext(c.Request.Context())
+	c.JSON(code.HTTPStatus(), resp)
+}
+
+func newErrorResponse(code model.ErrorCode, err error) model.ErrorResponse {
+	resp := model.ErrorResponse{Code: code, Message: code.Message()}
+	if err == nil {
+		return resp
+	}
+	if _, ok := status.FromError(err); ok {
+		grpcErr := util.ConvertGRPCError(err)
+		resp.Message = grpcErr.Message
+		resp.Details = grpcErr.Details
+		return resp
+	}
+	if code.HTTPStatus() >= 500 {
+		resp.Details = err.Error()
+	} else {
+		resp.Message = err.Error()
+	}
+	return resp
+}
+
+// respondValidationError reports a failed request Validate as
+// INVALID_REQUEST, listing the offending fields when err is FieldErrors.
+func respondValidationError(c *gin.Context, err error) {
+	resp := model.ValidationErrorResponse{ErrorResponse: newErrorResponse(model.ErrCodeInvalidRequest, err)}
+	var fields model.FieldErrors
+	if errors.As(err, &fields) {
+		resp.Fields = fields
+	}
+	resp.RequestID = util.RequestIDFromContext(c.Request.Context())
+	c.JSON(resp.Code.HTTPStatus(), resp)
+}
+
+// upstreamErrorCode classifies a failed coordinator call with
+// model.ErrorCodeForGRPC. Errors that carry no gRPC status are internal.
+func upstreamErrorCode(err error, notFound model.ErrorCode) model.ErrorCode {
+	st, ok := status.FromError(err)
+	if !ok {
+		return model.ErrCodeInternal
+	}
+	return model.ErrorCodeForGRPC(st.Code(), notFound)
+}
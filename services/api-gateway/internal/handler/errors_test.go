@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/flexsearch/api-gateway/internal/model"
+	"github.com/flexsearch/api-gateway/internal/util"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// documentedStatuses mirrors the table on model.ErrorCode.
+var documentedStatuses = map[model.ErrorCode]int{
+	model.ErrCodeInvalidRequest:           http.StatusBadRequest,
+	model.ErrCodeInvalidDocuments:         http.StatusBadRequest,
+	model.ErrCodeInvalidMappings:          http.StatusBadRequest,
+	model.ErrCodeBatchTooLarge:            http.StatusBadRequest,
+	model.ErrCodeOutOfRange:               http.StatusRequestedRangeNotSatisfiable,
+	model.ErrCodeUnauthenticated:          http.StatusUnauthorized,
+	model.ErrCodeInvalidRefreshToken:      http.StatusUnauthorized,
+	model.ErrCodePermissionDenied:         http.StatusForbidden,
+	model.ErrCodeNotFound:                 http.StatusNotFound,
+	model.ErrCodeDocumentNotFound:         http.StatusNotFound,
+	model.ErrCodeIndexNotFound:            http.StatusNotFound,
+	model.ErrCodeTaskNotFound:             http.StatusNotFound,
+	model.ErrCodeConflict:                 http.StatusConflict,
+	model.ErrCodeVersionConflict:          http.StatusConflict,
+	model.ErrCodePreconditionFailed:       http.StatusPreconditionFailed,
+	model.ErrCodeRateLimited:              http.StatusTooManyRequests,
+	model.ErrCodeInternal:                 http.StatusInternalServerError,
+	model.ErrCodeResponseValidationFailed: http.StatusInternalServerError,
+	model.ErrCodeNotImplemented:           http.StatusNotImplemented,
+	model.ErrCodeUnavailable:              http.StatusServiceUnavailable,
+	model.ErrCodeTimeout:                  http.StatusGatewayTimeout,
+}
+
+func respondErrorRecorder(t *testing.T, code model.ErrorCode, err error) (*httptest.ResponseRecorder, map[string]interface{}) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	c.Request = c.Request.WithContext(util.ContextWithRequestID(c.Request.Context(), "req-7"))
+
+	RespondError(c, code, err)
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to decode error body %q: %v", w.Body.String(), err)
+	}
+	return w, body
+}
+
+func TestErrorCodesMatchDocumentedStatus(t *testing.T) {
+	if got, want := len(model.ErrorCodes()), len(documentedStatuses); got != want {
+		t.Errorf("Expected %d error codes, got %d", want, got)
+	}
+
+	for _, code := range model.ErrorCodes() {
+		want, ok := documentedStatuses[code]
+		if !ok {
+			t.Errorf("Code %s is not documented", code)
+			continue
+		}
+		if got := code.HTTPStatus(); got != want {
+			t.Errorf("%s.HTTPStatus() = %d, want %d", code, got, want)
+		}
+		if code.Message() == "" {
+			t.Errorf("%s has no default message", code)
+		}
+
+		w, body := respondErrorRecorder(t, code, nil)
+		if w.Code != want {
+			t.Errorf("RespondError(%s) status = %d, want %d", code, w.Code, want)
+		}
+		if body["code"] != string(code) || body["message"] != code.Message() || body["request_id"] != "req-7" {
+			t.Errorf("RespondError(%s) body = %v", code, body)
+		}
+		if _, ok := body["details"]; ok {
+			t.Errorf("RespondError(%s) with no error should omit details, got %v", code, body)
+		}
+	}
+}
+
+func TestRespondErrorMessageShape(t *testing.T) {
+	tests := []struct {
+		name        string
+		code        model.ErrorCode
+		err         error
+		wantMessage string
+		wantDetails string
+	}{
+		{"client error", model.ErrCodeInvalidRequest, errors.New("query is required"), "query is required", ""},
+		{"server error", model.ErrCodeInternal, errors.New("redis: connection refused"), "Internal server error", "redis: connection refused"},
+		{"coordinator error", model.ErrCodeUnavailable, status.Error(codes.Unavailable, "no engines available"), "no engines available", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, body := respondErrorRecorder(t, tt.code, tt.err)
+			if w.Code != tt.code.HTTPStatus() {
+				t.Errorf("Expected status %d, got %d", tt.code.HTTPStatus(), w.Code)
+			}
+			if body["message"] != tt.wantMessage {
+				t.Errorf("Expected message %q, got %v", tt.wantMessage, body["message"])
+			}
+			details, _ := body["details"].(string)
+			if details != tt.wantDetails {
+				t.Errorf("Expected details %q, got %q", tt.wantDetails, details)
+			}
+		})
+	}
+}
+
+func TestUpstreamErrorCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		want model.ErrorCode
+	}{
+		{status.Error(codes.InvalidArgument, "bad"), model.ErrCodeInvalidRequest},
+		{status.Error(codes.NotFound, "missing"), model.ErrCodeIndexNotFound},
+		{status.Error(codes.AlreadyExists, "exists"), model.ErrCodeConflict},
+		{status.Error(codes.ResourceExhausted, "slow down"), model.ErrCodeRateLimited},
+		{status.Error(codes.Unavailable, "down"), model.ErrCodeUnavailable},
+		{status.Error(codes.DeadlineExceeded, "late"), model.ErrCodeTimeout},
+		{status.Error(codes.Internal, "boom"), model.ErrCodeInternal},
+		{errors.New("not a status"), model.ErrCodeInternal},
+	}
+
+	for _, tt := range tests {
+		if got := upstreamErrorCode(tt.err, model.ErrCodeIndexNotFound); got != tt.want {
+			t.Errorf("upstreamErrorCode(%v) = %s, want %s", tt.err, got, tt.want)
+		}
+	}
+
+	if got := updateErrorCode(status.Error(codes.Aborted, "stale")); got != model.ErrCodeVersionConflict {
+		t.Errorf("updateErrorCode(Aborted) = %s, want %s", got, model.ErrCodeVersionConflict)
+	}
+}
@@ -3,6 +3,9 @@ package handler
 import (
 	"context"
 	"net/http"
+	"os"
+	"os/signal"
+	"sync/atomic"
 	"time"
 
 	"github.com/flexsearch/api-gateway/internal/client"
@@ -10,18 +13,29 @@ import (
 	"github.com/flexsearch/api-gateway/internal/middleware"
 	pb "github.com/flexsearch/api-gateway/proto"
 	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
+// readinessTimeout bounds all dependency checks made by Ready.
+const readinessTimeout = 2 * time.Second
+
+type redisPinger interface {
+	Ping(ctx context.Context) *redis.StatusCmd
+}
+
 type HealthHandler struct {
 	client               *client.CircuitBreakerCoordinatorClient
 	config               *config.Config
 	logger               *zap.Logger
 	tracer               trace.Tracer
 	circuitBreakerClient *client.CircuitBreakerCoordinatorClient
+	redis                redisPinger
+	draining             atomic.Bool
+	inFlight             atomic.Int64
 }
 
 func NewHealthHandler(client *client.CircuitBreakerCoordinatorClient, cfg *config.Config, logger *zap.Logger) *HealthHandler {
@@ -34,6 +48,55 @@ func NewHealthHandler(client *client.CircuitBreakerCoordinatorClient, cfg *confi
 	}
 }
 
+// SetRedis sets the Redis client that Ready pings.
+func (h *HealthHandler) SetRedis(client redisPinger) {
+	h.redis = client
+}
+
+// StartDraining makes Ready answer 503 so load balancers stop sending new
+// traffic while in-flight requests finish. Liveness is unaffected.
+func (h *HealthHandler) StartDraining() {
+	h.draining.Store(true)
+}
+
+func (h *HealthHandler) Draining() bool {
+	return h.draining.Load()
+}
+
+// InFlight returns the number of requests currently passing through
+// TrackInFlight.
+func (h *HealthHandler) InFlight() int64 {
+	return h.inFlight.Load()
+}
+
+// TrackInFlight counts requests in progress for drain reporting.
+func (h *HealthHandler) TrackInFlight() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		h.inFlight.Add(1)
+		defer h.inFlight.Add(-1)
+		c.Next()
+	}
+}
+
+// DrainOnSignal starts draining on the first of signals and then delivers
+// it on the returned channel. Later signals get the default behaviour, so a
+// second SIGTERM still kills the process.
+func (h *HealthHandler) DrainOnSignal(signals ...os.Signal) <-chan os.Signal {
+	notify := make(chan os.Signal, 1)
+	signal.Notify(notify, signals...)
+
+	received := make(chan os.Signal, 1)
+	go func() {
+		sig := <-notify
+		signal.Stop(notify)
+		h.StartDraining()
+		received <- sig
+	}()
+	return received
+}
+
+// Check is the liveness probe: it reports healthy whenever the process can
+// serve HTTP, regardless of its dependencies.
 func (h *HealthHandler) Check(c *gin.Context) {
 	requestID := middleware.GetRequestID(c)
 
@@ -46,6 +109,79 @@ func (h *HealthHandler) Check(c *gin.Context) {
 	})
 }
 
+// Ready is the readiness probe. It pings Redis and calls the coordinator
+// health RPC within readinessTimeout and answers 503 when either fails, or
+// straight away once draining has started.
+func (h *HealthHandler) Ready(c *gin.Context) {
+	if h.Draining() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"status":     "draining",
+			"in_flight":  h.InFlight(),
+			"timestamp":  time.Now().UTC().Format(time.RFC3339),
+			"request_id": middleware.GetRequestID(c),
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), readinessTimeout)
+	defer cancel()
+	ctx, span := h.tracer.Start(ctx, "HealthHandler.Ready")
+	defer span.End()
+
+	checks := map[string]gin.H{
+		"redis":       h.checkRedis(ctx),
+		"coordinator": h.checkCoordinatorReady(ctx),
+	}
+
+	code := http.StatusOK
+	overallStatus := "ready"
+	for name, check := range checks {
+		if check["status"] != "healthy" {
+			code = http.StatusServiceUnavailable
+			overallStatus = "not_ready"
+			span.SetAttributes(attribute.String("unhealthy."+name, check["error"].(string)))
+		}
+	}
+
+	c.JSON(code, gin.H{
+		"status":     overallStatus,
+		"checks":     checks,
+		"timestamp":  time.Now().UTC().Format(time.RFC3339),
+		"request_id": middleware.GetRequestID(c),
+	})
+}
+
+func (h *HealthHandler) checkRedis(ctx context.Context) gin.H {
+	if h.redis == nil {
+		return gin.H{"status": "unhealthy", "error": "redis client not configured"}
+	}
+
+	start := time.Now()
+	if err := h.redis.Ping(ctx).Err(); err != nil {
+		h.logger.Warn("Redis readiness check failed", zap.Error(err))
+		return gin.H{"status": "unhealthy", "latency_ms": time.Since(start).Milliseconds(), "error": err.Error()}
+	}
+	return gin.H{"status": "healthy", "latency_ms": time.Since(start).Milliseconds()}
+}
+
+func (h *HealthHandler) checkCoordinatorReady(ctx context.Context) gin.H {
+	if h.client == nil {
+		return gin.H{"status": "unhealthy", "error": "coordinator client not configured"}
+	}
+
+	start := time.Now()
+	resp, err := h.client.HealthCheck(ctx, &pb.HealthCheckRequest{Service: "coordinator"})
+	latency := time.Since(start).Milliseconds()
+	if err != nil {
+		h.logger.Warn("Coordinator readiness check failed", zap.Error(err))
+		return gin.H{"status": "unhealthy", "latency_ms": latency, "error": err.Error()}
+	}
+	if resp.Status != "healthy" {
+		return gin.H{"status": "unhealthy", "latency_ms": latency, "error": "coordinator reported " + resp.Status}
+	}
+	return gin.H{"status": "healthy", "latency_ms": latency}
+}
+
 func (h *HealthHandler) CheckServices(c *gin.Context) {
 	ctx := c.Request.Context()
 	ctx, span := h.tracer.Start(ctx, "HealthHandler.CheckServices")
@@ -1,11 +1,25 @@
 package handler
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"syscall"
 	"testing"
+	"time"
 
+	"github.com/alicebob/miniredis/v2"
+	"github.com/flexsearch/api-gateway/internal/client"
+	pb "github.com/flexsearch/api-gateway/proto"
 	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func init() {
@@ -86,6 +100,107 @@ func TestHealthHandler_CheckServices(t *testing.T) {
 	}
 }
 
+// fakeHealthConn answers the coordinator health RPC with status, or with
+// err when it is set.
+type fakeHealthConn struct {
+	status string
+	err    error
+}
+
+func (f *fakeHealthConn) Invoke(ctx context.Context, method string, args, reply interface{}, opts ...grpc.CallOption) error {
+	if f.err != nil {
+		return f.err
+	}
+	resp, ok := reply.(*pb.HealthCheckResponse)
+	if !ok {
+		return fmt.Errorf("unexpected method %s", method)
+	}
+	*resp = pb.HealthCheckResponse{Status: f.status, Version: "1.0.0"}
+	return nil
+}
+
+func (f *fakeHealthConn) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return nil, fmt.Errorf("streams are not supported")
+}
+
+func getReady(t *testing.T, conn *fakeHealthConn) (int, map[string]interface{}) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	redisClient := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { redisClient.Close() })
+
+	h := NewHealthHandler(client.NewCircuitBreakerCoordinatorClientWithConn(conn), nil, zap.NewNop())
+	h.SetRedis(redisClient)
+
+	router := gin.New()
+	router.GET("/healthz", h.Ready)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	return w.Code, body
+}
+
+func TestHealthHandler_Ready(t *testing.T) {
+	code, body := getReady(t, &fakeHealthConn{status: "healthy"})
+
+	if code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %v", http.StatusOK, code, body)
+	}
+	if body["status"] != "ready" {
+		t.Errorf("Expected status ready, got %v", body["status"])
+	}
+	checks := body["checks"].(map[string]interface{})
+	for _, name := range []string{"redis", "coordinator"} {
+		check := checks[name].(map[string]interface{})
+		if check["status"] != "healthy" {
+			t.Errorf("Expected %s to be healthy, got %v", name, check)
+		}
+	}
+}
+
+func TestHealthHandler_Ready_CoordinatorDown(t *testing.T) {
+	code, body := getReady(t, &fakeHealthConn{err: status.Error(codes.Unavailable, "connection refused")})
+
+	if code != http.StatusServiceUnavailable {
+		t.Fatalf("Expected status %d, got %d: %v", http.StatusServiceUnavailable, code, body)
+	}
+	if body["status"] != "not_ready" {
+		t.Errorf("Expected status not_ready, got %v", body["status"])
+	}
+	checks := body["checks"].(map[string]interface{})
+	if coordinator := checks["coordinator"].(map[string]interface{}); coordinator["status"] != "unhealthy" {
+		t.Errorf("Expected coordinator to be unhealthy, got %v", coordinator)
+	}
+	if redisCheck := checks["redis"].(map[string]interface{}); redisCheck["status"] != "healthy" {
+		t.Errorf("Expected redis to stay healthy, got %v", redisCheck)
+	}
+}
+
+func TestHealthHandler_Ready_RedisDown(t *testing.T) {
+	h := NewHealthHandler(client.NewCircuitBreakerCoordinatorClientWithConn(&fakeHealthConn{status: "healthy"}), nil, zap.NewNop())
+	mr := miniredis.RunT(t)
+	redisClient := redis.NewClient(&redis.Options{Addr: mr.Addr(), MaxRetries: -1})
+	defer redisClient.Close()
+	mr.Close()
+	h.SetRedis(redisClient)
+
+	router := gin.New()
+	router.GET("/healthz", h.Ready)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status %d, got %d: %s", http.StatusServiceUnavailable, w.Code, w.Body.String())
+	}
+}
+
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(s) > 0 && containsHelper(s, substr))
 }
@@ -98,3 +213,70 @@ func containsHelper(s, substr string) bool {
 	}
 	return false
 }
+
+func TestHealthHandler_DrainOnSIGTERM(t *testing.T) {
+	mr := miniredis.RunT(t)
+	redisClient := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer redisClient.Close()
+
+	h := NewHealthHandler(client.NewCircuitBreakerCoordinatorClientWithConn(&fakeHealthConn{status: "healthy"}), nil, zap.NewNop())
+	h.SetRedis(redisClient)
+
+	release := make(chan struct{})
+	router := gin.New()
+	router.Use(h.TrackInFlight())
+	router.GET("/health", h.Check)
+	router.GET("/healthz", h.Ready)
+	router.GET("/slow", func(c *gin.Context) {
+		<-release
+		c.Status(http.StatusOK)
+	})
+
+	probe := func(path string) int {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w.Code
+	}
+
+	if code := probe("/healthz"); code != http.StatusOK {
+		t.Fatalf("Expected ready before SIGTERM, got %d", code)
+	}
+
+	slowDone := make(chan int)
+	go func() { slowDone <- probe("/slow") }()
+	deadline := time.Now().Add(time.Second)
+	for h.InFlight() < 1 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	received := h.DrainOnSignal(syscall.SIGTERM)
+	if err := syscall.Kill(os.Getpid(), syscall.SIGTERM); err != nil {
+		t.Fatalf("Failed to send SIGTERM: %v", err)
+	}
+	select {
+	case sig := <-received:
+		if sig != syscall.SIGTERM {
+			t.Errorf("Expected SIGTERM, got %v", sig)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the signal to be delivered")
+	}
+
+	if !h.Draining() {
+		t.Error("Expected the handler to be draining")
+	}
+	if code := probe("/healthz"); code != http.StatusServiceUnavailable {
+		t.Errorf("Expected readiness 503 while draining, got %d", code)
+	}
+	if code := probe("/health"); code != http.StatusOK {
+		t.Errorf("Expected liveness to still pass while draining, got %d", code)
+	}
+	if h.InFlight() < 1 {
+		t.Errorf("Expected the slow request to still be in flight, got %d", h.InFlight())
+	}
+
+	close(release)
+	if code := <-slowDone; code != http.StatusOK {
+		t.Errorf("Expected the in-flight request to finish, got %d", code)
+	}
+}
@@ -1,8 +1,17 @@
 package handler
 
 import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/flexsearch/api-gateway/internal/client"
 	"github.com/flexsearch/api-gateway/internal/model"
@@ -16,21 +25,47 @@ import (
 )
 
 type SearchHandler struct {
-	client  *client.CoordinatorClient
-	metrics *util.Metrics
-	logger  *zap.Logger
-	tracer  trace.Tracer
+	client          *client.CoordinatorClient
+	metrics         *util.Metrics
+	logger          *zap.Logger
+	tracer          trace.Tracer
+	maxResultWindow int
 }
 
 func NewSearchHandler(client *client.CoordinatorClient, metrics *util.Metrics, logger *zap.Logger) *SearchHandler {
 	return &SearchHandler{
-		client:  client,
-		metrics: metrics,
-		logger:  logger,
-		tracer:  otel.Tracer("search-handler"),
+		client:          client,
+		metrics:         metrics,
+		logger:          logger,
+		tracer:          otel.Tracer("search-handler"),
+		maxResultWindow: model.DefaultMaxResultWindow,
 	}
 }
 
+// SetMaxResultWindow caps page * page_size for every search endpoint. Zero
+// disables the check.
+func (h *SearchHandler) SetMaxResultWindow(n int) {
+	h.maxResultWindow = n
+}
+
+// checkPageDepth rejects pages that reach past the result window. Unset
+// page and page size count as their defaults.
+func (h *SearchHandler) checkPageDepth(page, pageSize int) error {
+	if h.maxResultWindow <= 0 {
+		return nil
+	}
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = model.DefaultPageSize
+	}
+	if depth := page * pageSize; depth > h.maxResultWindow {
+		return fmt.Errorf("page * page_size must not exceed %d, got %d; use search_after to page through deeper results", h.maxResultWindow, depth)
+	}
+	return nil
+}
+
 func (h *SearchHandler) Search(c *gin.Context) {
 	ctx := c.Request.Context()
 	ctx, span := h.tracer.Start(ctx, "SearchHandler.Search")
@@ -40,10 +75,15 @@ func (h *SearchHandler) Search(c *gin.Context) {
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.logger.Error("Failed to parse search request",
 			zap.Error(err))
-		c.JSON(http.StatusBadRequest, model.ErrorResponse{
-			Code:    "INVALID_REQUEST",
-			Message: err.Error(),
-		})
+		RespondError(c, model.ErrCodeInvalidRequest, err)
+		return
+	}
+	if err := req.Validate(); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+	if err := h.checkPageDepth(req.Page, req.PageSize); err != nil {
+		RespondError(c, model.ErrCodeInvalidRequest, err)
 		return
 	}
 
@@ -53,18 +93,7 @@ func (h *SearchHandler) Search(c *gin.Context) {
 		attribute.Int("page_size", req.PageSize),
 	)
 
-	grpcReq := &pb.SearchRequest{
-		Query:     req.Query,
-		Indexes:   req.Indexes,
-		Page:      int32(req.Page),
-		PageSize:  int32(req.PageSize),
-		Filters:   req.Filters,
-		Fields:    req.Fields,
-		Highlight: req.Highlight,
-		SortBy:    req.SortBy,
-		SortOrder: req.SortOrder,
-		Explain:   req.Explain,
-	}
+	grpcReq := searchRequestToProto(req)
 
 	h.metrics.IncrementCounter("search_requests_total", []string{"endpoint:search"})
 
@@ -74,91 +103,242 @@ func (h *SearchHandler) Search(c *gin.Context) {
 			zap.Error(err),
 			zap.String("query", req.Query))
 		h.metrics.IncrementCounter("search_errors_total", []string{"error_type:grpc"})
-		grpcErr := util.ConvertGRPCError(err)
-		c.JSON(grpcErr.HTTPStatus, model.ErrorResponse{
-			Code:    "SEARCH_FAILED",
-			Message: grpcErr.Message,
-			Details: grpcErr.Details,
-		})
+		RespondError(c, upstreamErrorCode(err, model.ErrCodeNotFound), err)
 		return
 	}
 
-	results := make([]model.SearchResult, len(resp.Results))
-	for i, r := range resp.Results {
-		results[i] = model.SearchResult{
-			ID:         r.Id,
-			Score:      r.Score,
-			Fields:     r.Fields,
-			Highlights: r.Highlights,
-		}
-	}
-
 	h.metrics.IncrementCounter("search_success_total", []string{})
 	h.metrics.RecordHistogram("search_latency_seconds", float64(resp.TookMs)/1000, []string{})
 
-	searchResponse := model.SearchResponse{
-		Results:    results,
-		Total:      int(resp.Total),
-		Page:       int(resp.Page),
-		PageSize:   int(resp.PageSize),
-		TotalPages: int(resp.TotalPages),
-		TookMs:     resp.TookMs,
-	}
+	searchResponse := searchResponseFromProto(resp)
 
 	// Validate response before sending
 	if err := searchResponse.Validate(); err != nil {
 		h.logger.Error("Search response validation failed",
 			zap.Error(err),
 			zap.String("query", req.Query))
-		c.JSON(http.StatusInternalServerError, model.ErrorResponse{
-			Code:    "RESPONSE_VALIDATION_FAILED",
-			Message: "Internal server error",
-			Details: err.Error(),
-		})
+		RespondError(c, model.ErrCodeResponseValidationFailed, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, searchResponse)
 }
 
+// MultiSearch runs a JSON array of search requests in one coordinator call.
+// Responses keep the request order; a query that fails carries an error
+// without failing the rest of the batch.
+func (h *SearchHandler) MultiSearch(c *gin.Context) {
+	ctx := c.Request.Context()
+	ctx, span := h.tracer.Start(ctx, "SearchHandler.MultiSearch")
+	defer span.End()
+
+	var reqs []model.SearchRequest
+	if err := c.ShouldBindJSON(&reqs); err != nil {
+		h.logger.Error("Failed to parse multi-search request",
+			zap.Error(err))
+		RespondError(c, model.ErrCodeInvalidRequest, err)
+		return
+	}
+
+	if len(reqs) == 0 {
+		RespondError(c, model.ErrCodeInvalidRequest, errors.New("multi-search requires at least one query"))
+		return
+	}
+	if len(reqs) > model.MaxMultiSearchRequests {
+		RespondError(c, model.ErrCodeBatchTooLarge, fmt.Errorf("multi-search accepts at most %d queries, got %d", model.MaxMultiSearchRequests, len(reqs)))
+		return
+	}
+	var invalid model.FieldErrors
+	for i := range reqs {
+		var fields model.FieldErrors
+		if errors.As(reqs[i].Validate(), &fields) {
+			for _, fe := range fields {
+				fe.Field = fmt.Sprintf("[%d].%s", i, fe.Field)
+				invalid = append(invalid, fe)
+			}
+		}
+	}
+	if len(invalid) > 0 {
+		respondValidationError(c, invalid)
+		return
+	}
+	for i, req := range reqs {
+		if err := h.checkPageDepth(req.Page, req.PageSize); err != nil {
+			RespondError(c, model.ErrCodeInvalidRequest, fmt.Errorf("query %d: %w", i, err))
+			return
+		}
+	}
+
+	span.SetAttributes(attribute.Int("query_count", len(reqs)))
+
+	grpcReq := &pb.MultiSearchRequest{
+		Requests: make([]*pb.SearchRequest, len(reqs)),
+	}
+	for i, req := range reqs {
+		grpcReq.Requests[i] = searchRequestToProto(req)
+	}
+
+	h.metrics.IncrementCounter("search_requests_total", []string{"endpoint:msearch"})
+
+	resp, err := h.client.MultiSearch(ctx, grpcReq)
+	if err != nil {
+		h.logger.Error("Multi-search failed",
+			zap.Error(err),
+			zap.Int("query_count", len(reqs)))
+		h.metrics.IncrementCounter("search_errors_total", []string{"error_type:grpc"})
+		RespondError(c, upstreamErrorCode(err, model.ErrCodeNotFound), err)
+		return
+	}
+
+	if len(resp.Responses) != len(reqs) {
+		h.logger.Error("Multi-search response count mismatch",
+			zap.Int("expected", len(reqs)),
+			zap.Int("actual", len(resp.Responses)))
+		RespondError(c, model.ErrCodeResponseValidationFailed, fmt.Errorf("expected %d responses, got %d", len(reqs), len(resp.Responses)))
+		return
+	}
+
+	responses := make([]model.SearchResponse, len(resp.Responses))
+	for i, r := range resp.Responses {
+		if r == nil {
+			responses[i] = model.SearchResponse{Results: []model.SearchResult{}, Error: "no response"}
+			continue
+		}
+		responses[i] = searchResponseFromProto(r)
+		if r.Error != "" {
+			continue
+		}
+		if err := responses[i].Validate(); err != nil {
+			h.logger.Error("Search response validation failed",
+				zap.Error(err),
+				zap.String("query", reqs[i].Query))
+			responses[i] = model.SearchResponse{Results: []model.SearchResult{}, Error: "invalid response"}
+		}
+	}
+
+	h.metrics.IncrementCounter("search_success_total", []string{})
+
+	c.JSON(http.StatusOK, model.MultiSearchResponse{Responses: responses})
+}
+
 func (h *SearchHandler) SearchGet(c *gin.Context) {
 	ctx := c.Request.Context()
 	ctx, span := h.tracer.Start(ctx, "SearchHandler.SearchGet")
 	defer span.End()
 
-	query := c.Query("query")
-	indexes := c.QueryArray("index")
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+	var req model.SearchRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.logger.Error("Failed to parse search query",
+			zap.Error(err))
+		RespondError(c, model.ErrCodeInvalidRequest, err)
+		return
+	}
+	if err := req.Validate(); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+	if err := h.checkPageDepth(req.Page, req.PageSize); err != nil {
+		RespondError(c, model.ErrCodeInvalidRequest, err)
+		return
+	}
 
 	span.SetAttributes(
-		attribute.String("query", query),
-		attribute.Int("page", page),
-		attribute.Int("page_size", pageSize),
+		attribute.String("query", req.Query),
+		attribute.Int("page", req.Page),
+		attribute.Int("page_size", req.PageSize),
 	)
 
-	grpcReq := &pb.SearchRequest{
-		Query:     query,
-		Indexes:   indexes,
-		Page:      int32(page),
-		PageSize:  int32(pageSize),
-		Highlight: c.Query("highlight") == "true",
-	}
+	grpcReq := searchRequestToProto(req)
 
 	resp, err := h.client.Search(ctx, grpcReq)
 	if err != nil {
 		h.logger.Error("Search failed",
 			zap.Error(err),
-			zap.String("query", query))
-		grpcErr := util.ConvertGRPCError(err)
-		c.JSON(grpcErr.HTTPStatus, model.ErrorResponse{
-			Code:    "SEARCH_FAILED",
-			Message: grpcErr.Message,
-			Details: grpcErr.Details,
-		})
+			zap.String("query", req.Query))
+		RespondError(c, upstreamErrorCode(err, model.ErrCodeNotFound), err)
+		return
+	}
+
+	searchResponse := searchResponseFromProto(resp)
+
+	// Validate response before sending
+	if err := searchResponse.Validate(); err != nil {
+		h.logger.Error("Search response validation failed",
+			zap.Error(err),
+			zap.String("query", req.Query))
+		RespondError(c, model.ErrCodeResponseValidationFailed, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, searchResponse)
+}
+
+// Suggest returns typeahead suggestions for the q prefix. An empty prefix
+// yields an empty list without calling the coordinator.
+func (h *SearchHandler) Suggest(c *gin.Context) {
+	ctx := c.Request.Context()
+	ctx, span := h.tracer.Start(ctx, "SearchHandler.Suggest")
+	defer span.End()
+
+	prefix := strings.TrimSpace(c.Query("q"))
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(model.DefaultSuggestLimit)))
+	if err != nil || limit < 1 || limit > model.MaxSuggestLimit {
+		RespondError(c, model.ErrCodeInvalidRequest, fmt.Errorf("limit must be between 1 and %d", model.MaxSuggestLimit))
+		return
+	}
+
+	span.SetAttributes(
+		attribute.String("prefix", prefix),
+		attribute.Int("limit", limit),
+	)
+
+	if prefix == "" {
+		c.JSON(http.StatusOK, model.SuggestResponse{Suggestions: []string{}})
+		return
+	}
+
+	h.metrics.IncrementCounter("search_requests_total", []string{"endpoint:suggest"})
+
+	resp, err := h.client.Suggest(ctx, &pb.SuggestRequest{
+		Prefix: prefix,
+		Limit:  int32(limit),
+	})
+	if err != nil {
+		h.logger.Error("Suggest failed",
+			zap.Error(err),
+			zap.String("prefix", prefix))
+		h.metrics.IncrementCounter("search_errors_total", []string{"error_type:grpc"})
+		RespondError(c, upstreamErrorCode(err, model.ErrCodeNotFound), err)
 		return
 	}
 
+	suggestions := resp.Suggestions
+	if suggestions == nil {
+		suggestions = []string{}
+	}
+	c.JSON(http.StatusOK, model.SuggestResponse{Suggestions: suggestions})
+}
+
+func searchRequestToProto(req model.SearchRequest) *pb.SearchRequest {
+	return &pb.SearchRequest{
+		Query:          req.Query,
+		Indexes:        req.Indexes,
+		Page:           int32(req.Page),
+		PageSize:       int32(req.PageSize),
+		Filters:        req.Filters,
+		Fields:         req.Fields,
+		Highlight:      req.Highlight,
+		SortBy:         req.SortBy,
+		SortOrder:      req.SortOrder,
+		Explain:        req.Explain,
+		DryRun:         req.DryRun,
+		PerEngineLimit: int32(req.PerEngineLimit),
+		Facets:         req.Facets,
+		SearchAfter:    req.SearchAfter,
+	}
+}
+
+func searchResponseFromProto(resp *pb.SearchResponse) model.SearchResponse {
 	results := make([]model.SearchResult, len(resp.Results))
 	for i, r := range resp.Results {
 		results[i] = model.SearchResult{
@@ -166,32 +346,48 @@ func (h *SearchHandler) SearchGet(c *gin.Context) {
 			Score:      r.Score,
 			Fields:     r.Fields,
 			Highlights: r.Highlights,
+			Explain:    r.Explain,
 		}
 	}
 
-	searchResponse := model.SearchResponse{
-		Results:    results,
-		Total:      int(resp.Total),
-		Page:       int(resp.Page),
-		PageSize:   int(resp.PageSize),
-		TotalPages: int(resp.TotalPages),
-		TookMs:     resp.TookMs,
+	var facets map[string][]model.FacetBucket
+	if len(resp.Facets) > 0 {
+		facets = make(map[string][]model.FacetBucket, len(resp.Facets))
+		for field, buckets := range resp.Facets {
+			converted := make([]model.FacetBucket, len(buckets))
+			for i, b := range buckets {
+				converted[i] = model.FacetBucket{Value: b.Value, Count: b.Count}
+			}
+			facets[field] = converted
+		}
 	}
 
-	// Validate response before sending
-	if err := searchResponse.Validate(); err != nil {
-		h.logger.Error("Search response validation failed",
-			zap.Error(err),
-			zap.String("query", query))
-		c.JSON(http.StatusInternalServerError, model.ErrorResponse{
-			Code:    "RESPONSE_VALIDATION_FAILED",
-			Message: "Internal server error",
-			Details: err.Error(),
-		})
-		return
+	var routing *model.RoutingExplain
+	if resp.Routing != nil {
+		routing = &model.RoutingExplain{
+			Strategy: resp.Routing.Strategy,
+			Reasons:  resp.Routing.Reasons,
+			Engines:  resp.Routing.Engines,
+			Weights:  resp.Routing.Weights,
+		}
 	}
 
-	c.JSON(http.StatusOK, searchResponse)
+	return model.SearchResponse{
+		Results:        results,
+		Total:          int(resp.Total),
+		Page:           int(resp.Page),
+		PageSize:       int(resp.PageSize),
+		TotalPages:     int(resp.TotalPages),
+		TookMs:         resp.TookMs,
+		Degraded:       resp.Degraded,
+		FailedEngines:  resp.FailedEngines,
+		Error:          resp.Error,
+		Facets:         facets,
+		DidYouMean:     resp.DidYouMean,
+		NextCursor:     resp.NextCursor,
+		SkippedEngines: resp.SkippedEngines,
+		Routing:        routing,
+	}
 }
 
 type DocumentHandler struct {
@@ -219,10 +415,7 @@ func (h *DocumentHandler) Create(c *gin.Context) {
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.logger.Error("Failed to parse document request",
 			zap.Error(err))
-		c.JSON(http.StatusBadRequest, model.ErrorResponse{
-			Code:    "INVALID_REQUEST",
-			Message: err.Error(),
-		})
+		RespondError(c, model.ErrCodeInvalidRequest, err)
 		return
 	}
 
@@ -241,10 +434,7 @@ func (h *DocumentHandler) Create(c *gin.Context) {
 			zap.Error(err),
 			zap.String("index_id", req.IndexID))
 		h.metrics.IncrementCounter("document_errors_total", []string{"operation:create"})
-		c.JSON(http.StatusInternalServerError, model.ErrorResponse{
-			Code:    "ADD_DOCUMENT_FAILED",
-			Message: err.Error(),
-		})
+		RespondError(c, upstreamErrorCode(err, model.ErrCodeNotFound), err)
 		return
 	}
 
@@ -284,22 +474,95 @@ func (h *DocumentHandler) Get(c *gin.Context) {
 			zap.String("index_id", indexID),
 			zap.String("document_id", documentID))
 		h.metrics.IncrementCounter("document_errors_total", []string{"operation:get"})
-		c.JSON(http.StatusInternalServerError, model.ErrorResponse{
-			Code:    "GET_DOCUMENT_FAILED",
-			Message: err.Error(),
-		})
+		RespondError(c, upstreamErrorCode(err, model.ErrCodeDocumentNotFound), err)
 		return
 	}
 
 	h.metrics.IncrementCounter("document_success_total", []string{"operation:get"})
 
 	c.JSON(http.StatusOK, model.DocumentResponse{
-		ID:     resp.Id,
-		Fields: resp.Fields,
-		Score:  resp.Score,
+		ID:      resp.Id,
+		Fields:  resp.Fields,
+		Score:   resp.Score,
+		Version: resp.Version,
 	})
 }
 
+func (h *DocumentHandler) MultiGet(c *gin.Context) {
+	ctx := c.Request.Context()
+	ctx, span := h.tracer.Start(ctx, "DocumentHandler.MultiGet")
+	defer span.End()
+
+	var req model.MultiGetDocumentsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Failed to parse mget request",
+			zap.Error(err))
+		RespondError(c, model.ErrCodeInvalidRequest, err)
+		return
+	}
+
+	if len(req.IDs) == 0 {
+		RespondError(c, model.ErrCodeInvalidRequest, errors.New("mget requires at least one document ID"))
+		return
+	}
+	if len(req.IDs) > model.MaxGetDocuments {
+		RespondError(c, model.ErrCodeBatchTooLarge, fmt.Errorf("mget accepts at most %d IDs, got %d", model.MaxGetDocuments, len(req.IDs)))
+		return
+	}
+
+	span.SetAttributes(
+		attribute.String("index_id", req.IndexID),
+		attribute.Int("document_count", len(req.IDs)),
+	)
+
+	grpcReq := &pb.GetDocumentsRequest{
+		IndexId:     req.IndexID,
+		DocumentIds: req.IDs,
+	}
+
+	h.metrics.IncrementCounter("document_requests_total", []string{"operation:mget"})
+
+	resp, err := h.client.GetDocuments(ctx, grpcReq)
+	if err != nil {
+		h.logger.Error("Multi-get documents failed",
+			zap.Error(err),
+			zap.String("index_id", req.IndexID))
+		h.metrics.IncrementCounter("document_errors_total", []string{"operation:mget"})
+		RespondError(c, upstreamErrorCode(err, model.ErrCodeNotFound), err)
+		return
+	}
+
+	// Answer in request order even if the coordinator returned fewer
+	// documents or reordered them.
+	byID := make(map[string]*pb.DocumentResponse, len(resp.Documents))
+	for _, doc := range resp.Documents {
+		if doc != nil {
+			byID[doc.Id] = doc
+		}
+	}
+
+	out := model.MultiGetDocumentsResponse{
+		Documents: make([]model.MultiGetDocumentResult, len(req.IDs)),
+	}
+	for i, id := range req.IDs {
+		result := model.MultiGetDocumentResult{ID: id}
+		if doc, ok := byID[id]; ok && doc.Success {
+			result.Found = true
+			result.Fields = doc.Fields
+			result.Version = doc.Version
+		} else if ok && doc.Error != "" {
+			result.Error = doc.Error
+		} else {
+			result.Error = "document not found"
+		}
+		out.Documents[i] = result
+	}
+
+	h.metrics.IncrementCounter("document_success_total", []string{"operation:mget"})
+
+	c.JSON(http.StatusOK, out)
+}
+
 func (h *DocumentHandler) Update(c *gin.Context) {
 	ctx := c.Request.Context()
 	ctx, span := h.tracer.Start(ctx, "DocumentHandler.Update")
@@ -312,10 +575,7 @@ func (h *DocumentHandler) Update(c *gin.Context) {
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.logger.Error("Failed to parse update request",
 			zap.Error(err))
-		c.JSON(http.StatusBadRequest, model.ErrorResponse{
-			Code:    "INVALID_REQUEST",
-			Message: err.Error(),
-		})
+		RespondError(c, model.ErrCodeInvalidRequest, err)
 		return
 	}
 
@@ -328,6 +588,7 @@ func (h *DocumentHandler) Update(c *gin.Context) {
 		IndexId:    indexID,
 		DocumentId: documentID,
 		Fields:     req.Fields,
+		Version:    req.Version,
 	}
 
 	h.metrics.IncrementCounter("document_requests_total", []string{"operation:update"})
@@ -339,10 +600,7 @@ func (h *DocumentHandler) Update(c *gin.Context) {
 			zap.String("index_id", indexID),
 			zap.String("document_id", documentID))
 		h.metrics.IncrementCounter("document_errors_total", []string{"operation:update"})
-		c.JSON(http.StatusInternalServerError, model.ErrorResponse{
-			Code:    "UPDATE_DOCUMENT_FAILED",
-			Message: err.Error(),
-		})
+		RespondError(c, updateErrorCode(err), err)
 		return
 	}
 
@@ -351,9 +609,76 @@ func (h *DocumentHandler) Update(c *gin.Context) {
 	c.JSON(http.StatusOK, model.UpdateDocumentResponse{
 		Success: resp.Success,
 		Message: resp.Message,
+		Version: resp.Version,
 	})
 }
 
+func (h *DocumentHandler) Patch(c *gin.Context) {
+	ctx := c.Request.Context()
+	ctx, span := h.tracer.Start(ctx, "DocumentHandler.Patch")
+	defer span.End()
+
+	indexID := c.Param("index_id")
+	documentID := c.Param("id")
+
+	var req model.PatchDocumentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Failed to parse patch request",
+			zap.Error(err))
+		RespondError(c, model.ErrCodeInvalidRequest, err)
+		return
+	}
+
+	span.SetAttributes(
+		attribute.String("index_id", indexID),
+		attribute.String("document_id", documentID),
+	)
+
+	grpcReq := &pb.MergeDocumentRequest{
+		IndexId:    indexID,
+		DocumentId: documentID,
+		Fields:     make(map[string]string, len(req.Fields)),
+		Version:    req.Version,
+	}
+	for name, value := range req.Fields {
+		if value == nil {
+			grpcReq.DeleteFields = append(grpcReq.DeleteFields, name)
+			continue
+		}
+		grpcReq.Fields[name] = *value
+	}
+	sort.Strings(grpcReq.DeleteFields)
+
+	h.metrics.IncrementCounter("document_requests_total", []string{"operation:patch"})
+
+	resp, err := h.client.MergeDocument(ctx, grpcReq)
+	if err != nil {
+		h.logger.Error("Patch document failed",
+			zap.Error(err),
+			zap.String("index_id", indexID),
+			zap.String("document_id", documentID))
+		h.metrics.IncrementCounter("document_errors_total", []string{"operation:patch"})
+		RespondError(c, updateErrorCode(err), err)
+		return
+	}
+
+	h.metrics.IncrementCounter("document_success_total", []string{"operation:patch"})
+
+	c.JSON(http.StatusOK, model.DocumentResponse{
+		ID:      resp.Id,
+		Fields:  resp.Fields,
+		Version: resp.Version,
+	})
+}
+
+func updateErrorCode(err error) model.ErrorCode {
+	code := upstreamErrorCode(err, model.ErrCodeDocumentNotFound)
+	if code == model.ErrCodeConflict {
+		return model.ErrCodeVersionConflict
+	}
+	return code
+}
+
 func (h *DocumentHandler) Delete(c *gin.Context) {
 	ctx := c.Request.Context()
 	ctx, span := h.tracer.Start(ctx, "DocumentHandler.Delete")
@@ -381,10 +706,7 @@ func (h *DocumentHandler) Delete(c *gin.Context) {
 			zap.String("index_id", indexID),
 			zap.String("document_id", documentID))
 		h.metrics.IncrementCounter("document_errors_total", []string{"operation:delete"})
-		c.JSON(http.StatusInternalServerError, model.ErrorResponse{
-			Code:    "DELETE_DOCUMENT_FAILED",
-			Message: err.Error(),
-		})
+		RespondError(c, upstreamErrorCode(err, model.ErrCodeDocumentNotFound), err)
 		return
 	}
 
@@ -405,10 +727,7 @@ func (h *DocumentHandler) Batch(c *gin.Context) {
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.logger.Error("Failed to parse batch request",
 			zap.Error(err))
-		c.JSON(http.StatusBadRequest, model.ErrorResponse{
-			Code:    "INVALID_REQUEST",
-			Message: err.Error(),
-		})
+		RespondError(c, model.ErrCodeInvalidRequest, err)
 		return
 	}
 
@@ -417,8 +736,30 @@ func (h *DocumentHandler) Batch(c *gin.Context) {
 		attribute.Int("batch_size", len(req.Documents)),
 	)
 
-	docs := make([]map[string]string, len(req.Documents))
-	copy(docs, req.Documents)
+	rejected := model.ValidateBatchDocuments(req.Documents)
+	if len(rejected) > 0 && (!req.Partial || len(rejected) == len(req.Documents)) {
+		c.JSON(model.ErrCodeInvalidDocuments.HTTPStatus(), model.BatchValidationErrorResponse{
+			ErrorResponse: model.ErrorResponse{
+				Code:      model.ErrCodeInvalidDocuments,
+				Message:   fmt.Sprintf("%d of %d documents are invalid", len(rejected), len(req.Documents)),
+				RequestID: util.RequestIDFromContext(c.Request.Context()),
+			},
+			Documents: rejected,
+		})
+		return
+	}
+
+	docs := make([]map[string]string, 0, len(req.Documents)-len(rejected))
+	positions := make([]int, 0, cap(docs))
+	next := 0
+	for i, doc := range req.Documents {
+		if next < len(rejected) && rejected[next].Index == i {
+			next++
+			continue
+		}
+		docs = append(docs, doc)
+		positions = append(positions, i)
+	}
 
 	grpcReq := &pb.BatchDocumentsRequest{
 		IndexId:   req.IndexID,
@@ -434,22 +775,165 @@ func (h *DocumentHandler) Batch(c *gin.Context) {
 			zap.Error(err),
 			zap.String("index_id", req.IndexID))
 		h.metrics.IncrementCounter("document_errors_total", []string{"operation:batch"})
-		c.JSON(http.StatusInternalServerError, model.ErrorResponse{
-			Code:    "BATCH_DOCUMENTS_FAILED",
-			Message: err.Error(),
-		})
+		RespondError(c, upstreamErrorCode(err, model.ErrCodeNotFound), err)
 		return
 	}
 
 	h.metrics.IncrementCounter("document_success_total", []string{"operation:batch"})
 
+	errs := renumberBatchErrors(resp.Errors, positions)
+	for _, r := range rejected {
+		errs = append(errs, fmt.Sprintf("document %d: %s", r.Index, r.Error))
+	}
+
 	c.JSON(http.StatusOK, model.BatchDocumentsResponse{
 		SuccessCount: int(resp.SuccessCount),
-		FailureCount: int(resp.FailureCount),
-		Errors:       resp.Errors,
+		FailureCount: int(resp.FailureCount) + len(rejected),
+		Errors:       errs,
+		Rejected:     rejected,
 	})
 }
 
+// renumberBatchErrors rewrites the coordinator's "document N ..." errors,
+// which count positions in the dispatched documents, to the positions in
+// the request, positions[N] being the request position of document N.
+// Errors in any other form are kept as they are.
+func renumberBatchErrors(errs []string, positions []int) []string {
+	out := make([]string, len(errs))
+	for i, msg := range errs {
+		out[i] = msg
+		rest, ok := strings.CutPrefix(msg, "document ")
+		if !ok {
+			continue
+		}
+		end := strings.IndexFunc(rest, func(r rune) bool { return r < '0' || r > '9' })
+		if end < 0 {
+			end = len(rest)
+		}
+		n, err := strconv.Atoi(rest[:end])
+		if err != nil || n >= len(positions) {
+			continue
+		}
+		out[i] = "document " + strconv.Itoa(positions[n]) + rest[end:]
+	}
+	return out
+}
+
+// BulkStream ingests NDJSON documents for the index_id query parameter,
+// one JSON object per line. Documents are streamed to the coordinator in
+// chunks of model.BulkChunkSize, so the body is never held in memory whole.
+func (h *DocumentHandler) BulkStream(c *gin.Context) {
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+	ctx, span := h.tracer.Start(ctx, "DocumentHandler.BulkStream")
+	defer span.End()
+
+	indexID := c.Query("index_id")
+	if indexID == "" {
+		RespondError(c, model.ErrCodeInvalidRequest, errors.New("index_id query parameter is required"))
+		return
+	}
+
+	span.SetAttributes(attribute.String("index_id", indexID))
+
+	h.metrics.IncrementCounter("document_requests_total", []string{"operation:bulk"})
+
+	stream, err := h.client.BatchDocumentsStream(ctx)
+	if err != nil {
+		h.bulkFailed(c, indexID, err)
+		return
+	}
+
+	var out model.BulkIngestResponse
+	chunk := make([]map[string]string, 0, model.BulkChunkSize)
+	send := func() error {
+		if len(chunk) == 0 {
+			return nil
+		}
+		err := stream.Send(&pb.BatchDocumentsRequest{
+			IndexId:   indexID,
+			Documents: chunk,
+		})
+		chunk = make([]map[string]string, 0, model.BulkChunkSize)
+		return err
+	}
+
+	scanner := bufio.NewScanner(c.Request.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), model.MaxBulkLineBytes)
+
+	var sendErr error
+	for line := 1; sendErr == nil && scanner.Scan(); line++ {
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+
+		var doc map[string]string
+		if err := json.Unmarshal(text, &doc); err != nil {
+			out.FailureCount++
+			out.Errors = append(out.Errors, fmt.Sprintf("line %d: invalid JSON: %v", line, err))
+			continue
+		}
+		if err := model.ValidateBatchDocument(doc); err != nil {
+			out.FailureCount++
+			out.Errors = append(out.Errors, fmt.Sprintf("line %d: %v", line, err))
+			continue
+		}
+
+		chunk = append(chunk, doc)
+		if len(chunk) == model.BulkChunkSize {
+			sendErr = send()
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		h.logger.Error("Failed to read bulk request",
+			zap.Error(err),
+			zap.String("index_id", indexID))
+		RespondError(c, model.ErrCodeInvalidRequest, err)
+		return
+	}
+	if sendErr == nil {
+		sendErr = send()
+	}
+
+	// A failed Send only reports io.EOF; the stream status comes from
+	// CloseAndRecv.
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		h.bulkFailed(c, indexID, err)
+		return
+	}
+	if sendErr != nil && !errors.Is(sendErr, io.EOF) {
+		h.bulkFailed(c, indexID, sendErr)
+		return
+	}
+
+	out.SuccessCount += int(resp.SuccessCount)
+	out.FailureCount += int(resp.FailureCount)
+	out.Chunks = make([]model.BulkChunkAck, 0, len(resp.Chunks))
+	for _, ack := range resp.Chunks {
+		out.Chunks = append(out.Chunks, model.BulkChunkAck{
+			Chunk:        int(ack.Chunk),
+			SuccessCount: int(ack.SuccessCount),
+			FailureCount: int(ack.FailureCount),
+			Errors:       ack.Errors,
+		})
+	}
+
+	h.metrics.IncrementCounter("document_success_total", []string{"operation:bulk"})
+
+	c.JSON(http.StatusOK, out)
+}
+
+func (h *DocumentHandler) bulkFailed(c *gin.Context, indexID string, err error) {
+	h.logger.Error("Bulk ingest failed",
+		zap.Error(err),
+		zap.String("index_id", indexID))
+	h.metrics.IncrementCounter("document_errors_total", []string{"operation:bulk"})
+	RespondError(c, upstreamErrorCode(err, model.ErrCodeNotFound), err)
+}
+
 type IndexHandler struct {
 	client  *client.CoordinatorClient
 	metrics *util.Metrics
@@ -475,10 +959,12 @@ func (h *IndexHandler) Create(c *gin.Context) {
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.logger.Error("Failed to parse create index request",
 			zap.Error(err))
-		c.JSON(http.StatusBadRequest, model.ErrorResponse{
-			Code:    "INVALID_REQUEST",
-			Message: err.Error(),
-		})
+		RespondError(c, model.ErrCodeInvalidRequest, err)
+		return
+	}
+
+	if err := model.ValidateMappings(req.Mappings); err != nil {
+		RespondError(c, model.ErrCodeInvalidMappings, err)
 		return
 	}
 
@@ -493,6 +979,16 @@ func (h *IndexHandler) Create(c *gin.Context) {
 		Fields:    req.Fields,
 		Options:   req.Options,
 	}
+	if len(req.Mappings) > 0 {
+		grpcReq.Mappings = make(map[string]*pb.FieldMapping, len(req.Mappings))
+		for field, mapping := range req.Mappings {
+			grpcReq.Mappings[field] = &pb.FieldMapping{
+				Type:     mapping.Type,
+				Analyzer: mapping.Analyzer,
+				Boost:    mapping.Boost,
+			}
+		}
+	}
 
 	h.metrics.IncrementCounter("index_requests_total", []string{"operation:create"})
 
@@ -502,10 +998,7 @@ func (h *IndexHandler) Create(c *gin.Context) {
 			zap.Error(err),
 			zap.String("name", req.Name))
 		h.metrics.IncrementCounter("index_errors_total", []string{"operation:create"})
-		c.JSON(http.StatusInternalServerError, model.ErrorResponse{
-			Code:    "CREATE_INDEX_FAILED",
-			Message: err.Error(),
-		})
+		RespondError(c, upstreamErrorCode(err, model.ErrCodeNotFound), err)
 		return
 	}
 
@@ -542,10 +1035,7 @@ func (h *IndexHandler) List(c *gin.Context) {
 	if err != nil {
 		h.logger.Error("List indexes failed", zap.Error(err))
 		h.metrics.IncrementCounter("index_errors_total", []string{"operation:list"})
-		c.JSON(http.StatusInternalServerError, model.ErrorResponse{
-			Code:    "LIST_INDEXES_FAILED",
-			Message: err.Error(),
-		})
+		RespondError(c, upstreamErrorCode(err, model.ErrCodeNotFound), err)
 		return
 	}
 
@@ -591,10 +1081,7 @@ func (h *IndexHandler) Get(c *gin.Context) {
 			zap.Error(err),
 			zap.String("index_id", indexID))
 		h.metrics.IncrementCounter("index_errors_total", []string{"operation:get"})
-		c.JSON(http.StatusInternalServerError, model.ErrorResponse{
-			Code:    "GET_INDEX_FAILED",
-			Message: err.Error(),
-		})
+		RespondError(c, upstreamErrorCode(err, model.ErrCodeIndexNotFound), err)
 		return
 	}
 
@@ -633,10 +1120,7 @@ func (h *IndexHandler) Delete(c *gin.Context) {
 			zap.Error(err),
 			zap.String("index_id", indexID))
 		h.metrics.IncrementCounter("index_errors_total", []string{"operation:delete"})
-		c.JSON(http.StatusInternalServerError, model.ErrorResponse{
-			Code:    "DELETE_INDEX_FAILED",
-			Message: err.Error(),
-		})
+		RespondError(c, upstreamErrorCode(err, model.ErrCodeIndexNotFound), err)
 		return
 	}
 
@@ -648,6 +1132,130 @@ func (h *IndexHandler) Delete(c *gin.Context) {
 	})
 }
 
+func (h *IndexHandler) GetTask(c *gin.Context) {
+	ctx := c.Request.Context()
+	ctx, span := h.tracer.Start(ctx, "IndexHandler.GetTask")
+	defer span.End()
+
+	taskID := c.Param("id")
+
+	span.SetAttributes(attribute.String("task_id", taskID))
+
+	h.metrics.IncrementCounter("index_requests_total", []string{"operation:task_status"})
+
+	resp, err := h.client.GetTaskStatus(ctx, &pb.GetTaskStatusRequest{TaskId: taskID})
+	if err != nil {
+		h.logger.Error("Get task status failed",
+			zap.Error(err),
+			zap.String("task_id", taskID))
+		h.metrics.IncrementCounter("index_errors_total", []string{"operation:task_status"})
+		RespondError(c, upstreamErrorCode(err, model.ErrCodeTaskNotFound), err)
+		return
+	}
+
+	h.metrics.IncrementCounter("index_success_total", []string{"operation:task_status"})
+
+	c.JSON(http.StatusOK, model.TaskStatusResponse{
+		ID:         resp.Id,
+		Type:       resp.Type,
+		State:      resp.State,
+		Percent:    resp.Percent,
+		Error:      resp.Error,
+		StartedAt:  resp.StartedAt,
+		FinishedAt: resp.FinishedAt,
+	})
+}
+
+func (h *IndexHandler) Stats(c *gin.Context) {
+	ctx := c.Request.Context()
+	ctx, span := h.tracer.Start(ctx, "IndexHandler.Stats")
+	defer span.End()
+
+	indexID := c.Param("id")
+
+	span.SetAttributes(attribute.String("index_id", indexID))
+
+	h.metrics.IncrementCounter("index_requests_total", []string{"operation:stats"})
+
+	resp, err := h.client.GetIndexStats(ctx, &pb.GetIndexStatsRequest{IndexId: indexID})
+	if err != nil {
+		h.logger.Error("Get index stats failed",
+			zap.Error(err),
+			zap.String("index_id", indexID))
+		h.metrics.IncrementCounter("index_errors_total", []string{"operation:stats"})
+		RespondError(c, upstreamErrorCode(err, model.ErrCodeIndexNotFound), err)
+		return
+	}
+
+	h.metrics.IncrementCounter("index_success_total", []string{"operation:stats"})
+
+	engines := make([]model.EngineIndexStats, 0, len(resp.Engines))
+	for _, e := range resp.Engines {
+		engines = append(engines, model.EngineIndexStats{
+			Engine:        e.Engine,
+			DocumentCount: e.DocumentCount,
+			IndexSize:     e.IndexSize,
+			LastUpdated:   e.LastUpdated,
+		})
+	}
+
+	c.JSON(http.StatusOK, model.IndexStatsResponse{
+		IndexID:       indexID,
+		DocumentCount: resp.DocumentCount,
+		IndexSize:     resp.IndexSize,
+		LastUpdated:   resp.LastUpdated,
+		Engines:       engines,
+		Partial:       resp.Partial,
+		Notes:         resp.Notes,
+	})
+}
+
+// Reconcile checks that every engine holds the same number of documents for
+// the index. With ?rebuild=true mismatched engines are re-fed in a
+// background task whose ID is returned in the report.
+func (h *IndexHandler) Reconcile(c *gin.Context) {
+	ctx := c.Request.Context()
+	ctx, span := h.tracer.Start(ctx, "IndexHandler.Reconcile")
+	defer span.End()
+
+	indexID := c.Param("id")
+	rebuild := c.Query("rebuild") == "true"
+
+	span.SetAttributes(
+		attribute.String("index_id", indexID),
+		attribute.Bool("rebuild", rebuild),
+	)
+
+	h.metrics.IncrementCounter("index_requests_total", []string{"operation:reconcile"})
+
+	resp, err := h.client.ReconcileIndex(ctx, &pb.ReconcileIndexRequest{IndexId: indexID, Rebuild: rebuild})
+	if err != nil {
+		h.logger.Error("Reconcile index failed",
+			zap.Error(err),
+			zap.String("index_id", indexID))
+		h.metrics.IncrementCounter("index_errors_total", []string{"operation:reconcile"})
+		RespondError(c, upstreamErrorCode(err, model.ErrCodeIndexNotFound), err)
+		return
+	}
+
+	h.metrics.IncrementCounter("index_success_total", []string{"operation:reconcile"})
+	if !resp.Consistent {
+		h.logger.Warn("Index document counts diverge",
+			zap.String("index_id", indexID),
+			zap.Strings("mismatched", resp.Mismatched))
+	}
+
+	c.JSON(http.StatusOK, model.ReconcileResponse{
+		IndexID:       indexID,
+		Expected:      resp.Expected,
+		Counts:        resp.Counts,
+		Consistent:    resp.Consistent,
+		Mismatched:    resp.Mismatched,
+		Unreachable:   resp.Unreachable,
+		RebuildTaskID: resp.RebuildTaskId,
+	})
+}
+
 func (h *IndexHandler) Rebuild(c *gin.Context) {
 	ctx := c.Request.Context()
 	ctx, span := h.tracer.Start(ctx, "IndexHandler.Rebuild")
@@ -674,10 +1282,7 @@ func (h *IndexHandler) Rebuild(c *gin.Context) {
 			zap.Error(err),
 			zap.String("index_id", indexID))
 		h.metrics.IncrementCounter("index_errors_total", []string{"operation:rebuild"})
-		c.JSON(http.StatusInternalServerError, model.ErrorResponse{
-			Code:    "REBUILD_INDEX_FAILED",
-			Message: err.Error(),
-		})
+		RespondError(c, upstreamErrorCode(err, model.ErrCodeIndexNotFound), err)
 		return
 	}
 
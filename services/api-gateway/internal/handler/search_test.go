@@ -0,0 +1,1414 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flexsearch/api-gateway/internal/client"
+	"github.com/flexsearch/api-gateway/internal/middleware"
+	"github.com/flexsearch/api-gateway/internal/model"
+	"github.com/flexsearch/api-gateway/internal/util"
+	pb "github.com/flexsearch/api-gateway/proto"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var searchTestMetrics = util.NewMetrics("search_handler_test")
+
+// fakeSearchConn answers Search and MultiSearch calls by echoing each query
+// back as a single result, failing queries named "broken". Suggest calls
+// return the configured suggestions.
+type fakeSearchConn struct {
+	calls       int
+	suggestions []string
+	lastSearch  *pb.SearchRequest
+	lastSuggest *pb.SuggestRequest
+}
+
+func (f *fakeSearchConn) Invoke(ctx context.Context, method string, args, reply interface{}, opts ...grpc.CallOption) error {
+	f.calls++
+	switch method {
+	case "/coordinator.SearchService/Search":
+		f.lastSearch = args.(*pb.SearchRequest)
+		var out pb.MultiSearchResponse
+		f.multiSearch(&pb.MultiSearchRequest{Requests: []*pb.SearchRequest{args.(*pb.SearchRequest)}}, &out)
+		*reply.(*pb.SearchResponse) = *out.Responses[0]
+		return nil
+	case "/coordinator.SearchService/MultiSearch":
+		return f.multiSearch(args.(*pb.MultiSearchRequest), reply.(*pb.MultiSearchResponse))
+	case "/coordinator.SearchService/Suggest":
+		f.lastSuggest = args.(*pb.SuggestRequest)
+		reply.(*pb.SuggestResponse).Suggestions = f.suggestions
+		return nil
+	default:
+		return fmt.Errorf("unexpected method %s", method)
+	}
+}
+
+func (f *fakeSearchConn) multiSearch(in *pb.MultiSearchRequest, out *pb.MultiSearchResponse) error {
+	for _, req := range in.Requests {
+		if req.Query == "broken" {
+			out.Responses = append(out.Responses, &pb.SearchResponse{Error: "engine failure"})
+			continue
+		}
+		out.Responses = append(out.Responses, &pb.SearchResponse{
+			Results:    []*pb.SearchResult{{Id: req.Query, Score: 1}},
+			Total:      1,
+			Page:       1,
+			PageSize:   10,
+			TotalPages: 1,
+			Facets: map[string][]*pb.FacetBucket{
+				"author": {{Value: req.Query, Count: 1}},
+			},
+		})
+	}
+	return nil
+}
+
+func (f *fakeSearchConn) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return nil, fmt.Errorf("streams are not supported")
+}
+
+func postMultiSearch(t *testing.T, conn *fakeSearchConn, body interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	h := NewSearchHandler(client.NewCoordinatorClientWithConn(conn), searchTestMetrics, zap.NewNop())
+	router := gin.New()
+	router.POST("/api/v1/msearch", h.MultiSearch)
+
+	payload, _ := json.Marshal(body)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/msearch", bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestSearchHandler_MultiSearch(t *testing.T) {
+	conn := &fakeSearchConn{}
+	w := postMultiSearch(t, conn, []model.SearchRequest{
+		{Query: "alpha"},
+		{Query: "broken"},
+		{Query: "gamma"},
+	})
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var resp model.MultiSearchResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(resp.Responses) != 3 {
+		t.Fatalf("Expected 3 responses, got %d", len(resp.Responses))
+	}
+
+	for i, want := range []string{"alpha", "", "gamma"} {
+		r := resp.Responses[i]
+		if want == "" {
+			if r.Error == "" {
+				t.Errorf("Expected response %d to carry an error", i)
+			}
+			continue
+		}
+		if r.Error != "" {
+			t.Errorf("Expected response %d to succeed, got error %q", i, r.Error)
+		}
+		if len(r.Results) != 1 || r.Results[0].ID != want {
+			t.Errorf("Expected response %d to hold %q, got %+v", i, want, r.Results)
+		}
+		if buckets := r.Facets["author"]; len(buckets) != 1 || buckets[0] != (model.FacetBucket{Value: want, Count: 1}) {
+			t.Errorf("Expected response %d to carry its author facet, got %+v", i, r.Facets)
+		}
+	}
+}
+
+func TestSearchHandler_MultiSearchRejectsInvalidBatches(t *testing.T) {
+	oversized := make([]model.SearchRequest, model.MaxMultiSearchRequests+1)
+	for i := range oversized {
+		oversized[i] = model.SearchRequest{Query: "q"}
+	}
+
+	tests := []struct {
+		name string
+		body interface{}
+	}{
+		{"empty", []model.SearchRequest{}},
+		{"oversized", oversized},
+		{"invalid query", []model.SearchRequest{{Query: "ok"}, {Query: ""}}},
+		{"not an array", model.SearchRequest{Query: "q"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := &fakeSearchConn{}
+			w := postMultiSearch(t, conn, tt.body)
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+			}
+			if conn.calls != 0 {
+				t.Errorf("Expected no coordinator call, got %d", conn.calls)
+			}
+		})
+	}
+}
+
+func TestSearchHandler_SearchRejectsInvalidFields(t *testing.T) {
+	tests := []struct {
+		name      string
+		req       model.SearchRequest
+		wantField string
+	}{
+		{"bad sort order", model.SearchRequest{Query: "go", SortBy: "date", SortOrder: "sideways"}, "sort_order"},
+		{"empty filter key", model.SearchRequest{Query: "go", Filters: map[string]string{" ": "x"}}, "filters"},
+		{"malformed index name", model.SearchRequest{Query: "go", Indexes: []string{"articles", "bad/name"}}, "indexes[1]"},
+		{"cursor with page", model.SearchRequest{Query: "go", Page: 3, SearchAfter: "abc"}, "page"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			conn := &fakeSearchConn{}
+			h := NewSearchHandler(client.NewCoordinatorClientWithConn(conn), searchTestMetrics, zap.NewNop())
+			router := gin.New()
+			router.POST("/api/v1/search", h.Search)
+
+			body, _ := json.Marshal(tt.req)
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/search", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("Expected status %d, got %d: %s", http.StatusBadRequest, w.Code, w.Body.String())
+			}
+			if conn.calls != 0 {
+				t.Errorf("Expected no coordinator call, got %d", conn.calls)
+			}
+			var resp model.ValidationErrorResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if resp.Code != model.ErrCodeInvalidRequest {
+				t.Errorf("Expected code %s, got %s", model.ErrCodeInvalidRequest, resp.Code)
+			}
+			if len(resp.Fields) != 1 || resp.Fields[0].Field != tt.wantField {
+				t.Errorf("Expected one error for %s, got %+v", tt.wantField, resp.Fields)
+			}
+		})
+	}
+}
+
+func getSearch(t *testing.T, conn *fakeSearchConn, query string) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	h := NewSearchHandler(client.NewCoordinatorClientWithConn(conn), searchTestMetrics, zap.NewNop())
+	router := gin.New()
+	router.GET("/api/v1/search", h.SearchGet)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/search?"+query, nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestSearchHandler_SearchGet(t *testing.T) {
+	conn := &fakeSearchConn{}
+	w := getSearch(t, conn, "query=go&index=articles&index=news&page=2&page_size=5&highlight=true")
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	sent := conn.lastSearch
+	if sent.Query != "go" || len(sent.Indexes) != 2 || sent.Indexes[1] != "news" {
+		t.Errorf("Expected the query and both indexes to be forwarded, got %+v", sent)
+	}
+	if sent.Page != 2 || sent.PageSize != 5 || !sent.Highlight {
+		t.Errorf("Expected page 2 of 5 with highlighting, got %+v", sent)
+	}
+}
+
+func TestSearchHandler_SearchForwardsPerEngineLimit(t *testing.T) {
+	conn := &fakeSearchConn{}
+	w := getSearch(t, conn, "query=go&page_size=5&per_engine_limit=40")
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if conn.lastSearch.PerEngineLimit != 40 {
+		t.Errorf("Expected per_engine_limit 40 to be forwarded, got %d", conn.lastSearch.PerEngineLimit)
+	}
+}
+
+func TestSearchHandler_SearchGetRejectsInvalidQuery(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		wantField string
+	}{
+		{"missing query", "page=1", ""},
+		{"page size too large", "query=go&page_size=500", ""},
+		{"malformed page", "query=go&page=two", ""},
+		{"malformed index name", "query=go&index=articles&index=bad/name", "indexes[1]"},
+		{"cursor with page", "query=go&page=3&search_after=abc", "page"},
+		{"per engine limit too large", "query=go&per_engine_limit=5000", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := &fakeSearchConn{}
+			w := getSearch(t, conn, tt.query)
+
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("Expected status %d, got %d: %s", http.StatusBadRequest, w.Code, w.Body.String())
+			}
+			if conn.calls != 0 {
+				t.Errorf("Expected no coordinator call, got %d", conn.calls)
+			}
+			if tt.wantField == "" {
+				return
+			}
+			var resp model.ValidationErrorResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if len(resp.Fields) != 1 || resp.Fields[0].Field != tt.wantField {
+				t.Errorf("Expected one error for %s, got %+v", tt.wantField, resp.Fields)
+			}
+		})
+	}
+}
+
+func TestSearchHandler_MultiSearchReportsInvalidFields(t *testing.T) {
+	conn := &fakeSearchConn{}
+	w := postMultiSearch(t, conn, []model.SearchRequest{
+		{Query: "ok"},
+		{Query: "go", SortOrder: "up"},
+	})
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusBadRequest, w.Code, w.Body.String())
+	}
+	var resp model.ValidationErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(resp.Fields) != 1 || resp.Fields[0].Field != "[1].sort_order" {
+		t.Errorf("Expected an error for [1].sort_order, got %+v", resp.Fields)
+	}
+}
+
+func TestSearchHandler_MaxResultWindow(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	conn := &fakeSearchConn{}
+	h := NewSearchHandler(client.NewCoordinatorClientWithConn(conn), searchTestMetrics, zap.NewNop())
+	h.SetMaxResultWindow(100)
+	router := gin.New()
+	router.GET("/api/v1/search", h.SearchGet)
+	router.POST("/api/v1/search", h.Search)
+
+	tests := []struct {
+		name       string
+		page       int
+		wantStatus int
+	}{
+		{"under the limit", 9, http.StatusOK},
+		{"at the limit", 10, http.StatusOK},
+		{"over the limit", 11, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn.calls = 0
+			get := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/search?query=go&page=%d&page_size=10", tt.page), nil)
+			body, _ := json.Marshal(model.SearchRequest{Query: "go", Page: tt.page, PageSize: 10})
+			post := httptest.NewRequest(http.MethodPost, "/api/v1/search", bytes.NewReader(body))
+			post.Header.Set("Content-Type", "application/json")
+
+			for _, req := range []*http.Request{get, post} {
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				if w.Code != tt.wantStatus {
+					t.Fatalf("%s: expected status %d, got %d: %s", req.Method, tt.wantStatus, w.Code, w.Body.String())
+				}
+				if tt.wantStatus == http.StatusBadRequest && !strings.Contains(w.Body.String(), "search_after") {
+					t.Errorf("%s: expected the error to point to search_after, got %s", req.Method, w.Body.String())
+				}
+			}
+
+			wantCalls := 2
+			if tt.wantStatus != http.StatusOK {
+				wantCalls = 0
+			}
+			if conn.calls != wantCalls {
+				t.Errorf("Expected %d coordinator calls, got %d", wantCalls, conn.calls)
+			}
+		})
+	}
+}
+
+func getSuggest(t *testing.T, conn *fakeSearchConn, query string) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	h := NewSearchHandler(client.NewCoordinatorClientWithConn(conn), searchTestMetrics, zap.NewNop())
+	router := gin.New()
+	router.GET("/api/v1/suggest", h.Suggest)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/suggest?"+query, nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestSearchHandler_Suggest(t *testing.T) {
+	conn := &fakeSearchConn{suggestions: []string{"golang", "google"}}
+	w := getSuggest(t, conn, "q=go&limit=5")
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var resp model.SuggestResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(resp.Suggestions) != 2 || resp.Suggestions[0] != "golang" {
+		t.Errorf("Expected coordinator suggestions, got %v", resp.Suggestions)
+	}
+	if conn.lastSuggest.Prefix != "go" || conn.lastSuggest.Limit != 5 {
+		t.Errorf("Expected prefix go and limit 5, got %+v", conn.lastSuggest)
+	}
+}
+
+func TestSearchHandler_SuggestEmptyPrefix(t *testing.T) {
+	conn := &fakeSearchConn{suggestions: []string{"golang"}}
+	w := getSuggest(t, conn, "q=+")
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if body := w.Body.String(); body != `{"suggestions":[]}` {
+		t.Errorf("Expected an empty suggestion list, got %s", body)
+	}
+	if conn.calls != 0 {
+		t.Errorf("Expected no coordinator call for an empty prefix, got %d", conn.calls)
+	}
+}
+
+func TestSearchHandler_SuggestInvalidLimit(t *testing.T) {
+	for _, limit := range []string{"0", "51", "ten"} {
+		conn := &fakeSearchConn{}
+		if w := getSuggest(t, conn, "q=go&limit="+limit); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d for limit %s, got %d", http.StatusBadRequest, limit, w.Code)
+		}
+	}
+}
+
+// fakeDocumentConn serves a fixed set of stored documents. GetDocuments
+// returns them in reverse request order so handlers must restore it, and
+// MergeDocument and UpdateDocument apply writes like the coordinator does,
+// rejecting stale versions with Aborted. BatchDocuments accepts every
+// document it receives, and BatchDocumentsStream opens a fakeBatchStream.
+type fakeDocumentConn struct {
+	calls     int
+	docs      map[string]map[string]string
+	versions  map[string]int64
+	lastMerge *pb.MergeDocumentRequest
+	lastBatch *pb.BatchDocumentsRequest
+	stream    *fakeBatchStream
+}
+
+func (f *fakeDocumentConn) Invoke(ctx context.Context, method string, args, reply interface{}, opts ...grpc.CallOption) error {
+	f.calls++
+	switch method {
+	case "/coordinator.DocumentService/GetDocuments":
+		return f.getDocuments(args.(*pb.GetDocumentsRequest), reply.(*pb.GetDocumentsResponse))
+	case "/coordinator.DocumentService/MergeDocument":
+		return f.mergeDocument(args.(*pb.MergeDocumentRequest), reply.(*pb.DocumentResponse))
+	case "/coordinator.DocumentService/UpdateDocument":
+		return f.updateDocument(args.(*pb.UpdateDocumentRequest), reply.(*pb.UpdateDocumentResponse))
+	case "/coordinator.DocumentService/BatchDocuments":
+		f.lastBatch = args.(*pb.BatchDocumentsRequest)
+		out := reply.(*pb.BatchDocumentsResponse)
+		for i, doc := range f.lastBatch.Documents {
+			if doc["title"] == "fail" {
+				out.FailureCount++
+				out.Errors = append(out.Errors, fmt.Sprintf("document %d (%s): engine failure", i, doc["id"]))
+			} else {
+				out.SuccessCount++
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unexpected method %s", method)
+	}
+}
+
+func (f *fakeDocumentConn) mergeDocument(in *pb.MergeDocumentRequest, out *pb.DocumentResponse) error {
+	f.lastMerge = in
+	fields, ok := f.docs[in.DocumentId]
+	if !ok {
+		return status.Errorf(codes.NotFound, "document %s does not exist", in.DocumentId)
+	}
+	for name, value := range in.Fields {
+		fields[name] = value
+	}
+	for _, name := range in.DeleteFields {
+		delete(fields, name)
+	}
+
+	out.Id = in.DocumentId
+	out.Fields = fields
+	out.Success = true
+	return nil
+}
+
+func (f *fakeDocumentConn) updateDocument(in *pb.UpdateDocumentRequest, out *pb.UpdateDocumentResponse) error {
+	current := f.versions[in.DocumentId]
+	if in.Version != 0 && in.Version != current {
+		return status.Errorf(codes.Aborted, "expected version %d, stored version is %d", in.Version, current)
+	}
+	f.docs[in.DocumentId] = in.Fields
+	f.versions[in.DocumentId] = current + 1
+
+	out.Success = true
+	out.Version = current + 1
+	return nil
+}
+
+func (f *fakeDocumentConn) getDocuments(in *pb.GetDocumentsRequest, out *pb.GetDocumentsResponse) error {
+	for i := len(in.DocumentIds) - 1; i >= 0; i-- {
+		id := in.DocumentIds[i]
+		if fields, ok := f.docs[id]; ok {
+			out.Documents = append(out.Documents, &pb.DocumentResponse{Id: id, Fields: fields, Success: true})
+		} else {
+			out.Documents = append(out.Documents, &pb.DocumentResponse{Id: id, Error: "document not found"})
+		}
+	}
+	return nil
+}
+
+func (f *fakeDocumentConn) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	f.calls++
+	if method != "/coordinator.DocumentService/BatchDocumentsStream" || !desc.ClientStreams {
+		return nil, fmt.Errorf("unexpected stream %s", method)
+	}
+	f.stream = &fakeBatchStream{ctx: ctx}
+	return f.stream, nil
+}
+
+// fakeBatchStream records every chunk sent and, on close, acknowledges each
+// one, failing documents whose title is "fail".
+type fakeBatchStream struct {
+	ctx    context.Context
+	chunks []*pb.BatchDocumentsRequest
+	closed bool
+}
+
+func (s *fakeBatchStream) SendMsg(m interface{}) error {
+	s.chunks = append(s.chunks, m.(*pb.BatchDocumentsRequest))
+	return nil
+}
+
+func (s *fakeBatchStream) RecvMsg(m interface{}) error {
+	if !s.closed {
+		return fmt.Errorf("RecvMsg before CloseSend")
+	}
+	out := m.(*pb.BatchDocumentsStreamResponse)
+	for i, chunk := range s.chunks {
+		ack := &pb.BatchChunkAck{Chunk: int32(i)}
+		for _, doc := range chunk.Documents {
+			if doc["title"] == "fail" {
+				ack.FailureCount++
+				ack.Errors = append(ack.Errors, "document "+doc["id"]+": engine failure")
+			} else {
+				ack.SuccessCount++
+			}
+		}
+		out.SuccessCount += ack.SuccessCount
+		out.FailureCount += ack.FailureCount
+		out.Chunks = append(out.Chunks, ack)
+	}
+	return nil
+}
+
+func (s *fakeBatchStream) CloseSend() error {
+	s.closed = true
+	return nil
+}
+
+func (s *fakeBatchStream) Header() (metadata.MD, error) { return nil, nil }
+
+func (s *fakeBatchStream) Trailer() metadata.MD { return nil }
+
+func (s *fakeBatchStream) Context() context.Context { return s.ctx }
+
+func postMultiGet(t *testing.T, conn *fakeDocumentConn, body interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	h := NewDocumentHandler(client.NewCoordinatorClientWithConn(conn), searchTestMetrics, zap.NewNop())
+	router := gin.New()
+	router.POST("/api/v1/documents/mget", h.MultiGet)
+
+	payload, _ := json.Marshal(body)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/documents/mget", bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestDocumentHandler_MultiGet(t *testing.T) {
+	conn := &fakeDocumentConn{docs: map[string]map[string]string{
+		"a": {"title": "Alpha"},
+		"c": {"title": "Gamma"},
+	}}
+	w := postMultiGet(t, conn, model.MultiGetDocumentsRequest{
+		IndexID: "docs",
+		IDs:     []string{"c", "b", "a"},
+	})
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var resp model.MultiGetDocumentsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(resp.Documents) != 3 {
+		t.Fatalf("Expected 3 documents, got %d", len(resp.Documents))
+	}
+
+	want := []struct {
+		id    string
+		found bool
+		title string
+	}{
+		{"c", true, "Gamma"},
+		{"b", false, ""},
+		{"a", true, "Alpha"},
+	}
+	for i, tt := range want {
+		doc := resp.Documents[i]
+		if doc.ID != tt.id || doc.Found != tt.found {
+			t.Errorf("Expected document %d to be %s found=%v, got %+v", i, tt.id, tt.found, doc)
+		}
+		if doc.Fields["title"] != tt.title {
+			t.Errorf("Expected document %s title %q, got %q", tt.id, tt.title, doc.Fields["title"])
+		}
+		if !tt.found && doc.Error == "" {
+			t.Errorf("Expected missing document %s to carry an error", tt.id)
+		}
+	}
+}
+
+func TestDocumentHandler_MultiGetRejectsInvalidRequests(t *testing.T) {
+	tests := []struct {
+		name string
+		body interface{}
+	}{
+		{"no ids", model.MultiGetDocumentsRequest{IndexID: "docs", IDs: []string{}}},
+		{"too many ids", model.MultiGetDocumentsRequest{IndexID: "docs", IDs: make([]string, model.MaxGetDocuments+1)}},
+		{"missing index", model.MultiGetDocumentsRequest{IDs: []string{"a"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := &fakeDocumentConn{}
+			w := postMultiGet(t, conn, tt.body)
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+			}
+			if conn.calls != 0 {
+				t.Errorf("Expected no coordinator call, got %d", conn.calls)
+			}
+		})
+	}
+}
+
+func patchDocument(t *testing.T, conn *fakeDocumentConn, id, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	h := NewDocumentHandler(client.NewCoordinatorClientWithConn(conn), searchTestMetrics, zap.NewNop())
+	router := gin.New()
+	router.PATCH("/api/v1/documents/:index_id/:id", h.Patch)
+
+	req := httptest.NewRequest(http.MethodPatch, "/api/v1/documents/docs/"+id, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestDocumentHandler_PatchMergesFields(t *testing.T) {
+	conn := &fakeDocumentConn{docs: map[string]map[string]string{
+		"1": {"title": "Original", "author": "alice", "draft": "yes"},
+	}}
+	w := patchDocument(t, conn, "1", `{"fields": {"title": "Renamed", "draft": null}}`)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	if got := conn.lastMerge.Fields; len(got) != 1 || got["title"] != "Renamed" {
+		t.Errorf("Expected only the title to be set, got %v", got)
+	}
+	if got := conn.lastMerge.DeleteFields; len(got) != 1 || got[0] != "draft" {
+		t.Errorf("Expected draft to be deleted, got %v", got)
+	}
+
+	var resp model.DocumentResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	want := map[string]string{"title": "Renamed", "author": "alice"}
+	if len(resp.Fields) != len(want) || resp.Fields["title"] != want["title"] || resp.Fields["author"] != want["author"] {
+		t.Errorf("Expected merged fields %v, got %v", want, resp.Fields)
+	}
+}
+
+func TestDocumentHandler_PatchMissingDocument(t *testing.T) {
+	conn := &fakeDocumentConn{docs: map[string]map[string]string{}}
+	w := patchDocument(t, conn, "missing", `{"fields": {"title": "Renamed"}}`)
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusNotFound, w.Code, w.Body.String())
+	}
+
+	var resp model.ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.Code != "DOCUMENT_NOT_FOUND" {
+		t.Errorf("Expected code DOCUMENT_NOT_FOUND, got %s", resp.Code)
+	}
+}
+
+func putDocument(t *testing.T, conn *fakeDocumentConn, id string, body model.UpdateDocumentRequest) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	h := NewDocumentHandler(client.NewCoordinatorClientWithConn(conn), searchTestMetrics, zap.NewNop())
+	router := gin.New()
+	router.PUT("/api/v1/documents/:index_id/:id", h.Update)
+
+	payload, _ := json.Marshal(body)
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/documents/docs/"+id, bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestDocumentHandler_UpdateWithVersion(t *testing.T) {
+	conn := &fakeDocumentConn{
+		docs:     map[string]map[string]string{"1": {"title": "v3"}},
+		versions: map[string]int64{"1": 3},
+	}
+	w := putDocument(t, conn, "1", model.UpdateDocumentRequest{
+		IndexID: "docs",
+		Fields:  map[string]string{"title": "v4"},
+		Version: 3,
+	})
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var resp model.UpdateDocumentResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if !resp.Success || resp.Version != 4 {
+		t.Errorf("Expected success at version 4, got %+v", resp)
+	}
+}
+
+func TestDocumentHandler_UpdateStaleVersion(t *testing.T) {
+	conn := &fakeDocumentConn{
+		docs:     map[string]map[string]string{"1": {"title": "v3"}},
+		versions: map[string]int64{"1": 3},
+	}
+	w := putDocument(t, conn, "1", model.UpdateDocumentRequest{
+		IndexID: "docs",
+		Fields:  map[string]string{"title": "stale"},
+		Version: 2,
+	})
+
+	if w.Code != http.StatusConflict {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusConflict, w.Code, w.Body.String())
+	}
+
+	var resp model.ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.Code != "VERSION_CONFLICT" {
+		t.Errorf("Expected code VERSION_CONFLICT, got %s", resp.Code)
+	}
+	if conn.docs["1"]["title"] != "v3" {
+		t.Errorf("Expected the stored document to be unchanged, got %v", conn.docs["1"])
+	}
+}
+
+func postBatch(t *testing.T, conn *fakeDocumentConn, body model.BatchDocumentsRequest) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	h := NewDocumentHandler(client.NewCoordinatorClientWithConn(conn), searchTestMetrics, zap.NewNop())
+	router := gin.New()
+	router.POST("/api/v1/documents/batch", h.Batch)
+
+	payload, _ := json.Marshal(body)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/documents/batch", bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestDocumentHandler_BatchAllValid(t *testing.T) {
+	conn := &fakeDocumentConn{}
+	w := postBatch(t, conn, model.BatchDocumentsRequest{
+		IndexID: "docs",
+		Documents: []map[string]string{
+			{"id": "1", "title": "One"},
+			{"id": "2", "title": "Two"},
+		},
+	})
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var resp model.BatchDocumentsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.SuccessCount != 2 || resp.FailureCount != 0 || len(resp.Rejected) != 0 {
+		t.Errorf("Expected 2 successes and no failures, got %+v", resp)
+	}
+}
+
+func TestDocumentHandler_BatchPartial(t *testing.T) {
+	conn := &fakeDocumentConn{}
+	w := postBatch(t, conn, model.BatchDocumentsRequest{
+		IndexID: "docs",
+		Partial: true,
+		Documents: []map[string]string{
+			{"id": "1", "title": "One"},
+			{},
+			{"title": "No ID"},
+			{"id": "4", "title": "Four"},
+		},
+	})
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if n := len(conn.lastBatch.Documents); n != 2 {
+		t.Fatalf("Expected only the 2 valid documents to be dispatched, got %d", n)
+	}
+	if conn.lastBatch.Documents[1]["id"] != "4" {
+		t.Errorf("Expected valid documents to keep their order, got %v", conn.lastBatch.Documents)
+	}
+
+	var resp model.BatchDocumentsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.SuccessCount != 2 || resp.FailureCount != 2 {
+		t.Errorf("Expected 2 successes and 2 failures, got %+v", resp)
+	}
+	if len(resp.Rejected) != 2 || resp.Rejected[0].Index != 1 || resp.Rejected[1].Index != 2 {
+		t.Errorf("Expected documents 1 and 2 to be rejected, got %+v", resp.Rejected)
+	}
+	if err := resp.Validate(); err != nil {
+		t.Errorf("Expected a valid response, got %v", err)
+	}
+}
+
+func TestDocumentHandler_BatchPartialEngineErrors(t *testing.T) {
+	conn := &fakeDocumentConn{}
+	w := postBatch(t, conn, model.BatchDocumentsRequest{
+		IndexID: "docs",
+		Partial: true,
+		Documents: []map[string]string{
+			{},
+			{"id": "2", "title": "Two"},
+			{"title": "No ID"},
+			{"id": "4", "title": "fail"},
+		},
+	})
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var resp model.BatchDocumentsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.SuccessCount != 1 || resp.FailureCount != 3 {
+		t.Errorf("Expected 1 success and 3 failures, got %+v", resp)
+	}
+	if len(resp.Errors) == 0 || resp.Errors[0] != "document 3 (4): engine failure" {
+		t.Errorf("Expected the engine error at the request position 3, got %v", resp.Errors)
+	}
+}
+
+func TestDocumentHandler_BatchInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		partial bool
+		docs    []map[string]string
+	}{
+		{"mixed without partial", false, []map[string]string{{"id": "1"}, {"title": "No ID"}}},
+		{"all invalid with partial", true, []map[string]string{{}, {"id": " "}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := &fakeDocumentConn{}
+			w := postBatch(t, conn, model.BatchDocumentsRequest{
+				IndexID:   "docs",
+				Partial:   tt.partial,
+				Documents: tt.docs,
+			})
+
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+			}
+			if conn.calls != 0 {
+				t.Errorf("Expected no coordinator call, got %d", conn.calls)
+			}
+
+			var resp model.BatchValidationErrorResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if resp.Code != "INVALID_DOCUMENTS" || len(resp.Documents) == 0 {
+				t.Errorf("Expected per-document errors, got %+v", resp)
+			}
+			for _, docErr := range resp.Documents {
+				if docErr.Error == "" {
+					t.Errorf("Expected document %d to carry an error", docErr.Index)
+				}
+			}
+		})
+	}
+}
+
+func postBulk(t *testing.T, conn *fakeDocumentConn, query, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	h := NewDocumentHandler(client.NewCoordinatorClientWithConn(conn), searchTestMetrics, zap.NewNop())
+	router := gin.New()
+	router.POST("/api/v1/documents/bulk", h.BulkStream)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/documents/bulk?"+query, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestDocumentHandler_BulkStreamChunks(t *testing.T) {
+	var body strings.Builder
+	total := 2*model.BulkChunkSize + 50
+	for i := 0; i < total; i++ {
+		title := "ok"
+		if i%100 == 7 {
+			title = "fail"
+		}
+		fmt.Fprintf(&body, `{"id": "%d", "title": "%s"}`+"\n", i, title)
+		if i == 10 {
+			body.WriteString("\n")
+			body.WriteString("not json\n")
+			body.WriteString(`{"title": "no id"}` + "\n")
+		}
+	}
+
+	conn := &fakeDocumentConn{}
+	w := postBulk(t, conn, "index_id=docs", body.String())
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	sizes := make([]int, len(conn.stream.chunks))
+	for i, chunk := range conn.stream.chunks {
+		sizes[i] = len(chunk.Documents)
+		if chunk.IndexId != "docs" {
+			t.Errorf("Expected chunk %d for index docs, got %q", i, chunk.IndexId)
+		}
+	}
+	if len(sizes) != 3 || sizes[0] != model.BulkChunkSize || sizes[1] != model.BulkChunkSize || sizes[2] != 50 {
+		t.Errorf("Expected chunks of %d, %d and 50 documents, got %v", model.BulkChunkSize, model.BulkChunkSize, sizes)
+	}
+
+	var resp model.BulkIngestResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.SuccessCount != total-3 || resp.FailureCount != 5 {
+		t.Errorf("Expected %d successes and 5 failures, got %d and %d", total-3, resp.SuccessCount, resp.FailureCount)
+	}
+	if len(resp.Chunks) != 3 || resp.Chunks[2].Chunk != 2 || resp.Chunks[2].FailureCount != 1 {
+		t.Errorf("Expected an ack per chunk, got %+v", resp.Chunks)
+	}
+	if len(resp.Errors) != 2 {
+		t.Errorf("Expected the 2 malformed lines to be reported, got %v", resp.Errors)
+	}
+}
+
+func TestDocumentHandler_BulkStreamRequiresIndex(t *testing.T) {
+	conn := &fakeDocumentConn{}
+	w := postBulk(t, conn, "", `{"id": "1"}`)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	if conn.calls != 0 {
+		t.Errorf("Expected no coordinator call, got %d", conn.calls)
+	}
+}
+
+// fakeTaskConn answers GetTaskStatus from a fixed set of tasks and returns
+// NotFound for any other ID.
+type fakeTaskConn struct {
+	tasks map[string]*pb.TaskStatusResponse
+}
+
+func (f *fakeTaskConn) Invoke(ctx context.Context, method string, args, reply interface{}, opts ...grpc.CallOption) error {
+	if method != "/coordinator.IndexService/GetTaskStatus" {
+		return fmt.Errorf("unexpected method %s", method)
+	}
+	id := args.(*pb.GetTaskStatusRequest).TaskId
+	task, ok := f.tasks[id]
+	if !ok {
+		return status.Errorf(codes.NotFound, "no task with id %s", id)
+	}
+	*reply.(*pb.TaskStatusResponse) = *task
+	return nil
+}
+
+func (f *fakeTaskConn) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return nil, fmt.Errorf("streams are not supported")
+}
+
+func getTask(t *testing.T, conn *fakeTaskConn, id string) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	h := NewIndexHandler(client.NewCoordinatorClientWithConn(conn), searchTestMetrics, zap.NewNop())
+	router := gin.New()
+	router.GET("/api/v1/tasks/:id", h.GetTask)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/tasks/"+id, nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestIndexHandler_GetTask(t *testing.T) {
+	conn := &fakeTaskConn{tasks: map[string]*pb.TaskStatusResponse{
+		"task-running": {Id: "task-running", Type: "rebuild", State: "running", Percent: 40, StartedAt: "2026-01-02T10:00:00Z"},
+		"task-done": {
+			Id:         "task-done",
+			Type:       "rebuild",
+			State:      "completed",
+			Percent:    100,
+			StartedAt:  "2026-01-02T10:00:00Z",
+			FinishedAt: "2026-01-02T10:05:00Z",
+		},
+	}}
+
+	tests := []struct {
+		id       string
+		state    string
+		percent  float64
+		finished bool
+	}{
+		{"task-running", "running", 40, false},
+		{"task-done", "completed", 100, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			w := getTask(t, conn, tt.id)
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+			}
+
+			var resp model.TaskStatusResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if resp.ID != tt.id || resp.State != tt.state || resp.Percent != tt.percent {
+				t.Errorf("Expected %s %s at %v%%, got %+v", tt.id, tt.state, tt.percent, resp)
+			}
+			if (resp.FinishedAt != "") != tt.finished {
+				t.Errorf("Expected finished=%v, got finished_at %q", tt.finished, resp.FinishedAt)
+			}
+		})
+	}
+}
+
+func TestIndexHandler_GetTaskUnknown(t *testing.T) {
+	w := getTask(t, &fakeTaskConn{}, "task-missing")
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("Expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+
+	var resp model.ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.Code != "TASK_NOT_FOUND" {
+		t.Errorf("Expected code TASK_NOT_FOUND, got %s", resp.Code)
+	}
+}
+
+// fakeIndexConn records CreateIndex requests and answers GetIndexStats and
+// ReconcileIndex from stats and reports, returning NotFound for any other
+// index.
+type fakeIndexConn struct {
+	calls     int
+	last      *pb.CreateIndexRequest
+	stats     map[string]*pb.IndexStatsResponse
+	reports   map[string]*pb.ReconcileReport
+	reconcile *pb.ReconcileIndexRequest
+}
+
+func (f *fakeIndexConn) Invoke(ctx context.Context, method string, args, reply interface{}, opts ...grpc.CallOption) error {
+	switch method {
+	case "/coordinator.IndexService/CreateIndex":
+		f.calls++
+		f.last = args.(*pb.CreateIndexRequest)
+		*reply.(*pb.CreateIndexResponse) = pb.CreateIndexResponse{Id: f.last.Name, Success: true}
+		return nil
+	case "/coordinator.IndexService/GetIndexStats":
+		id := args.(*pb.GetIndexStatsRequest).IndexId
+		stats, ok := f.stats[id]
+		if !ok {
+			return status.Errorf(codes.NotFound, "index %s does not exist", id)
+		}
+		*reply.(*pb.IndexStatsResponse) = *stats
+		return nil
+	case "/coordinator.IndexService/ReconcileIndex":
+		f.reconcile = args.(*pb.ReconcileIndexRequest)
+		report, ok := f.reports[f.reconcile.IndexId]
+		if !ok {
+			return status.Errorf(codes.NotFound, "index %s does not exist", f.reconcile.IndexId)
+		}
+		*reply.(*pb.ReconcileReport) = *report
+		return nil
+	default:
+		return fmt.Errorf("unexpected method %s", method)
+	}
+}
+
+func (f *fakeIndexConn) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return nil, fmt.Errorf("streams are not supported")
+}
+
+func postIndex(t *testing.T, conn *fakeIndexConn, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	h := NewIndexHandler(client.NewCoordinatorClientWithConn(conn), searchTestMetrics, zap.NewNop())
+	router := gin.New()
+	router.POST("/api/v1/indexes", h.Create)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/indexes", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestIndexHandler_CreateWithMappings(t *testing.T) {
+	conn := &fakeIndexConn{}
+	w := postIndex(t, conn, `{"name":"articles","index_type":"fulltext","mappings":{
+		"title":{"type":"text","analyzer":"english","boost":2},
+		"published":{"type":"date"}}}`)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	if conn.calls != 1 {
+		t.Fatalf("Expected one coordinator call, got %d", conn.calls)
+	}
+
+	title := conn.last.Mappings["title"]
+	if title == nil || title.Type != "text" || title.Analyzer != "english" || title.Boost != 2 {
+		t.Errorf("Expected title mapping to be forwarded, got %+v", title)
+	}
+	if published := conn.last.Mappings["published"]; published == nil || published.Type != "date" {
+		t.Errorf("Expected published mapping to be forwarded, got %+v", published)
+	}
+}
+
+func TestIndexHandler_CreateRejectsInvalidMappings(t *testing.T) {
+	for _, body := range []string{
+		`{"name":"articles","index_type":"fulltext","mappings":{"title":{"type":"blob"}}}`,
+		`{"name":"articles","index_type":"fulltext","mappings":{"year":{"type":"integer","analyzer":"english"}}}`,
+		`{"name":"articles","index_type":"fulltext","mappings":{"title":{"type":"text","boost":-1}}}`,
+	} {
+		conn := &fakeIndexConn{}
+		w := postIndex(t, conn, body)
+
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("Expected status %d for %s, got %d", http.StatusBadRequest, body, w.Code)
+		}
+
+		var resp model.ErrorResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		if resp.Code != "INVALID_MAPPINGS" {
+			t.Errorf("Expected code INVALID_MAPPINGS, got %s", resp.Code)
+		}
+		if conn.calls != 0 {
+			t.Errorf("Expected no coordinator call, got %d", conn.calls)
+		}
+	}
+}
+
+func getIndexStats(t *testing.T, conn *fakeIndexConn, id string) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	h := NewIndexHandler(client.NewCoordinatorClientWithConn(conn), searchTestMetrics, zap.NewNop())
+	router := gin.New()
+	router.GET("/api/v1/indexes/:id/stats", h.Stats)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/indexes/"+id+"/stats", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestIndexHandler_StatsPartial(t *testing.T) {
+	conn := &fakeIndexConn{stats: map[string]*pb.IndexStatsResponse{
+		"articles": {
+			IndexId:       "articles",
+			DocumentCount: 120,
+			IndexSize:     4096,
+			Engines:       []*pb.EngineIndexStats{{Engine: "flexsearch", DocumentCount: 120, IndexSize: 4096}},
+			Partial:       true,
+			Notes:         []string{"index not present in engine vector"},
+		},
+	}}
+
+	w := getIndexStats(t, conn, "articles")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var resp model.IndexStatsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.IndexID != "articles" || resp.DocumentCount != 120 || resp.IndexSize != 4096 {
+		t.Errorf("Expected articles stats, got %+v", resp)
+	}
+	if !resp.Partial || len(resp.Notes) != 1 || len(resp.Engines) != 1 || resp.Engines[0].Engine != "flexsearch" {
+		t.Errorf("Expected partial stats from flexsearch with a note, got %+v", resp)
+	}
+}
+
+func TestIndexHandler_StatsUnknownIndex(t *testing.T) {
+	w := getIndexStats(t, &fakeIndexConn{}, "missing")
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("Expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+
+	var resp model.ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.Code != "INDEX_NOT_FOUND" {
+		t.Errorf("Expected code INDEX_NOT_FOUND, got %s", resp.Code)
+	}
+}
+
+func postReconcile(t *testing.T, conn *fakeIndexConn, target string) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	h := NewIndexHandler(client.NewCoordinatorClientWithConn(conn), searchTestMetrics, zap.NewNop())
+	router := gin.New()
+	router.POST("/admin/indexes/:id/reconcile", h.Reconcile)
+
+	req := httptest.NewRequest(http.MethodPost, target, nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestIndexHandler_ReconcileReportsMismatch(t *testing.T) {
+	conn := &fakeIndexConn{reports: map[string]*pb.ReconcileReport{
+		"articles": {
+			IndexId:       "articles",
+			Expected:      3,
+			Counts:        map[string]int64{"flexsearch": 3, "bm25": 1},
+			Mismatched:    []string{"bm25"},
+			RebuildTaskId: "task-1",
+		},
+	}}
+
+	w := postReconcile(t, conn, "/admin/indexes/articles/reconcile?rebuild=true")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if conn.reconcile == nil || !conn.reconcile.Rebuild {
+		t.Errorf("Expected the rebuild flag to be forwarded, got %+v", conn.reconcile)
+	}
+
+	var resp model.ReconcileResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.Consistent || len(resp.Mismatched) != 1 || resp.Mismatched[0] != "bm25" || resp.Counts["bm25"] != 1 {
+		t.Errorf("Expected bm25 to be flagged, got %+v", resp)
+	}
+	if resp.RebuildTaskID != "task-1" {
+		t.Errorf("Expected rebuild task task-1, got %q", resp.RebuildTaskID)
+	}
+}
+
+func TestIndexHandler_ReconcileUnknownIndex(t *testing.T) {
+	w := postReconcile(t, &fakeIndexConn{}, "/admin/indexes/missing/reconcile")
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("Expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+// slowSearchConn blocks every call until its context is done and reports
+// the context error, like a coordinator that never answers.
+type slowSearchConn struct {
+	cancelled chan error
+}
+
+func (f *slowSearchConn) Invoke(ctx context.Context, method string, args, reply interface{}, opts ...grpc.CallOption) error {
+	<-ctx.Done()
+	f.cancelled <- ctx.Err()
+	return status.FromContextError(ctx.Err()).Err()
+}
+
+func (f *slowSearchConn) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return nil, fmt.Errorf("streams are not supported")
+}
+
+func TestSearchHandler_RequestTimeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	conn := &slowSearchConn{cancelled: make(chan error, 1)}
+
+	h := NewSearchHandler(client.NewCoordinatorClientWithConn(conn), searchTestMetrics, zap.NewNop())
+	router := gin.New()
+	router.Use(middleware.TimeoutMiddleware(20 * time.Millisecond))
+	router.POST("/api/v1/search", h.Search)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/search", strings.NewReader(`{"query":"go","index_id":"articles"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusGatewayTimeout {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusGatewayTimeout, w.Code, w.Body.String())
+	}
+
+	select {
+	case err := <-conn.cancelled:
+		if err != context.DeadlineExceeded {
+			t.Errorf("Expected the coordinator call to hit the deadline, got %v", err)
+		}
+	default:
+		t.Error("Expected the coordinator call to be cancelled")
+	}
+}
+
+// failingSearchConn rejects every call as unavailable and records the
+// request ID the handler passed down in the context.
+type failingSearchConn struct {
+	requestID string
+}
+
+func (f *failingSearchConn) Invoke(ctx context.Context, method string, args, reply interface{}, opts ...grpc.CallOption) error {
+	f.requestID = util.RequestIDFromContext(ctx)
+	return status.Error(codes.Unavailable, "coordinator unavailable")
+}
+
+func (f *failingSearchConn) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return nil, fmt.Errorf("streams are not supported")
+}
+
+func TestSearchHandler_ErrorCarriesRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	conn := &failingSearchConn{}
+
+	h := NewSearchHandler(client.NewCoordinatorClientWithConn(conn), searchTestMetrics, zap.NewNop())
+	router := gin.New()
+	router.Use(middleware.RequestIDMiddleware())
+	router.POST("/api/v1/search", h.Search)
+
+	for _, tt := range []struct {
+		name string
+		body string
+	}{
+		{"coordinator error", `{"query":"go","index_id":"articles"}`},
+		{"invalid request", `{"query":`},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/search", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("X-Request-ID", "client-req-42")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code < 400 {
+				t.Fatalf("Expected an error status, got %d", w.Code)
+			}
+			var body model.ErrorResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("Failed to decode error body: %v", err)
+			}
+			if body.RequestID != "client-req-42" {
+				t.Errorf("Expected request_id client-req-42 in the error body, got %q", body.RequestID)
+			}
+			if got := w.Header().Get("X-Request-ID"); got != "client-req-42" {
+				t.Errorf("Expected X-Request-ID header to be echoed, got %q", got)
+			}
+		})
+	}
+
+	if conn.requestID != "client-req-42" {
+		t.Errorf("Expected the coordinator call context to carry the request ID, got %q", conn.requestID)
+	}
+}
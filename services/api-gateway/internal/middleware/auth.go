@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 
@@ -30,7 +31,7 @@ func AuthMiddleware(jwtManager *util.JWTManager) gin.HandlerFunc {
 			return
 		}
 
-		claims, err := jwtManager.ValidateToken(tokenString)
+		claims, err := jwtManager.ValidateTokenContext(c.Request.Context(), tokenString)
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token", "details": err.Error()})
 			c.Abort()
@@ -40,11 +41,138 @@ func AuthMiddleware(jwtManager *util.JWTManager) gin.HandlerFunc {
 		c.Set("user_id", claims.UserID)
 		c.Set("username", claims.Username)
 		c.Set("role", claims.Role)
+		if claims.Tier != "" {
+			c.Set("rate_limit_tier", claims.Tier)
+		}
+		if claims.Tenant != "" {
+			setTenant(c, claims.Tenant)
+		}
+		c.Set("scopes", claims.Scopes)
+
+		c.Next()
+	}
+}
+
+// APIKeyHeader carries the static key used by APIKeyMiddleware.
+const APIKeyHeader = "X-API-Key"
+
+// APIKeyMiddleware authenticates requests by the X-API-Key header. The key's
+// tier and scopes take the place of the JWT claims.
+func APIKeyMiddleware(keys util.APIKeyValidator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		secret := c.GetHeader(APIKeyHeader)
+		if secret == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing API key"})
+			c.Abort()
+			return
+		}
+
+		if !authenticateAPIKey(c, keys, secret) {
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// AuthOrAPIKeyMiddleware accepts either credential: requests carrying an
+// X-API-Key header are checked against keys, all others need a JWT.
+func AuthOrAPIKeyMiddleware(jwtManager *util.JWTManager, keys util.APIKeyValidator) gin.HandlerFunc {
+	jwtAuth := AuthMiddleware(jwtManager)
+	return func(c *gin.Context) {
+		secret := c.GetHeader(APIKeyHeader)
+		if secret == "" {
+			jwtAuth(c)
+			return
+		}
+
+		if !authenticateAPIKey(c, keys, secret) {
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// OptionalAPIKeyMiddleware resolves a valid X-API-Key before rate limiting
+// so the key's tier applies. Invalid keys are left for the auth middleware
+// to reject.
+func OptionalAPIKeyMiddleware(keys util.APIKeyValidator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if secret := c.GetHeader(APIKeyHeader); secret != "" {
+			if key, err := keys.Validate(c.Request.Context(), secret); err == nil {
+				setAPIKeyContext(c, key)
+			}
+		}
 
 		c.Next()
 	}
 }
 
+func authenticateAPIKey(c *gin.Context, keys util.APIKeyValidator, secret string) bool {
+	key, err := keys.Validate(c.Request.Context(), secret)
+	switch {
+	case errors.Is(err, util.ErrAPIKeyRevoked):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "API key revoked"})
+	case errors.Is(err, util.ErrAPIKeyExpired):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "API key expired"})
+	case errors.Is(err, util.ErrAPIKeyNotFound):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid API key"})
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "API key lookup failed", "details": err.Error()})
+	default:
+		setAPIKeyContext(c, key)
+		return true
+	}
+
+	c.Abort()
+	return false
+}
+
+func setAPIKeyContext(c *gin.Context, key *util.APIKey) {
+	c.Set("user_id", "apikey:"+key.ID)
+	c.Set("username", key.Name)
+	c.Set("role", key.Role)
+	if key.Tier != "" {
+		c.Set("rate_limit_tier", key.Tier)
+	}
+	if key.Tenant != "" {
+		setTenant(c, key.Tenant)
+	}
+	c.Set("scopes", key.Scopes)
+	c.Set("auth_method", "api_key")
+}
+
+// RequireRole rejects requests whose authenticated role is not role. It must
+// run after AuthMiddleware.
+func RequireRole(role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString("role") != role {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireScope rejects requests whose token does not grant scope. It must
+// run after AuthMiddleware.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, granted := range c.GetStringSlice("scopes") {
+			if granted == scope {
+				c.Next()
+				return
+			}
+		}
+
+		c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient scope", "required_scope": scope})
+		c.Abort()
+	}
+}
+
 func OptionalAuthMiddleware(jwtManager *util.JWTManager) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
@@ -64,7 +192,7 @@ func OptionalAuthMiddleware(jwtManager *util.JWTManager) gin.HandlerFunc {
 			return
 		}
 
-		claims, err := jwtManager.ValidateToken(tokenString)
+		claims, err := jwtManager.ValidateTokenContext(c.Request.Context(), tokenString)
 		if err != nil {
 			c.Next()
 			return
@@ -73,6 +201,13 @@ func OptionalAuthMiddleware(jwtManager *util.JWTManager) gin.HandlerFunc {
 		c.Set("user_id", claims.UserID)
 		c.Set("username", claims.Username)
 		c.Set("role", claims.Role)
+		if claims.Tier != "" {
+			c.Set("rate_limit_tier", claims.Tier)
+		}
+		if claims.Tenant != "" {
+			setTenant(c, claims.Tenant)
+		}
+		c.Set("scopes", claims.Scopes)
 
 		c.Next()
 	}
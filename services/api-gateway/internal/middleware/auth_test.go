@@ -1,9 +1,12 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/flexsearch/api-gateway/internal/util"
 	"github.com/gin-gonic/gin"
@@ -232,3 +235,214 @@ func TestOptionalAuthMiddleware_ValidToken(t *testing.T) {
 		t.Errorf("Expected user_id 'user456', got '%s'", capturedUserID)
 	}
 }
+
+func newScopedRouter(jwtManager *util.JWTManager) *gin.Engine {
+	router := gin.New()
+	router.Use(AuthMiddleware(jwtManager))
+	router.DELETE("/indexes/:id", RequireScope(util.ScopeIndexesDelete), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "deleted"})
+	})
+	return router
+}
+
+func TestRequireScope_Allowed(t *testing.T) {
+	jwtManager := util.NewJWTManager("test-secret", "test-issuer", 24)
+	router := newScopedRouter(jwtManager)
+
+	token, err := jwtManager.IssueToken(util.CustomClaims{
+		UserID: "user123",
+		Role:   "user",
+		Scopes: []string{"search", util.ScopeIndexesDelete},
+	})
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodDelete, "/indexes/books", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+}
+
+func TestRequireScope_Denied(t *testing.T) {
+	jwtManager := util.NewJWTManager("test-secret", "test-issuer", 24)
+	router := newScopedRouter(jwtManager)
+
+	for name, scopes := range map[string][]string{
+		"no scopes":   nil,
+		"other scope": {"search"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			token, err := jwtManager.IssueToken(util.CustomClaims{UserID: "user123", Role: "admin", Scopes: scopes})
+			if err != nil {
+				t.Fatalf("Failed to generate token: %v", err)
+			}
+
+			req := httptest.NewRequest(http.MethodDelete, "/indexes/books", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != http.StatusForbidden {
+				t.Errorf("Expected status %d, got %d", http.StatusForbidden, w.Code)
+			}
+		})
+	}
+}
+
+// fakeAPIKeys validates secrets against a fixed set of keys and errors.
+type fakeAPIKeys map[string]struct {
+	key *util.APIKey
+	err error
+}
+
+func (f fakeAPIKeys) Validate(ctx context.Context, secret string) (*util.APIKey, error) {
+	entry, ok := f[secret]
+	if !ok {
+		return nil, util.ErrAPIKeyNotFound
+	}
+	return entry.key, entry.err
+}
+
+func testAPIKeys() fakeAPIKeys {
+	return fakeAPIKeys{
+		"valid": {key: &util.APIKey{
+			ID:     "k1",
+			Name:   "indexer",
+			Tier:   string(util.TierPremium),
+			Scopes: []string{util.ScopeDocumentsDelete},
+		}},
+		"revoked": {err: util.ErrAPIKeyRevoked},
+		"expired": {err: util.ErrAPIKeyExpired},
+	}
+}
+
+func TestAPIKeyMiddleware(t *testing.T) {
+	router := gin.New()
+	router.Use(APIKeyMiddleware(testAPIKeys()))
+	router.GET("/test", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{
+			"user_id": c.GetString("user_id"),
+			"tier":    c.GetString("rate_limit_tier"),
+			"scopes":  c.GetStringSlice("scopes"),
+		})
+	})
+
+	tests := []struct {
+		name    string
+		key     string
+		status  int
+		message string
+	}{
+		{"valid key", "valid", http.StatusOK, ""},
+		{"revoked key", "revoked", http.StatusUnauthorized, "API key revoked"},
+		{"expired key", "expired", http.StatusUnauthorized, "API key expired"},
+		{"unknown key", "bogus", http.StatusUnauthorized, "Invalid API key"},
+		{"missing key", "", http.StatusUnauthorized, "Missing API key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			if tt.key != "" {
+				req.Header.Set(APIKeyHeader, tt.key)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.status {
+				t.Fatalf("Expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+			if tt.message != "" && !strings.Contains(w.Body.String(), tt.message) {
+				t.Errorf("Expected %q in the body, got %s", tt.message, w.Body.String())
+			}
+			if tt.status == http.StatusOK && !strings.Contains(w.Body.String(), `"user_id":"apikey:k1"`) {
+				t.Errorf("Expected the key identity in context, got %s", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestAuthOrAPIKeyMiddleware(t *testing.T) {
+	jwtManager := util.NewJWTManager("test-secret", "test-issuer", 24)
+	token, err := jwtManager.GenerateToken("user-1", "alice", "user")
+	if err != nil {
+		t.Fatalf("GenerateToken returned error: %v", err)
+	}
+
+	router := gin.New()
+	router.Use(AuthOrAPIKeyMiddleware(jwtManager, testAPIKeys()))
+	router.GET("/test", func(c *gin.Context) {
+		c.String(http.StatusOK, c.GetString("user_id"))
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || w.Body.String() != "user-1" {
+		t.Errorf("Expected the JWT to authenticate, got %d: %s", w.Code, w.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set(APIKeyHeader, "valid")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || w.Body.String() != "apikey:k1" {
+		t.Errorf("Expected the API key to authenticate, got %d: %s", w.Code, w.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set(APIKeyHeader, "revoked")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected a revoked key to be refused even with a JWT, got %d", w.Code)
+	}
+}
+
+func TestAPIKeyMiddleware_RateLimitTier(t *testing.T) {
+	limiter := newTestLimiter(t, map[util.RateLimitTier]util.TierConfig{
+		util.TierFree:    {Limit: 1, Burst: 1, Window: time.Hour},
+		util.TierPremium: {Limit: 10, Burst: 10, Window: time.Hour},
+	})
+	keys := testAPIKeys()
+
+	router := gin.New()
+	router.Use(OptionalAPIKeyMiddleware(keys))
+	router.Use(RateLimitMiddleware(limiter, RateLimitConfig{Enabled: true, ByUser: true, ByIP: true}))
+	router.Use(APIKeyMiddleware(keys))
+	router.GET("/test", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	})
+
+	for i := 0; i < 3; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req.Header.Set(APIKeyHeader, "valid")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Request %d: expected status %d, got %d", i, http.StatusOK, w.Code)
+		}
+		if tier := w.Header().Get("X-RateLimit-Tier"); tier != string(util.TierPremium) {
+			t.Fatalf("Expected the key's premium tier, got %q", tier)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set(APIKeyHeader, "revoked")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if tier := w.Header().Get("X-RateLimit-Tier"); tier != string(util.TierFree) {
+		t.Errorf("Expected a revoked key to fall back to the free tier, got %q", tier)
+	}
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected the revoked key to be refused, got %d", w.Code)
+	}
+}
@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const bodyLimitKey = "body_limit"
+
+// limitedBody caps reads from the original request body and remembers
+// whether the cap was hit. A declared Content-Length over the cap fails the
+// first read without touching the body.
+type limitedBody struct {
+	io.ReadCloser
+	raw      io.ReadCloser
+	length   int64
+	max      int64
+	started  bool
+	exceeded bool
+}
+
+func (b *limitedBody) limit(w http.ResponseWriter, maxBytes int64) {
+	b.max = maxBytes
+	b.ReadCloser = http.MaxBytesReader(w, b.raw, maxBytes)
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if !b.started {
+		b.started = true
+		if b.length > b.max {
+			b.exceeded = true
+			return 0, &http.MaxBytesError{Limit: b.max}
+		}
+	}
+
+	n, err := b.ReadCloser.Read(p)
+	var maxErr *http.MaxBytesError
+	if errors.As(err, &maxErr) {
+		b.exceeded = true
+	}
+	return n, err
+}
+
+// bodyLimitWriter drops whatever the handler writes once the body limit has
+// been hit, so the middleware can answer 413 instead.
+type bodyLimitWriter struct {
+	gin.ResponseWriter
+	body *limitedBody
+}
+
+func (w *bodyLimitWriter) WriteHeader(code int) {
+	if w.body.exceeded {
+		return
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *bodyLimitWriter) Write(data []byte) (int, error) {
+	if w.body.exceeded {
+		return len(data), nil
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *bodyLimitWriter) WriteString(s string) (int, error) {
+	if w.body.exceeded {
+		return len(s), nil
+	}
+	return w.ResponseWriter.WriteString(s)
+}
+
+// BodySizeLimitMiddleware answers 413 once a handler reads more than
+// maxBytes of the request body. Applied again on a route, it replaces the
+// global limit rather than stacking with it, so routes such as bulk ingest
+// can allow larger bodies. maxBytes <= 0 disables it.
+func BodySizeLimitMiddleware(maxBytes int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if maxBytes <= 0 || c.Request.Body == nil || c.Request.Body == http.NoBody {
+			c.Next()
+			return
+		}
+
+		if existing, ok := c.Get(bodyLimitKey); ok {
+			existing.(*limitedBody).limit(c.Writer, maxBytes)
+			c.Next()
+			return
+		}
+
+		body := &limitedBody{raw: c.Request.Body, length: c.Request.ContentLength}
+		body.limit(c.Writer, maxBytes)
+		c.Request.Body = body
+		c.Set(bodyLimitKey, body)
+
+		writer := c.Writer
+		c.Writer = &bodyLimitWriter{ResponseWriter: writer, body: body}
+		c.Next()
+		c.Writer = writer
+
+		if body.exceeded && !writer.Written() {
+			bodyTooLarge(c, body.max)
+		}
+	}
+}
+
+func bodyTooLarge(c *gin.Context, maxBytes int64) {
+	c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+		"error":     "Request body too large",
+		"max_bytes": maxBytes,
+	})
+	c.Abort()
+}
@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newBodyLimitRouter(global int64, routes ...gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(BodySizeLimitMiddleware(global))
+	handler := func(c *gin.Context) {
+		var body map[string]interface{}
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"fields": len(body)})
+	}
+	router.POST("/test", handler)
+	router.POST("/bulk", append(routes, handler)...)
+	return router
+}
+
+func jsonBody(size int) string {
+	return `{"data":"` + strings.Repeat("x", size) + `"}`
+}
+
+func TestBodySizeLimitMiddleware_UnderLimit(t *testing.T) {
+	router := newBodyLimitRouter(1024)
+
+	req := httptest.NewRequest(http.MethodPost, "/test", strings.NewReader(jsonBody(100)))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+}
+
+func TestBodySizeLimitMiddleware_OverLimit(t *testing.T) {
+	router := newBodyLimitRouter(1024)
+
+	req := httptest.NewRequest(http.MethodPost, "/test", strings.NewReader(jsonBody(4096)))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected status %d, got %d: %s", http.StatusRequestEntityTooLarge, w.Code, w.Body.String())
+	}
+	if strings.Contains(w.Body.String(), "fields") {
+		t.Errorf("Expected the handler response to be dropped, got %s", w.Body.String())
+	}
+}
+
+func TestBodySizeLimitMiddleware_OverLimitWithoutContentLength(t *testing.T) {
+	router := newBodyLimitRouter(1024)
+
+	// io.MultiReader hides the length, as with a chunked upload.
+	req := httptest.NewRequest(http.MethodPost, "/test", io.MultiReader(bytes.NewReader([]byte(jsonBody(4096)))))
+	req.ContentLength = -1
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected status %d, got %d: %s", http.StatusRequestEntityTooLarge, w.Code, w.Body.String())
+	}
+}
+
+func TestBodySizeLimitMiddleware_RouteOverride(t *testing.T) {
+	router := newBodyLimitRouter(1024, BodySizeLimitMiddleware(8192))
+
+	req := httptest.NewRequest(http.MethodPost, "/bulk", strings.NewReader(jsonBody(4096)))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected the route limit to allow the body, got %d: %s", w.Code, w.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/bulk", strings.NewReader(jsonBody(16384)))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected status %d over the route limit, got %d", http.StatusRequestEntityTooLarge, w.Code)
+	}
+}
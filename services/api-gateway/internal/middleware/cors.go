@@ -2,50 +2,55 @@ package middleware
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
 )
 
 type CORSConfig struct {
+	// AllowOrigins lists exact origins, "*" for any origin, or subdomain
+	// wildcards such as "https://*.example.com".
 	AllowOrigins     []string
 	AllowMethods     []string
 	AllowHeaders     []string
 	AllowCredentials bool
+	Logger           *zap.Logger
 }
 
 func CORSMiddleware(config CORSConfig) gin.HandlerFunc {
+	logger := config.Logger
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+
+	allowAny := len(config.AllowOrigins) == 0
+	for _, allowed := range config.AllowOrigins {
+		if allowed == "*" {
+			allowAny = true
+		}
+	}
+	if allowAny && config.AllowCredentials {
+		logger.Warn("CORS credentials cannot be combined with a wildcard origin; they are only sent to explicitly listed origins")
+	}
+
 	return func(c *gin.Context) {
 		origin := c.Request.Header.Get("Origin")
 
-		if len(config.AllowOrigins) > 0 {
-			allowed := false
-			var allowedOrigin string
-			for _, allowedOrigin = range config.AllowOrigins {
-				if allowedOrigin == "*" {
-					allowed = true
-					break
-				}
-				if allowedOrigin == origin {
-					allowed = true
-					break
-				}
-			}
-			if allowed {
-				if allowedOrigin == "*" {
-					if origin != "" {
-						c.Writer.Header().Set("Access-Control-Allow-Origin", origin)
-					} else {
-						c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-					}
-				} else {
-					c.Writer.Header().Set("Access-Control-Allow-Origin", origin)
-				}
-			}
-		} else {
+		listed := origin != "" && originAllowed(config.AllowOrigins, origin)
+		switch {
+		case origin != "" && (allowAny || listed):
+			c.Writer.Header().Set("Access-Control-Allow-Origin", origin)
+			c.Writer.Header().Add("Vary", "Origin")
+		case origin == "" && allowAny:
 			c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 		}
 
-		if config.AllowCredentials {
+		// Credentials are only vouched for on origins matching an explicit
+		// or subdomain wildcard entry. "*" admits any origin, and letting
+		// every site make credentialed requests is what browsers forbid it
+		// for.
+		if config.AllowCredentials && listed {
 			c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
 		}
 
@@ -83,3 +88,30 @@ func CORSMiddleware(config CORSConfig) gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+func originAllowed(allowOrigins []string, origin string) bool {
+	for _, allowed := range allowOrigins {
+		if allowed == origin || matchWildcardOrigin(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchWildcardOrigin reports whether origin matches a pattern such as
+// "https://*.example.com". The wildcard covers one or more subdomain labels
+// but never the scheme, port or the bare parent domain.
+func matchWildcardOrigin(pattern, origin string) bool {
+	i := strings.Index(pattern, "://*.")
+	if i < 0 {
+		return false
+	}
+	prefix := pattern[:i+len("://")]
+	suffix := pattern[i+len("://*"):]
+
+	if !strings.HasPrefix(origin, prefix) || !strings.HasSuffix(origin, suffix) {
+		return false
+	}
+	sub := origin[len(prefix) : len(origin)-len(suffix)]
+	return sub != "" && !strings.ContainsAny(sub, "/:@")
+}
@@ -139,3 +139,125 @@ func TestCORSMiddleware_DefaultValues(t *testing.T) {
 		t.Error("Default Access-Control-Allow-Headers should be set")
 	}
 }
+
+func corsRequest(t *testing.T, config CORSConfig, origin string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	router := gin.New()
+	router.Use(CORSMiddleware(config))
+	router.GET("/test", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	if origin != "" {
+		req.Header.Set("Origin", origin)
+	}
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestCORSMiddleware_WildcardSubdomain(t *testing.T) {
+	config := CORSConfig{
+		AllowOrigins:     []string{"https://*.example.com"},
+		AllowCredentials: true,
+	}
+
+	tests := []struct {
+		origin  string
+		allowed bool
+	}{
+		{"https://app.example.com", true},
+		{"https://eu.app.example.com", true},
+		{"https://example.com", false},
+		{"http://app.example.com", false},
+		{"https://app.example.com:8443", false},
+		{"https://app.example.com.evil.io", false},
+		{"https://evil.io/.example.com", false},
+	}
+
+	for _, tt := range tests {
+		w := corsRequest(t, config, tt.origin)
+		got := w.Header().Get("Access-Control-Allow-Origin")
+		if tt.allowed && got != tt.origin {
+			t.Errorf("Expected %s to be echoed, got '%s'", tt.origin, got)
+		}
+		if !tt.allowed && got != "" {
+			t.Errorf("Expected no Access-Control-Allow-Origin for %s, got '%s'", tt.origin, got)
+		}
+	}
+}
+
+func TestCORSMiddleware_DisallowedOrigin(t *testing.T) {
+	config := CORSConfig{
+		AllowOrigins:     []string{"https://app.example.com"},
+		AllowCredentials: true,
+	}
+
+	w := corsRequest(t, config, "https://evil.io")
+
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("Expected no Access-Control-Allow-Origin, got '%s'", got)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Credentials"); got != "" {
+		t.Errorf("Expected no Access-Control-Allow-Credentials, got '%s'", got)
+	}
+}
+
+func TestCORSMiddleware_CredentialsWithWildcard(t *testing.T) {
+	config := CORSConfig{
+		AllowOrigins:     []string{"*"},
+		AllowCredentials: true,
+	}
+
+	w := corsRequest(t, config, "https://app.example.com")
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "https://app.example.com" {
+		t.Errorf("Expected the request origin instead of '*', got '%s'", got)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Credentials"); got != "" {
+		t.Errorf("Expected no credentials for an origin only '*' admits, got '%s'", got)
+	}
+	if w.Header().Get("Vary") != "Origin" {
+		t.Errorf("Expected Vary: Origin, got '%s'", w.Header().Get("Vary"))
+	}
+
+	w = corsRequest(t, config, "")
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "*" {
+		t.Errorf("Expected '*' without an Origin header, got '%s'", got)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Credentials"); got != "" {
+		t.Errorf("Expected no credentials alongside '*', got '%s'", got)
+	}
+}
+
+func TestCORSMiddleware_CredentialsOnlyForListedOrigins(t *testing.T) {
+	tests := []struct {
+		name        string
+		origins     []string
+		origin      string
+		credentials bool
+	}{
+		{"exact entry next to *", []string{"*", "https://app.example.com"}, "https://app.example.com", true},
+		{"wildcard entry next to *", []string{"*", "https://*.example.com"}, "https://eu.example.com", true},
+		{"unlisted origin next to *", []string{"*", "https://app.example.com"}, "https://evil.io", false},
+		{"no origins configured", nil, "https://evil.io", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := corsRequest(t, CORSConfig{AllowOrigins: tt.origins, AllowCredentials: true}, tt.origin)
+			if got := w.Header().Get("Access-Control-Allow-Origin"); got != tt.origin {
+				t.Errorf("Expected %s to be echoed, got '%s'", tt.origin, got)
+			}
+			got := w.Header().Get("Access-Control-Allow-Credentials")
+			if tt.credentials && got != "true" {
+				t.Errorf("Expected credentials for %s, got '%s'", tt.origin, got)
+			}
+			if !tt.credentials && got != "" {
+				t.Errorf("Expected no credentials for %s, got '%s'", tt.origin, got)
+			}
+		})
+	}
+}
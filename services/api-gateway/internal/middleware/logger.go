@@ -20,7 +20,7 @@ func (lm *LoggingMiddleware) Middleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		start := time.Now()
 		path := c.Request.URL.Path
-		query := c.Request.URL.RawQuery
+		query := lm.logger.Redactor().Query(c.Request.URL.RawQuery)
 
 		requestID := GetRequestID(c)
 
@@ -67,11 +67,13 @@ func (lm *LoggingMiddleware) Middleware() gin.HandlerFunc {
 	}
 }
 
-func RequestLoggingMiddleware(logger *zap.Logger) gin.HandlerFunc {
+// RequestLoggingMiddleware logs the start and end of every request. The
+// redactor, which may be nil, masks sensitive query parameters.
+func RequestLoggingMiddleware(logger *zap.Logger, redactor *util.Redactor) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		start := time.Now()
 		path := c.Request.URL.Path
-		query := c.Request.URL.RawQuery
+		query := redactor.Query(c.Request.URL.RawQuery)
 
 		requestID := GetRequestID(c)
 
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/flexsearch/api-gateway/internal/util"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestRequestLoggingMiddleware_RedactsQuery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	core, logs := observer.New(zap.InfoLevel)
+
+	router := gin.New()
+	router.Use(RequestLoggingMiddleware(zap.New(core), util.NewRedactor([]string{"email"})))
+	router.GET("/api/v1/search", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/search?q=shoes&email=jane%40example.com", nil))
+
+	started := logs.FilterMessage("HTTP request started").All()
+	if len(started) != 1 {
+		t.Fatalf("expected one start entry, got %d", len(started))
+	}
+	query, _ := started[0].ContextMap()["query"].(string)
+	if strings.Contains(query, "jane") {
+		t.Errorf("expected email to be redacted from query, got %q", query)
+	}
+	if query != "q=shoes&email="+util.RedactedValue {
+		t.Errorf("unexpected logged query %q", query)
+	}
+}
+
+func TestRequestLoggingMiddleware_NilRedactor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	core, logs := observer.New(zap.InfoLevel)
+
+	router := gin.New()
+	router.Use(RequestLoggingMiddleware(zap.New(core), nil))
+	router.GET("/api/v1/search", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/search?email=jane", nil))
+
+	started := logs.FilterMessage("HTTP request started").All()
+	if got := started[0].ContextMap()["query"]; got != "email=jane" {
+		t.Errorf("expected query to pass through without a redactor, got %v", got)
+	}
+}
@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/flexsearch/api-gateway/internal/util"
+	"github.com/gin-gonic/gin"
+)
+
+// unmatchedRoute labels requests that hit no route, so arbitrary 404 paths
+// don't each create a series.
+const unmatchedRoute = "unmatched"
+
+// MetricsMiddleware records request counts by method, route and status,
+// request durations and the in-flight gauge. Routes are labelled by their
+// template (c.FullPath()), not the raw URL.
+func MetricsMiddleware(m *util.Metrics) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		m.IncrementInFlight()
+		defer m.DecrementInFlight()
+
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		method := c.Request.Method
+
+		m.IncrementHTTPRequest(method, route, strconv.Itoa(c.Writer.Status()))
+		m.RecordHTTPDuration(method, route, time.Since(start))
+	}
+}
@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/flexsearch/api-gateway/internal/util"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestMetricsMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	reg := prometheus.NewRegistry()
+	metrics := util.NewMetricsWithRegistry("test", reg)
+
+	router := gin.New()
+	router.Use(MetricsMiddleware(metrics))
+	router.GET("/api/v1/documents/:id", func(c *gin.Context) {
+		if c.Param("id") == "missing" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"id": c.Param("id")})
+	})
+
+	for _, path := range []string{"/api/v1/documents/1", "/api/v1/documents/2", "/api/v1/documents/missing", "/nope/abc"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("Failed to gather metrics: %v", err)
+	}
+
+	series := make(map[string]float64)
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			var labels []string
+			for _, label := range metric.GetLabel() {
+				labels = append(labels, label.GetName()+"="+label.GetValue())
+			}
+			key := family.GetName() + "{" + strings.Join(labels, ",") + "}"
+			switch {
+			case metric.GetCounter() != nil:
+				series[key] = metric.GetCounter().GetValue()
+			case metric.GetHistogram() != nil:
+				series[key] = float64(metric.GetHistogram().GetSampleCount())
+			case metric.GetGauge() != nil:
+				series[key] = metric.GetGauge().GetValue()
+			}
+		}
+	}
+
+	expected := map[string]float64{
+		"test_http_requests_total{endpoint=/api/v1/documents/:id,method=GET,status=200}": 2,
+		"test_http_requests_total{endpoint=/api/v1/documents/:id,method=GET,status=404}": 1,
+		"test_http_requests_total{endpoint=unmatched,method=GET,status=404}":             1,
+		"test_http_request_duration_seconds{endpoint=/api/v1/documents/:id,method=GET}":  3,
+		"test_http_request_duration_seconds{endpoint=unmatched,method=GET}":              1,
+		"test_http_requests_in_flight{}":                                                 0,
+	}
+	for key, want := range expected {
+		got, ok := series[key]
+		if !ok {
+			t.Errorf("Expected series %s to exist", key)
+			continue
+		}
+		if got != want {
+			t.Errorf("Expected %s = %v, got %v", key, want, got)
+		}
+	}
+}
@@ -1,8 +1,8 @@
 package middleware
 
 import (
-	"context"
 	"fmt"
+	"math"
 	"net/http"
 	"strings"
 	"time"
@@ -21,6 +21,10 @@ type RateLimitConfig struct {
 	HeaderBased   bool
 	HeaderName    string
 	TierHeader    string
+	// Endpoints overrides the tier limits for individual routes. Keys are
+	// gin route patterns, optionally prefixed with a method, e.g.
+	// "POST /api/v1/documents/batch". Overridden routes get their own bucket.
+	Endpoints map[string]util.TierConfig
 }
 
 func RateLimitMiddleware(limiter *util.RateLimiter, config RateLimitConfig) gin.HandlerFunc {
@@ -33,7 +37,13 @@ func RateLimitMiddleware(limiter *util.RateLimiter, config RateLimitConfig) gin.
 		key := determineRateLimitKey(c, config)
 		tier := determineUserTier(c, config)
 
-		allowed, err := limiter.Allow(c.Request.Context(), key, tier)
+		tierConfig := limiter.TierConfig(tier)
+		if route, override, ok := endpointOverride(c, config); ok {
+			key = fmt.Sprintf("%s:route:%s", key, route)
+			tierConfig = override
+		}
+
+		result, err := limiter.ConsumeWithConfig(c.Request.Context(), key, tierConfig)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{
 				"error":   "Rate limit error",
@@ -43,44 +53,43 @@ func RateLimitMiddleware(limiter *util.RateLimiter, config RateLimitConfig) gin.
 			return
 		}
 
-		if !allowed {
-			tierConfig, exists := limiter.GetConfig().Tiers[tier]
-			if !exists {
-				tierConfig = util.TierConfig{
-					Limit:  limiter.GetConfig().DefaultLimit,
-					Burst:  limiter.GetConfig().DefaultBurst,
-					Window: limiter.GetConfig().DefaultWindow,
-				}
-			}
+		c.Header("X-RateLimit-Limit", fmt.Sprintf("%d", tierConfig.Limit))
+		c.Header("X-RateLimit-Remaining", fmt.Sprintf("%d", result.Remaining))
+		c.Header("X-RateLimit-Reset", getResetTime(tierConfig.Window))
+		c.Header("X-RateLimit-Tier", string(tier))
 
+		if !result.Allowed {
+			retryAfter := retryAfterSeconds(result.RetryAfter)
+			c.Header("Retry-After", fmt.Sprintf("%d", retryAfter))
 			c.JSON(http.StatusTooManyRequests, gin.H{
 				"error":       "Rate limit exceeded",
 				"limit":       tierConfig.Limit,
 				"burst":       tierConfig.Burst,
 				"window":      tierConfig.Window.String(),
 				"tier":        string(tier),
-				"retry_after": tierConfig.Window.Seconds(),
+				"retry_after": retryAfter,
 			})
 			c.Abort()
 			return
 		}
 
-		tierConfig, exists := limiter.GetConfig().Tiers[tier]
-		if !exists {
-			tierConfig = util.TierConfig{
-				Limit:  limiter.GetConfig().DefaultLimit,
-				Burst:  limiter.GetConfig().DefaultBurst,
-				Window: limiter.GetConfig().DefaultWindow,
-			}
-		}
+		c.Next()
+	}
+}
 
-		c.Header("X-RateLimit-Limit", fmt.Sprintf("%d", tierConfig.Limit))
-		c.Header("X-RateLimit-Remaining", fmt.Sprintf("%d", getRemainingTokens(c.Request.Context(), limiter, key, tier)))
-		c.Header("X-RateLimit-Reset", getResetTime(tierConfig.Window))
-		c.Header("X-RateLimit-Tier", string(tier))
+func endpointOverride(c *gin.Context, config RateLimitConfig) (string, util.TierConfig, bool) {
+	if len(config.Endpoints) == 0 || c.FullPath() == "" {
+		return "", util.TierConfig{}, false
+	}
 
-		c.Next()
+	route := c.Request.Method + " " + c.FullPath()
+	if override, ok := config.Endpoints[route]; ok {
+		return route, override, true
+	}
+	if override, ok := config.Endpoints[c.FullPath()]; ok {
+		return c.FullPath(), override, true
 	}
+	return "", util.TierConfig{}, false
 }
 
 func determineRateLimitKey(c *gin.Context, config RateLimitConfig) string {
@@ -103,7 +112,16 @@ func determineRateLimitKey(c *gin.Context, config RateLimitConfig) string {
 	return "global"
 }
 
+// determineUserTier prefers the tier from a validated token over the tier
+// header, which any client can set.
 func determineUserTier(c *gin.Context, config RateLimitConfig) util.RateLimitTier {
+	if userTier := c.GetString("rate_limit_tier"); userTier != "" {
+		tier := util.RateLimitTier(strings.ToLower(userTier))
+		if isValidTier(tier) {
+			return tier
+		}
+	}
+
 	if config.TierHeader != "" {
 		if tierStr := c.GetHeader(config.TierHeader); tierStr != "" {
 			tier := util.RateLimitTier(strings.ToLower(tierStr))
@@ -113,13 +131,6 @@ func determineUserTier(c *gin.Context, config RateLimitConfig) util.RateLimitTie
 		}
 	}
 
-	if userTier := c.GetString("rate_limit_tier"); userTier != "" {
-		tier := util.RateLimitTier(strings.ToLower(userTier))
-		if isValidTier(tier) {
-			return tier
-		}
-	}
-
 	if roles := c.GetStringSlice("user_roles"); len(roles) > 0 {
 		for _, role := range roles {
 			if strings.Contains(strings.ToLower(role), "enterprise") {
@@ -144,34 +155,14 @@ func isValidTier(tier util.RateLimitTier) bool {
 	}
 }
 
-func getRemainingTokens(ctx context.Context, limiter *util.RateLimiter, key string, tier util.RateLimitTier) int {
-	tierConfig, exists := limiter.GetConfig().Tiers[tier]
-	if !exists {
-		tierConfig = util.TierConfig{
-			Burst: limiter.GetConfig().DefaultBurst,
-		}
-	}
-
-	stats, err := limiter.GetStats(ctx, key)
-	if err != nil || !stats["exists"].(bool) {
-		return tierConfig.Burst
-	}
-
-	value, ok := stats["value"].(string)
-	if !ok || value == "" {
-		return tierConfig.Burst
+// retryAfterSeconds rounds up to whole seconds as required by the
+// Retry-After header, never advertising less than one second.
+func retryAfterSeconds(d time.Duration) int {
+	seconds := int(math.Ceil(d.Seconds()))
+	if seconds < 1 {
+		return 1
 	}
-
-	parts := []byte(value)
-	if len(parts) >= 16 {
-		tokens := int(int64(parts[0]) | int64(parts[1])<<8 | int64(parts[2])<<16 | int64(parts[3])<<24)
-		if tokens < 0 {
-			tokens = 0
-		}
-		return tokens
-	}
-
-	return tierConfig.Burst
+	return seconds
 }
 
 func getResetTime(window time.Duration) string {
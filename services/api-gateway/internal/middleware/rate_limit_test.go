@@ -0,0 +1,199 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/flexsearch/api-gateway/internal/util"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+func newTestLimiter(t *testing.T, tiers map[util.RateLimitTier]util.TierConfig) *util.RateLimiter {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	limiterConfig := util.DefaultRateLimitConfig()
+	limiterConfig.Tiers = tiers
+	return util.NewRateLimiter(client, limiterConfig)
+}
+
+func newRateLimitRouter(t *testing.T, tier util.TierConfig) *gin.Engine {
+	t.Helper()
+
+	limiter := newTestLimiter(t, map[util.RateLimitTier]util.TierConfig{util.TierFree: tier})
+
+	router := gin.New()
+	router.Use(RateLimitMiddleware(limiter, RateLimitConfig{
+		Enabled: true,
+		ByIP:    true,
+	}))
+	router.GET("/test", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	})
+
+	return router
+}
+
+func TestRateLimitMiddleware_RemainingDecrements(t *testing.T) {
+	router := newRateLimitRouter(t, util.TierConfig{Limit: 1, Burst: 3, Window: time.Hour})
+
+	for want := 2; want >= 0; want-- {
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+		}
+
+		remaining, err := strconv.Atoi(w.Header().Get("X-RateLimit-Remaining"))
+		if err != nil {
+			t.Fatalf("Invalid X-RateLimit-Remaining header: %v", err)
+		}
+		if remaining != want {
+			t.Errorf("Expected X-RateLimit-Remaining %d, got %d", want, remaining)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusTooManyRequests {
+		t.Errorf("Expected status %d, got %d", http.StatusTooManyRequests, w.Code)
+	}
+	if got := w.Header().Get("X-RateLimit-Remaining"); got != "0" {
+		t.Errorf("Expected X-RateLimit-Remaining 0 when rejected, got %q", got)
+	}
+}
+
+func TestRateLimitMiddleware_RetryAfter(t *testing.T) {
+	router := newRateLimitRouter(t, util.TierConfig{Limit: 6, Burst: 1, Window: time.Minute})
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if w.Header().Get("Retry-After") != "" {
+		t.Error("Expected no Retry-After header on allowed requests")
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/test", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("Expected status %d, got %d", http.StatusTooManyRequests, w.Code)
+	}
+
+	retryAfter, err := strconv.Atoi(w.Header().Get("Retry-After"))
+	if err != nil {
+		t.Fatalf("Invalid Retry-After header: %v", err)
+	}
+	// One token refills every 10s, well inside the one minute window.
+	if retryAfter < 1 || retryAfter > 10 {
+		t.Errorf("Expected Retry-After between 1 and 10 seconds, got %d", retryAfter)
+	}
+
+	var body struct {
+		RetryAfter int `json:"retry_after"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if body.RetryAfter != retryAfter {
+		t.Errorf("Expected body retry_after %d to match header, got %d", retryAfter, body.RetryAfter)
+	}
+}
+
+func TestRateLimitMiddleware_TierFromJWT(t *testing.T) {
+	jwtManager := util.NewJWTManager("test-secret", "test-issuer", 24)
+	limiter := newTestLimiter(t, util.DefaultRateLimitConfig().Tiers)
+
+	router := gin.New()
+	router.Use(AuthMiddleware(jwtManager))
+	router.Use(RateLimitMiddleware(limiter, RateLimitConfig{
+		Enabled:    true,
+		ByUser:     true,
+		TierHeader: "X-RateLimit-Tier",
+	}))
+	router.GET("/test", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	})
+
+	token, err := jwtManager.GenerateTokenWithTier("user123", "testuser", "user", "premium")
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("X-RateLimit-Tier", "enterprise")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if got := w.Header().Get("X-RateLimit-Tier"); got != string(util.TierPremium) {
+		t.Errorf("Expected tier %q from the token, got %q", util.TierPremium, got)
+	}
+	premium := util.DefaultRateLimitConfig().Tiers[util.TierPremium]
+	if got := w.Header().Get("X-RateLimit-Limit"); got != strconv.Itoa(premium.Limit) {
+		t.Errorf("Expected premium limit %d, got %s", premium.Limit, got)
+	}
+}
+
+func TestRateLimitMiddleware_EndpointOverride(t *testing.T) {
+	limiter := newTestLimiter(t, map[util.RateLimitTier]util.TierConfig{
+		util.TierFree: {Limit: 1, Burst: 5, Window: time.Hour},
+	})
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("user_id", "user123")
+		c.Next()
+	})
+	router.Use(RateLimitMiddleware(limiter, RateLimitConfig{
+		Enabled: true,
+		ByUser:  true,
+		Endpoints: map[string]util.TierConfig{
+			"POST /api/v1/documents/batch": {Limit: 1, Burst: 1, Window: time.Hour},
+		},
+	}))
+	ok := func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"status": "ok"}) }
+	router.POST("/api/v1/search", ok)
+	router.POST("/api/v1/documents/batch", ok)
+
+	do := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := do("/api/v1/documents/batch"); w.Code != http.StatusOK {
+		t.Fatalf("Expected first batch request to pass, got %d", w.Code)
+	}
+	if w := do("/api/v1/documents/batch"); w.Code != http.StatusTooManyRequests {
+		t.Errorf("Expected second batch request to be limited, got %d", w.Code)
+	}
+
+	w := do("/api/v1/search")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected search to be unaffected by the batch limit, got %d", w.Code)
+	}
+	if got := w.Header().Get("X-RateLimit-Remaining"); got != "4" {
+		t.Errorf("Expected search to use the tier bucket with 4 remaining, got %s", got)
+	}
+}
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"github.com/flexsearch/api-gateway/internal/util"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
@@ -9,7 +10,7 @@ import (
 func RequestIDMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Check if request ID already exists in headers
-		requestID := c.GetHeader("X-Request-ID")
+		requestID := c.GetHeader(util.RequestIDHeader)
 		if requestID == "" {
 			// Generate new UUID if not provided
 			requestID = uuid.New().String()
@@ -18,11 +19,15 @@ func RequestIDMiddleware() gin.HandlerFunc {
 		// Set request ID in context for use in handlers
 		c.Set("request_id", requestID)
 
+		// Carry it on the request context too, so the coordinator client
+		// can forward it in gRPC metadata
+		c.Request = c.Request.WithContext(util.ContextWithRequestID(c.Request.Context(), requestID))
+
 		// Add request ID to response headers
-		c.Header("X-Request-ID", requestID)
+		c.Header(util.RequestIDHeader, requestID)
 
 		// Also add to request headers for downstream services
-		c.Request.Header.Set("X-Request-ID", requestID)
+		c.Request.Header.Set(util.RequestIDHeader, requestID)
 
 		c.Next()
 	}
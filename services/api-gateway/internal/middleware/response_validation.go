@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"reflect"
@@ -15,11 +17,19 @@ type ValidatableResponse interface {
 	Validate() error
 }
 
+// ResponseFactory returns an empty response value that a captured body is
+// decoded into before it is validated.
+type ResponseFactory func() ValidatableResponse
+
 // ResponseValidationConfig holds configuration for response validation
 type ResponseValidationConfig struct {
 	Enabled         bool
 	ValidateOnError bool  // Whether to validate responses even when status >= 400
 	MaxResponseSize int64 // Maximum response size in bytes
+	// Validators maps "METHOD /route/:pattern", as reported by gin's
+	// FullPath, to the type its response body must decode into. Routes
+	// without an entry are passed through untouched.
+	Validators map[string]ResponseFactory
 }
 
 // DefaultResponseValidationConfig returns default configuration
@@ -31,7 +41,10 @@ func DefaultResponseValidationConfig() ResponseValidationConfig {
 	}
 }
 
-// ResponseValidationMiddleware validates HTTP responses
+// ResponseValidationMiddleware buffers the JSON body of every route with a
+// registered validator, decodes it into the route's response type and
+// validates it. Invalid responses are replaced with a 500 before anything
+// reaches the client.
 func ResponseValidationMiddleware(logger *zap.Logger, config ResponseValidationConfig) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		if !config.Enabled {
@@ -39,71 +52,129 @@ func ResponseValidationMiddleware(logger *zap.Logger, config ResponseValidationC
 			return
 		}
 
+		factory, ok := config.Validators[c.Request.Method+" "+c.FullPath()]
+		if !ok {
+			c.Next()
+			return
+		}
+
 		// Create a custom response writer to capture the response
+		original := c.Writer
 		writer := &responseCaptureWriter{
-			ResponseWriter: c.Writer,
-			body:           make([]byte, 0),
+			ResponseWriter: original,
 			maxSize:        config.MaxResponseSize,
 		}
 		c.Writer = writer
 
 		c.Next()
 
-		// Only validate successful responses unless configured otherwise
-		if c.Writer.Status() >= 400 && !config.ValidateOnError {
+		c.Writer = original
+		status := writer.Status()
+
+		if writer.overflow {
+			logger.Error("Response too large",
+				zap.String("path", c.Request.URL.Path),
+				zap.Int("status", status),
+				zap.Int64("response_size", writer.size),
+				zap.Int64("max_size", config.MaxResponseSize),
+			)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":    "Response too large",
+				"max_size": config.MaxResponseSize,
+			})
 			return
 		}
 
-		// Validate response if it implements ValidatableResponse
-		if response, exists := c.Get("response"); exists {
-			if validatable, ok := response.(ValidatableResponse); ok {
-				if err := validatable.Validate(); err != nil {
-					logger.Error("Response validation failed",
-						zap.String("path", c.Request.URL.Path),
-						zap.Int("status", c.Writer.Status()),
-						zap.Error(err),
-					)
-
-					// Return validation error
-					c.JSON(http.StatusInternalServerError, gin.H{
-						"error":   "Response validation failed",
-						"details": err.Error(),
-					})
-					return
-				}
+		// Only validate successful responses unless configured otherwise
+		if writer.body.Len() > 0 && (status < 400 || config.ValidateOnError) {
+			if err := validateBody(writer.body.Bytes(), factory); err != nil {
+				logger.Error("Response validation failed",
+					zap.String("path", c.Request.URL.Path),
+					zap.Int("status", status),
+					zap.Error(err),
+				)
+
+				// Return validation error
+				c.JSON(http.StatusInternalServerError, gin.H{
+					"error":   "Response validation failed",
+					"details": err.Error(),
+				})
+				return
 			}
 		}
 
-		// Validate response body size
-		if int64(len(writer.body)) > config.MaxResponseSize {
-			logger.Error("Response too large",
-				zap.String("path", c.Request.URL.Path),
-				zap.Int("status", c.Writer.Status()),
-				zap.Int("response_size", len(writer.body)),
-				zap.Int64("max_size", config.MaxResponseSize),
-			)
+		original.WriteHeader(status)
+		if writer.body.Len() > 0 {
+			if _, err := original.Write(writer.body.Bytes()); err != nil {
+				logger.Warn("Failed to write response", zap.Error(err))
+			}
 		}
 	}
 }
 
-// responseCaptureWriter captures the response body for validation
+func validateBody(body []byte, factory ResponseFactory) error {
+	response := factory()
+	if err := json.Unmarshal(body, response); err != nil {
+		return fmt.Errorf("response is not valid JSON for its route: %w", err)
+	}
+	return response.Validate()
+}
+
+// responseCaptureWriter holds back the status and body until the response
+// has been validated. Once the body outgrows maxSize the buffer is dropped
+// and later writes are discarded, so the middleware can answer with a
+// clean error instead of a truncated body.
 type responseCaptureWriter struct {
 	gin.ResponseWriter
-	body    []byte
-	maxSize int64
+	body     bytes.Buffer
+	status   int
+	size     int64
+	maxSize  int64
+	overflow bool
 }
 
-func (w *responseCaptureWriter) Write(data []byte) (int, error) {
-	// Check if adding this data would exceed max size
-	if int64(len(w.body)+len(data)) > w.maxSize {
-		return 0, fmt.Errorf("response size exceeds maximum allowed size of %d bytes", w.maxSize)
+func (w *responseCaptureWriter) WriteHeader(code int) {
+	w.status = code
+}
+
+func (w *responseCaptureWriter) WriteHeaderNow() {
+	if w.status == 0 {
+		w.status = http.StatusOK
 	}
+}
+
+func (w *responseCaptureWriter) Status() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
 
-	// Capture the data
-	w.body = append(w.body, data...)
+func (w *responseCaptureWriter) Size() int {
+	return int(w.size)
+}
+
+func (w *responseCaptureWriter) Written() bool {
+	return w.status != 0 || w.size > 0
+}
+
+func (w *responseCaptureWriter) Write(data []byte) (int, error) {
+	w.WriteHeaderNow()
+	w.size += int64(len(data))
+
+	if w.overflow {
+		return len(data), nil
+	}
+	if w.maxSize > 0 && w.size > w.maxSize {
+		w.overflow = true
+		w.body = bytes.Buffer{}
+		return len(data), nil
+	}
+	return w.body.Write(data)
+}
 
-	// Write to the underlying ResponseWriter
-	return w.ResponseWriter.Write(data)
+func (w *responseCaptureWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
 }
 
 // ValidationError represents a validation error with field information
@@ -0,0 +1,175 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type testValidatedResponse struct {
+	ID    string `json:"id"`
+	Count int    `json:"count"`
+}
+
+func (r *testValidatedResponse) Validate() error {
+	if r.ID == "" {
+		return errors.New("id cannot be empty")
+	}
+	if r.Count < 0 {
+		return errors.New("count cannot be negative")
+	}
+	return nil
+}
+
+func newValidationRouter(respond gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	config := DefaultResponseValidationConfig()
+	config.Validators = map[string]ResponseFactory{
+		"GET /items/:id": func() ValidatableResponse { return &testValidatedResponse{} },
+	}
+
+	router := gin.New()
+	router.Use(ResponseValidationMiddleware(zap.NewNop(), config))
+	router.GET("/items/:id", respond)
+	router.GET("/other", respond)
+	return router
+}
+
+func TestResponseValidationMiddleware_ValidResponse(t *testing.T) {
+	router := newValidationRouter(func(c *gin.Context) {
+		c.Header("X-Item", c.Param("id"))
+		c.JSON(http.StatusCreated, gin.H{"id": c.Param("id"), "count": 3})
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/items/42", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	if w.Header().Get("X-Item") != "42" {
+		t.Errorf("Expected handler headers to be kept, got %v", w.Header())
+	}
+
+	var resp testValidatedResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.ID != "42" || resp.Count != 3 {
+		t.Errorf("Expected the handler body to pass through, got %+v", resp)
+	}
+}
+
+func TestResponseValidationMiddleware_InvalidResponse(t *testing.T) {
+	router := newValidationRouter(func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"id": "", "count": -1})
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/items/42", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("Expected status %d, got %d", http.StatusInternalServerError, w.Code)
+	}
+
+	var resp map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Expected a single JSON error body, got %q: %v", w.Body.String(), err)
+	}
+	if resp["error"] != "Response validation failed" || !strings.Contains(resp["details"].(string), "id cannot be empty") {
+		t.Errorf("Expected a validation error, got %v", resp)
+	}
+}
+
+func TestResponseValidationMiddleware_MalformedBody(t *testing.T) {
+	router := newValidationRouter(func(c *gin.Context) {
+		c.String(http.StatusOK, "not json")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/items/42", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status %d, got %d", http.StatusInternalServerError, w.Code)
+	}
+}
+
+func TestResponseValidationMiddleware_UnregisteredRouteAndErrors(t *testing.T) {
+	router := newValidationRouter(func(c *gin.Context) {
+		if c.FullPath() == "/other" {
+			c.JSON(http.StatusOK, gin.H{"id": ""})
+			return
+		}
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/other", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected unregistered routes to pass through, got %d", w.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/items/42", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound || !strings.Contains(w.Body.String(), "not found") {
+		t.Errorf("Expected error responses to skip validation, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestResponseValidationMiddleware_OversizeResponse(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	config := DefaultResponseValidationConfig()
+	config.MaxResponseSize = 64
+	config.Validators = map[string]ResponseFactory{
+		"GET /items": func() ValidatableResponse { return &testValidatedResponse{} },
+	}
+
+	var writeErrs int
+	router := gin.New()
+	router.Use(ResponseValidationMiddleware(zap.NewNop(), config))
+	router.GET("/items", func(c *gin.Context) {
+		c.Header("Content-Type", "application/json")
+		c.Status(http.StatusOK)
+		chunks := []string{`{"id":"1","count":1,"pad":"`, strings.Repeat("x", 40), strings.Repeat("y", 40), `"}`}
+		for _, chunk := range chunks {
+			if _, err := c.Writer.Write([]byte(chunk)); err != nil {
+				writeErrs++
+			}
+		}
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/items", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if writeErrs != 0 {
+		t.Errorf("Expected handler writes to succeed, got %d errors", writeErrs)
+	}
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("Expected status %d, got %d", http.StatusInternalServerError, w.Code)
+	}
+
+	var resp map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Expected a coherent JSON error, got %q: %v", w.Body.String(), err)
+	}
+	if resp["error"] != "Response too large" {
+		t.Errorf("Expected a response too large error, got %v", resp)
+	}
+	if strings.Contains(w.Body.String(), "xxxx") {
+		t.Errorf("Expected no part of the oversize body to reach the client, got %q", w.Body.String())
+	}
+}
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"github.com/flexsearch/api-gateway/internal/util"
+	"github.com/gin-gonic/gin"
+)
+
+// TenantMiddleware reads the caller's tenant from header, when one is
+// configured. A tenant claim found later by the auth middleware replaces
+// it.
+func TenantMiddleware(header string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if header != "" {
+			if tenant := c.GetHeader(header); tenant != "" {
+				setTenant(c, tenant)
+			}
+		}
+		c.Next()
+	}
+}
+
+// setTenant records tenant for handlers and on the request context, so the
+// coordinator client can forward it in gRPC metadata.
+func setTenant(c *gin.Context, tenant string) {
+	c.Set("tenant", tenant)
+	c.Request = c.Request.WithContext(util.ContextWithTenant(c.Request.Context(), tenant))
+}
+
+// GetTenant returns the caller's tenant, or "" when it has none.
+func GetTenant(c *gin.Context) string {
+	tenant, _ := c.Get("tenant")
+	id, _ := tenant.(string)
+	return id
+}
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/flexsearch/api-gateway/internal/util"
+	"github.com/gin-gonic/gin"
+)
+
+func serveTenant(t *testing.T, header string, jwtManager *util.JWTManager, req *http.Request) (tenant, forwarded string) {
+	t.Helper()
+
+	router := gin.New()
+	router.Use(TenantMiddleware(header))
+	if jwtManager != nil {
+		router.Use(AuthMiddleware(jwtManager))
+	}
+	router.GET("/test", func(c *gin.Context) {
+		tenant = GetTenant(c)
+		forwarded = util.TenantFromContext(c.Request.Context())
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	return tenant, forwarded
+}
+
+func TestTenantMiddleware_ReadsConfiguredHeader(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("X-Tenant-ID", "acme")
+
+	tenant, forwarded := serveTenant(t, "X-Tenant-ID", nil, req)
+	if tenant != "acme" || forwarded != "acme" {
+		t.Errorf("Expected tenant acme, got %q (forwarded %q)", tenant, forwarded)
+	}
+}
+
+func TestTenantMiddleware_IgnoresHeaderUnlessConfigured(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("X-Tenant-ID", "acme")
+
+	if tenant, forwarded := serveTenant(t, "", nil, req); tenant != "" || forwarded != "" {
+		t.Errorf("Expected no tenant, got %q (forwarded %q)", tenant, forwarded)
+	}
+}
+
+func TestTenantMiddleware_ClaimWinsOverHeader(t *testing.T) {
+	jwtManager := util.NewJWTManager("test-secret", "test-issuer", 24)
+	token, err := jwtManager.IssueToken(util.CustomClaims{UserID: "user-1", Tenant: "globex"})
+	if err != nil {
+		t.Fatalf("Failed to issue token: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("X-Tenant-ID", "acme")
+
+	tenant, forwarded := serveTenant(t, "X-Tenant-ID", jwtManager, req)
+	if tenant != "globex" || forwarded != "globex" {
+		t.Errorf("Expected the token's tenant globex, got %q (forwarded %q)", tenant, forwarded)
+	}
+}
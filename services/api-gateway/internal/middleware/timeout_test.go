@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// slowUpstream stands in for a coordinator call that only returns once its
+// context is done.
+type slowUpstream struct {
+	delay     time.Duration
+	cancelled chan error
+}
+
+func (u *slowUpstream) Call(ctx context.Context) error {
+	select {
+	case <-time.After(u.delay):
+		return nil
+	case <-ctx.Done():
+		u.cancelled <- ctx.Err()
+		return ctx.Err()
+	}
+}
+
+func newTimeoutRouter(timeout time.Duration, upstream *slowUpstream) *gin.Engine {
+	router := gin.New()
+	router.Use(TimeoutMiddleware(timeout))
+	router.GET("/test", func(c *gin.Context) {
+		if err := upstream.Call(c.Request.Context()); err != nil {
+			c.JSON(http.StatusRequestTimeout, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	})
+	return router
+}
+
+func TestTimeoutMiddleware_SlowUpstream(t *testing.T) {
+	upstream := &slowUpstream{delay: time.Second, cancelled: make(chan error, 1)}
+	router := newTimeoutRouter(20*time.Millisecond, upstream)
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	w := httptest.NewRecorder()
+	start := time.Now()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusGatewayTimeout {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusGatewayTimeout, w.Code, w.Body.String())
+	}
+	if strings.Contains(w.Body.String(), "deadline exceeded") {
+		t.Errorf("Expected the handler's error body to be dropped, got %s", w.Body.String())
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected the request to end at the deadline, took %v", elapsed)
+	}
+
+	select {
+	case err := <-upstream.cancelled:
+		if err != context.DeadlineExceeded {
+			t.Errorf("Expected the upstream context to hit its deadline, got %v", err)
+		}
+	default:
+		t.Error("Expected the upstream call to be cancelled")
+	}
+}
+
+func TestTimeoutMiddleware_FastUpstream(t *testing.T) {
+	upstream := &slowUpstream{delay: time.Millisecond, cancelled: make(chan error, 1)}
+	router := newTimeoutRouter(time.Second, upstream)
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+}
@@ -2,26 +2,46 @@ package middleware
 
 import (
 	"context"
-	"io"
+	"fmt"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
 	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
 	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
 	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
 	"go.uber.org/zap"
 )
 
+const (
+	ExporterStdout   = "stdout"
+	ExporterOTLPGRPC = "otlp-grpc"
+	ExporterOTLPHTTP = "otlp-http"
+	ExporterNone     = "none"
+)
+
+// otlpTracePath is where OTLP/HTTP collectors accept spans by default.
+const otlpTracePath = "/v1/traces"
+
 type TracingConfig struct {
 	ServiceName string
 	Enabled     bool
 	SampleRate  float64
+	// Exporter is one of the Exporter* constants; empty means none.
+	Exporter string
+	// Endpoint is the collector address for the OTLP exporters.
+	Endpoint string
+	Insecure bool
 }
 
 type TracingMiddleware struct {
@@ -35,10 +55,17 @@ func InitTracing(config *TracingConfig, logger *zap.Logger) (func(context.Contex
 		return func(ctx context.Context) error { return nil }, nil
 	}
 
-	exporter, err := stdouttrace.New(
-		stdouttrace.WithPrettyPrint(),
-		stdouttrace.WithWriter(io.Discard),
-	)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	if config.Exporter == "" || config.Exporter == ExporterNone {
+		otel.SetTracerProvider(noop.NewTracerProvider())
+		return func(ctx context.Context) error { return nil }, nil
+	}
+
+	exporter, err := newSpanExporter(config)
 	if err != nil {
 		return nil, err
 	}
@@ -67,16 +94,72 @@ func InitTracing(config *TracingConfig, logger *zap.Logger) (func(context.Contex
 	)
 
 	otel.SetTracerProvider(tp)
-	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
-		propagation.TraceContext{},
-		propagation.Baggage{},
-	))
+	logger.Info("Tracing enabled",
+		zap.String("exporter", config.Exporter),
+		zap.String("endpoint", config.Endpoint),
+	)
 
 	return func(ctx context.Context) error {
 		return tp.Shutdown(ctx)
 	}, nil
 }
 
+func newSpanExporter(config *TracingConfig) (sdktrace.SpanExporter, error) {
+	switch config.Exporter {
+	case ExporterStdout:
+		return stdouttrace.New(stdouttrace.WithPrettyPrint())
+	case ExporterOTLPGRPC, ExporterOTLPHTTP:
+		if config.Endpoint == "" {
+			return nil, fmt.Errorf("tracing exporter %s requires an endpoint", config.Exporter)
+		}
+		if config.Exporter == ExporterOTLPGRPC {
+			return otlptracegrpc.New(context.Background(), otlpGRPCOptions(config)...)
+		}
+		opts, err := otlpHTTPOptions(config)
+		if err != nil {
+			return nil, err
+		}
+		return otlptracehttp.New(context.Background(), opts...)
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter %q", config.Exporter)
+	}
+}
+
+// otlpGRPCOptions and otlpHTTPOptions accept Endpoint as either host:port
+// or a full URL. A URL's scheme decides whether TLS is used; otherwise
+// Insecure does. An HTTP URL without a path posts to the standard traces
+// path.
+func otlpGRPCOptions(config *TracingConfig) []otlptracegrpc.Option {
+	if strings.Contains(config.Endpoint, "://") {
+		return []otlptracegrpc.Option{otlptracegrpc.WithEndpointURL(config.Endpoint)}
+	}
+	opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(config.Endpoint)}
+	if config.Insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+	return opts
+}
+
+func otlpHTTPOptions(config *TracingConfig) ([]otlptracehttp.Option, error) {
+	if !strings.Contains(config.Endpoint, "://") {
+		opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(config.Endpoint)}
+		if config.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		return opts, nil
+	}
+
+	u, err := url.Parse(config.Endpoint)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid OTLP endpoint %q", config.Endpoint)
+	}
+	opts := []otlptracehttp.Option{otlptracehttp.WithEndpointURL(config.Endpoint)}
+	if u.Path == "" || u.Path == "/" {
+		opts = append(opts, otlptracehttp.WithURLPath(otlpTracePath))
+	}
+	return opts, nil
+}
+
 func NewTracingMiddleware(config *TracingConfig, logger *zap.Logger) *TracingMiddleware {
 	tracer := otel.Tracer(config.ServiceName)
 	return &TracingMiddleware{
@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	"go.uber.org/zap"
+)
+
+func TestNewSpanExporter(t *testing.T) {
+	stdout, err := newSpanExporter(&TracingConfig{Exporter: ExporterStdout})
+	if err != nil {
+		t.Fatalf("Expected stdout exporter, got %v", err)
+	}
+	if _, ok := stdout.(*stdouttrace.Exporter); !ok {
+		t.Errorf("Expected *stdouttrace.Exporter, got %T", stdout)
+	}
+
+	for _, exporter := range []string{ExporterOTLPGRPC, ExporterOTLPHTTP} {
+		got, err := newSpanExporter(&TracingConfig{Exporter: exporter, Endpoint: "localhost:4317", Insecure: true})
+		if err != nil {
+			t.Fatalf("Expected %s exporter, got %v", exporter, err)
+		}
+		otlp, ok := got.(*otlptrace.Exporter)
+		if !ok {
+			t.Fatalf("Expected *otlptrace.Exporter for %s, got %T", exporter, got)
+		}
+		otlp.Shutdown(context.Background())
+	}
+
+	if _, err := newSpanExporter(&TracingConfig{Exporter: ExporterOTLPGRPC}); err == nil {
+		t.Error("Expected an OTLP exporter without an endpoint to fail")
+	}
+	if _, err := newSpanExporter(&TracingConfig{Exporter: ExporterOTLPHTTP, Endpoint: "http://"}); err == nil {
+		t.Error("Expected an OTLP endpoint URL without a host to fail")
+	}
+	if _, err := newSpanExporter(&TracingConfig{Exporter: "jaeger"}); err == nil {
+		t.Error("Expected an unknown exporter to fail")
+	}
+}
+
+func TestInitTracing_NoneInstallsNoopProvider(t *testing.T) {
+	previous := otel.GetTracerProvider()
+	defer otel.SetTracerProvider(previous)
+
+	shutdown, err := InitTracing(&TracingConfig{ServiceName: "test", Enabled: true, Exporter: ExporterNone}, zap.NewNop())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer shutdown(context.Background())
+
+	if _, ok := otel.GetTracerProvider().(noop.TracerProvider); !ok {
+		t.Fatalf("Expected a no-op tracer provider, got %T", otel.GetTracerProvider())
+	}
+	_, span := otel.Tracer("test").Start(context.Background(), "op")
+	defer span.End()
+	if span.IsRecording() {
+		t.Error("Expected spans from the no-op provider not to record")
+	}
+}
+
+func TestOTLPHTTPExporter_ExportSpans(t *testing.T) {
+	var path, contentType string
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		contentType = r.Header.Get("Content-Type")
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	for _, endpoint := range []string{server.URL, strings.TrimPrefix(server.URL, "http://")} {
+		path, contentType, body = "", "", nil
+		exporter, err := newSpanExporter(&TracingConfig{Exporter: ExporterOTLPHTTP, Endpoint: endpoint, Insecure: true})
+		if err != nil {
+			t.Fatalf("Expected exporter for %s, got %v", endpoint, err)
+		}
+		tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+		_, span := tp.Tracer("gateway").Start(context.Background(), "GET /api/v1/search")
+		traceID := span.SpanContext().TraceID()
+		span.End()
+		if err := tp.Shutdown(context.Background()); err != nil {
+			t.Fatalf("Expected clean shutdown, got %v", err)
+		}
+
+		if path != otlpTracePath {
+			t.Errorf("Expected export to %s for %s, got %s", otlpTracePath, endpoint, path)
+		}
+		if contentType != "application/x-protobuf" {
+			t.Errorf("Expected protobuf content type, got %q", contentType)
+		}
+		if !bytes.Contains(body, []byte("GET /api/v1/search")) || !bytes.Contains(body, traceID[:]) {
+			t.Errorf("Expected the exported request to carry the span name and trace ID, got %d bytes", len(body))
+		}
+	}
+}
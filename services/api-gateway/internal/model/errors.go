@@ -0,0 +1,145 @@
+package model
+
+import (
+	"net/http"
+
+	"google.golang.org/grpc/codes"
+)
+
+// ErrorCode is the machine-readable code of an ErrorResponse. Every code
+// has a fixed HTTP status, so clients can branch on either:
+//
+//	INVALID_REQUEST             400  malformed body or parameters
+//	INVALID_DOCUMENTS           400  a batch with per-document errors
+//	INVALID_MAPPINGS            400  index field mappings that don't validate
+//	BATCH_TOO_LARGE             400  more items than the endpoint accepts
+//	OUT_OF_RANGE                416  a page or offset past the end of the results
+//	UNAUTHENTICATED             401  missing or invalid credentials
+//	INVALID_REFRESH_TOKEN       401  refresh token rejected
+//	PERMISSION_DENIED           403  credentials lack the required scope
+//	NOT_FOUND                   404  the requested resource does not exist
+//	DOCUMENT_NOT_FOUND          404
+//	INDEX_NOT_FOUND             404
+//	TASK_NOT_FOUND              404
+//	CONFLICT                    409  the resource already exists or changed
+//	VERSION_CONFLICT            409  a document update lost an optimistic lock
+//	PRECONDITION_FAILED         412  the system is not in a state to do it
+//	RATE_LIMITED                429
+//	INTERNAL_ERROR              500
+//	RESPONSE_VALIDATION_FAILED  500  the coordinator returned a bad response
+//	NOT_IMPLEMENTED             501
+//	UNAVAILABLE                 503  the coordinator is unreachable
+//	TIMEOUT                     504  the coordinator did not answer in time
+type ErrorCode string
+
+const (
+	ErrCodeInvalidRequest           ErrorCode = "INVALID_REQUEST"
+	ErrCodeInvalidDocuments         ErrorCode = "INVALID_DOCUMENTS"
+	ErrCodeInvalidMappings          ErrorCode = "INVALID_MAPPINGS"
+	ErrCodeBatchTooLarge            ErrorCode = "BATCH_TOO_LARGE"
+	ErrCodeOutOfRange               ErrorCode = "OUT_OF_RANGE"
+	ErrCodeUnauthenticated          ErrorCode = "UNAUTHENTICATED"
+	ErrCodeInvalidRefreshToken      ErrorCode = "INVALID_REFRESH_TOKEN"
+	ErrCodePermissionDenied         ErrorCode = "PERMISSION_DENIED"
+	ErrCodeNotFound                 ErrorCode = "NOT_FOUND"
+	ErrCodeDocumentNotFound         ErrorCode = "DOCUMENT_NOT_FOUND"
+	ErrCodeIndexNotFound            ErrorCode = "INDEX_NOT_FOUND"
+	ErrCodeTaskNotFound             ErrorCode = "TASK_NOT_FOUND"
+	ErrCodeConflict                 ErrorCode = "CONFLICT"
+	ErrCodeVersionConflict          ErrorCode = "VERSION_CONFLICT"
+	ErrCodePreconditionFailed       ErrorCode = "PRECONDITION_FAILED"
+	ErrCodeRateLimited              ErrorCode = "RATE_LIMITED"
+	ErrCodeInternal                 ErrorCode = "INTERNAL_ERROR"
+	ErrCodeResponseValidationFailed ErrorCode = "RESPONSE_VALIDATION_FAILED"
+	ErrCodeNotImplemented           ErrorCode = "NOT_IMPLEMENTED"
+	ErrCodeUnavailable              ErrorCode = "UNAVAILABLE"
+	ErrCodeTimeout                  ErrorCode = "TIMEOUT"
+)
+
+type errorCodeInfo struct {
+	status  int
+	message string
+}
+
+var errorCodes = map[ErrorCode]errorCodeInfo{
+	ErrCodeInvalidRequest:           {http.StatusBadRequest, "Invalid request"},
+	ErrCodeInvalidDocuments:         {http.StatusBadRequest, "Invalid documents"},
+	ErrCodeInvalidMappings:          {http.StatusBadRequest, "Invalid mappings"},
+	ErrCodeBatchTooLarge:            {http.StatusBadRequest, "Batch too large"},
+	ErrCodeOutOfRange:               {http.StatusRequestedRangeNotSatisfiable, "Out of range"},
+	ErrCodeUnauthenticated:          {http.StatusUnauthorized, "Unauthenticated"},
+	ErrCodeInvalidRefreshToken:      {http.StatusUnauthorized, "Invalid refresh token"},
+	ErrCodePermissionDenied:         {http.StatusForbidden, "Permission denied"},
+	ErrCodeNotFound:                 {http.StatusNotFound, "Not found"},
+	ErrCodeDocumentNotFound:         {http.StatusNotFound, "Document not found"},
+	ErrCodeIndexNotFound:            {http.StatusNotFound, "Index not found"},
+	ErrCodeTaskNotFound:             {http.StatusNotFound, "Task not found"},
+	ErrCodeConflict:                 {http.StatusConflict, "Conflict"},
+	ErrCodeVersionConflict:          {http.StatusConflict, "Version conflict"},
+	ErrCodePreconditionFailed:       {http.StatusPreconditionFailed, "Precondition failed"},
+	ErrCodeRateLimited:              {http.StatusTooManyRequests, "Rate limit exceeded"},
+	ErrCodeInternal:                 {http.StatusInternalServerError, "Internal server error"},
+	ErrCodeResponseValidationFailed: {http.StatusInternalServerError, "Internal server error"},
+	ErrCodeNotImplemented:           {http.StatusNotImplemented, "Not implemented"},
+	ErrCodeUnavailable:              {http.StatusServiceUnavailable, "Service unavailable"},
+	ErrCodeTimeout:                  {http.StatusGatewayTimeout, "Request timeout"},
+}
+
+// ErrorCodes lists every defined code.
+func ErrorCodes() []ErrorCode {
+	out := make([]ErrorCode, 0, len(errorCodes))
+	for code := range errorCodes {
+		out = append(out, code)
+	}
+	return out
+}
+
+// HTTPStatus is the status the code is always sent with; unknown codes
+// are treated as internal errors.
+func (c ErrorCode) HTTPStatus() int {
+	if info, ok := errorCodes[c]; ok {
+		return info.status
+	}
+	return http.StatusInternalServerError
+}
+
+// Message is the default human-readable message for the code, used when
+// there is no more specific error text.
+func (c ErrorCode) Message() string {
+	if info, ok := errorCodes[c]; ok {
+		return info.message
+	}
+	return errorCodes[ErrCodeInternal].message
+}
+
+// ErrorCodeForGRPC classifies a coordinator gRPC status code. notFound
+// names the missing resource when the call is about a specific one; pass
+// ErrCodeNotFound otherwise.
+func ErrorCodeForGRPC(code codes.Code, notFound ErrorCode) ErrorCode {
+	switch code {
+	case codes.InvalidArgument:
+		return ErrCodeInvalidRequest
+	case codes.OutOfRange:
+		return ErrCodeOutOfRange
+	case codes.Unauthenticated:
+		return ErrCodeUnauthenticated
+	case codes.PermissionDenied:
+		return ErrCodePermissionDenied
+	case codes.NotFound:
+		return notFound
+	case codes.AlreadyExists, codes.Aborted:
+		return ErrCodeConflict
+	case codes.FailedPrecondition:
+		return ErrCodePreconditionFailed
+	case codes.ResourceExhausted:
+		return ErrCodeRateLimited
+	case codes.Unimplemented:
+		return ErrCodeNotImplemented
+	case codes.Unavailable:
+		return ErrCodeUnavailable
+	case codes.DeadlineExceeded, codes.Canceled:
+		return ErrCodeTimeout
+	default:
+		return ErrCodeInternal
+	}
+}
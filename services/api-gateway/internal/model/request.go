@@ -1,25 +1,102 @@
 package model
 
+// MaxMultiSearchRequests caps the number of queries in one msearch batch.
+const MaxMultiSearchRequests = 20
+
+// MaxGetDocuments caps the number of IDs in one mget request.
+const MaxGetDocuments = 100
+
+// Bulk ingest limits: documents are sent to the coordinator in chunks of
+// BulkChunkSize, and one NDJSON line may hold at most MaxBulkLineBytes.
+const (
+	BulkChunkSize    = 100
+	MaxBulkLineBytes = 1 << 20
+)
+
+// Suggest limits: DefaultSuggestLimit applies when no limit is given.
+const (
+	DefaultSuggestLimit = 10
+	MaxSuggestLimit     = 50
+)
+
+// Pagination defaults: DefaultPageSize applies when no page size is given,
+// and DefaultMaxResultWindow bounds how deep page * page_size may reach.
+const (
+	DefaultPageSize        = 10
+	DefaultMaxResultWindow = 10000
+)
+
+// SearchRequest is the body of POST /search. GET /search binds the fields
+// with a form tag from its query string, one index parameter per index.
 type SearchRequest struct {
-	Query     string            `json:"query" binding:"required,min=1,max=100"`
-	Indexes   []string          `json:"indexes"`
-	Page      int               `json:"page" binding:"omitempty,min=1"`
-	PageSize  int               `json:"page_size" binding:"omitempty,min=1,max=100"`
-	Filters   map[string]string `json:"filters"`
-	Fields    []string          `json:"fields"`
-	Highlight bool              `json:"highlight"`
-	SortBy    string            `json:"sort_by"`
-	SortOrder string            `json:"sort_order"`
-	Explain   bool              `json:"explain"`
+	Query     string            `json:"query" form:"query" binding:"required,min=1,max=100"`
+	Indexes   []string          `json:"indexes" form:"index"`
+	Page      int               `json:"page" form:"page" binding:"omitempty,min=1"`
+	PageSize  int               `json:"page_size" form:"page_size" binding:"omitempty,min=1,max=100"`
+	Filters   map[string]string `json:"filters" form:"-"`
+	Fields    []string          `json:"fields" form:"-"`
+	Highlight bool              `json:"highlight" form:"highlight"`
+	SortBy    string            `json:"sort_by" form:"-"`
+	SortOrder string            `json:"sort_order" form:"-"`
+	Explain   bool              `json:"explain" form:"explain"`
+	Facets    []string          `json:"facets" form:"-"`
+	// SearchAfter is the next_cursor of the previous page. It replaces
+	// page and must be sent with the same sort_by and sort_order.
+	SearchAfter string `json:"search_after" form:"search_after"`
+	// DryRun returns only the routing decision that explain adds, without
+	// running the search.
+	DryRun bool `json:"dry_run" form:"dry_run"`
+	// PerEngineLimit is how many candidates each engine is asked for
+	// before fusion. Zero leaves the coordinator's default.
+	PerEngineLimit int `json:"per_engine_limit" form:"per_engine_limit" binding:"omitempty,min=1,max=1000"`
 }
 
 type SearchResponse struct {
-	Results    []SearchResult `json:"results"`
-	Total      int            `json:"total"`
-	Page       int            `json:"page"`
-	PageSize   int            `json:"page_size"`
-	TotalPages int            `json:"total_pages"`
-	TookMs     float64        `json:"took_ms"`
+	Results       []SearchResult           `json:"results"`
+	Total         int                      `json:"total"`
+	Page          int                      `json:"page"`
+	PageSize      int                      `json:"page_size"`
+	TotalPages    int                      `json:"total_pages"`
+	TookMs        float64                  `json:"took_ms"`
+	Degraded      bool                     `json:"degraded,omitempty"`
+	FailedEngines []string                 `json:"failed_engines,omitempty"`
+	Error         string                   `json:"error,omitempty"`
+	Facets        map[string][]FacetBucket `json:"facets,omitempty"`
+	DidYouMean    string                   `json:"did_you_mean,omitempty"`
+	// NextCursor is set when the page is full; pass it as search_after to
+	// fetch the page that follows.
+	NextCursor string `json:"next_cursor,omitempty"`
+	// SkippedEngines were not queried because their circuit breaker was
+	// open.
+	SkippedEngines []string `json:"skipped_engines,omitempty"`
+	// Routing is set when the request asked for explain or dry_run.
+	Routing *RoutingExplain `json:"routing,omitempty"`
+}
+
+// RoutingExplain is how the coordinator routed a query: the strategy, the
+// query signals it matched on, and the engines and weights it chose.
+type RoutingExplain struct {
+	Strategy string             `json:"strategy"`
+	Reasons  []string           `json:"reasons,omitempty"`
+	Engines  []string           `json:"engines"`
+	Weights  map[string]float64 `json:"weights,omitempty"`
+}
+
+// FacetBucket is the number of matching documents holding one value of a
+// facet field.
+type FacetBucket struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+type SuggestResponse struct {
+	Suggestions []string `json:"suggestions"`
+}
+
+// MultiSearchResponse holds one response per query, in request order. A
+// failed query has Error set instead of results.
+type MultiSearchResponse struct {
+	Responses []SearchResponse `json:"responses"`
 }
 
 type SearchResult struct {
@@ -27,6 +104,9 @@ type SearchResult struct {
 	Score      float64           `json:"score"`
 	Fields     map[string]string `json:"fields"`
 	Highlights map[string]string `json:"highlights,omitempty"`
+	// Explain holds the score components behind Score when the request
+	// set explain.
+	Explain map[string]float64 `json:"explain,omitempty"`
 }
 
 type AddDocumentRequest struct {
@@ -46,19 +126,50 @@ type GetDocumentRequest struct {
 }
 
 type DocumentResponse struct {
-	ID     string            `json:"id"`
-	Fields map[string]string `json:"fields"`
-	Score  float64           `json:"score,omitempty"`
+	ID      string            `json:"id"`
+	Fields  map[string]string `json:"fields"`
+	Score   float64           `json:"score,omitempty"`
+	Version int64             `json:"version,omitempty"`
 }
 
+type MultiGetDocumentsRequest struct {
+	IndexID string   `json:"index_id" binding:"required"`
+	IDs     []string `json:"ids" binding:"required"`
+}
+
+// MultiGetDocumentResult is one entry of an mget response. Found is false,
+// and Error set, for IDs the coordinator could not return.
+type MultiGetDocumentResult struct {
+	ID      string            `json:"id"`
+	Found   bool              `json:"found"`
+	Fields  map[string]string `json:"fields,omitempty"`
+	Error   string            `json:"error,omitempty"`
+	Version int64             `json:"version,omitempty"`
+}
+
+type MultiGetDocumentsResponse struct {
+	Documents []MultiGetDocumentResult `json:"documents"`
+}
+
+// UpdateDocumentRequest replaces a document's fields. A non-zero Version
+// must match the stored version or the update fails with 409.
 type UpdateDocumentRequest struct {
 	IndexID string            `json:"index_id" binding:"required"`
 	Fields  map[string]string `json:"fields" binding:"required"`
+	Version int64             `json:"version,omitempty"`
 }
 
 type UpdateDocumentResponse struct {
 	Success bool   `json:"success"`
 	Message string `json:"message,omitempty"`
+	Version int64  `json:"version,omitempty"`
+}
+
+// PatchDocumentRequest merges Fields into an existing document. A null
+// value deletes that field; fields not mentioned are left as they are.
+type PatchDocumentRequest struct {
+	Fields  map[string]*string `json:"fields" binding:"required"`
+	Version int64              `json:"version,omitempty"`
 }
 
 type DeleteDocumentRequest struct {
@@ -71,23 +182,84 @@ type DeleteDocumentResponse struct {
 	Message string `json:"message,omitempty"`
 }
 
+// RequiredDocumentFields must be present and non-blank in every batch
+// document.
+var RequiredDocumentFields = []string{"id"}
+
+// BatchDocumentsRequest carries documents for one index. With Partial set,
+// invalid documents are reported in the response and the rest are still
+// written; otherwise any invalid document rejects the whole batch.
 type BatchDocumentsRequest struct {
 	IndexID   string              `json:"index_id" binding:"required"`
 	Documents []map[string]string `json:"documents" binding:"required,min=1,max=100"`
 	Refresh   bool                `json:"refresh"`
+	Partial   bool                `json:"partial"`
 }
 
 type BatchDocumentsResponse struct {
+	SuccessCount int             `json:"success_count"`
+	FailureCount int             `json:"failure_count"`
+	Errors       []string        `json:"errors,omitempty"`
+	Rejected     []DocumentError `json:"rejected,omitempty"`
+}
+
+// BulkChunkAck is the coordinator's acknowledgment of one bulk chunk.
+type BulkChunkAck struct {
+	Chunk        int      `json:"chunk"`
 	SuccessCount int      `json:"success_count"`
 	FailureCount int      `json:"failure_count"`
 	Errors       []string `json:"errors,omitempty"`
 }
 
+// BulkIngestResponse totals a streamed NDJSON ingest. Lines rejected by the
+// gateway count as failures and are listed in Errors.
+type BulkIngestResponse struct {
+	SuccessCount int            `json:"success_count"`
+	FailureCount int            `json:"failure_count"`
+	Chunks       []BulkChunkAck `json:"chunks"`
+	Errors       []string       `json:"errors,omitempty"`
+}
+
+// DocumentError explains why the document at Index of a batch was rejected.
+type DocumentError struct {
+	Index int    `json:"index"`
+	Error string `json:"error"`
+}
+
+// BatchValidationErrorResponse is the 400 body for a batch with invalid
+// documents.
+type BatchValidationErrorResponse struct {
+	ErrorResponse
+	Documents []DocumentError `json:"documents"`
+}
+
+// ValidationErrorResponse is the 400 body for a request whose fields failed
+// Validate.
+type ValidationErrorResponse struct {
+	ErrorResponse
+	Fields []FieldError `json:"fields"`
+}
+
 type CreateIndexRequest struct {
-	Name      string            `json:"name" binding:"required,min=1,max=100"`
-	IndexType string            `json:"index_type" binding:"required"`
-	Fields    []string          `json:"fields"`
-	Options   map[string]string `json:"options"`
+	Name      string                  `json:"name" binding:"required,min=1,max=100"`
+	IndexType string                  `json:"index_type" binding:"required"`
+	Fields    []string                `json:"fields"`
+	Options   map[string]string       `json:"options"`
+	Mappings  map[string]FieldMapping `json:"mappings,omitempty"`
+}
+
+// FieldTypes and Analyzers list the values a FieldMapping may use.
+var (
+	FieldTypes = []string{"text", "keyword", "integer", "float", "boolean", "date", "vector"}
+	Analyzers  = []string{"standard", "simple", "whitespace", "keyword", "english"}
+)
+
+// FieldMapping declares how one index field is analyzed and scored.
+// Analyzer only applies to text fields; a zero Boost means 1.
+type FieldMapping struct {
+	Type     string  `json:"type"`
+	Analyzer string  `json:"analyzer,omitempty"`
+	Boost    float64 `json:"boost,omitempty"`
 }
 
 type CreateIndexResponse struct {
@@ -144,13 +316,79 @@ type RebuildIndexResponse struct {
 	TaskID  string `json:"task_id,omitempty"`
 }
 
+// TaskStatusResponse reports the progress of an async task such as a
+// rebuild. State is one of running, completed or failed.
+type TaskStatusResponse struct {
+	ID         string  `json:"id"`
+	Type       string  `json:"type"`
+	State      string  `json:"state"`
+	Percent    float64 `json:"percent"`
+	Error      string  `json:"error,omitempty"`
+	StartedAt  string  `json:"started_at"`
+	FinishedAt string  `json:"finished_at,omitempty"`
+}
+
+// IndexStatsResponse aggregates the stats each engine reports for an index.
+// Partial is set when some engines lack the index or could not be reached;
+// Notes says which.
+type IndexStatsResponse struct {
+	IndexID       string             `json:"index_id"`
+	DocumentCount int64              `json:"document_count"`
+	IndexSize     int64              `json:"index_size"`
+	LastUpdated   string             `json:"last_updated,omitempty"`
+	Engines       []EngineIndexStats `json:"engines,omitempty"`
+	Partial       bool               `json:"partial"`
+	Notes         []string           `json:"notes,omitempty"`
+}
+
+type EngineIndexStats struct {
+	Engine        string `json:"engine"`
+	DocumentCount int64  `json:"document_count"`
+	IndexSize     int64  `json:"index_size"`
+	LastUpdated   string `json:"last_updated,omitempty"`
+}
+
+// ReconcileResponse reports how each engine's document count compares with
+// the coordinator's document store for one index.
+type ReconcileResponse struct {
+	IndexID       string           `json:"index_id"`
+	Expected      int64            `json:"expected"`
+	Counts        map[string]int64 `json:"counts"`
+	Consistent    bool             `json:"consistent"`
+	Mismatched    []string         `json:"mismatched,omitempty"`
+	Unreachable   []string         `json:"unreachable,omitempty"`
+	RebuildTaskID string           `json:"rebuild_task_id,omitempty"`
+}
+
 type ErrorResponse struct {
-	Code    string `json:"code"`
-	Message string `json:"message"`
-	Details string `json:"details,omitempty"`
+	Code    ErrorCode `json:"code"`
+	Message string    `json:"message"`
+	Details string    `json:"details,omitempty"`
+	// RequestID echoes the X-Request-ID of the failed request.
+	RequestID string `json:"request_id,omitempty"`
 }
 
 type SuccessResponse struct {
 	Message string      `json:"message"`
 	Data    interface{} `json:"data,omitempty"`
 }
+
+type RateLimitResetRequest struct {
+	Pattern string `json:"pattern,omitempty"`
+	UserID  string `json:"user_id,omitempty"`
+}
+
+type RateLimitResetResponse struct {
+	Patterns []string `json:"patterns"`
+	Reset    int      `json:"reset"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type TokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
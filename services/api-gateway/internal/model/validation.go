@@ -2,9 +2,66 @@ package model
 
 import (
 	"fmt"
+	"regexp"
+	"sort"
 	"strings"
 )
 
+// indexNamePattern matches the names an index can be created with.
+var indexNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,99}$`)
+
+// FieldError names a request field that failed validation.
+type FieldError struct {
+	Field string `json:"field"`
+	Error string `json:"error"`
+}
+
+// FieldErrors is returned by request Validate methods, one entry per
+// invalid field.
+type FieldErrors []FieldError
+
+func (e FieldErrors) Error() string {
+	parts := make([]string, len(e))
+	for i, fe := range e {
+		parts[i] = fe.Field + ": " + fe.Error
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Validate checks the semantics the binding tags can't express. It returns
+// FieldErrors listing every problem, or nil.
+func (r *SearchRequest) Validate() error {
+	var errs FieldErrors
+
+	switch strings.ToLower(r.SortOrder) {
+	case "", "asc", "desc":
+	default:
+		errs = append(errs, FieldError{Field: "sort_order", Error: fmt.Sprintf("must be asc or desc, got %q", r.SortOrder)})
+	}
+
+	for i, name := range r.Indexes {
+		if !indexNamePattern.MatchString(name) {
+			errs = append(errs, FieldError{Field: fmt.Sprintf("indexes[%d]", i), Error: fmt.Sprintf("malformed index name %q", name)})
+		}
+	}
+
+	if r.SearchAfter != "" && r.Page > 1 {
+		errs = append(errs, FieldError{Field: "page", Error: "cannot be combined with search_after"})
+	}
+
+	for key := range r.Filters {
+		if strings.TrimSpace(key) == "" {
+			errs = append(errs, FieldError{Field: "filters", Error: "filter key cannot be empty"})
+			break
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
 // Validate implements ValidatableResponse for SearchResponse
 func (r *SearchResponse) Validate() error {
 	if r.Total < 0 {
@@ -154,6 +211,74 @@ func (r *BatchDocumentsResponse) Validate() error {
 	return nil
 }
 
+// ValidateBatchDocuments checks each batch document before it is sent to the
+// coordinator, returning one DocumentError per invalid document in order.
+func ValidateBatchDocuments(docs []map[string]string) []DocumentError {
+	var errs []DocumentError
+	for i, doc := range docs {
+		if err := ValidateBatchDocument(doc); err != nil {
+			errs = append(errs, DocumentError{Index: i, Error: err.Error()})
+		}
+	}
+	return errs
+}
+
+// ValidateBatchDocument reports why doc cannot be written, or nil.
+func ValidateBatchDocument(doc map[string]string) error {
+	if len(doc) == 0 {
+		return fmt.Errorf("document is empty")
+	}
+
+	for _, field := range RequiredDocumentFields {
+		if strings.TrimSpace(doc[field]) == "" {
+			return fmt.Errorf("missing required field %q", field)
+		}
+	}
+
+	return nil
+}
+
+// ValidateMappings checks the field mappings of a create index request.
+func ValidateMappings(mappings map[string]FieldMapping) error {
+	fields := make([]string, 0, len(mappings))
+	for field := range mappings {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	for _, field := range fields {
+		mapping := mappings[field]
+		if strings.TrimSpace(field) == "" {
+			return fmt.Errorf("field name cannot be empty")
+		}
+		if !contains(FieldTypes, mapping.Type) {
+			return fmt.Errorf("field %s: unknown type %q", field, mapping.Type)
+		}
+		if mapping.Analyzer != "" {
+			if mapping.Type != "text" {
+				return fmt.Errorf("field %s: analyzer only applies to text fields", field)
+			}
+			if !contains(Analyzers, mapping.Analyzer) {
+				return fmt.Errorf("field %s: unknown analyzer %q", field, mapping.Analyzer)
+			}
+		}
+		if mapping.Boost < 0 {
+			return fmt.Errorf("field %s: boost cannot be negative", field)
+		}
+	}
+
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
 // Validate implements ValidatableResponse for CreateIndexResponse
 func (r *CreateIndexResponse) Validate() error {
 	if r.ID == "" {
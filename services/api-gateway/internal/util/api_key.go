@@ -0,0 +1,173 @@
+package util
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const apiKeySecretPrefix = "fsk_"
+
+var (
+	ErrAPIKeyNotFound = errors.New("api key not found")
+	ErrAPIKeyRevoked  = errors.New("api key revoked")
+	ErrAPIKeyExpired  = errors.New("api key expired")
+)
+
+// APIKey describes a static credential for machine clients. The secret is
+// never stored; keys are looked up by its SHA-256 hash.
+type APIKey struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Role      string    `json:"role,omitempty"`
+	Tier      string    `json:"tier,omitempty"`
+	Tenant    string    `json:"tenant,omitempty"`
+	Scopes    []string  `json:"scopes,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	// ExpiresAt is optional; a zero value never expires.
+	ExpiresAt time.Time `json:"expires_at,omitempty"`
+	Revoked   bool      `json:"revoked,omitempty"`
+}
+
+// APIKeyValidator resolves a presented secret to its key. It returns
+// ErrAPIKeyNotFound, ErrAPIKeyRevoked or ErrAPIKeyExpired for keys that
+// must be refused.
+type APIKeyValidator interface {
+	Validate(ctx context.Context, secret string) (*APIKey, error)
+}
+
+type RedisAPIKeyStore struct {
+	client *redis.Client
+	prefix string
+}
+
+func NewRedisAPIKeyStore(client *redis.Client, prefix string) *RedisAPIKeyStore {
+	if prefix == "" {
+		prefix = "apikey"
+	}
+	return &RedisAPIKeyStore{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+// Create stores key under a new random secret and returns the secret, which
+// is the only time it is available.
+func (s *RedisAPIKeyStore) Create(ctx context.Context, key APIKey) (string, *APIKey, error) {
+	secret, err := randomHex(24)
+	if err != nil {
+		return "", nil, err
+	}
+	secret = apiKeySecretPrefix + secret
+
+	if key.ID == "" {
+		if key.ID, err = randomHex(8); err != nil {
+			return "", nil, err
+		}
+	}
+	if key.CreatedAt.IsZero() {
+		key.CreatedAt = time.Now()
+	}
+	key.Revoked = false
+
+	data, err := json.Marshal(key)
+	if err != nil {
+		return "", nil, err
+	}
+
+	hash := hashAPIKey(secret)
+	created, err := s.client.SetNX(ctx, s.idKey(key.ID), hash, 0).Result()
+	if err != nil {
+		return "", nil, err
+	}
+	if !created {
+		return "", nil, fmt.Errorf("api key %s already exists", key.ID)
+	}
+	if err := s.client.Set(ctx, s.hashKey(hash), data, 0).Err(); err != nil {
+		return "", nil, err
+	}
+
+	return secret, &key, nil
+}
+
+func (s *RedisAPIKeyStore) Validate(ctx context.Context, secret string) (*APIKey, error) {
+	key, err := s.load(ctx, hashAPIKey(secret))
+	if err != nil {
+		return nil, err
+	}
+	if key.Revoked {
+		return nil, ErrAPIKeyRevoked
+	}
+	if !key.ExpiresAt.IsZero() && !time.Now().Before(key.ExpiresAt) {
+		return nil, ErrAPIKeyExpired
+	}
+	return key, nil
+}
+
+// Revoke marks the key as revoked. The record is kept so revoked keys are
+// reported as such rather than as unknown.
+func (s *RedisAPIKeyStore) Revoke(ctx context.Context, id string) error {
+	hash, err := s.client.Get(ctx, s.idKey(id)).Result()
+	if errors.Is(err, redis.Nil) {
+		return ErrAPIKeyNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	key, err := s.load(ctx, hash)
+	if err != nil {
+		return err
+	}
+	key.Revoked = true
+
+	data, err := json.Marshal(key)
+	if err != nil {
+		return err
+	}
+	return s.client.Set(ctx, s.hashKey(hash), data, 0).Err()
+}
+
+func (s *RedisAPIKeyStore) load(ctx context.Context, hash string) (*APIKey, error) {
+	data, err := s.client.Get(ctx, s.hashKey(hash)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrAPIKeyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var key APIKey
+	if err := json.Unmarshal(data, &key); err != nil {
+		return nil, fmt.Errorf("corrupt api key record: %w", err)
+	}
+	return &key, nil
+}
+
+func (s *RedisAPIKeyStore) hashKey(hash string) string {
+	return fmt.Sprintf("%s:hash:%s", s.prefix, hash)
+}
+
+func (s *RedisAPIKeyStore) idKey(id string) string {
+	return fmt.Sprintf("%s:id:%s", s.prefix, id)
+}
+
+func hashAPIKey(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,88 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func newTestAPIKeyStore(t *testing.T) *RedisAPIKeyStore {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	return NewRedisAPIKeyStore(client, "")
+}
+
+func TestRedisAPIKeyStore_Validate(t *testing.T) {
+	store := newTestAPIKeyStore(t)
+	ctx := context.Background()
+
+	secret, created, err := store.Create(ctx, APIKey{
+		Name:   "indexer",
+		Tier:   string(TierPremium),
+		Scopes: []string{ScopeDocumentsDelete},
+	})
+	if err != nil {
+		t.Fatalf("Create returned error: %v", err)
+	}
+
+	key, err := store.Validate(ctx, secret)
+	if err != nil {
+		t.Fatalf("Expected a valid key, got %v", err)
+	}
+	if key.ID != created.ID || key.Tier != string(TierPremium) || len(key.Scopes) != 1 {
+		t.Errorf("Expected the stored key back, got %+v", key)
+	}
+
+	if _, err := store.Validate(ctx, secret+"x"); !errors.Is(err, ErrAPIKeyNotFound) {
+		t.Errorf("Expected ErrAPIKeyNotFound for an unknown secret, got %v", err)
+	}
+}
+
+func TestRedisAPIKeyStore_Revoke(t *testing.T) {
+	store := newTestAPIKeyStore(t)
+	ctx := context.Background()
+
+	secret, key, err := store.Create(ctx, APIKey{Name: "indexer"})
+	if err != nil {
+		t.Fatalf("Create returned error: %v", err)
+	}
+	if err := store.Revoke(ctx, key.ID); err != nil {
+		t.Fatalf("Revoke returned error: %v", err)
+	}
+
+	if _, err := store.Validate(ctx, secret); !errors.Is(err, ErrAPIKeyRevoked) {
+		t.Errorf("Expected ErrAPIKeyRevoked, got %v", err)
+	}
+	if err := store.Revoke(ctx, "missing"); !errors.Is(err, ErrAPIKeyNotFound) {
+		t.Errorf("Expected ErrAPIKeyNotFound revoking an unknown key, got %v", err)
+	}
+}
+
+func TestRedisAPIKeyStore_Expiry(t *testing.T) {
+	store := newTestAPIKeyStore(t)
+	ctx := context.Background()
+
+	expired, _, err := store.Create(ctx, APIKey{Name: "old", ExpiresAt: time.Now().Add(-time.Minute)})
+	if err != nil {
+		t.Fatalf("Create returned error: %v", err)
+	}
+	if _, err := store.Validate(ctx, expired); !errors.Is(err, ErrAPIKeyExpired) {
+		t.Errorf("Expected ErrAPIKeyExpired, got %v", err)
+	}
+
+	current, _, err := store.Create(ctx, APIKey{Name: "new", ExpiresAt: time.Now().Add(time.Hour)})
+	if err != nil {
+		t.Fatalf("Create returned error: %v", err)
+	}
+	if _, err := store.Validate(ctx, current); err != nil {
+		t.Errorf("Expected an unexpired key to validate, got %v", err)
+	}
+}
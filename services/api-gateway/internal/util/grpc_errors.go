@@ -1,16 +1,21 @@
 package util
 
 import (
+	"encoding/json"
 	"net/http"
 
+	"github.com/flexsearch/api-gateway/internal/model"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
 )
 
 // GRPCError represents a gRPC error with HTTP status mapping
 type GRPCError struct {
-	Code       codes.Code
-	Message    string
+	Code    codes.Code
+	Message string
+	// Details is a JSON array of the detail payloads attached to the
+	// status, or empty when there are none.
 	Details    string
 	HTTPStatus int
 }
@@ -26,7 +31,7 @@ func ConvertGRPCError(err error) *GRPCError {
 		return &GRPCError{
 			Code:       st.Code(),
 			Message:    st.Message(),
-			Details:    st.Message(),
+			Details:    marshalStatusDetails(st),
 			HTTPStatus: httpStatus,
 		}
 	}
@@ -34,51 +39,45 @@ func ConvertGRPCError(err error) *GRPCError {
 	return &GRPCError{
 		Code:       codes.Unknown,
 		Message:    err.Error(),
-		Details:    err.Error(),
 		HTTPStatus: http.StatusInternalServerError,
 	}
 }
 
-// mapGRPCCodeToHTTP maps gRPC status codes to HTTP status codes
+// marshalStatusDetails serializes the status detail payloads, such as
+// errdetails.BadRequest field violations, keeping their type URLs so
+// clients can tell them apart.
+func marshalStatusDetails(st *status.Status) string {
+	details := st.Proto().GetDetails()
+	if len(details) == 0 {
+		return ""
+	}
+
+	encoded := make([]json.RawMessage, 0, len(details))
+	for _, detail := range details {
+		data, err := protojson.Marshal(detail)
+		if err != nil {
+			continue
+		}
+		encoded = append(encoded, data)
+	}
+	if len(encoded) == 0 {
+		return ""
+	}
+
+	data, err := json.Marshal(encoded)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// mapGRPCCodeToHTTP maps gRPC status codes to HTTP status codes, the
+// status of the error code the handlers answer a failed call with.
 func mapGRPCCodeToHTTP(code codes.Code) int {
-	switch code {
-	case codes.OK:
+	if code == codes.OK {
 		return http.StatusOK
-	case codes.Canceled:
-		return http.StatusRequestTimeout
-	case codes.Unknown:
-		return http.StatusInternalServerError
-	case codes.InvalidArgument:
-		return http.StatusBadRequest
-	case codes.DeadlineExceeded:
-		return http.StatusRequestTimeout
-	case codes.NotFound:
-		return http.StatusNotFound
-	case codes.AlreadyExists:
-		return http.StatusConflict
-	case codes.PermissionDenied:
-		return http.StatusForbidden
-	case codes.ResourceExhausted:
-		return http.StatusTooManyRequests
-	case codes.FailedPrecondition:
-		return http.StatusPreconditionFailed
-	case codes.Aborted:
-		return http.StatusConflict
-	case codes.OutOfRange:
-		return http.StatusRequestedRangeNotSatisfiable
-	case codes.Unimplemented:
-		return http.StatusNotImplemented
-	case codes.Internal:
-		return http.StatusInternalServerError
-	case codes.Unavailable:
-		return http.StatusServiceUnavailable
-	case codes.DataLoss:
-		return http.StatusInternalServerError
-	case codes.Unauthenticated:
-		return http.StatusUnauthorized
-	default:
-		return http.StatusInternalServerError
 	}
+	return model.ErrorCodeForGRPC(code, model.ErrCodeNotFound).HTTPStatus()
 }
 
 // IsRetryable determines if the error is retryable
@@ -0,0 +1,98 @@
+package util
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestConvertGRPCError_CodeMapping(t *testing.T) {
+	tests := []struct {
+		code codes.Code
+		want int
+	}{
+		{codes.NotFound, http.StatusNotFound},
+		{codes.InvalidArgument, http.StatusBadRequest},
+		{codes.PermissionDenied, http.StatusForbidden},
+		{codes.Unavailable, http.StatusServiceUnavailable},
+		{codes.Unauthenticated, http.StatusUnauthorized},
+		{codes.ResourceExhausted, http.StatusTooManyRequests},
+		{codes.AlreadyExists, http.StatusConflict},
+		{codes.Aborted, http.StatusConflict},
+		{codes.DeadlineExceeded, http.StatusGatewayTimeout},
+		{codes.Canceled, http.StatusGatewayTimeout},
+		{codes.OutOfRange, http.StatusRequestedRangeNotSatisfiable},
+		{codes.Internal, http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		grpcErr := ConvertGRPCError(status.Error(tt.code, "failure"))
+		if grpcErr.HTTPStatus != tt.want {
+			t.Errorf("Expected %s to map to %d, got %d", tt.code, tt.want, grpcErr.HTTPStatus)
+		}
+		if grpcErr.Code != tt.code || grpcErr.Message != "failure" {
+			t.Errorf("Unexpected conversion for %s: %+v", tt.code, grpcErr)
+		}
+		if grpcErr.Details != "" {
+			t.Errorf("Expected no details for %s, got %q", tt.code, grpcErr.Details)
+		}
+	}
+}
+
+func TestConvertGRPCError_Details(t *testing.T) {
+	st, err := status.New(codes.InvalidArgument, "invalid search request").WithDetails(
+		&errdetails.BadRequest{
+			FieldViolations: []*errdetails.BadRequest_FieldViolation{
+				{Field: "page_size", Description: "must be at most 100"},
+				{Field: "query", Description: "must not be empty"},
+			},
+		},
+		&errdetails.RetryInfo{},
+	)
+	if err != nil {
+		t.Fatalf("Failed to attach details: %v", err)
+	}
+
+	grpcErr := ConvertGRPCError(st.Err())
+	if grpcErr.HTTPStatus != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, grpcErr.HTTPStatus)
+	}
+
+	var details []struct {
+		Type            string `json:"@type"`
+		FieldViolations []struct {
+			Field       string `json:"field"`
+			Description string `json:"description"`
+		} `json:"fieldViolations"`
+	}
+	if err := json.Unmarshal([]byte(grpcErr.Details), &details); err != nil {
+		t.Fatalf("Expected details to be a JSON array, got %q: %v", grpcErr.Details, err)
+	}
+	if len(details) != 2 {
+		t.Fatalf("Expected 2 details, got %d", len(details))
+	}
+	if details[0].Type != "type.googleapis.com/google.rpc.BadRequest" {
+		t.Errorf("Unexpected detail type %q", details[0].Type)
+	}
+	if len(details[0].FieldViolations) != 2 || details[0].FieldViolations[0].Field != "page_size" {
+		t.Errorf("Unexpected field violations: %+v", details[0].FieldViolations)
+	}
+	if details[1].Type != "type.googleapis.com/google.rpc.RetryInfo" {
+		t.Errorf("Unexpected detail type %q", details[1].Type)
+	}
+}
+
+func TestConvertGRPCError_NonStatus(t *testing.T) {
+	if ConvertGRPCError(nil) != nil {
+		t.Error("Expected nil for nil error")
+	}
+
+	grpcErr := ConvertGRPCError(http.ErrServerClosed)
+	if grpcErr.Code != codes.Unknown || grpcErr.HTTPStatus != http.StatusInternalServerError {
+		t.Errorf("Unexpected conversion: %+v", grpcErr)
+	}
+}
@@ -1,52 +1,233 @@
 package util
 
 import (
+	"context"
+	"crypto/rsa"
 	"fmt"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// Token types. Tokens issued before types were introduced carry no type and
+// are treated as access tokens.
+const (
+	TokenTypeAccess  = "access"
+	TokenTypeRefresh = "refresh"
+)
+
+// defaultRefreshExpiration is the refresh token lifetime in hours.
+const defaultRefreshExpiration = 7 * 24
+
+// Scopes required by destructive routes.
+const (
+	ScopeIndexesDelete   = "indexes:delete"
+	ScopeDocumentsDelete = "documents:delete"
 )
 
 type CustomClaims struct {
-	UserID   string `json:"user_id"`
-	Username string `json:"username"`
-	Role     string `json:"role"`
+	UserID   string   `json:"user_id"`
+	Username string   `json:"username"`
+	Role     string   `json:"role"`
+	Tier     string   `json:"tier,omitempty"`
+	Tenant   string   `json:"tenant,omitempty"`
+	Scopes   []string `json:"scopes,omitempty"`
+	Type     string   `json:"typ,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// TokenBlacklist records revoked token IDs until the tokens would have
+// expired anyway.
+type TokenBlacklist interface {
+	Revoke(ctx context.Context, tokenID string, expiresAt time.Time) error
+	IsRevoked(ctx context.Context, tokenID string) (bool, error)
+}
+
+type RedisTokenBlacklist struct {
+	client *redis.Client
+	prefix string
+}
+
+func NewRedisTokenBlacklist(client *redis.Client, prefix string) *RedisTokenBlacklist {
+	if prefix == "" {
+		prefix = "jwt:revoked"
+	}
+	return &RedisTokenBlacklist{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+func (b *RedisTokenBlacklist) Revoke(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+	return b.client.Set(ctx, b.key(tokenID), 1, ttl).Err()
+}
+
+func (b *RedisTokenBlacklist) IsRevoked(ctx context.Context, tokenID string) (bool, error) {
+	n, err := b.client.Exists(ctx, b.key(tokenID)).Result()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+func (b *RedisTokenBlacklist) key(tokenID string) string {
+	return fmt.Sprintf("%s:%s", b.prefix, tokenID)
+}
+
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+)
+
+type JWTManagerConfig struct {
+	// Algorithm is AlgorithmHS256 (the default) or AlgorithmRS256.
+	Algorithm string
+	Secret    string
+	// PrivateKey signs RS256 tokens. Managers that only validate tokens
+	// can leave it nil and set PublicKey.
+	PrivateKey *rsa.PrivateKey
+	PublicKey  *rsa.PublicKey
+	Issuer     string
+	Expiration int
+	// RefreshExpiration is the refresh token lifetime in hours, seven days
+	// by default.
+	RefreshExpiration int
+}
+
 type JWTManager struct {
-	secretKey  []byte
-	issuer     string
-	expiration int
+	method            jwt.SigningMethod
+	signKey           interface{}
+	verifyKey         interface{}
+	issuer            string
+	expiration        int
+	refreshExpiration int
+	blacklist         TokenBlacklist
 }
 
 func NewJWTManager(secret, issuer string, expiration int) *JWTManager {
 	return &JWTManager{
-		secretKey:  []byte(secret),
-		issuer:     issuer,
-		expiration: expiration,
+		method:            jwt.SigningMethodHS256,
+		signKey:           []byte(secret),
+		verifyKey:         []byte(secret),
+		issuer:            issuer,
+		expiration:        expiration,
+		refreshExpiration: defaultRefreshExpiration,
 	}
 }
 
+func NewJWTManagerWithConfig(config JWTManagerConfig) (*JWTManager, error) {
+	refreshExpiration := config.RefreshExpiration
+	if refreshExpiration <= 0 {
+		refreshExpiration = defaultRefreshExpiration
+	}
+
+	switch config.Algorithm {
+	case "", AlgorithmHS256:
+		if config.Secret == "" {
+			return nil, fmt.Errorf("HS256 requires a secret")
+		}
+		manager := NewJWTManager(config.Secret, config.Issuer, config.Expiration)
+		manager.refreshExpiration = refreshExpiration
+		return manager, nil
+	case AlgorithmRS256:
+		publicKey := config.PublicKey
+		if publicKey == nil && config.PrivateKey != nil {
+			publicKey = &config.PrivateKey.PublicKey
+		}
+		if publicKey == nil {
+			return nil, fmt.Errorf("RS256 requires a public or private key")
+		}
+
+		manager := &JWTManager{
+			method:            jwt.SigningMethodRS256,
+			verifyKey:         publicKey,
+			issuer:            config.Issuer,
+			expiration:        config.Expiration,
+			refreshExpiration: refreshExpiration,
+		}
+		if config.PrivateKey != nil {
+			manager.signKey = config.PrivateKey
+		}
+		return manager, nil
+	default:
+		return nil, fmt.Errorf("unsupported JWT algorithm: %s", config.Algorithm)
+	}
+}
+
+// SetBlacklist enables token revocation. Without a blacklist RevokeToken
+// fails and ValidateToken does not check revocation.
+func (j *JWTManager) SetBlacklist(blacklist TokenBlacklist) {
+	j.blacklist = blacklist
+}
+
 func (j *JWTManager) GenerateToken(userID, username, role string) (string, error) {
-	now := time.Now()
-	expirationTime := now.Add(time.Duration(j.expiration) * time.Hour)
+	return j.GenerateTokenWithTier(userID, username, role, "")
+}
 
-	claims := CustomClaims{
+// GenerateTokenWithTier issues a token carrying the caller's rate limit tier.
+func (j *JWTManager) GenerateTokenWithTier(userID, username, role, tier string) (string, error) {
+	return j.IssueToken(CustomClaims{
 		UserID:   userID,
 		Username: username,
 		Role:     role,
-		RegisteredClaims: jwt.RegisteredClaims{
-			Issuer:    j.issuer,
-			Subject:   userID,
-			ExpiresAt: jwt.NewNumericDate(expirationTime),
-			IssuedAt:  jwt.NewNumericDate(now),
-			NotBefore: jwt.NewNumericDate(now),
-		},
+		Tier:     tier,
+	})
+}
+
+// IssueToken signs an access token for the identity fields of claims, such
+// as scopes. The type and registered claims are always set by the manager.
+func (j *JWTManager) IssueToken(claims CustomClaims) (string, error) {
+	return j.issue(claims, TokenTypeAccess, j.expiration)
+}
+
+// GenerateRefreshToken signs a long-lived refresh token for claims. It can
+// only be exchanged for access tokens, never used to authenticate requests.
+func (j *JWTManager) GenerateRefreshToken(claims CustomClaims) (string, error) {
+	return j.issue(claims, TokenTypeRefresh, j.refreshExpiration)
+}
+
+// ExchangeRefreshToken mints a fresh access token from a valid refresh
+// token, even when the previous access token has expired.
+func (j *JWTManager) ExchangeRefreshToken(refresh string) (string, error) {
+	claims, err := j.validate(context.Background(), refresh, TokenTypeRefresh)
+	if err != nil {
+		return "", err
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString(j.secretKey)
+	return j.IssueToken(*claims)
+}
+
+// AccessTokenLifetime is how long newly issued access tokens stay valid.
+func (j *JWTManager) AccessTokenLifetime() time.Duration {
+	return time.Duration(j.expiration) * time.Hour
+}
+
+func (j *JWTManager) issue(claims CustomClaims, tokenType string, expiration int) (string, error) {
+	now := time.Now()
+	expirationTime := now.Add(time.Duration(expiration) * time.Hour)
+
+	claims.Type = tokenType
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		ID:        uuid.NewString(),
+		Issuer:    j.issuer,
+		Subject:   claims.UserID,
+		ExpiresAt: jwt.NewNumericDate(expirationTime),
+		IssuedAt:  jwt.NewNumericDate(now),
+		NotBefore: jwt.NewNumericDate(now),
+	}
+
+	if j.signKey == nil {
+		return "", fmt.Errorf("failed to generate token: no signing key configured")
+	}
+
+	token := jwt.NewWithClaims(j.method, claims)
+	tokenString, err := token.SignedString(j.signKey)
 	if err != nil {
 		return "", fmt.Errorf("failed to generate token: %w", err)
 	}
@@ -55,11 +236,67 @@ func (j *JWTManager) GenerateToken(userID, username, role string) (string, error
 }
 
 func (j *JWTManager) ValidateToken(tokenString string) (*CustomClaims, error) {
+	return j.ValidateTokenContext(context.Background(), tokenString)
+}
+
+// ValidateTokenContext validates an access token and, when a blacklist is
+// set, rejects tokens that have been revoked.
+func (j *JWTManager) ValidateTokenContext(ctx context.Context, tokenString string) (*CustomClaims, error) {
+	return j.validate(ctx, tokenString, TokenTypeAccess)
+}
+
+func (j *JWTManager) validate(ctx context.Context, tokenString, tokenType string) (*CustomClaims, error) {
+	claims, err := j.parseToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	if actual := claims.Type; actual != tokenType && !(actual == "" && tokenType == TokenTypeAccess) {
+		return nil, fmt.Errorf("expected %s token", tokenType)
+	}
+
+	if j.blacklist != nil && claims.ID != "" {
+		revoked, err := j.blacklist.IsRevoked(ctx, claims.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check token revocation: %w", err)
+		}
+		if revoked {
+			return nil, fmt.Errorf("token has been revoked")
+		}
+	}
+
+	return claims, nil
+}
+
+// RevokeToken blacklists the token until its expiry.
+func (j *JWTManager) RevokeToken(ctx context.Context, tokenString string) error {
+	if j.blacklist == nil {
+		return fmt.Errorf("token revocation is not configured")
+	}
+
+	claims, err := j.parseToken(tokenString)
+	if err != nil {
+		return err
+	}
+	if claims.ID == "" {
+		return fmt.Errorf("token has no ID and cannot be revoked")
+	}
+	if claims.ExpiresAt == nil {
+		return fmt.Errorf("token has no expiry and cannot be revoked")
+	}
+
+	if err := j.blacklist.Revoke(ctx, claims.ID, claims.ExpiresAt.Time); err != nil {
+		return fmt.Errorf("failed to revoke token: %w", err)
+	}
+	return nil
+}
+
+func (j *JWTManager) parseToken(tokenString string) (*CustomClaims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &CustomClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		if token.Method.Alg() != j.method.Alg() {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
-		return j.secretKey, nil
+		return j.verifyKey, nil
 	})
 
 	if err != nil {
@@ -79,5 +316,5 @@ func (j *JWTManager) RefreshToken(tokenString string) (string, error) {
 		return "", err
 	}
 
-	return j.GenerateToken(claims.UserID, claims.Username, claims.Role)
+	return j.IssueToken(*claims)
 }
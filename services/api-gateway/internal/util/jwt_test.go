@@ -1,8 +1,14 @@
 package util
 
 import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
 	"testing"
 	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
 )
 
 func TestJWTManager_GenerateToken(t *testing.T) {
@@ -149,3 +155,267 @@ func TestJWTManager_InvalidTokenFormat(t *testing.T) {
 		}
 	}
 }
+
+func TestJWTManager_TierClaim(t *testing.T) {
+	jwtManager := NewJWTManager("test-secret-key", "test-issuer", 24)
+
+	token, err := jwtManager.GenerateTokenWithTier("user123", "testuser", "user", "premium")
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+
+	claims, err := jwtManager.ValidateToken(token)
+	if err != nil {
+		t.Fatalf("Failed to validate token: %v", err)
+	}
+	if claims.Tier != "premium" {
+		t.Errorf("Expected Tier 'premium', got '%s'", claims.Tier)
+	}
+
+	refreshed, err := jwtManager.RefreshToken(token)
+	if err != nil {
+		t.Fatalf("Failed to refresh token: %v", err)
+	}
+	claims, err = jwtManager.ValidateToken(refreshed)
+	if err != nil {
+		t.Fatalf("Failed to validate refreshed token: %v", err)
+	}
+	if claims.Tier != "premium" {
+		t.Errorf("Expected refreshed token to keep tier 'premium', got '%s'", claims.Tier)
+	}
+}
+
+func newTestBlacklist(t *testing.T) (*RedisTokenBlacklist, *miniredis.Miniredis) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	return NewRedisTokenBlacklist(client, ""), mr
+}
+
+func TestJWTManager_RevokeToken(t *testing.T) {
+	jwtManager := NewJWTManager("test-secret-key", "test-issuer", 1)
+	blacklist, mr := newTestBlacklist(t)
+	jwtManager.SetBlacklist(blacklist)
+	ctx := context.Background()
+
+	token, err := jwtManager.GenerateToken("user123", "testuser", "admin")
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+	other, err := jwtManager.GenerateToken("user123", "testuser", "admin")
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+
+	claims, err := jwtManager.ValidateTokenContext(ctx, token)
+	if err != nil {
+		t.Fatalf("Failed to validate token: %v", err)
+	}
+	if claims.ID == "" {
+		t.Fatal("Expected token to carry a jti claim")
+	}
+
+	if err := jwtManager.RevokeToken(ctx, token); err != nil {
+		t.Fatalf("Failed to revoke token: %v", err)
+	}
+
+	if _, err := jwtManager.ValidateTokenContext(ctx, token); err == nil {
+		t.Error("Expected revoked token to fail validation")
+	}
+	if _, err := jwtManager.ValidateTokenContext(ctx, other); err != nil {
+		t.Errorf("Expected other tokens for the same user to stay valid, got %v", err)
+	}
+
+	key := "jwt:revoked:" + claims.ID
+	if ttl := mr.TTL(key); ttl <= 59*time.Minute || ttl > time.Hour {
+		t.Errorf("Expected blacklist entry to expire with the token in about 1h, got %v", ttl)
+	}
+
+	mr.FastForward(time.Hour + time.Second)
+	if mr.Exists(key) {
+		t.Error("Expected blacklist entry to expire once the token has expired")
+	}
+}
+
+func TestJWTManager_RevokeTokenWithoutBlacklist(t *testing.T) {
+	jwtManager := NewJWTManager("test-secret-key", "test-issuer", 1)
+
+	token, _ := jwtManager.GenerateToken("user123", "testuser", "admin")
+	if err := jwtManager.RevokeToken(context.Background(), token); err == nil {
+		t.Error("Expected error revoking without a blacklist")
+	}
+}
+
+func newTestRSAKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate RSA key: %v", err)
+	}
+	return key
+}
+
+func TestJWTManager_RS256(t *testing.T) {
+	key := newTestRSAKey(t)
+
+	signer, err := NewJWTManagerWithConfig(JWTManagerConfig{
+		Algorithm:  AlgorithmRS256,
+		PrivateKey: key,
+		Issuer:     "test-issuer",
+		Expiration: 24,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create RS256 manager: %v", err)
+	}
+
+	token, err := signer.GenerateToken("user123", "testuser", "admin")
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+
+	verifier, err := NewJWTManagerWithConfig(JWTManagerConfig{
+		Algorithm: AlgorithmRS256,
+		PublicKey: &key.PublicKey,
+		Issuer:    "test-issuer",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create RS256 verifier: %v", err)
+	}
+
+	claims, err := verifier.ValidateToken(token)
+	if err != nil {
+		t.Fatalf("Failed to validate token with public key: %v", err)
+	}
+	if claims.UserID != "user123" {
+		t.Errorf("Expected UserID 'user123', got '%s'", claims.UserID)
+	}
+
+	if _, err := verifier.GenerateToken("user123", "testuser", "admin"); err == nil {
+		t.Error("Expected a verify-only manager to refuse to sign tokens")
+	}
+
+	otherKey := newTestRSAKey(t)
+	other, _ := NewJWTManagerWithConfig(JWTManagerConfig{Algorithm: AlgorithmRS256, PublicKey: &otherKey.PublicKey})
+	if _, err := other.ValidateToken(token); err == nil {
+		t.Error("Expected validation with a different public key to fail")
+	}
+}
+
+func TestJWTManager_RejectsMismatchedAlgorithm(t *testing.T) {
+	key := newTestRSAKey(t)
+	rsManager, _ := NewJWTManagerWithConfig(JWTManagerConfig{Algorithm: AlgorithmRS256, PrivateKey: key, Expiration: 24})
+	hsManager := NewJWTManager("test-secret-key", "test-issuer", 24)
+
+	rsToken, err := rsManager.GenerateToken("user123", "testuser", "admin")
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+	if _, err := hsManager.ValidateToken(rsToken); err == nil {
+		t.Error("Expected HS256 manager to reject an RS256 token")
+	}
+
+	hsToken, err := hsManager.GenerateToken("user123", "testuser", "admin")
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+	if _, err := rsManager.ValidateToken(hsToken); err == nil {
+		t.Error("Expected RS256 manager to reject an HS256 token")
+	}
+}
+
+func TestNewJWTManagerWithConfig_Invalid(t *testing.T) {
+	tests := []JWTManagerConfig{
+		{Algorithm: AlgorithmHS256},
+		{Algorithm: AlgorithmRS256},
+		{Algorithm: "none", Secret: "secret"},
+	}
+
+	for _, config := range tests {
+		if _, err := NewJWTManagerWithConfig(config); err == nil {
+			t.Errorf("Expected error for config %+v", config)
+		}
+	}
+
+	manager, err := NewJWTManagerWithConfig(JWTManagerConfig{Secret: "test-secret-key", Expiration: 24})
+	if err != nil {
+		t.Fatalf("Expected HS256 by default, got %v", err)
+	}
+	token, _ := manager.GenerateToken("user123", "testuser", "admin")
+	if _, err := NewJWTManager("test-secret-key", "", 24).ValidateToken(token); err != nil {
+		t.Errorf("Expected default manager to be compatible with NewJWTManager, got %v", err)
+	}
+}
+
+func TestJWTManager_ScopesClaim(t *testing.T) {
+	jwtManager := NewJWTManager("test-secret-key", "test-issuer", 24)
+
+	token, err := jwtManager.IssueToken(CustomClaims{UserID: "user123", Scopes: []string{ScopeIndexesDelete}})
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+
+	refreshed, err := jwtManager.RefreshToken(token)
+	if err != nil {
+		t.Fatalf("Failed to refresh token: %v", err)
+	}
+
+	claims, err := jwtManager.ValidateToken(refreshed)
+	if err != nil {
+		t.Fatalf("Failed to validate token: %v", err)
+	}
+	if len(claims.Scopes) != 1 || claims.Scopes[0] != ScopeIndexesDelete {
+		t.Errorf("Expected scopes to survive refresh, got %v", claims.Scopes)
+	}
+	if claims.Subject != "user123" {
+		t.Errorf("Expected subject 'user123', got '%s'", claims.Subject)
+	}
+}
+
+func TestJWTManager_ExchangeRefreshToken(t *testing.T) {
+	jwtManager := NewJWTManager("test-secret-key", "test-issuer", 1)
+
+	refresh, err := jwtManager.GenerateRefreshToken(CustomClaims{UserID: "user123", Role: "admin", Scopes: []string{ScopeIndexesDelete}})
+	if err != nil {
+		t.Fatalf("Failed to generate refresh token: %v", err)
+	}
+
+	if _, err := jwtManager.ValidateToken(refresh); err == nil {
+		t.Error("Expected a refresh token to be rejected as an access token")
+	}
+
+	access, err := jwtManager.ExchangeRefreshToken(refresh)
+	if err != nil {
+		t.Fatalf("Failed to exchange refresh token: %v", err)
+	}
+
+	claims, err := jwtManager.ValidateToken(access)
+	if err != nil {
+		t.Fatalf("Failed to validate exchanged access token: %v", err)
+	}
+	if claims.Type != TokenTypeAccess || claims.UserID != "user123" || claims.Role != "admin" {
+		t.Errorf("Unexpected access token claims: %+v", claims)
+	}
+	if len(claims.Scopes) != 1 || claims.Scopes[0] != ScopeIndexesDelete {
+		t.Errorf("Expected scopes to carry over, got %v", claims.Scopes)
+	}
+	if ttl := time.Until(claims.ExpiresAt.Time); ttl > time.Hour {
+		t.Errorf("Expected access token lifetime, got %v", ttl)
+	}
+}
+
+func TestJWTManager_ExchangeRejectsAccessToken(t *testing.T) {
+	jwtManager := NewJWTManager("test-secret-key", "test-issuer", 1)
+
+	access, err := jwtManager.GenerateToken("user123", "testuser", "admin")
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+
+	if _, err := jwtManager.ExchangeRefreshToken(access); err == nil {
+		t.Error("Expected an access token to be rejected as a refresh token")
+	}
+}
@@ -11,6 +11,8 @@ type Logger struct {
 	sugar *zap.SugaredLogger
 	mu    sync.RWMutex
 	level zapcore.Level
+	// redactor masks sensitive fields; nil unless WithRedaction was used.
+	redactor *Redactor
 }
 
 var (
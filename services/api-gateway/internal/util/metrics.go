@@ -1,10 +1,12 @@
 package util
 
 import (
-	"github.com/prometheus/client_golang/prometheus"
-	"github.com/prometheus/client_golang/prometheus/promauto"
+	"strings"
 	"sync"
 	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 )
 
 type Metrics struct {
@@ -20,8 +22,15 @@ type Metrics struct {
 }
 
 func NewMetrics(namespace string) *Metrics {
+	return NewMetricsWithRegistry(namespace, prometheus.DefaultRegisterer)
+}
+
+// NewMetricsWithRegistry registers the collectors with reg instead of the
+// default registry, so tests can inspect them in isolation.
+func NewMetricsWithRegistry(namespace string, reg prometheus.Registerer) *Metrics {
+	factory := promauto.With(reg)
 	m := &Metrics{
-		httpRequestsTotal: promauto.NewCounterVec(
+		httpRequestsTotal: factory.NewCounterVec(
 			prometheus.CounterOpts{
 				Namespace: namespace,
 				Name:      "http_requests_total",
@@ -29,7 +38,7 @@ func NewMetrics(namespace string) *Metrics {
 			},
 			[]string{"method", "endpoint", "status"},
 		),
-		httpRequestsDuration: promauto.NewHistogramVec(
+		httpRequestsDuration: factory.NewHistogramVec(
 			prometheus.HistogramOpts{
 				Namespace: namespace,
 				Name:      "http_request_duration_seconds",
@@ -38,14 +47,14 @@ func NewMetrics(namespace string) *Metrics {
 			},
 			[]string{"method", "endpoint"},
 		),
-		httpRequestsInFlight: promauto.NewGauge(
+		httpRequestsInFlight: factory.NewGauge(
 			prometheus.GaugeOpts{
 				Namespace: namespace,
 				Name:      "http_requests_in_flight",
 				Help:      "Number of HTTP requests currently being processed",
 			},
 		),
-		searchLatency: promauto.NewHistogramVec(
+		searchLatency: factory.NewHistogramVec(
 			prometheus.HistogramOpts{
 				Namespace: namespace,
 				Name:      "search_latency_seconds",
@@ -54,7 +63,7 @@ func NewMetrics(namespace string) *Metrics {
 			},
 			[]string{"index"},
 		),
-		documentOperations: promauto.NewCounterVec(
+		documentOperations: factory.NewCounterVec(
 			prometheus.CounterOpts{
 				Namespace: namespace,
 				Name:      "document_operations_total",
@@ -62,7 +71,7 @@ func NewMetrics(namespace string) *Metrics {
 			},
 			[]string{"operation", "status"},
 		),
-		indexOperations: promauto.NewCounterVec(
+		indexOperations: factory.NewCounterVec(
 			prometheus.CounterOpts{
 				Namespace: namespace,
 				Name:      "index_operations_total",
@@ -70,7 +79,7 @@ func NewMetrics(namespace string) *Metrics {
 			},
 			[]string{"operation", "status"},
 		),
-		errorCounter: promauto.NewCounterVec(
+		errorCounter: factory.NewCounterVec(
 			prometheus.CounterOpts{
 				Namespace: namespace,
 				Name:      "errors_total",
@@ -105,7 +114,29 @@ func (m *Metrics) IncrementCounter(name string, labels []string) {
 		if len(labels) >= 2 {
 			m.errorCounter.WithLabelValues(labels[0], labels[1]).Inc()
 		}
+	// Handlers report outcomes as <kind>_success_total and <kind>_errors_total
+	// with an "operation:<name>" label; both land in the operations counter
+	// under the matching status. Attempts are already counted per route by
+	// http_requests_total.
+	case "document_success_total":
+		m.documentOperations.WithLabelValues(labelValue(labels, "operation"), "success").Inc()
+	case "document_errors_total":
+		m.documentOperations.WithLabelValues(labelValue(labels, "operation"), "error").Inc()
+	case "index_success_total":
+		m.indexOperations.WithLabelValues(labelValue(labels, "operation"), "success").Inc()
+	case "index_errors_total":
+		m.indexOperations.WithLabelValues(labelValue(labels, "operation"), "error").Inc()
+	}
+}
+
+// labelValue returns the value of a "key:value" label, or "unknown".
+func labelValue(labels []string, key string) string {
+	for _, label := range labels {
+		if k, v, ok := strings.Cut(label, ":"); ok && k == key {
+			return v
+		}
 	}
+	return "unknown"
 }
 
 func (m *Metrics) RecordHistogram(name string, value float64, labels []string) {
@@ -0,0 +1,100 @@
+package util
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// gatherSeries flattens reg into "name{k=v,...}" keys mapped to the counter
+// value, or the sample count for histograms.
+func gatherSeries(t *testing.T, reg *prometheus.Registry) map[string]float64 {
+	t.Helper()
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("Failed to gather metrics: %v", err)
+	}
+
+	series := make(map[string]float64)
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			var labels []string
+			for _, label := range metric.GetLabel() {
+				labels = append(labels, label.GetName()+"="+label.GetValue())
+			}
+			key := family.GetName() + "{" + strings.Join(labels, ",") + "}"
+			switch {
+			case metric.GetCounter() != nil:
+				series[key] = metric.GetCounter().GetValue()
+			case metric.GetHistogram() != nil:
+				series[key] = float64(metric.GetHistogram().GetSampleCount())
+			}
+		}
+	}
+	return series
+}
+
+func TestMetrics_IncrementIndexOperation(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := NewMetricsWithRegistry("test", reg)
+
+	m.IncrementIndexOperation("create", "success")
+	m.IncrementIndexOperation("create", "success")
+	m.IncrementIndexOperation("delete", "error")
+
+	series := gatherSeries(t, reg)
+	if got := series["test_index_operations_total{operation=create,status=success}"]; got != 2 {
+		t.Errorf("Expected 2 successful creates, got %v", got)
+	}
+	if got := series["test_index_operations_total{operation=delete,status=error}"]; got != 1 {
+		t.Errorf("Expected 1 failed delete, got %v", got)
+	}
+}
+
+func TestMetrics_IncrementCounter(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := NewMetricsWithRegistry("test", reg)
+
+	m.IncrementCounter("index_success_total", []string{"operation:stats"})
+	m.IncrementCounter("index_errors_total", []string{"operation:stats"})
+	m.IncrementCounter("document_success_total", []string{"operation:get"})
+	m.IncrementCounter("document_errors_total", []string{})
+	m.IncrementCounter("http_requests_total", []string{"GET", "/health", "200"})
+	m.IncrementCounter("no_such_metric", []string{"x"})
+
+	series := gatherSeries(t, reg)
+	expected := map[string]float64{
+		"test_index_operations_total{operation=stats,status=success}":      1,
+		"test_index_operations_total{operation=stats,status=error}":        1,
+		"test_document_operations_total{operation=get,status=success}":     1,
+		"test_document_operations_total{operation=unknown,status=error}":   1,
+		"test_http_requests_total{endpoint=/health,method=GET,status=200}": 1,
+	}
+	for key, want := range expected {
+		if got := series[key]; got != want {
+			t.Errorf("Expected %s = %v, got %v", key, want, got)
+		}
+	}
+	if len(series) != len(expected) {
+		t.Errorf("Expected %d series, got %v", len(expected), series)
+	}
+}
+
+func TestMetrics_RecordHistogram(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := NewMetricsWithRegistry("test", reg)
+
+	m.RecordHistogram("search_latency_seconds", 0.05, []string{"articles"})
+	m.RecordHistogram("http_request_duration_seconds", 0.01, []string{"POST", "/api/v1/search"})
+	m.RecordHistogram("search_latency_seconds", 0.05, []string{})
+
+	series := gatherSeries(t, reg)
+	if got := series["test_search_latency_seconds{index=articles}"]; got != 1 {
+		t.Errorf("Expected one search latency observation, got %v", got)
+	}
+	if got := series["test_http_request_duration_seconds{endpoint=/api/v1/search,method=POST}"]; got != 1 {
+		t.Errorf("Expected one request duration observation, got %v", got)
+	}
+}
@@ -3,7 +3,10 @@ package util
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -18,8 +21,14 @@ const (
 	TierEnterprise RateLimitTier = "enterprise"
 )
 
+const (
+	AlgorithmTokenBucket   = "token_bucket"
+	AlgorithmSlidingWindow = "sliding_window"
+)
+
 type RateLimitConfig struct {
 	Enabled       bool
+	Algorithm     string
 	DefaultLimit  int
 	DefaultBurst  int
 	DefaultWindow time.Duration
@@ -38,6 +47,7 @@ type TierConfig struct {
 func DefaultRateLimitConfig() RateLimitConfig {
 	return RateLimitConfig{
 		Enabled:       true,
+		Algorithm:     AlgorithmTokenBucket,
 		DefaultLimit:  100,
 		DefaultBurst:  20,
 		DefaultWindow: time.Minute,
@@ -72,6 +82,8 @@ func DefaultRateLimitConfig() RateLimitConfig {
 type RateLimiter struct {
 	redis  *redis.Client
 	config RateLimitConfig
+	now    func() time.Time
+	seq    uint64
 	mu     sync.RWMutex
 }
 
@@ -79,83 +91,221 @@ func NewRateLimiter(redisClient *redis.Client, config RateLimitConfig) *RateLimi
 	return &RateLimiter{
 		redis:  redisClient,
 		config: config,
+		now:    time.Now,
 	}
 }
 
+// RateLimitResult describes the outcome of consuming a token.
+type RateLimitResult struct {
+	Allowed    bool
+	Remaining  int
+	RetryAfter time.Duration
+}
+
 func (rl *RateLimiter) Allow(ctx context.Context, key string, tier RateLimitTier) (bool, error) {
-	if !rl.config.Enabled {
-		return true, nil
+	result, err := rl.Consume(ctx, key, tier)
+	if err != nil {
+		return false, err
 	}
+	return result.Allowed, nil
+}
 
-	tierConfig, exists := rl.config.Tiers[tier]
-	if !exists {
-		tierConfig = TierConfig{
-			Limit:  rl.config.DefaultLimit,
-			Burst:  rl.config.DefaultBurst,
-			Window: rl.config.DefaultWindow,
-		}
+// Consume takes a token for key and reports the tokens left in the bucket.
+func (rl *RateLimiter) Consume(ctx context.Context, key string, tier RateLimitTier) (RateLimitResult, error) {
+	return rl.ConsumeWithConfig(ctx, key, rl.TierConfig(tier))
+}
+
+// ConsumeWithConfig takes a token for key using explicit limits, e.g. an
+// endpoint override, instead of the limits of a tier.
+func (rl *RateLimiter) ConsumeWithConfig(ctx context.Context, key string, tierConfig TierConfig) (RateLimitResult, error) {
+	if !rl.config.Enabled {
+		return RateLimitResult{Allowed: true, Remaining: tierConfig.Burst}, nil
 	}
 
+	if rl.config.Algorithm == AlgorithmSlidingWindow {
+		return rl.allowSlidingWindow(ctx, key, tierConfig)
+	}
 	return rl.allowRequest(ctx, key, tierConfig)
 }
 
-func (rl *RateLimiter) allowRequest(ctx context.Context, key string, config TierConfig) (bool, error) {
-	bucketKey := fmt.Sprintf("%s:bucket:%s", rl.config.RedisPrefix, key)
+// TierConfig returns the limits for tier, falling back to the defaults.
+func (rl *RateLimiter) TierConfig(tier RateLimitTier) TierConfig {
+	if tierConfig, exists := rl.config.Tiers[tier]; exists {
+		return tierConfig
+	}
+	return TierConfig{
+		Limit:  rl.config.DefaultLimit,
+		Burst:  rl.config.DefaultBurst,
+		Window: rl.config.DefaultWindow,
+	}
+}
 
-	pipe := rl.redis.Pipeline()
-	getCmd := pipe.Get(ctx, bucketKey)
+// TokenBucket is the stored state of a single rate limit bucket.
+type TokenBucket struct {
+	Tokens     int
+	LastRefill time.Time
+}
 
-	_, err := pipe.Exec(ctx)
-	if err != nil && err != redis.Nil {
-		return false, err
+// Encode returns the stored form of the bucket, "<tokens>:<last refill unix
+// millis>". tokenBucketScript reads and writes the same layout.
+func (b TokenBucket) Encode() string {
+	return fmt.Sprintf("%d:%d", b.Tokens, b.LastRefill.UnixMilli())
+}
+
+func DecodeTokenBucket(value string) (TokenBucket, error) {
+	tokensStr, refillStr, ok := strings.Cut(value, ":")
+	if !ok {
+		return TokenBucket{}, fmt.Errorf("invalid token bucket %q", value)
 	}
 
-	var tokens int
-	var lastRefill time.Time
+	tokens, err := strconv.Atoi(tokensStr)
+	if err != nil || tokens < 0 {
+		return TokenBucket{}, fmt.Errorf("invalid token count in bucket %q", value)
+	}
 
-	if err == redis.Nil {
-		tokens = config.Burst
-		lastRefill = time.Now()
-	} else {
-		value, _ := getCmd.Result()
-		if value != "" {
-			parts := []byte(value)
-			if len(parts) >= 16 {
-				tokens = int(int64(parts[0]) | int64(parts[1])<<8 | int64(parts[2])<<16 | int64(parts[3])<<24)
-				lastRefill = time.Unix(int64(parts[4])|int64(parts[5])<<8|int64(parts[6])<<16|int64(parts[7])<<24,
-					int64(parts[8])|int64(parts[9])<<8|int64(parts[10])<<16|int64(parts[11])<<24)
-			}
-		}
+	refill, err := strconv.ParseInt(refillStr, 10, 64)
+	if err != nil {
+		return TokenBucket{}, fmt.Errorf("invalid refill time in bucket %q", value)
 	}
 
-	now := time.Now()
-	elapsed := now.Sub(lastRefill)
-	tokensToAdd := int(elapsed.Seconds() * float64(config.Limit) / config.Window.Seconds())
+	return TokenBucket{
+		Tokens:     tokens,
+		LastRefill: time.UnixMilli(refill),
+	}, nil
+}
+
+// tokenBucketScript refills and consumes from a bucket in a single atomic
+// step so concurrent requests cannot observe the same token count. Buckets
+// use the TokenBucket.Encode layout. Refill time only advances by whole
+// token intervals so partial progress is not lost.
+//
+// KEYS[1] bucket key
+// ARGV[1] burst, ARGV[2] milliseconds per token, ARGV[3] now in unix
+// millis, ARGV[4] key TTL in milliseconds.
+//
+// Returns {allowed (0 or 1), remaining tokens, milliseconds until the next
+// token refills (0 while tokens remain)}.
+var tokenBucketScript = redis.NewScript(`
+local burst = tonumber(ARGV[1])
+local interval = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local ttl = tonumber(ARGV[4])
+
+local tokens = burst
+local last = now
+
+local value = redis.call("GET", KEYS[1])
+if value then
+	local t, l = string.match(value, "^(%d+):(%d+)$")
+	if t then
+		tokens = tonumber(t)
+		last = tonumber(l)
+	end
+end
+
+if last > now then
+	last = now
+end
+
+if interval > 0 then
+	local refill = math.floor((now - last) / interval)
+	if refill > 0 then
+		tokens = math.min(tokens + refill, burst)
+		last = math.floor(last + refill * interval)
+	end
+end
+
+if tokens >= burst then
+	last = now
+end
+
+local allowed = 0
+if tokens > 0 then
+	tokens = tokens - 1
+	allowed = 1
+end
+
+local retry = 0
+if tokens == 0 then
+	if interval > 0 then
+		retry = math.ceil(last + interval - now)
+	else
+		retry = ttl
+	end
+end
+
+redis.call("SET", KEYS[1], string.format("%d:%d", tokens, last), "PX", ttl)
+return {allowed, tokens, retry}
+`)
+
+func (rl *RateLimiter) allowRequest(ctx context.Context, key string, config TierConfig) (RateLimitResult, error) {
+	bucketKey := fmt.Sprintf("%s:bucket:%s", rl.config.RedisPrefix, key)
 
-	if tokensToAdd > 0 {
-		tokens = min(tokens+tokensToAdd, config.Burst)
-		lastRefill = now
+	var interval float64
+	if config.Limit > 0 {
+		interval = float64(config.Window.Milliseconds()) / float64(config.Limit)
 	}
 
-	if tokens > 0 {
-		tokens--
+	ttl := config.Window.Milliseconds()
+	if ttl <= 0 {
+		ttl = time.Minute.Milliseconds()
+	}
 
-		value := fmt.Sprintf("%d:%d", tokens, lastRefill.Unix())
-		err = rl.redis.Set(ctx, bucketKey, value, config.Window).Err()
-		if err != nil {
-			return false, err
-		}
+	result, err := tokenBucketScript.Run(ctx, rl.redis, []string{bucketKey},
+		config.Burst, interval, rl.now().UnixMilli(), ttl).Int64Slice()
+	if err != nil {
+		return RateLimitResult{}, err
+	}
+	if len(result) != 3 {
+		return RateLimitResult{}, fmt.Errorf("unexpected rate limit script result: %v", result)
+	}
+
+	return RateLimitResult{
+		Allowed:    result[0] == 1,
+		Remaining:  int(result[1]),
+		RetryAfter: time.Duration(result[2]) * time.Millisecond,
+	}, nil
+}
 
-		return true, nil
+// allowSlidingWindow admits at most config.Limit requests in any trailing
+// window, with no burst allowance. Each request is added to a sorted set
+// before counting so concurrent requests can never all see a free slot;
+// requests that land over the limit are removed again.
+func (rl *RateLimiter) allowSlidingWindow(ctx context.Context, key string, config TierConfig) (RateLimitResult, error) {
+	windowKey := fmt.Sprintf("%s:window:%s", rl.config.RedisPrefix, key)
+
+	now := rl.now()
+	windowStart := now.Add(-config.Window)
+	member := fmt.Sprintf("%d-%d", now.UnixNano(), atomic.AddUint64(&rl.seq, 1))
+
+	pipe := rl.redis.TxPipeline()
+	pipe.ZRemRangeByScore(ctx, windowKey, "-inf", fmt.Sprintf("(%d", windowStart.UnixMilli()))
+	pipe.ZAdd(ctx, windowKey, redis.Z{Score: float64(now.UnixMilli()), Member: member})
+	countCmd := pipe.ZCount(ctx, windowKey, "-inf", "+inf")
+	oldestCmd := pipe.ZRangeWithScores(ctx, windowKey, 0, 0)
+	pipe.PExpire(ctx, windowKey, config.Window)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return RateLimitResult{}, err
 	}
 
-	value := fmt.Sprintf("%d:%d", tokens, lastRefill.Unix())
-	err = rl.redis.Set(ctx, bucketKey, value, config.Window).Err()
-	if err != nil {
-		return false, err
+	count := int(countCmd.Val())
+	if count <= config.Limit {
+		return RateLimitResult{
+			Allowed:   true,
+			Remaining: config.Limit - count,
+		}, nil
+	}
+
+	if err := rl.redis.ZRem(ctx, windowKey, member).Err(); err != nil {
+		return RateLimitResult{}, err
 	}
 
-	return false, nil
+	result := RateLimitResult{RetryAfter: config.Window}
+	if oldest := oldestCmd.Val(); len(oldest) > 0 {
+		expires := time.UnixMilli(int64(oldest[0].Score)).Add(config.Window)
+		result.RetryAfter = expires.Sub(now)
+	}
+	return result, nil
 }
 
 func (rl *RateLimiter) GetStats(ctx context.Context, key string) (map[string]interface{}, error) {
@@ -173,11 +323,35 @@ func (rl *RateLimiter) GetStats(ctx context.Context, key string) (map[string]int
 
 	if err == nil && value != "" {
 		stats["value"] = value
+		if bucket, decodeErr := DecodeTokenBucket(value); decodeErr == nil {
+			stats["tokens"] = bucket.Tokens
+			stats["last_refill"] = bucket.LastRefill
+		}
 	}
 
 	return stats, nil
 }
 
+// GetBucket returns the stored bucket for key. The boolean is false when no
+// bucket exists yet, i.e. the key has its full burst available.
+func (rl *RateLimiter) GetBucket(ctx context.Context, key string) (TokenBucket, bool, error) {
+	bucketKey := fmt.Sprintf("%s:bucket:%s", rl.config.RedisPrefix, key)
+
+	value, err := rl.redis.Get(ctx, bucketKey).Result()
+	if err == redis.Nil {
+		return TokenBucket{}, false, nil
+	}
+	if err != nil {
+		return TokenBucket{}, false, err
+	}
+
+	bucket, err := DecodeTokenBucket(value)
+	if err != nil {
+		return TokenBucket{}, false, err
+	}
+	return bucket, true, nil
+}
+
 func (rl *RateLimiter) GetConfig() RateLimitConfig {
 	rl.mu.RLock()
 	defer rl.mu.RUnlock()
@@ -189,6 +363,36 @@ func (rl *RateLimiter) Reset(ctx context.Context, key string) error {
 	return rl.redis.Del(ctx, bucketKey).Err()
 }
 
+// ResetByPattern deletes every bucket whose key matches pattern, a Redis
+// glob applied to the key without the limiter prefix (e.g. "user:42*" for
+// all of a user's buckets). It returns the number of buckets removed.
+func (rl *RateLimiter) ResetByPattern(ctx context.Context, pattern string) (int, error) {
+	reset := 0
+	for _, kind := range []string{"bucket", "window"} {
+		match := fmt.Sprintf("%s:%s:%s", rl.config.RedisPrefix, kind, pattern)
+
+		iter := rl.redis.Scan(ctx, 0, match, 100).Iterator()
+		var keys []string
+		for iter.Next(ctx) {
+			keys = append(keys, iter.Val())
+		}
+		if err := iter.Err(); err != nil {
+			return reset, err
+		}
+		if len(keys) == 0 {
+			continue
+		}
+
+		deleted, err := rl.redis.Del(ctx, keys...).Result()
+		if err != nil {
+			return reset, err
+		}
+		reset += int(deleted)
+	}
+
+	return reset, nil
+}
+
 func min(a, b int) int {
 	if a < b {
 		return a
@@ -0,0 +1,370 @@
+package util
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func newTestRateLimiter(t *testing.T, tier TierConfig) (*RateLimiter, *miniredis.Miniredis) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	config := DefaultRateLimitConfig()
+	config.Tiers = map[RateLimitTier]TierConfig{TierFree: tier}
+
+	return NewRateLimiter(client, config), mr
+}
+
+func TestRateLimiter_AllowConsumesBurst(t *testing.T) {
+	limiter, _ := newTestRateLimiter(t, TierConfig{Limit: 1, Burst: 3, Window: time.Hour})
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		allowed, err := limiter.Allow(ctx, "user:1", TierFree)
+		if err != nil {
+			t.Fatalf("Allow failed: %v", err)
+		}
+		if !allowed {
+			t.Fatalf("Expected request %d to be allowed", i+1)
+		}
+	}
+
+	allowed, err := limiter.Allow(ctx, "user:1", TierFree)
+	if err != nil {
+		t.Fatalf("Allow failed: %v", err)
+	}
+	if allowed {
+		t.Error("Expected request beyond burst to be rejected")
+	}
+
+	allowed, _ = limiter.Allow(ctx, "user:2", TierFree)
+	if !allowed {
+		t.Error("Expected other keys to have their own bucket")
+	}
+}
+
+func TestRateLimiter_Refill(t *testing.T) {
+	limiter, _ := newTestRateLimiter(t, TierConfig{Limit: 60, Burst: 2, Window: time.Minute})
+	ctx := context.Background()
+
+	now := time.Now()
+	limiter.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if allowed, _ := limiter.Allow(ctx, "user:1", TierFree); !allowed {
+			t.Fatalf("Expected request %d to be allowed", i+1)
+		}
+	}
+	if allowed, _ := limiter.Allow(ctx, "user:1", TierFree); allowed {
+		t.Fatal("Expected empty bucket to reject")
+	}
+
+	now = now.Add(500 * time.Millisecond)
+	if allowed, _ := limiter.Allow(ctx, "user:1", TierFree); allowed {
+		t.Error("Expected no refill before a full token interval")
+	}
+
+	now = now.Add(600 * time.Millisecond)
+	if allowed, _ := limiter.Allow(ctx, "user:1", TierFree); !allowed {
+		t.Error("Expected a token to refill after one interval")
+	}
+	if allowed, _ := limiter.Allow(ctx, "user:1", TierFree); allowed {
+		t.Error("Expected only one token to refill")
+	}
+}
+
+func TestRateLimiter_ConcurrentAllowNeverExceedsLimit(t *testing.T) {
+	const burst = 20
+	tier := TierConfig{Limit: 60, Burst: burst, Window: time.Minute}
+	limiter, _ := newTestRateLimiter(t, tier)
+	ctx := context.Background()
+
+	var allowed int64
+	var wg sync.WaitGroup
+
+	start := time.Now()
+	for i := 0; i < 200; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ok, err := limiter.Allow(ctx, "user:1", TierFree)
+			if err != nil {
+				t.Errorf("Allow failed: %v", err)
+				return
+			}
+			if ok {
+				atomic.AddInt64(&allowed, 1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	refill := int64(time.Since(start).Seconds() * float64(tier.Limit) / tier.Window.Seconds())
+	if limit := burst + refill; allowed > limit {
+		t.Errorf("Expected at most %d allowed requests, got %d", limit, allowed)
+	}
+	if allowed < burst {
+		t.Errorf("Expected the full burst of %d to be allowed, got %d", burst, allowed)
+	}
+}
+
+func TestTokenBucket_RoundTrip(t *testing.T) {
+	tests := []TokenBucket{
+		{Tokens: 0, LastRefill: time.UnixMilli(0)},
+		{Tokens: 7, LastRefill: time.UnixMilli(1700000000123)},
+		{Tokens: 1000, LastRefill: time.Now().Truncate(time.Millisecond)},
+	}
+
+	for _, bucket := range tests {
+		decoded, err := DecodeTokenBucket(bucket.Encode())
+		if err != nil {
+			t.Fatalf("Failed to decode %q: %v", bucket.Encode(), err)
+		}
+		if decoded.Tokens != bucket.Tokens || !decoded.LastRefill.Equal(bucket.LastRefill) {
+			t.Errorf("Expected %+v after round trip, got %+v", bucket, decoded)
+		}
+	}
+}
+
+func TestDecodeTokenBucket_Invalid(t *testing.T) {
+	for _, value := range []string{"", "5", "x:100", "5:x", "-1:100"} {
+		if _, err := DecodeTokenBucket(value); err == nil {
+			t.Errorf("Expected error decoding %q", value)
+		}
+	}
+}
+
+func TestRateLimiter_GetBucketAfterAllow(t *testing.T) {
+	limiter, _ := newTestRateLimiter(t, TierConfig{Limit: 60, Burst: 5, Window: time.Minute})
+	ctx := context.Background()
+
+	if _, exists, err := limiter.GetBucket(ctx, "user:1"); err != nil || exists {
+		t.Fatalf("Expected no bucket before the first request, got exists=%v err=%v", exists, err)
+	}
+
+	now := time.UnixMilli(1700000000123)
+	limiter.now = func() time.Time { return now }
+
+	if allowed, err := limiter.Allow(ctx, "user:1", TierFree); err != nil || !allowed {
+		t.Fatalf("Expected request to be allowed, got %v (err %v)", allowed, err)
+	}
+
+	bucket, exists, err := limiter.GetBucket(ctx, "user:1")
+	if err != nil || !exists {
+		t.Fatalf("Expected stored bucket, got exists=%v err=%v", exists, err)
+	}
+	if bucket.Tokens != 4 {
+		t.Errorf("Expected 4 tokens, got %d", bucket.Tokens)
+	}
+	if !bucket.LastRefill.Equal(now) {
+		t.Errorf("Expected refill time %v, got %v", now, bucket.LastRefill)
+	}
+}
+
+func TestRateLimiter_ReadsGoEncodedBucket(t *testing.T) {
+	limiter, mr := newTestRateLimiter(t, TierConfig{Limit: 60, Burst: 5, Window: time.Minute})
+	ctx := context.Background()
+
+	now := time.UnixMilli(1700000000000)
+	limiter.now = func() time.Time { return now }
+	mr.Set("ratelimit:bucket:user:1", TokenBucket{Tokens: 2, LastRefill: now}.Encode())
+
+	if allowed, _ := limiter.Allow(ctx, "user:1", TierFree); !allowed {
+		t.Fatal("Expected request to be allowed")
+	}
+
+	bucket, _, err := limiter.GetBucket(ctx, "user:1")
+	if err != nil {
+		t.Fatalf("GetBucket failed: %v", err)
+	}
+	if bucket.Tokens != 1 {
+		t.Errorf("Expected the script to consume from the stored bucket, got %d tokens", bucket.Tokens)
+	}
+}
+
+func TestRateLimiter_ConsumeRetryAfter(t *testing.T) {
+	limiter, _ := newTestRateLimiter(t, TierConfig{Limit: 6, Burst: 1, Window: time.Minute})
+	ctx := context.Background()
+
+	now := time.UnixMilli(1700000000000)
+	limiter.now = func() time.Time { return now }
+
+	result, err := limiter.Consume(ctx, "user:1", TierFree)
+	if err != nil || !result.Allowed {
+		t.Fatalf("Expected request to be allowed, got %+v (err %v)", result, err)
+	}
+
+	now = now.Add(4 * time.Second)
+	result, err = limiter.Consume(ctx, "user:1", TierFree)
+	if err != nil {
+		t.Fatalf("Consume failed: %v", err)
+	}
+	if result.Allowed {
+		t.Fatal("Expected request to be rejected")
+	}
+	if result.RetryAfter != 6*time.Second {
+		t.Errorf("Expected retry after 6s, got %v", result.RetryAfter)
+	}
+}
+
+func TestRateLimiter_SlidingWindowVersusTokenBucket(t *testing.T) {
+	tier := TierConfig{Limit: 10, Burst: 10, Window: time.Minute}
+	ctx := context.Background()
+
+	countAllowed := func(limiter *RateLimiter, n int) int {
+		allowed := 0
+		for i := 0; i < n; i++ {
+			ok, err := limiter.Allow(ctx, "user:1", TierFree)
+			if err != nil {
+				t.Fatalf("Allow failed: %v", err)
+			}
+			if ok {
+				allowed++
+			}
+		}
+		return allowed
+	}
+
+	bucket, _ := newTestRateLimiter(t, tier)
+	window, _ := newTestRateLimiter(t, tier)
+	window.config.Algorithm = AlgorithmSlidingWindow
+
+	now := time.UnixMilli(1700000000000)
+	clock := func() time.Time { return now }
+	bucket.now, window.now = clock, clock
+
+	if got := countAllowed(bucket, 15); got != 10 {
+		t.Errorf("Expected token bucket to allow its burst of 10, got %d", got)
+	}
+	if got := countAllowed(window, 15); got != 10 {
+		t.Errorf("Expected sliding window to allow its limit of 10, got %d", got)
+	}
+
+	// Halfway through the window the bucket has refilled half its tokens,
+	// while the sliding window still counts every earlier request.
+	now = now.Add(30 * time.Second)
+	if got := countAllowed(bucket, 10); got != 5 {
+		t.Errorf("Expected token bucket to refill 5 tokens, got %d", got)
+	}
+	if got := countAllowed(window, 10); got != 0 {
+		t.Errorf("Expected sliding window to reject until the window passes, got %d", got)
+	}
+
+	now = now.Add(30*time.Second + time.Millisecond)
+	if got := countAllowed(window, 15); got != 10 {
+		t.Errorf("Expected sliding window to admit a full window again, got %d", got)
+	}
+}
+
+func TestRateLimiter_SlidingWindowResult(t *testing.T) {
+	limiter, _ := newTestRateLimiter(t, TierConfig{Limit: 2, Burst: 2, Window: time.Minute})
+	limiter.config.Algorithm = AlgorithmSlidingWindow
+	ctx := context.Background()
+
+	now := time.UnixMilli(1700000000000)
+	limiter.now = func() time.Time { return now }
+
+	result, err := limiter.Consume(ctx, "user:1", TierFree)
+	if err != nil || !result.Allowed || result.Remaining != 1 {
+		t.Fatalf("Expected first request allowed with 1 remaining, got %+v (err %v)", result, err)
+	}
+
+	now = now.Add(20 * time.Second)
+	if result, _ = limiter.Consume(ctx, "user:1", TierFree); !result.Allowed || result.Remaining != 0 {
+		t.Fatalf("Expected second request allowed with 0 remaining, got %+v", result)
+	}
+
+	now = now.Add(10 * time.Second)
+	result, err = limiter.Consume(ctx, "user:1", TierFree)
+	if err != nil {
+		t.Fatalf("Consume failed: %v", err)
+	}
+	if result.Allowed {
+		t.Fatal("Expected third request to be rejected")
+	}
+	if result.RetryAfter != 30*time.Second {
+		t.Errorf("Expected retry after 30s until the oldest request expires, got %v", result.RetryAfter)
+	}
+}
+
+func TestRateLimiter_SlidingWindowConcurrent(t *testing.T) {
+	const limit = 20
+	limiter, _ := newTestRateLimiter(t, TierConfig{Limit: limit, Burst: limit, Window: time.Minute})
+	limiter.config.Algorithm = AlgorithmSlidingWindow
+	ctx := context.Background()
+
+	var allowed int64
+	var wg sync.WaitGroup
+	for i := 0; i < 200; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ok, err := limiter.Allow(ctx, "user:1", TierFree)
+			if err != nil {
+				t.Errorf("Allow failed: %v", err)
+				return
+			}
+			if ok {
+				atomic.AddInt64(&allowed, 1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if allowed > limit {
+		t.Errorf("Expected at most %d allowed requests, got %d", limit, allowed)
+	}
+}
+
+func TestRateLimiter_ResetByPattern(t *testing.T) {
+	limiter, mr := newTestRateLimiter(t, TierConfig{Limit: 60, Burst: 5, Window: time.Minute})
+	ctx := context.Background()
+
+	keys := []string{"user:42", "user:42:route:POST /api/v1/documents/batch", "user:420", "user:7", "ip:10.0.0.1"}
+	for _, key := range keys {
+		if _, err := limiter.Allow(ctx, key, TierFree); err != nil {
+			t.Fatalf("Allow failed: %v", err)
+		}
+	}
+	limiter.config.Algorithm = AlgorithmSlidingWindow
+	if _, err := limiter.Allow(ctx, "user:42", TierFree); err != nil {
+		t.Fatalf("Allow failed: %v", err)
+	}
+
+	reset, err := limiter.ResetByPattern(ctx, "user:42:*")
+	if err != nil {
+		t.Fatalf("ResetByPattern failed: %v", err)
+	}
+	if reset != 1 {
+		t.Errorf("Expected 1 bucket reset, got %d", reset)
+	}
+
+	reset, err = limiter.ResetByPattern(ctx, "user:42")
+	if err != nil {
+		t.Fatalf("ResetByPattern failed: %v", err)
+	}
+	if reset != 2 {
+		t.Errorf("Expected the bucket and window for user:42 to be reset, got %d", reset)
+	}
+
+	for key, want := range map[string]bool{
+		"ratelimit:bucket:user:42":     false,
+		"ratelimit:window:user:42":     false,
+		"ratelimit:bucket:user:420":    true,
+		"ratelimit:bucket:user:7":      true,
+		"ratelimit:bucket:ip:10.0.0.1": true,
+	} {
+		if got := mr.Exists(key); got != want {
+			t.Errorf("Expected %s exists=%v, got %v", key, want, got)
+		}
+	}
+}
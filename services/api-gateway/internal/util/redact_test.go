@@ -0,0 +1,83 @@
+package util
+
+import (
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func newObservedLogger() (*Logger, *observer.ObservedLogs) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	logger := zap.New(core)
+	return &Logger{Logger: logger, sugar: logger.Sugar(), level: zapcore.DebugLevel}, logs
+}
+
+func TestWithRedactionMasksSensitiveFields(t *testing.T) {
+	base, logs := newObservedLogger()
+	logger := base.WithRedaction([]string{"email", "SSN"})
+
+	logger.Infow("user search",
+		"email", "jane@example.com",
+		"ssn", "123-45-6789",
+		"query", "running shoes",
+	)
+	logger.Logger.Info("typed fields", zap.String("Email", "jane@example.com"), zap.Int("limit", 10))
+
+	entries := logs.All()
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+
+	fields := entries[0].ContextMap()
+	if fields["email"] != RedactedValue || fields["ssn"] != RedactedValue {
+		t.Errorf("expected email and ssn to be redacted, got %v", fields)
+	}
+	if fields["query"] != "running shoes" {
+		t.Errorf("expected query to pass through, got %v", fields["query"])
+	}
+
+	fields = entries[1].ContextMap()
+	if fields["Email"] != RedactedValue {
+		t.Errorf("expected case-insensitive match on Email, got %v", fields["Email"])
+	}
+	if fields["limit"] != int64(10) {
+		t.Errorf("expected limit to pass through, got %v", fields["limit"])
+	}
+}
+
+func TestWithRedactionMasksNestedAndContextFields(t *testing.T) {
+	base, logs := newObservedLogger()
+	logger := base.WithRedaction([]string{"email"})
+
+	logger.Logger.With(zap.String("email", "ctx@example.com")).Info("with context")
+	logger.Infow("filters", "filters", map[string]interface{}{
+		"email": "jane@example.com",
+		"lang":  "en",
+		"owner": map[string]interface{}{"email": "owner@example.com"},
+	})
+
+	entries := logs.All()
+	if got := entries[0].ContextMap()["email"]; got != RedactedValue {
+		t.Errorf("expected With field to be redacted, got %v", got)
+	}
+
+	filters, ok := entries[1].ContextMap()["filters"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected filters map, got %T", entries[1].ContextMap()["filters"])
+	}
+	if filters["email"] != RedactedValue || filters["lang"] != "en" {
+		t.Errorf("unexpected filters %v", filters)
+	}
+	if owner := filters["owner"].(map[string]interface{}); owner["email"] != RedactedValue {
+		t.Errorf("expected nested email to be redacted, got %v", owner)
+	}
+}
+
+func TestWithRedactionWithoutFields(t *testing.T) {
+	base, _ := newObservedLogger()
+	if logger := base.WithRedaction(nil); logger != base || logger.Redactor() != nil {
+		t.Error("expected WithRedaction(nil) to return the logger unchanged")
+	}
+}
@@ -0,0 +1,23 @@
+package util
+
+import "context"
+
+const (
+	RequestIDHeader = "X-Request-ID"
+	// RequestIDMetadataKey carries the request ID to the coordinator in
+	// gRPC metadata.
+	RequestIDMetadataKey = "x-request-id"
+)
+
+type requestIDKey struct{}
+
+// ContextWithRequestID stores the request ID in ctx so it survives into
+// code that only sees the request context, such as gRPC interceptors.
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
@@ -0,0 +1,41 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// DefaultShutdownTimeout is used when no shutdown timeout is configured.
+const DefaultShutdownTimeout = 30 * time.Second
+
+// ShutdownFunc releases one resource, giving up when ctx is done.
+type ShutdownFunc func(ctx context.Context) error
+
+// Shutdown runs fns in order under a single deadline of timeout, so closing
+// clients shares the drain window with the HTTP server. Every fn runs even
+// if an earlier one fails or the deadline has passed; their errors are
+// joined.
+func Shutdown(timeout time.Duration, fns ...ShutdownFunc) error {
+	if timeout <= 0 {
+		timeout = DefaultShutdownTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var errs []error
+	for _, fn := range fns {
+		if err := fn(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// CloseFunc adapts a Close method to a ShutdownFunc.
+func CloseFunc(close func() error) ShutdownFunc {
+	return func(ctx context.Context) error {
+		return close()
+	}
+}
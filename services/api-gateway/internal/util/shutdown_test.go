@@ -0,0 +1,69 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestShutdown_RespectsTimeout(t *testing.T) {
+	timeout := 50 * time.Millisecond
+
+	var deadline time.Time
+	var closed bool
+	start := time.Now()
+	err := Shutdown(timeout,
+		func(ctx context.Context) error {
+			deadline, _ = ctx.Deadline()
+			<-ctx.Done()
+			return ctx.Err()
+		},
+		CloseFunc(func() error {
+			closed = true
+			return nil
+		}),
+	)
+	elapsed := time.Since(start)
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the blocked step to hit the deadline, got %v", err)
+	}
+	if got := deadline.Sub(start); got < timeout || got > timeout+20*time.Millisecond {
+		t.Errorf("Expected a deadline %v after start, got %v", timeout, got)
+	}
+	if elapsed < timeout || elapsed > time.Second {
+		t.Errorf("Expected shutdown to take about %v, took %v", timeout, elapsed)
+	}
+	if !closed {
+		t.Error("Expected later steps to run after the deadline")
+	}
+}
+
+func TestShutdown_DefaultTimeout(t *testing.T) {
+	var remaining time.Duration
+	if err := Shutdown(0, func(ctx context.Context) error {
+		deadline, _ := ctx.Deadline()
+		remaining = time.Until(deadline)
+		return nil
+	}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if remaining <= DefaultShutdownTimeout-time.Second || remaining > DefaultShutdownTimeout {
+		t.Errorf("Expected the default %v timeout, got %v remaining", DefaultShutdownTimeout, remaining)
+	}
+}
+
+func TestShutdown_JoinsErrors(t *testing.T) {
+	first := errors.New("server")
+	second := errors.New("redis")
+
+	err := Shutdown(time.Second,
+		func(ctx context.Context) error { return first },
+		CloseFunc(func() error { return second }),
+	)
+	if !errors.Is(err, first) || !errors.Is(err, second) {
+		t.Errorf("Expected both errors, got %v", err)
+	}
+}
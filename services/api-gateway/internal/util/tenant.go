@@ -0,0 +1,20 @@
+package util
+
+import "context"
+
+// TenantMetadataKey carries the caller's tenant to the coordinator in gRPC
+// metadata, where it namespaces cached search results.
+const TenantMetadataKey = "x-tenant-id"
+
+type tenantKey struct{}
+
+// ContextWithTenant stores the caller's tenant in ctx for the coordinator
+// client to forward.
+func ContextWithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+func TenantFromContext(ctx context.Context) string {
+	tenant, _ := ctx.Value(tenantKey{}).(string)
+	return tenant
+}
@@ -0,0 +1,13 @@
+package proto
+
+import (
+	"github.com/flexsearch/shared/codec"
+	"google.golang.org/grpc"
+)
+
+// WithJSONCodec makes every call on the connection use the shared JSON
+// codec. The messages in this package are plain structs, which the
+// default protobuf codec refuses to marshal.
+func WithJSONCodec() grpc.DialOption {
+	return grpc.WithDefaultCallOptions(grpc.CallContentSubtype(codec.Name))
+}
@@ -7,25 +7,66 @@ import (
 )
 
 type SearchRequest struct {
-	Query     string            `json:"query"`
-	Indexes   []string          `json:"indexes"`
-	Page      int32             `json:"page"`
-	PageSize  int32             `json:"page_size"`
-	Filters   map[string]string `json:"filters"`
-	Fields    []string          `json:"fields"`
-	Highlight bool              `json:"highlight"`
-	SortBy    string            `json:"sort_by"`
-	SortOrder string            `json:"sort_order"`
-	Explain   bool              `json:"explain"`
+	Query          string            `json:"query"`
+	Indexes        []string          `json:"indexes"`
+	Page           int32             `json:"page"`
+	PageSize       int32             `json:"page_size"`
+	Filters        map[string]string `json:"filters"`
+	Fields         []string          `json:"fields"`
+	Highlight      bool              `json:"highlight"`
+	SortBy         string            `json:"sort_by"`
+	SortOrder      string            `json:"sort_order"`
+	Explain        bool              `json:"explain"`
+	Facets         []string          `json:"facets"`
+	SearchAfter    string            `json:"search_after"`
+	DryRun         bool              `json:"dry_run"`
+	PerEngineLimit int32             `json:"per_engine_limit"`
 }
 
 type SearchResponse struct {
-	Results    []*SearchResult `json:"results"`
-	Total      int32           `json:"total"`
-	Page       int32           `json:"page"`
-	PageSize   int32           `json:"page_size"`
-	TotalPages int32           `json:"total_pages"`
-	TookMs     float64         `json:"took_ms"`
+	Results        []*SearchResult           `json:"results"`
+	Total          int32                     `json:"total"`
+	Page           int32                     `json:"page"`
+	PageSize       int32                     `json:"page_size"`
+	TotalPages     int32                     `json:"total_pages"`
+	TookMs         float64                   `json:"took_ms"`
+	Degraded       bool                      `json:"degraded"`
+	FailedEngines  []string                  `json:"failed_engines"`
+	Error          string                    `json:"error"`
+	Facets         map[string][]*FacetBucket `json:"facets"`
+	DidYouMean     string                    `json:"did_you_mean"`
+	NextCursor     string                    `json:"next_cursor"`
+	Routing        *RoutingExplain           `json:"routing"`
+	SkippedEngines []string                  `json:"skipped_engines"`
+}
+
+type RoutingExplain struct {
+	Strategy string             `json:"strategy"`
+	Reasons  []string           `json:"reasons"`
+	Engines  []string           `json:"engines"`
+	Weights  map[string]float64 `json:"weights"`
+}
+
+type FacetBucket struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+type MultiSearchRequest struct {
+	Requests []*SearchRequest `json:"requests"`
+}
+
+type MultiSearchResponse struct {
+	Responses []*SearchResponse `json:"responses"`
+}
+
+type SuggestRequest struct {
+	Prefix string `json:"prefix"`
+	Limit  int32  `json:"limit"`
+}
+
+type SuggestResponse struct {
+	Suggestions []string `json:"suggestions"`
 }
 
 type SearchResult struct {
@@ -42,9 +83,21 @@ type GetDocumentRequest struct {
 }
 
 type DocumentResponse struct {
-	Id     string            `json:"id"`
-	Fields map[string]string `json:"fields"`
-	Score  float64           `json:"score"`
+	Id      string            `json:"id"`
+	Fields  map[string]string `json:"fields"`
+	Score   float64           `json:"score"`
+	Success bool              `json:"success"`
+	Error   string            `json:"error,omitempty"`
+	Version int64             `json:"version"`
+}
+
+type GetDocumentsRequest struct {
+	IndexId     string   `json:"index_id"`
+	DocumentIds []string `json:"document_ids"`
+}
+
+type GetDocumentsResponse struct {
+	Documents []*DocumentResponse `json:"documents"`
 }
 
 type AddDocumentRequest struct {
@@ -62,11 +115,21 @@ type UpdateDocumentRequest struct {
 	IndexId    string            `json:"index_id"`
 	DocumentId string            `json:"document_id"`
 	Fields     map[string]string `json:"fields"`
+	Version    int64             `json:"version"`
 }
 
 type UpdateDocumentResponse struct {
 	Success bool   `json:"success"`
 	Message string `json:"message"`
+	Version int64  `json:"version"`
+}
+
+type MergeDocumentRequest struct {
+	IndexId      string            `json:"index_id"`
+	DocumentId   string            `json:"document_id"`
+	Fields       map[string]string `json:"fields"`
+	DeleteFields []string          `json:"delete_fields"`
+	Version      int64             `json:"version"`
 }
 
 type DeleteDocumentRequest struct {
@@ -91,11 +154,31 @@ type BatchDocumentsResponse struct {
 	Errors       []string `json:"errors"`
 }
 
+type BatchChunkAck struct {
+	Chunk        int32    `json:"chunk"`
+	SuccessCount int32    `json:"success_count"`
+	FailureCount int32    `json:"failure_count"`
+	Errors       []string `json:"errors"`
+}
+
+type BatchDocumentsStreamResponse struct {
+	SuccessCount int32            `json:"success_count"`
+	FailureCount int32            `json:"failure_count"`
+	Chunks       []*BatchChunkAck `json:"chunks"`
+}
+
 type CreateIndexRequest struct {
-	Name      string            `json:"name"`
-	IndexType string            `json:"index_type"`
-	Fields    []string          `json:"fields"`
-	Options   map[string]string `json:"options"`
+	Name      string                   `json:"name"`
+	IndexType string                   `json:"index_type"`
+	Fields    []string                 `json:"fields"`
+	Options   map[string]string        `json:"options"`
+	Mappings  map[string]*FieldMapping `json:"mappings"`
+}
+
+type FieldMapping struct {
+	Type     string  `json:"type"`
+	Analyzer string  `json:"analyzer"`
+	Boost    float64 `json:"boost"`
 }
 
 type CreateIndexResponse struct {
@@ -152,6 +235,56 @@ type RebuildIndexResponse struct {
 	TaskId  string `json:"task_id"`
 }
 
+type GetTaskStatusRequest struct {
+	TaskId string `json:"task_id"`
+}
+
+type TaskStatusResponse struct {
+	Id         string  `json:"id"`
+	Type       string  `json:"type"`
+	State      string  `json:"state"`
+	Percent    float64 `json:"percent"`
+	Error      string  `json:"error"`
+	StartedAt  string  `json:"started_at"`
+	FinishedAt string  `json:"finished_at"`
+}
+
+type GetIndexStatsRequest struct {
+	IndexId string `json:"index_id"`
+}
+
+type EngineIndexStats struct {
+	Engine        string `json:"engine"`
+	DocumentCount int64  `json:"document_count"`
+	IndexSize     int64  `json:"index_size"`
+	LastUpdated   string `json:"last_updated"`
+}
+
+type IndexStatsResponse struct {
+	IndexId       string              `json:"index_id"`
+	DocumentCount int64               `json:"document_count"`
+	IndexSize     int64               `json:"index_size"`
+	LastUpdated   string              `json:"last_updated"`
+	Engines       []*EngineIndexStats `json:"engines"`
+	Partial       bool                `json:"partial"`
+	Notes         []string            `json:"notes"`
+}
+
+type ReconcileIndexRequest struct {
+	IndexId string `json:"index_id"`
+	Rebuild bool   `json:"rebuild"`
+}
+
+type ReconcileReport struct {
+	IndexId       string           `json:"index_id"`
+	Expected      int64            `json:"expected"`
+	Counts        map[string]int64 `json:"counts"`
+	Consistent    bool             `json:"consistent"`
+	Mismatched    []string         `json:"mismatched"`
+	Unreachable   []string         `json:"unreachable"`
+	RebuildTaskId string           `json:"rebuild_task_id"`
+}
+
 type HealthCheckRequest struct {
 	Service string `json:"service"`
 }
@@ -174,14 +307,19 @@ type ServiceStatus struct {
 
 type SearchServiceClient interface {
 	Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error)
+	MultiSearch(ctx context.Context, in *MultiSearchRequest, opts ...grpc.CallOption) (*MultiSearchResponse, error)
+	Suggest(ctx context.Context, in *SuggestRequest, opts ...grpc.CallOption) (*SuggestResponse, error)
 }
 
 type DocumentServiceClient interface {
 	GetDocument(ctx context.Context, in *GetDocumentRequest, opts ...grpc.CallOption) (*DocumentResponse, error)
+	GetDocuments(ctx context.Context, in *GetDocumentsRequest, opts ...grpc.CallOption) (*GetDocumentsResponse, error)
 	AddDocument(ctx context.Context, in *AddDocumentRequest, opts ...grpc.CallOption) (*AddDocumentResponse, error)
 	UpdateDocument(ctx context.Context, in *UpdateDocumentRequest, opts ...grpc.CallOption) (*UpdateDocumentResponse, error)
+	MergeDocument(ctx context.Context, in *MergeDocumentRequest, opts ...grpc.CallOption) (*DocumentResponse, error)
 	DeleteDocument(ctx context.Context, in *DeleteDocumentRequest, opts ...grpc.CallOption) (*DeleteDocumentResponse, error)
 	BatchDocuments(ctx context.Context, in *BatchDocumentsRequest, opts ...grpc.CallOption) (*BatchDocumentsResponse, error)
+	BatchDocumentsStream(ctx context.Context, opts ...grpc.CallOption) (DocumentService_BatchDocumentsStreamClient, error)
 }
 
 type IndexServiceClient interface {
@@ -190,6 +328,9 @@ type IndexServiceClient interface {
 	GetIndex(ctx context.Context, in *GetIndexRequest, opts ...grpc.CallOption) (*GetIndexResponse, error)
 	DeleteIndex(ctx context.Context, in *DeleteIndexRequest, opts ...grpc.CallOption) (*DeleteIndexResponse, error)
 	RebuildIndex(ctx context.Context, in *RebuildIndexRequest, opts ...grpc.CallOption) (*RebuildIndexResponse, error)
+	GetTaskStatus(ctx context.Context, in *GetTaskStatusRequest, opts ...grpc.CallOption) (*TaskStatusResponse, error)
+	GetIndexStats(ctx context.Context, in *GetIndexStatsRequest, opts ...grpc.CallOption) (*IndexStatsResponse, error)
+	ReconcileIndex(ctx context.Context, in *ReconcileIndexRequest, opts ...grpc.CallOption) (*ReconcileReport, error)
 }
 
 type HealthClient interface {
@@ -213,6 +354,24 @@ func (c *searchServiceClient) Search(ctx context.Context, in *SearchRequest, opt
 	return out, nil
 }
 
+func (c *searchServiceClient) MultiSearch(ctx context.Context, in *MultiSearchRequest, opts ...grpc.CallOption) (*MultiSearchResponse, error) {
+	out := new(MultiSearchResponse)
+	err := c.cc.Invoke(ctx, "/coordinator.SearchService/MultiSearch", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *searchServiceClient) Suggest(ctx context.Context, in *SuggestRequest, opts ...grpc.CallOption) (*SuggestResponse, error) {
+	out := new(SuggestResponse)
+	err := c.cc.Invoke(ctx, "/coordinator.SearchService/Suggest", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 type documentServiceClient struct {
 	cc grpc.ClientConnInterface
 }
@@ -230,6 +389,15 @@ func (c *documentServiceClient) GetDocument(ctx context.Context, in *GetDocument
 	return out, nil
 }
 
+func (c *documentServiceClient) GetDocuments(ctx context.Context, in *GetDocumentsRequest, opts ...grpc.CallOption) (*GetDocumentsResponse, error) {
+	out := new(GetDocumentsResponse)
+	err := c.cc.Invoke(ctx, "/coordinator.DocumentService/GetDocuments", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *documentServiceClient) AddDocument(ctx context.Context, in *AddDocumentRequest, opts ...grpc.CallOption) (*AddDocumentResponse, error) {
 	out := new(AddDocumentResponse)
 	err := c.cc.Invoke(ctx, "/coordinator.DocumentService/AddDocument", in, out, opts...)
@@ -248,6 +416,15 @@ func (c *documentServiceClient) UpdateDocument(ctx context.Context, in *UpdateDo
 	return out, nil
 }
 
+func (c *documentServiceClient) MergeDocument(ctx context.Context, in *MergeDocumentRequest, opts ...grpc.CallOption) (*DocumentResponse, error) {
+	out := new(DocumentResponse)
+	err := c.cc.Invoke(ctx, "/coordinator.DocumentService/MergeDocument", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *documentServiceClient) DeleteDocument(ctx context.Context, in *DeleteDocumentRequest, opts ...grpc.CallOption) (*DeleteDocumentResponse, error) {
 	out := new(DeleteDocumentResponse)
 	err := c.cc.Invoke(ctx, "/coordinator.DocumentService/DeleteDocument", in, out, opts...)
@@ -319,6 +496,33 @@ func (c *indexServiceClient) RebuildIndex(ctx context.Context, in *RebuildIndexR
 	return out, nil
 }
 
+func (c *indexServiceClient) GetTaskStatus(ctx context.Context, in *GetTaskStatusRequest, opts ...grpc.CallOption) (*TaskStatusResponse, error) {
+	out := new(TaskStatusResponse)
+	err := c.cc.Invoke(ctx, "/coordinator.IndexService/GetTaskStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *indexServiceClient) GetIndexStats(ctx context.Context, in *GetIndexStatsRequest, opts ...grpc.CallOption) (*IndexStatsResponse, error) {
+	out := new(IndexStatsResponse)
+	err := c.cc.Invoke(ctx, "/coordinator.IndexService/GetIndexStats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *indexServiceClient) ReconcileIndex(ctx context.Context, in *ReconcileIndexRequest, opts ...grpc.CallOption) (*ReconcileReport, error) {
+	out := new(ReconcileReport)
+	err := c.cc.Invoke(ctx, "/coordinator.IndexService/ReconcileIndex", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 type healthClient struct {
 	cc grpc.ClientConnInterface
 }
@@ -342,12 +546,68 @@ func (UnimplementedSearchServiceServer) Search(ctx context.Context, req *SearchR
 	return nil, nil
 }
 
+func (UnimplementedSearchServiceServer) MultiSearch(ctx context.Context, req *MultiSearchRequest) (*MultiSearchResponse, error) {
+	return nil, nil
+}
+
+func (UnimplementedSearchServiceServer) Suggest(ctx context.Context, req *SuggestRequest) (*SuggestResponse, error) {
+	return nil, nil
+}
+
+var documentServiceBatchDocumentsStreamDesc = &grpc.StreamDesc{
+	StreamName:    "BatchDocumentsStream",
+	ClientStreams: true,
+}
+
+func (c *documentServiceClient) BatchDocumentsStream(ctx context.Context, opts ...grpc.CallOption) (DocumentService_BatchDocumentsStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, documentServiceBatchDocumentsStreamDesc, "/coordinator.DocumentService/BatchDocumentsStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &documentServiceBatchDocumentsStreamClient{stream}, nil
+}
+
+type DocumentService_BatchDocumentsStreamClient interface {
+	Send(*BatchDocumentsRequest) error
+	CloseAndRecv() (*BatchDocumentsStreamResponse, error)
+	grpc.ClientStream
+}
+
+type documentServiceBatchDocumentsStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *documentServiceBatchDocumentsStreamClient) Send(m *BatchDocumentsRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *documentServiceBatchDocumentsStreamClient) CloseAndRecv() (*BatchDocumentsStreamResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(BatchDocumentsStreamResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+type DocumentService_BatchDocumentsStreamServer interface {
+	SendAndClose(*BatchDocumentsStreamResponse) error
+	Recv() (*BatchDocumentsRequest, error)
+	grpc.ServerStream
+}
+
 type UnimplementedDocumentServiceServer struct{}
 
 func (UnimplementedDocumentServiceServer) GetDocument(ctx context.Context, req *GetDocumentRequest) (*DocumentResponse, error) {
 	return nil, nil
 }
 
+func (UnimplementedDocumentServiceServer) GetDocuments(ctx context.Context, req *GetDocumentsRequest) (*GetDocumentsResponse, error) {
+	return nil, nil
+}
+
 func (UnimplementedDocumentServiceServer) AddDocument(ctx context.Context, req *AddDocumentRequest) (*AddDocumentResponse, error) {
 	return nil, nil
 }
@@ -356,6 +616,10 @@ func (UnimplementedDocumentServiceServer) UpdateDocument(ctx context.Context, re
 	return nil, nil
 }
 
+func (UnimplementedDocumentServiceServer) MergeDocument(ctx context.Context, req *MergeDocumentRequest) (*DocumentResponse, error) {
+	return nil, nil
+}
+
 func (UnimplementedDocumentServiceServer) DeleteDocument(ctx context.Context, req *DeleteDocumentRequest) (*DeleteDocumentResponse, error) {
 	return nil, nil
 }
@@ -364,6 +628,10 @@ func (UnimplementedDocumentServiceServer) BatchDocuments(ctx context.Context, re
 	return nil, nil
 }
 
+func (UnimplementedDocumentServiceServer) BatchDocumentsStream(stream DocumentService_BatchDocumentsStreamServer) error {
+	return nil
+}
+
 type UnimplementedIndexServiceServer struct{}
 
 func (UnimplementedIndexServiceServer) CreateIndex(ctx context.Context, req *CreateIndexRequest) (*CreateIndexResponse, error) {
@@ -386,6 +654,18 @@ func (UnimplementedIndexServiceServer) RebuildIndex(ctx context.Context, req *Re
 	return nil, nil
 }
 
+func (UnimplementedIndexServiceServer) GetTaskStatus(ctx context.Context, req *GetTaskStatusRequest) (*TaskStatusResponse, error) {
+	return nil, nil
+}
+
+func (UnimplementedIndexServiceServer) GetIndexStats(ctx context.Context, req *GetIndexStatsRequest) (*IndexStatsResponse, error) {
+	return nil, nil
+}
+
+func (UnimplementedIndexServiceServer) ReconcileIndex(ctx context.Context, req *ReconcileIndexRequest) (*ReconcileReport, error) {
+	return nil, nil
+}
+
 type UnimplementedHealthServer struct{}
 
 func (UnimplementedHealthServer) Check(ctx context.Context, req *HealthCheckRequest) (*HealthCheckResponse, error) {
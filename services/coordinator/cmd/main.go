@@ -8,6 +8,7 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/flexsearch/coordinator/internal/cache"
 	"github.com/flexsearch/coordinator/internal/config"
@@ -16,8 +17,18 @@ import (
 	"github.com/flexsearch/coordinator/internal/router"
 	coordinatorServer "github.com/flexsearch/coordinator/internal/server"
 	"github.com/flexsearch/coordinator/internal/service"
+	"github.com/flexsearch/coordinator/internal/suggest"
+	"github.com/flexsearch/coordinator/internal/task"
 	"github.com/flexsearch/coordinator/internal/util"
+	sharedcache "github.com/flexsearch/shared/cache"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/health"
 	healthpb "google.golang.org/grpc/health/grpc_health_v1"
@@ -26,39 +37,59 @@ import (
 
 const (
 	serviceName = "coordinator"
+	configPath  = "configs/config.yaml"
+	// cacheStatsInterval is how often the cache hit rate and size gauges
+	// are refreshed.
+	cacheStatsInterval = 15 * time.Second
 )
 
 func main() {
-	cfg, err := config.Load("configs/config.yaml")
+	cfg, err := config.Load(configPath)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to load config: %v\n", err)
 		os.Exit(1)
 	}
 
-	logger, err := util.NewLogger(cfg.Logging.Level, cfg.Logging.Format, cfg.Logging.Output)
+	logger, err := util.NewLoggerWithSampling(cfg.Logging.Level, cfg.Logging.Format, cfg.Logging.Output, &util.SamplingConfig{
+		Initial:    cfg.Logging.Sampling.Initial,
+		Thereafter: cfg.Logging.Sampling.Thereafter,
+		Tick:       cfg.Logging.Sampling.Tick,
+	})
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to initialize logger: %v\n", err)
 		os.Exit(1)
 	}
 	defer logger.Sync()
+	logger = logger.WithRedaction(cfg.Logging.RedactFields)
 
 	metrics := util.NewMetrics(serviceName)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	redisCache, err := cache.NewRedisCache(&cache.CacheConfig{
-		Enabled:    cfg.Cache.Enabled,
-		Host:       cfg.Redis.Host,
-		Port:       cfg.Redis.Port,
-		Password:   cfg.Redis.Password,
-		DB:         cfg.Redis.DB,
-		PoolSize:   cfg.Redis.PoolSize,
-		DefaultTTL: cfg.Cache.DefaultTTL,
-	}, logger)
+	redisCache, err := newRedisCache(cfg, logger, metrics)
 	if err != nil {
 		logger.Warnf("Redis cache initialization failed: %v", err)
 	}
+	if redisCache != nil {
+		go redisCache.ReportStats(ctx, cacheStatsInterval)
+	}
+
+	invalidator := newInvalidator(cfg, redisCache)
+
+	var bus *cache.InvalidationBus
+	if redisCache != nil && cfg.Cache.BusChannel != "" {
+		bus = cache.NewInvalidationBus(redisCache, cache.InvalidationBusConfig{
+			Channel:     cfg.Cache.BusChannel,
+			Invalidator: invalidator,
+		}, logger)
+		if err := bus.Start(ctx); err != nil {
+			logger.Warnf("Cache invalidation bus unavailable: %v", err)
+			bus = nil
+		} else {
+			defer bus.Close()
+		}
+	}
 
 	engines := initializeEngines(cfg, logger)
 
@@ -66,24 +97,94 @@ func main() {
 	optimizer := router.NewOptimizer(logger)
 
 	mergerConfig := &merger.MergerConfig{
-		Strategy: "rrf",
-		RRFK:     60,
-		TopK:     100,
+		Strategy:      cfg.Search.Fusion.Strategy,
+		RRFK:          60,
+		TopK:          100,
+		Normalization: cfg.Search.Fusion.Normalization,
 	}
-	resultMerger := merger.NewMerger("rrf", mergerConfig, logger)
+	resultMerger := merger.NewMerger(cfg.Search.Fusion.Strategy, mergerConfig, logger)
+
+	suggestions := suggest.NewTrie()
+
+	queryLoggerConfig := util.QueryLoggerConfig{SlowThreshold: cfg.Search.SlowQuery.Threshold}
+	if cfg.Search.SlowQuery.Record && redisCache != nil {
+		queryLoggerConfig.SlowSink = redisCache.SlowQueryList(cfg.Search.SlowQuery.MaxEntries)
+	}
+
+	documentService := newDocumentService(logger, engines, redisCache, suggestions, bus,
+		invalidator, invalidationRules(cfg.Cache.InvalidationRules))
 
 	searchService := service.NewSearchService(&service.SearchServiceConfig{
-		Config:    cfg,
+		Config:               cfg,
+		Logger:               logger,
+		Cache:                redisCache,
+		Router:               r,
+		Optimizer:            optimizer,
+		Merger:               resultMerger,
+		Engines:              engines,
+		Metrics:              metrics,
+		EngineTimeouts:       cfg.Search.EngineTimeouts,
+		MinSuccessfulEngines: cfg.Search.MinSuccessfulEngines,
+		FallbackEngines:      cfg.Search.FallbackEngines,
+		PerEngineLimit:       cfg.Search.PerEngineLimit,
+		DidYouMeanThreshold:  cfg.Search.DidYouMeanThreshold,
+		Suggestions:          suggestions,
+		IndexTypes:           documentService,
+		QueryLogger:          util.NewQueryLoggerWithConfig(logger, queryLoggerConfig),
+		Complexity: service.ComplexityLimits{
+			MaxTerms:     cfg.Search.Complexity.MaxTerms,
+			MaxFilters:   cfg.Search.Complexity.MaxFilters,
+			MaxIndexes:   cfg.Search.Complexity.MaxIndexes,
+			MaxWildcards: cfg.Search.Complexity.MaxWildcards,
+		},
+		Wildcards: service.WildcardPolicy{
+			MinPrefix: cfg.Search.Wildcards.MinPrefix,
+			Action:    cfg.Search.Wildcards.Action,
+		},
+		Highlighter: merger.NewHighlighter(merger.HighlighterConfig{
+			FragmentSize: cfg.Search.Highlight.FragmentSize,
+			ContextSize:  cfg.Search.Highlight.ContextSize,
+			MaxFragments: cfg.Search.Highlight.MaxFragments,
+		}),
+	})
+
+	tasks := task.NewRegistry(0)
+	indexService := service.NewIndexService(&service.IndexServiceConfig{
 		Logger:    logger,
-		Cache:     redisCache,
-		Router:    r,
-		Optimizer: optimizer,
-		Merger:    resultMerger,
-		Engines:   engines,
-		Metrics:   metrics,
+		Documents: documentService,
+		Tasks:     tasks,
 	})
 
-	grpcServer := setupGRPCServer(cfg, logger, searchService)
+	sampler := util.NewRatioSampler(cfg.Tracing.SampleRate)
+	shutdownTracing, err := setupTracing(ctx, cfg.Tracing, sampler)
+	if err != nil {
+		logger.Warnf("Tracing initialization failed: %v", err)
+		shutdownTracing = func(context.Context) error { return nil }
+	}
+	defer func() {
+		flushCtx, flushCancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+		defer flushCancel()
+		if err := shutdownTracing(flushCtx); err != nil {
+			logger.Errorf("Tracing shutdown error: %v", err)
+		}
+	}()
+
+	reloadTargets := config.ReloadTargets{
+		SetLogLevel:   logger.SetLevel,
+		SetSampleRate: sampler.SetRate,
+	}
+	if redisCache != nil {
+		reloadTargets.SetCacheTTL = redisCache.SetDefaultTTL
+	}
+	config.NewReloader(configPath, cfg, logger, reloadTargets).WatchSIGHUP(ctx)
+
+	grpcServer := setupGRPCServer(cfg, logger, coordinatorServer.NewCoordinatorServer(&coordinatorServer.CoordinatorServerConfig{
+		Logger:    logger,
+		Search:    searchService,
+		Documents: documentService,
+		Indexes:   indexService,
+		Tasks:     tasks,
+	}))
 	metricsServer := setupMetricsServer(cfg, metrics)
 
 	if cfg.Metrics.Enabled {
@@ -115,16 +216,25 @@ func main() {
 
 func initializeEngines(cfg *config.Config, logger *util.Logger) map[string]engine.EngineClient {
 	engines := make(map[string]engine.EngineClient)
+	readiness := engine.ReadinessConfig{
+		Attempts:       cfg.Engines.Readiness.Attempts,
+		InitialBackoff: cfg.Engines.Readiness.InitialBackoff,
+		MaxBackoff:     cfg.Engines.Readiness.MaxBackoff,
+	}
 
 	if cfg.Engines.FlexSearch.Enabled {
 		flexClient := engine.NewFlexSearchClient(&engine.ClientConfig{
-			Host:       cfg.Engines.FlexSearch.Host,
-			Port:       cfg.Engines.FlexSearch.Port,
-			Timeout:    cfg.Engines.FlexSearch.Timeout,
-			MaxRetries: cfg.Engines.FlexSearch.MaxRetries,
-			PoolSize:   cfg.Engines.FlexSearch.PoolSize,
+			Host:             cfg.Engines.FlexSearch.Host,
+			Port:             cfg.Engines.FlexSearch.Port,
+			Timeout:          cfg.Engines.FlexSearch.Timeout,
+			MaxRetries:       cfg.Engines.FlexSearch.MaxRetries,
+			MaxRetryElapsed:  cfg.Engines.FlexSearch.MaxRetryElapsed,
+			Jitter:           &cfg.Engines.FlexSearch.Jitter,
+			PoolSize:         cfg.Engines.FlexSearch.PoolSize,
+			KeepaliveTime:    cfg.Engines.FlexSearch.KeepaliveTime,
+			KeepaliveTimeout: cfg.Engines.FlexSearch.KeepaliveTimeout,
 		}, logger)
-		if err := flexClient.Connect(context.Background()); err != nil {
+		if err := engine.ConnectReady(context.Background(), flexClient, readiness); err != nil {
 			logger.Warnf("Failed to connect to FlexSearch: %v", err)
 		} else {
 			engines["flexsearch"] = flexClient
@@ -133,18 +243,22 @@ func initializeEngines(cfg *config.Config, logger *util.Logger) map[string]engin
 
 	if cfg.Engines.BM25.Enabled {
 		bm25Client := engine.NewBM25Client(&engine.ClientConfig{
-			Host:       cfg.Engines.BM25.Host,
-			Port:       cfg.Engines.BM25.Port,
-			Timeout:    cfg.Engines.BM25.Timeout,
-			MaxRetries: cfg.Engines.BM25.MaxRetries,
-			PoolSize:   cfg.Engines.BM25.PoolSize,
+			Host:             cfg.Engines.BM25.Host,
+			Port:             cfg.Engines.BM25.Port,
+			Timeout:          cfg.Engines.BM25.Timeout,
+			MaxRetries:       cfg.Engines.BM25.MaxRetries,
+			MaxRetryElapsed:  cfg.Engines.BM25.MaxRetryElapsed,
+			Jitter:           &cfg.Engines.BM25.Jitter,
+			PoolSize:         cfg.Engines.BM25.PoolSize,
+			KeepaliveTime:    cfg.Engines.BM25.KeepaliveTime,
+			KeepaliveTimeout: cfg.Engines.BM25.KeepaliveTimeout,
 		}, &engine.BM25EngineConfig{
 			K1:        cfg.Engines.BM25.K1,
 			B:         cfg.Engines.BM25.B,
 			MinLength: 2,
 			MaxLength: 100,
 		}, logger)
-		if err := bm25Client.Connect(context.Background()); err != nil {
+		if err := engine.ConnectReady(context.Background(), bm25Client, readiness); err != nil {
 			logger.Warnf("Failed to connect to BM25: %v", err)
 		} else {
 			engines["bm25"] = bm25Client
@@ -153,11 +267,15 @@ func initializeEngines(cfg *config.Config, logger *util.Logger) map[string]engin
 
 	if cfg.Engines.Vector.Enabled {
 		vectorClient, err := engine.NewVectorClient(&engine.ClientConfig{
-			Host:       cfg.Engines.Vector.Host,
-			Port:       cfg.Engines.Vector.Port,
-			Timeout:    cfg.Engines.Vector.Timeout,
-			MaxRetries: cfg.Engines.Vector.MaxRetries,
-			PoolSize:   cfg.Engines.Vector.PoolSize,
+			Host:             cfg.Engines.Vector.Host,
+			Port:             cfg.Engines.Vector.Port,
+			Timeout:          cfg.Engines.Vector.Timeout,
+			MaxRetries:       cfg.Engines.Vector.MaxRetries,
+			MaxRetryElapsed:  cfg.Engines.Vector.MaxRetryElapsed,
+			Jitter:           &cfg.Engines.Vector.Jitter,
+			PoolSize:         cfg.Engines.Vector.PoolSize,
+			KeepaliveTime:    cfg.Engines.Vector.KeepaliveTime,
+			KeepaliveTimeout: cfg.Engines.Vector.KeepaliveTimeout,
 		}, &engine.VectorEngineConfig{
 			Model:     cfg.Engines.Vector.Model,
 			Dimension: cfg.Engines.Vector.Dimension,
@@ -168,7 +286,7 @@ func initializeEngines(cfg *config.Config, logger *util.Logger) map[string]engin
 		}, logger)
 		if err != nil {
 			logger.Errorf("Failed to create Vector client: %v", err)
-		} else if err := vectorClient.Connect(context.Background()); err != nil {
+		} else if err := engine.ConnectReady(context.Background(), vectorClient, readiness); err != nil {
 			logger.Warnf("Failed to connect to Vector: %v", err)
 		} else {
 			engines["vector"] = vectorClient
@@ -179,7 +297,111 @@ func initializeEngines(cfg *config.Config, logger *util.Logger) map[string]engin
 	return engines
 }
 
-func setupGRPCServer(cfg *config.Config, logger *util.Logger, searchService *service.SearchService) *grpc.Server {
+// newRedisCache connects the search cache described by cfg, reporting its
+// gauges to metrics.
+func newRedisCache(cfg *config.Config, logger *util.Logger, metrics *util.Metrics) (*cache.RedisCache, error) {
+	return cache.NewRedisCache(&cache.CacheConfig{
+		Enabled:    cfg.Cache.Enabled,
+		Host:       cfg.Redis.Host,
+		Port:       cfg.Redis.Port,
+		Password:   cfg.Redis.Password,
+		DB:         cfg.Redis.DB,
+		PoolSize:   cfg.Redis.PoolSize,
+		DefaultTTL: cfg.Cache.DefaultTTL,
+		Metrics:    metrics,
+		LocalSize:  int(cfg.Cache.MaxSize),
+		LocalTTL:   cfg.Cache.LocalTTL,
+
+		Compress:        cfg.Cache.Compression,
+		CompressMinSize: cfg.Cache.CompressMinSize,
+
+		SoftTTL: cfg.Cache.SoftTTL,
+		HardTTL: cfg.Cache.HardTTL,
+	}, logger)
+}
+
+// newDocumentService builds the document service, dropping an index's
+// cached searches from Redis and every instance's local tier on writes and
+// applying rules to them through invalidator, when set.
+func newDocumentService(logger *util.Logger, engines map[string]engine.EngineClient, redisCache *cache.RedisCache, suggestions *suggest.Trie, bus *cache.InvalidationBus,
+	invalidator *sharedcache.CacheInvalidator, rules []sharedcache.InvalidationRule) *service.DocumentService {
+	return service.NewDocumentService(&service.DocumentServiceConfig{
+		Logger:            logger,
+		Engines:           engines,
+		Cache:             redisCache,
+		Invalidator:       invalidator,
+		InvalidationRules: rules,
+		Suggestions:       suggestions,
+		Bus:               bus,
+	})
+}
+
+// newInvalidator returns the invalidator that applies cache.invalidation_rules
+// to the Redis cache, or nil when there are no rules or no Redis.
+func newInvalidator(cfg *config.Config, redisCache *cache.RedisCache) *sharedcache.CacheInvalidator {
+	if len(cfg.Cache.InvalidationRules) == 0 || redisCache == nil || redisCache.Client() == nil {
+		return nil
+	}
+	return sharedcache.NewCacheInvalidator(redisCache.Client())
+}
+
+func invalidationRules(rules []config.InvalidationRuleConfig) []sharedcache.InvalidationRule {
+	out := make([]sharedcache.InvalidationRule, len(rules))
+	for i, rule := range rules {
+		out[i] = sharedcache.InvalidationRule{
+			Pattern:  rule.Pattern,
+			Strategy: sharedcache.InvalidationStrategy(rule.Strategy),
+			TTL:      rule.TTL,
+		}
+	}
+	return out
+}
+
+// setupTracing installs a tracer provider that samples with sampler and
+// batches spans to the configured exporter. It does nothing when tracing
+// is disabled or the exporter is none. The returned function flushes and
+// stops the provider.
+func setupTracing(ctx context.Context, cfg config.TracingConfig, sampler sdktrace.Sampler) (func(context.Context) error, error) {
+	if !cfg.Enabled || cfg.Exporter == "none" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := newSpanExporter(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(serviceName))),
+		sdktrace.WithSampler(sdktrace.ParentBased(sampler)),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+func newSpanExporter(ctx context.Context, cfg config.TracingConfig) (sdktrace.SpanExporter, error) {
+	switch cfg.Exporter {
+	case "", "stdout":
+		return stdouttrace.New()
+	case "otlp-grpc":
+		opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(cfg.Endpoint)}
+		if cfg.Insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		return otlptracegrpc.New(ctx, opts...)
+	case "otlp-http":
+		opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(cfg.Endpoint)}
+		if cfg.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		return otlptracehttp.New(ctx, opts...)
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter %q", cfg.Exporter)
+	}
+}
+
+func setupGRPCServer(cfg *config.Config, logger *util.Logger, coordinator *coordinatorServer.CoordinatorServer) *grpc.Server {
 	opts := []grpc.ServerOption{
 		grpc.MaxRecvMsgSize(cfg.GRPC.MaxRecvMsgSize),
 		grpc.MaxSendMsgSize(cfg.GRPC.MaxSendMsgSize),
@@ -187,11 +409,12 @@ func setupGRPCServer(cfg *config.Config, logger *util.Logger, searchService *ser
 
 	server := grpc.NewServer(opts...)
 
-	coordinatorServer.NewCoordinatorServer(logger, searchService)
+	coordinator.Register(server)
 
 	healthServer := health.NewServer()
 	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
 	healthServer.SetServingStatus(serviceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
 
 	reflection.Register(server)
 
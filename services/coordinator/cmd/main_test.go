@@ -0,0 +1,602 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	pb "github.com/flexsearch/api-gateway/proto"
+	"github.com/flexsearch/coordinator/internal/cache"
+	"github.com/flexsearch/coordinator/internal/config"
+	"github.com/flexsearch/coordinator/internal/engine"
+	"github.com/flexsearch/coordinator/internal/merger"
+	"github.com/flexsearch/coordinator/internal/model"
+	"github.com/flexsearch/coordinator/internal/router"
+	coordinatorServer "github.com/flexsearch/coordinator/internal/server"
+	"github.com/flexsearch/coordinator/internal/service"
+	"github.com/flexsearch/coordinator/internal/util"
+	"github.com/flexsearch/shared/codec"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+var testMetrics = util.NewMetrics("coordinator_main_test")
+
+type stubEngine struct {
+	name     string
+	mu       sync.Mutex
+	searches []*model.SearchRequest
+	// open reports the engine's breaker as tripped and the engine down.
+	open bool
+}
+
+func newStubEngines() (map[string]engine.EngineClient, []*stubEngine) {
+	engines := make(map[string]engine.EngineClient)
+	var stubs []*stubEngine
+	for _, name := range []string{"flexsearch", "bm25", "vector"} {
+		stub := &stubEngine{name: name}
+		engines[name] = stub
+		stubs = append(stubs, stub)
+	}
+	return engines, stubs
+}
+
+func (e *stubEngine) Connect(ctx context.Context) error { return nil }
+
+func (e *stubEngine) Disconnect() error { return nil }
+
+func (e *stubEngine) Search(ctx context.Context, req *model.SearchRequest) (*model.EngineResult, error) {
+	e.mu.Lock()
+	e.searches = append(e.searches, req)
+	e.mu.Unlock()
+	result := &model.EngineResult{Engine: e.name, Total: 8}
+	for i := 0; i < 8; i++ {
+		result.Results = append(result.Results, model.SearchResult{
+			ID:           fmt.Sprintf("doc-%d", i),
+			Index:        req.Index,
+			Score:        float64(8 - i),
+			Title:        fmt.Sprintf("Title %d", i),
+			EngineSource: e.name,
+		})
+	}
+	return result, nil
+}
+
+func (e *stubEngine) AddDocument(ctx context.Context, doc *model.DocumentRequest) error { return nil }
+
+func (e *stubEngine) DeleteDocument(ctx context.Context, index, id string) error { return nil }
+
+func (e *stubEngine) HealthCheck(ctx context.Context) bool { return !e.open }
+
+func (e *stubEngine) GetName() string { return e.name }
+
+func (e *stubEngine) GetCircuitBreakerState() string {
+	if e.open {
+		return "open"
+	}
+	return "closed"
+}
+
+func (e *stubEngine) GetCircuitBreakerFailures() int {
+	if e.open {
+		return 5
+	}
+	return 0
+}
+
+// dialAssembled serves what setupGRPCServer builds over an in-memory
+// listener and returns a client connection to it.
+func dialAssembled(t *testing.T, engines map[string]engine.EngineClient, redisCache *cache.RedisCache, opts ...grpc.DialOption) *grpc.ClientConn {
+	t.Helper()
+	return dialAssembledWithRules(t, engines, redisCache, nil, opts...)
+}
+
+// dialAssembledWithRules is dialAssembled with cache.invalidation_rules set
+// to rules.
+func dialAssembledWithRules(t *testing.T, engines map[string]engine.EngineClient, redisCache *cache.RedisCache, rules []config.InvalidationRuleConfig, opts ...grpc.DialOption) *grpc.ClientConn {
+	t.Helper()
+
+	logger, err := util.NewLogger("error", "json", "stdout")
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	cfg := &config.Config{}
+	cfg.GRPC.MaxRecvMsgSize = 4 << 20
+	cfg.GRPC.MaxSendMsgSize = 4 << 20
+	cfg.Cache.InvalidationRules = rules
+
+	documentService := newDocumentService(logger, engines, redisCache, nil, nil,
+		newInvalidator(cfg, redisCache), invalidationRules(cfg.Cache.InvalidationRules))
+	searchService := service.NewSearchService(&service.SearchServiceConfig{
+		Config:     cfg,
+		Logger:     logger,
+		Router:     router.NewRouter(logger),
+		Optimizer:  router.NewOptimizer(logger),
+		Merger:     merger.NewMerger("rrf", &merger.MergerConfig{RRFK: 60, TopK: 100}, logger),
+		Engines:    engines,
+		Metrics:    testMetrics,
+		Cache:      redisCache,
+		IndexTypes: documentService,
+	})
+	server := setupGRPCServer(cfg, logger, coordinatorServer.NewCoordinatorServer(&coordinatorServer.CoordinatorServerConfig{
+		Logger:    logger,
+		Search:    searchService,
+		Documents: documentService,
+		Indexes:   service.NewIndexService(&service.IndexServiceConfig{Logger: logger, Documents: documentService}),
+	}))
+
+	lis := bufconn.Listen(1 << 20)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	opts = append(opts,
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	conn, err := grpc.NewClient("passthrough:///bufnet", opts...)
+	if err != nil {
+		t.Fatalf("Failed to dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestAssembledServerServesSearch(t *testing.T) {
+	engines, stubs := newStubEngines()
+	conn := dialAssembled(t, engines, nil)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(),
+		"x-request-id", "req-42",
+		"x-tenant-id", "acme",
+	)
+	var resp coordinatorServer.SearchResponse
+	err := conn.Invoke(ctx, "/coordinator.SearchService/Search", &coordinatorServer.SearchRequest{
+		Query:    "golang",
+		Indexes:  []string{"books"},
+		Page:     2,
+		PageSize: 5,
+	}, &resp, grpc.CallContentSubtype(codec.Name))
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+
+	if len(resp.Results) != 3 || resp.Results[0].Id != "doc-5" {
+		t.Fatalf("Expected the second page to start at doc-5, got %+v", resp.Results)
+	}
+	if got := resp.Results[0].Fields["title"]; got != "Title 5" {
+		t.Errorf("Expected the title in the result fields, got %q", got)
+	}
+	if resp.Page != 2 || resp.PageSize != 5 || resp.TotalPages != 2 {
+		t.Errorf("Expected page 2 of 2 with size 5, got page %d of %d with size %d", resp.Page, resp.TotalPages, resp.PageSize)
+	}
+
+	var sent *model.SearchRequest
+	for _, stub := range stubs {
+		stub.mu.Lock()
+		if len(stub.searches) > 0 {
+			sent = stub.searches[0]
+		}
+		stub.mu.Unlock()
+	}
+	if sent == nil {
+		t.Fatal("Expected the search to reach an engine")
+	}
+	if sent.Index != "books" {
+		t.Errorf("Expected the first index to be searched, got %q", sent.Index)
+	}
+	if sent.RequestID != "req-42" || sent.Tenant != "acme" {
+		t.Errorf("Expected request ID and tenant from metadata, got %q and %q", sent.RequestID, sent.Tenant)
+	}
+}
+
+func TestAssembledServerServesHealth(t *testing.T) {
+	engines, _ := newStubEngines()
+	conn := dialAssembled(t, engines, nil)
+
+	var resp coordinatorServer.HealthCheckResponse
+	err := conn.Invoke(context.Background(), "/coordinator.Health/Check",
+		&coordinatorServer.HealthCheckRequest{Service: serviceName}, &resp,
+		grpc.CallContentSubtype(codec.Name))
+	if err != nil {
+		t.Fatalf("Health check failed: %v", err)
+	}
+	if resp.Status != "healthy" || resp.Details["bm25"] != "healthy" {
+		t.Errorf("Expected a healthy coordinator and engine, got %+v", resp)
+	}
+	if resp.Details["bm25.circuit_breaker"] != "closed" || resp.Details["bm25.failures"] != "0" {
+		t.Errorf("Expected the closed bm25 breaker to be reported, got %+v", resp.Details)
+	}
+
+	standard, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{Service: serviceName})
+	if err != nil {
+		t.Fatalf("Standard health check failed: %v", err)
+	}
+	if standard.Status != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("Expected SERVING, got %v", standard.Status)
+	}
+}
+
+func TestAssembledServerReportsOpenBreakers(t *testing.T) {
+	engines, stubs := newStubEngines()
+	for _, stub := range stubs {
+		stub.open = stub.name == "vector"
+	}
+	conn := dialAssembled(t, engines, nil, pb.WithJSONCodec())
+
+	resp, err := pb.NewHealthClient(conn).Check(context.Background(), &pb.HealthCheckRequest{Service: serviceName})
+	if err != nil {
+		t.Fatalf("Health check failed: %v", err)
+	}
+	if resp.Status != "degraded" || resp.Details["vector"] != "unhealthy" {
+		t.Errorf("Expected a degraded coordinator with vector down, got %+v", resp)
+	}
+	if resp.Details["vector.circuit_breaker"] != "open" || resp.Details["vector.failures"] != "5" {
+		t.Errorf("Expected the open vector breaker to be reported, got %+v", resp.Details)
+	}
+}
+
+func TestAssembledServerServesDocuments(t *testing.T) {
+	engines, _ := newStubEngines()
+	conn := dialAssembled(t, engines, nil)
+	codec := grpc.CallContentSubtype(codec.Name)
+
+	var added coordinatorServer.AddDocumentResponse
+	err := conn.Invoke(context.Background(), "/coordinator.DocumentService/AddDocument", &coordinatorServer.AddDocumentRequest{
+		IndexId: "books",
+		Fields:  map[string]string{"id": "doc-7", "title": "Go in action", "year": "2015"},
+	}, &added, codec)
+	if err != nil {
+		t.Fatalf("AddDocument failed: %v", err)
+	}
+	if !added.Success || added.Id != "doc-7" {
+		t.Fatalf("Expected doc-7 to be added, got %+v", added)
+	}
+
+	var doc coordinatorServer.DocumentResponse
+	err = conn.Invoke(context.Background(), "/coordinator.DocumentService/GetDocument",
+		&coordinatorServer.GetDocumentRequest{IndexId: "books", DocumentId: "doc-7"}, &doc, codec)
+	if err != nil {
+		t.Fatalf("GetDocument failed: %v", err)
+	}
+	if doc.Version != 1 || doc.Fields["title"] != "Go in action" || doc.Fields["year"] != "2015" {
+		t.Errorf("Expected the stored document at version 1, got %+v", doc)
+	}
+
+	err = conn.Invoke(context.Background(), "/coordinator.DocumentService/GetDocument",
+		&coordinatorServer.GetDocumentRequest{IndexId: "books", DocumentId: "missing"}, &doc, codec)
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound for a missing document, got %v", err)
+	}
+
+	err = conn.Invoke(context.Background(), "/coordinator.DocumentService/BatchDocuments",
+		&coordinatorServer.GetDocumentRequest{}, &doc, codec)
+	if status.Code(err) != codes.Unimplemented {
+		t.Errorf("Expected Unimplemented for an unserved method, got %v", err)
+	}
+}
+
+func TestAssembledServerWritesDropCachedSearches(t *testing.T) {
+	mr := miniredis.RunT(t)
+	port, _ := strconv.Atoi(mr.Port())
+	logger, err := util.NewLogger("error", "json", "stdout")
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	redisCache, err := cache.NewRedisCache(&cache.CacheConfig{
+		Enabled:    true,
+		Host:       mr.Host(),
+		Port:       port,
+		DefaultTTL: time.Minute,
+		LocalSize:  10,
+		LocalTTL:   time.Minute,
+	}, logger)
+	if err != nil {
+		t.Fatalf("Failed to create cache: %v", err)
+	}
+	t.Cleanup(func() { redisCache.Close() })
+
+	ctx := context.Background()
+	cached := &model.SearchRequest{Query: "golang", Index: "books", Limit: 10}
+	if err := redisCache.SetSearchResponse(ctx, cached, &model.SearchResponse{Total: 1}, 0); err != nil {
+		t.Fatalf("Failed to seed cache: %v", err)
+	}
+
+	engines, _ := newStubEngines()
+	conn := dialAssembled(t, engines, redisCache)
+
+	var added coordinatorServer.AddDocumentResponse
+	err = conn.Invoke(ctx, "/coordinator.DocumentService/AddDocument", &coordinatorServer.AddDocumentRequest{
+		IndexId: "books",
+		Fields:  map[string]string{"id": "doc-7", "title": "Go in action"},
+	}, &added, grpc.CallContentSubtype(codec.Name))
+	if err != nil {
+		t.Fatalf("AddDocument failed: %v", err)
+	}
+
+	if mr.Exists(redisCache.GenerateCacheKey(cached)) {
+		t.Error("Expected the write to drop the index's cached search from Redis")
+	}
+	if _, found := redisCache.GetSearchResponse(ctx, cached); found {
+		t.Error("Expected the write to drop the local copy too")
+	}
+}
+
+func TestAssembledServerAppliesInvalidationRules(t *testing.T) {
+	mr := miniredis.RunT(t)
+	port, _ := strconv.Atoi(mr.Port())
+	logger, err := util.NewLogger("error", "json", "stdout")
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	redisCache, err := cache.NewRedisCache(&cache.CacheConfig{
+		Enabled:    true,
+		Host:       mr.Host(),
+		Port:       port,
+		DefaultTTL: time.Minute,
+	}, logger)
+	if err != nil {
+		t.Fatalf("Failed to create cache: %v", err)
+	}
+	t.Cleanup(func() { redisCache.Close() })
+
+	// An untagged key under the index's prefix that only the rule reaches.
+	const legacy = "search:books:legacy"
+	mr.Set(legacy, "{}")
+
+	engines, _ := newStubEngines()
+	conn := dialAssembledWithRules(t, engines, redisCache, []config.InvalidationRuleConfig{
+		{Pattern: "search:books:*", Strategy: "time", TTL: 30 * time.Second},
+	})
+
+	var added coordinatorServer.AddDocumentResponse
+	err = conn.Invoke(context.Background(), "/coordinator.DocumentService/AddDocument", &coordinatorServer.AddDocumentRequest{
+		IndexId: "books",
+		Fields:  map[string]string{"id": "doc-7", "title": "Go in action"},
+	}, &added, grpc.CallContentSubtype(codec.Name))
+	if err != nil {
+		t.Fatalf("AddDocument failed: %v", err)
+	}
+
+	if ttl := mr.TTL(legacy); ttl != 30*time.Second {
+		t.Errorf("Expected the time rule to expire %s in 30s, got a TTL of %v", legacy, ttl)
+	}
+}
+
+// TestGatewayClientReachesServer calls the server through the gateway's
+// client stubs and dial option, as the gateway does.
+func TestGatewayClientReachesServer(t *testing.T) {
+	engines, _ := newStubEngines()
+	conn := dialAssembled(t, engines, nil, pb.WithJSONCodec())
+	ctx := context.Background()
+
+	resp, err := pb.NewSearchServiceClient(conn).Search(ctx, &pb.SearchRequest{
+		Query:    "golang",
+		Indexes:  []string{"books"},
+		Page:     1,
+		PageSize: 5,
+	})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(resp.Results) != 5 || resp.Results[0].Id != "doc-0" || resp.Total != 8 {
+		t.Errorf("Expected the first page of 8 results, got %d results of %d", len(resp.Results), resp.Total)
+	}
+
+	health, err := pb.NewHealthClient(conn).Check(ctx, &pb.HealthCheckRequest{Service: serviceName})
+	if err != nil {
+		t.Fatalf("Health check failed: %v", err)
+	}
+	if health.Status != "healthy" {
+		t.Errorf("Expected a healthy coordinator, got %s", health.Status)
+	}
+}
+
+func TestAssembledServerDefaultsPageSize(t *testing.T) {
+	engines, _ := newStubEngines()
+	conn := dialAssembled(t, engines, nil, pb.WithJSONCodec())
+
+	resp, err := pb.NewSearchServiceClient(conn).Search(context.Background(), &pb.SearchRequest{
+		Query:   "golang",
+		Indexes: []string{"books"},
+	})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if resp.Page != 1 || resp.PageSize != 10 || resp.TotalPages != 1 {
+		t.Errorf("Expected page 1 of 1 with the default size 10, got page %d of %d with size %d", resp.Page, resp.TotalPages, resp.PageSize)
+	}
+}
+
+func TestAssembledServerForwardsPerEngineLimit(t *testing.T) {
+	engines, stubs := newStubEngines()
+	conn := dialAssembled(t, engines, nil, pb.WithJSONCodec())
+
+	if _, err := pb.NewSearchServiceClient(conn).Search(context.Background(), &pb.SearchRequest{
+		Query:          "golang",
+		Indexes:        []string{"books"},
+		PageSize:       5,
+		PerEngineLimit: 40,
+	}); err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+
+	searched := 0
+	for _, stub := range stubs {
+		stub.mu.Lock()
+		searched += len(stub.searches)
+		for _, req := range stub.searches {
+			if req.Limit != 40 {
+				t.Errorf("Expected %s to be asked for 40 candidates, got %d", stub.name, req.Limit)
+			}
+		}
+		stub.mu.Unlock()
+	}
+	if searched == 0 {
+		t.Fatal("Expected at least one engine to be searched")
+	}
+}
+
+func TestAssembledServerRejectsSeveralIndexes(t *testing.T) {
+	engines, stubs := newStubEngines()
+	conn := dialAssembled(t, engines, nil, pb.WithJSONCodec())
+	client := pb.NewSearchServiceClient(conn)
+
+	_, err := client.Search(context.Background(), &pb.SearchRequest{
+		Query:   "golang",
+		Indexes: []string{"books", "articles"},
+	})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for two indexes, got %v", err)
+	}
+
+	_, err = client.MultiSearch(context.Background(), &pb.MultiSearchRequest{Requests: []*pb.SearchRequest{
+		{Query: "golang", Indexes: []string{"books"}},
+		{Query: "golang", Indexes: []string{"books", "articles"}},
+	}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for a multi-search naming two indexes, got %v", err)
+	}
+
+	for _, stub := range stubs {
+		if len(stub.searches) > 0 {
+			t.Errorf("Expected no engine to be searched, %s was", stub.name)
+		}
+	}
+}
+
+func TestAssembledServerRoutesByIndexType(t *testing.T) {
+	engines, stubs := newStubEngines()
+	conn := dialAssembled(t, engines, nil, pb.WithJSONCodec())
+	ctx := context.Background()
+
+	created, err := pb.NewIndexServiceClient(conn).CreateIndex(ctx, &pb.CreateIndexRequest{Name: "tags", IndexType: "keyword"})
+	if err != nil || !created.Success {
+		t.Fatalf("CreateIndex failed: %v", err)
+	}
+	if _, err := pb.NewSearchServiceClient(conn).Search(ctx, &pb.SearchRequest{
+		Query:   "how does a search engine rank all these pages",
+		Indexes: []string{"tags"},
+	}); err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+
+	for _, stub := range stubs {
+		stub.mu.Lock()
+		searched := len(stub.searches) > 0
+		stub.mu.Unlock()
+		if searched && stub.name == "vector" {
+			t.Error("Expected the vector engine to be skipped for a keyword index")
+		}
+	}
+}
+
+func TestRedisCacheReportsGauges(t *testing.T) {
+	mr := miniredis.RunT(t)
+	port, _ := strconv.Atoi(mr.Port())
+	logger, err := util.NewLogger("error", "json", "stdout")
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	cfg := &config.Config{
+		Redis: config.RedisConfig{Host: mr.Host(), Port: port},
+		Cache: config.CacheConfig{Enabled: true, DefaultTTL: time.Minute},
+	}
+	reg := prometheus.NewRegistry()
+	redisCache, err := newRedisCache(cfg, logger, util.NewMetricsWithRegistry("coordinator_main_gauges", reg))
+	if err != nil {
+		t.Fatalf("Failed to create cache: %v", err)
+	}
+	t.Cleanup(func() { redisCache.Close() })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := redisCache.Set(ctx, "a", []byte("1"), 0); err != nil {
+		t.Fatalf("Failed to set key: %v", err)
+	}
+	redisCache.Get(ctx, "a")
+	redisCache.Get(ctx, "missing")
+	go redisCache.ReportStats(ctx, 10*time.Millisecond)
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		if hitRate := gaugeValue(t, reg, "coordinator_main_gauges_cache_hit_rate"); hitRate == 0.5 {
+			return
+		} else if time.Now().After(deadline) {
+			t.Fatalf("Expected the reporter to set the hit rate gauge to 0.5, got %v", hitRate)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func gaugeValue(t *testing.T, reg *prometheus.Registry, name string) float64 {
+	t.Helper()
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("Failed to gather metrics: %v", err)
+	}
+	for _, family := range families {
+		if family.GetName() == name && len(family.GetMetric()) > 0 {
+			return family.GetMetric()[0].GetGauge().GetValue()
+		}
+	}
+	return 0
+}
+
+func TestSetupTracingFollowsSampleRate(t *testing.T) {
+	previous := otel.GetTracerProvider()
+	defer otel.SetTracerProvider(previous)
+
+	ctx := context.Background()
+	sampler := util.NewRatioSampler(0)
+	shutdown, err := setupTracing(ctx, config.TracingConfig{Enabled: true, Exporter: "stdout"}, sampler)
+	if err != nil {
+		t.Fatalf("setupTracing failed: %v", err)
+	}
+	defer shutdown(ctx)
+
+	tracer := otel.Tracer("coordinator_main_test")
+	_, span := tracer.Start(ctx, "dropped")
+	span.End()
+	if span.SpanContext().IsSampled() {
+		t.Error("Expected no spans to be sampled at rate 0")
+	}
+
+	sampler.SetRate(1)
+	_, span = tracer.Start(ctx, "kept")
+	span.End()
+	if !span.SpanContext().IsSampled() {
+		t.Error("Expected the reloaded rate to sample spans")
+	}
+}
+
+func TestSetupTracingDisabled(t *testing.T) {
+	previous := otel.GetTracerProvider()
+	defer otel.SetTracerProvider(previous)
+
+	for _, cfg := range []config.TracingConfig{
+		{Enabled: false, Exporter: "stdout"},
+		{Enabled: true, Exporter: "none"},
+	} {
+		if _, err := setupTracing(context.Background(), cfg, util.NewRatioSampler(1)); err != nil {
+			t.Fatalf("setupTracing(%+v) failed: %v", cfg, err)
+		}
+		if otel.GetTracerProvider() != previous {
+			t.Errorf("Expected setupTracing(%+v) to leave the tracer provider alone", cfg)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package cache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/flexsearch/coordinator/internal/util"
+	sharedcache "github.com/flexsearch/shared/cache"
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultInvalidationChannel is the pub/sub channel coordinators share
+// index invalidations on.
+const DefaultInvalidationChannel = "coordinator:cache:invalidate"
+
+// InvalidationBus tells other coordinator instances that an index changed.
+// Redis entries are shared, but each instance keeps its own local tier,
+// which a write elsewhere would otherwise leave stale until it expires.
+type InvalidationBus struct {
+	cache       *RedisCache
+	channel     string
+	origin      string
+	invalidator *sharedcache.CacheInvalidator
+	logger      *util.Logger
+
+	mu     sync.Mutex
+	pubsub *redis.PubSub
+	done   chan struct{}
+}
+
+type InvalidationBusConfig struct {
+	// Channel defaults to DefaultInvalidationChannel.
+	Channel string
+	// Invalidator, when set, applies its rules to the index's keys on
+	// every message received from another instance.
+	Invalidator *sharedcache.CacheInvalidator
+}
+
+// invalidationMessage is published for every invalidated index. Origin
+// identifies the publishing bus so it can skip its own messages.
+type invalidationMessage struct {
+	Index  string `json:"index"`
+	Origin string `json:"origin"`
+}
+
+func NewInvalidationBus(cache *RedisCache, config InvalidationBusConfig, logger *util.Logger) *InvalidationBus {
+	channel := config.Channel
+	if channel == "" {
+		channel = DefaultInvalidationChannel
+	}
+
+	origin := make([]byte, 8)
+	rand.Read(origin)
+
+	return &InvalidationBus{
+		cache:       cache,
+		channel:     channel,
+		origin:      hex.EncodeToString(origin),
+		invalidator: config.Invalidator,
+		logger:      logger,
+	}
+}
+
+// Start subscribes to the channel and returns once the subscription is
+// confirmed, handling messages in the background until ctx is done or
+// Close is called.
+func (b *InvalidationBus) Start(ctx context.Context) error {
+	if !b.cache.IsEnabled() {
+		return nil
+	}
+
+	pubsub := b.cache.client.Subscribe(ctx, b.channel)
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return fmt.Errorf("failed to subscribe to %s: %w", b.channel, err)
+	}
+
+	done := make(chan struct{})
+	b.mu.Lock()
+	b.pubsub, b.done = pubsub, done
+	b.mu.Unlock()
+
+	go func() {
+		defer close(done)
+		messages := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				pubsub.Close()
+				return
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+				b.handle(ctx, msg.Payload)
+			}
+		}
+	}()
+
+	b.logger.Infof("Subscribed to cache invalidations on %s", b.channel)
+	return nil
+}
+
+func (b *InvalidationBus) handle(ctx context.Context, payload string) {
+	var msg invalidationMessage
+	if err := json.Unmarshal([]byte(payload), &msg); err != nil {
+		b.logger.Warnf("Ignoring malformed cache invalidation %q: %v", payload, err)
+		return
+	}
+	if msg.Origin == b.origin || msg.Index == "" {
+		return
+	}
+
+	if b.cache.local != nil {
+		b.cache.local.DeleteByPrefix(ctx, IndexKeyPrefix(msg.Index))
+	}
+	if b.invalidator != nil {
+		if err := b.invalidator.InvalidatePattern(ctx, IndexKeyPattern(msg.Index)); err != nil {
+			b.logger.Warnf("Cache invalidation for index %s failed: %v", msg.Index, err)
+		}
+	}
+	b.logger.Debugf("Applied cache invalidation for index %s from %s", msg.Index, msg.Origin)
+}
+
+// Publish tells the other instances that index changed.
+func (b *InvalidationBus) Publish(ctx context.Context, index string) error {
+	if !b.cache.IsEnabled() {
+		return nil
+	}
+
+	payload, err := json.Marshal(invalidationMessage{Index: index, Origin: b.origin})
+	if err != nil {
+		return err
+	}
+	if err := b.cache.client.Publish(ctx, b.channel, payload).Err(); err != nil {
+		return fmt.Errorf("failed to publish invalidation for %s: %w", index, err)
+	}
+	return nil
+}
+
+// Close unsubscribes and waits for the message handler to stop.
+func (b *InvalidationBus) Close() error {
+	b.mu.Lock()
+	pubsub, done := b.pubsub, b.done
+	b.pubsub, b.done = nil, nil
+	b.mu.Unlock()
+
+	if pubsub == nil {
+		return nil
+	}
+	select {
+	case <-done:
+		// The handler already closed it when its context ended.
+		return nil
+	default:
+	}
+	err := pubsub.Close()
+	<-done
+	return err
+}
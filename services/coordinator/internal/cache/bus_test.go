@@ -0,0 +1,108 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	sharedcache "github.com/flexsearch/shared/cache"
+)
+
+func startTestBus(t *testing.T, c *RedisCache, config InvalidationBusConfig) *InvalidationBus {
+	t.Helper()
+
+	bus := NewInvalidationBus(c, config, c.logger)
+	if err := bus.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	t.Cleanup(func() { bus.Close() })
+	return bus
+}
+
+func waitForLocalMiss(t *testing.T, c *RedisCache, key string) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if _, ok := c.Local().Get(context.Background(), key); !ok {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("Expected %s to be dropped from the local tier", key)
+}
+
+func TestInvalidationBusClearsOtherInstances(t *testing.T) {
+	mr := miniredis.RunT(t)
+	local := func(config *CacheConfig) { config.LocalSize = 10 }
+	first, second := newTestRedisCacheOn(t, mr, local), newTestRedisCacheOn(t, mr, local)
+	publisher := startTestBus(t, first, InvalidationBusConfig{})
+	startTestBus(t, second, InvalidationBusConfig{})
+
+	ctx := context.Background()
+	docs, other := IndexKeyPrefix("docs")+"a", IndexKeyPrefix("other")+"a"
+	for _, key := range []string{docs, other} {
+		if err := second.Set(ctx, key, []byte("cached"), time.Minute); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+	}
+
+	if err := publisher.Publish(ctx, "docs"); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+
+	waitForLocalMiss(t, second, docs)
+	if _, ok := second.Local().Get(ctx, other); !ok {
+		t.Error("Expected other indexes to keep their local entries")
+	}
+}
+
+func TestInvalidationBusAppliesInvalidatorRules(t *testing.T) {
+	mr := miniredis.RunT(t)
+	local := func(config *CacheConfig) { config.LocalSize = 10 }
+	first, second := newTestRedisCacheOn(t, mr, local), newTestRedisCacheOn(t, mr, local)
+
+	invalidator := sharedcache.NewCacheInvalidator(second.Client())
+	invalidator.AddRule(sharedcache.InvalidationRule{Pattern: "search:*", Strategy: sharedcache.InvalidationStrategyManual})
+	publisher := startTestBus(t, first, InvalidationBusConfig{Channel: "test:invalidate"})
+	startTestBus(t, second, InvalidationBusConfig{Channel: "test:invalidate", Invalidator: invalidator})
+
+	ctx := context.Background()
+	key := IndexKeyPrefix("docs") + "a"
+	if err := second.Set(ctx, key, []byte("cached"), time.Minute); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	if err := publisher.Publish(ctx, "docs"); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+
+	waitForLocalMiss(t, second, key)
+	deadline := time.Now().Add(time.Second)
+	for mr.Exists(key) {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the invalidator to delete the Redis entry")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestInvalidationBusSkipsOwnMessages(t *testing.T) {
+	c, _ := newTestRedisCacheWith(t, func(config *CacheConfig) { config.LocalSize = 10 })
+	bus := startTestBus(t, c, InvalidationBusConfig{})
+
+	ctx := context.Background()
+	key := IndexKeyPrefix("docs") + "a"
+	c.Set(ctx, key, []byte("cached"), time.Minute)
+
+	bus.handle(ctx, `{"index":"docs","origin":"`+bus.origin+`"}`)
+	if _, ok := c.Local().Get(ctx, key); !ok {
+		t.Error("Expected a bus to ignore its own invalidations")
+	}
+
+	bus.handle(ctx, `{"index":"docs","origin":"elsewhere"}`)
+	if _, ok := c.Local().Get(ctx, key); ok {
+		t.Error("Expected another instance's invalidation to apply")
+	}
+}
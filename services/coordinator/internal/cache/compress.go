@@ -0,0 +1,51 @@
+package cache
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+)
+
+// gzipMarker prefixes compressed entries. Cached responses are JSON
+// objects, which always start with '{', so entries written before
+// compression was enabled still decode as they are.
+const gzipMarker byte = 0x01
+
+// defaultCompressMinSize is the smallest entry compressed when
+// CacheConfig.CompressMinSize is unset; below it gzip's header outweighs
+// the savings.
+const defaultCompressMinSize = 1024
+
+func compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte(gzipMarker)
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// decompress undoes compress, returning data unchanged when it carries no
+// marker.
+func decompress(data []byte) ([]byte, error) {
+	if len(data) == 0 || data[0] != gzipMarker {
+		return data, nil
+	}
+
+	r, err := gzip.NewReader(bytes.NewReader(data[1:]))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open compressed entry: %w", err)
+	}
+	defer r.Close()
+
+	out, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress entry: %w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,166 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/flexsearch/coordinator/internal/model"
+)
+
+// LRUCache is a bounded in-process Cache that evicts the least recently
+// used entry once it holds maxSize entries. It sits in front of Redis as
+// the first tier of RedisCache, but works on its own too.
+type LRUCache struct {
+	mu      sync.Mutex
+	maxSize int
+	entries map[string]*list.Element
+	order   *list.List // front is most recently used
+	stats   model.CacheStats
+	now     func() time.Time
+}
+
+type lruEntry struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+// NewLRUCache returns a cache of at most maxSize entries. A maxSize below
+// one is treated as one.
+func NewLRUCache(maxSize int) *LRUCache {
+	if maxSize < 1 {
+		maxSize = 1
+	}
+	return &LRUCache{
+		maxSize: maxSize,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+		stats:   model.CacheStats{MaxSize: int64(maxSize)},
+		now:     time.Now,
+	}
+}
+
+func (c *LRUCache) Get(_ context.Context, key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if ok && c.expired(elem.Value.(*lruEntry)) {
+		c.remove(elem)
+		ok = false
+	}
+	if !ok {
+		c.stats.Misses++
+		return nil, false
+	}
+
+	c.order.MoveToFront(elem)
+	c.stats.Hits++
+	return elem.Value.(*lruEntry).value, true
+}
+
+// Set stores value for ttl; a ttl of zero or less never expires, leaving
+// the entry to be evicted.
+func (c *LRUCache) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var expires time.Time
+	if ttl > 0 {
+		expires = c.now().Add(ttl)
+	}
+
+	if elem, ok := c.entries[key]; ok {
+		entry := elem.Value.(*lruEntry)
+		entry.value, entry.expires = value, expires
+		c.order.MoveToFront(elem)
+		return nil
+	}
+
+	c.entries[key] = c.order.PushFront(&lruEntry{key: key, value: value, expires: expires})
+	for c.order.Len() > c.maxSize {
+		c.remove(c.order.Back())
+	}
+	return nil
+}
+
+func (c *LRUCache) GetMulti(ctx context.Context, keys []string) (map[string][]byte, error) {
+	found := make(map[string][]byte, len(keys))
+	for _, key := range keys {
+		if val, ok := c.Get(ctx, key); ok {
+			found[key] = val
+		}
+	}
+	return found, nil
+}
+
+func (c *LRUCache) SetMulti(ctx context.Context, entries map[string][]byte, ttl time.Duration) error {
+	for key, value := range entries {
+		c.Set(ctx, key, value, ttl)
+	}
+	return nil
+}
+
+func (c *LRUCache) Delete(_ context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		c.remove(elem)
+	}
+	return nil
+}
+
+// DeleteByPrefix drops every entry whose key starts with prefix.
+func (c *LRUCache) DeleteByPrefix(_ context.Context, prefix string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, elem := range c.entries {
+		if strings.HasPrefix(key, prefix) {
+			c.remove(elem)
+		}
+	}
+	return nil
+}
+
+func (c *LRUCache) Clear(_ context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = make(map[string]*list.Element)
+	c.order.Init()
+	return nil
+}
+
+func (c *LRUCache) GetStats() *model.CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := c.stats
+	stats.Size = int64(c.order.Len())
+	if total := stats.Hits + stats.Misses; total > 0 {
+		stats.HitRate = float64(stats.Hits) / float64(total)
+	}
+	return &stats
+}
+
+// Len is the number of entries held, including expired ones not yet
+// dropped.
+func (c *LRUCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+func (c *LRUCache) expired(entry *lruEntry) bool {
+	return !entry.expires.IsZero() && !c.now().Before(entry.expires)
+}
+
+func (c *LRUCache) remove(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.entries, elem.Value.(*lruEntry).key)
+}
@@ -0,0 +1,70 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestLRUCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	c := NewLRUCache(2)
+	ctx := context.Background()
+
+	c.Set(ctx, "a", []byte("1"), 0)
+	c.Set(ctx, "b", []byte("2"), 0)
+	c.Get(ctx, "a")
+	c.Set(ctx, "c", []byte("3"), 0)
+
+	if _, ok := c.Get(ctx, "b"); ok {
+		t.Error("Expected b, the least recently used entry, to be evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok := c.Get(ctx, key); !ok {
+			t.Errorf("Expected %s to survive eviction", key)
+		}
+	}
+	if stats := c.GetStats(); stats.Size != 2 || stats.MaxSize != 2 {
+		t.Errorf("Expected size 2 of 2, got %d of %d", stats.Size, stats.MaxSize)
+	}
+}
+
+func TestLRUCacheExpiresEntries(t *testing.T) {
+	c := NewLRUCache(10)
+	ctx := context.Background()
+	now := time.Unix(1000, 0)
+	c.now = func() time.Time { return now }
+
+	c.Set(ctx, "short", []byte("1"), time.Second)
+	c.Set(ctx, "forever", []byte("2"), 0)
+	now = now.Add(2 * time.Second)
+
+	if _, ok := c.Get(ctx, "short"); ok {
+		t.Error("Expected the expired entry to miss")
+	}
+	if _, ok := c.Get(ctx, "forever"); !ok {
+		t.Error("Expected an entry without a TTL to stay")
+	}
+	if c.Len() != 1 {
+		t.Errorf("Expected the expired entry to be dropped, got %d entries", c.Len())
+	}
+}
+
+func TestLRUCacheDeleteByPrefix(t *testing.T) {
+	c := NewLRUCache(10)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		c.Set(ctx, fmt.Sprintf("search:docs:%d", i), []byte("x"), 0)
+	}
+	c.Set(ctx, "search:other:0", []byte("x"), 0)
+
+	c.DeleteByPrefix(ctx, IndexKeyPrefix("docs"))
+
+	if c.Len() != 1 {
+		t.Fatalf("Expected only the other index to remain, got %d entries", c.Len())
+	}
+	if _, ok := c.Get(ctx, "search:other:0"); !ok {
+		t.Error("Expected the other index to be left untouched")
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/flexsearch/coordinator/internal/model"
@@ -16,17 +18,41 @@ import (
 type Cache interface {
 	Get(ctx context.Context, key string) ([]byte, bool)
 	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// GetMulti returns the values of the keys that are present; absent
+	// keys are left out of the map.
+	GetMulti(ctx context.Context, keys []string) (map[string][]byte, error)
+	SetMulti(ctx context.Context, entries map[string][]byte, ttl time.Duration) error
 	Delete(ctx context.Context, key string) error
 	Clear(ctx context.Context) error
 	GetStats() *model.CacheStats
 }
 
+type SearchExecutor func(ctx context.Context, req *model.SearchRequest) (*model.SearchResponse, error)
+
+const warmupWorkers = 4
+
 type RedisCache struct {
 	client     *redis.Client
 	logger     *util.Logger
 	defaultTTL time.Duration
+	ttlMu      sync.RWMutex
 	stats      *model.CacheStats
+	statsMu    sync.Mutex
 	enabled    bool
+	metrics    *util.Metrics
+
+	// local is the in-process tier checked before Redis; nil without one.
+	local    *LRUCache
+	localTTL time.Duration
+
+	compress        bool
+	compressMinSize int
+
+	// softTTL and hardTTL enable stale-while-revalidate when softTTL is
+	// positive; see CacheConfig.
+	softTTL time.Duration
+	hardTTL time.Duration
+	now     func() time.Time
 }
 
 type CacheConfig struct {
@@ -37,6 +63,27 @@ type CacheConfig struct {
 	DB         int
 	PoolSize   int
 	DefaultTTL time.Duration
+	// Metrics, when set, receives the hit rate and size gauges on every
+	// GetStats call.
+	Metrics *util.Metrics
+	// LocalSize, when positive, puts an in-process LRU tier of that many
+	// entries in front of Redis.
+	LocalSize int
+	// LocalTTL caps how long an entry stays in the local tier, bounding how
+	// stale it can get when another instance invalidates Redis. Zero keeps
+	// entries for as long as they live in Redis.
+	LocalTTL time.Duration
+	// Compress gzips search responses of at least CompressMinSize bytes
+	// before storing them; zero means 1KiB. Uncompressed entries are read
+	// either way.
+	Compress        bool
+	CompressMinSize int
+	// SoftTTL, when positive, turns on stale-while-revalidate for search
+	// responses: they are fresh for SoftTTL, then served stale until
+	// HardTTL, when Redis expires them. A HardTTL no longer than SoftTTL
+	// leaves no stale window.
+	SoftTTL time.Duration
+	HardTTL time.Duration
 }
 
 func NewRedisCache(config *CacheConfig, logger *util.Logger) (*RedisCache, error) {
@@ -46,6 +93,8 @@ func NewRedisCache(config *CacheConfig, logger *util.Logger) (*RedisCache, error
 			defaultTTL: config.DefaultTTL,
 			stats:      &model.CacheStats{},
 			enabled:    false,
+			metrics:    config.Metrics,
+			now:        time.Now,
 		}, nil
 	}
 
@@ -69,56 +118,237 @@ func NewRedisCache(config *CacheConfig, logger *util.Logger) (*RedisCache, error
 		defaultTTL: config.DefaultTTL,
 		stats:      &model.CacheStats{},
 		enabled:    true,
+		metrics:    config.Metrics,
+		localTTL:   config.LocalTTL,
+
+		compress:        config.Compress,
+		compressMinSize: config.CompressMinSize,
+
+		softTTL: config.SoftTTL,
+		hardTTL: config.HardTTL,
+		now:     time.Now,
+	}
+	if cache.compressMinSize <= 0 {
+		cache.compressMinSize = defaultCompressMinSize
+	}
+	if config.LocalSize > 0 {
+		cache.local = NewLRUCache(config.LocalSize)
 	}
 
 	logger.Info("Redis cache initialized successfully")
 	return cache, nil
 }
 
+// DefaultTTL is the expiry applied to entries stored without an explicit TTL.
+func (c *RedisCache) DefaultTTL() time.Duration {
+	c.ttlMu.RLock()
+	defer c.ttlMu.RUnlock()
+	return c.defaultTTL
+}
+
+// SetDefaultTTL changes the default expiry for entries written from now on;
+// existing entries keep the TTL they were stored with.
+func (c *RedisCache) SetDefaultTTL(ttl time.Duration) {
+	c.ttlMu.Lock()
+	defer c.ttlMu.Unlock()
+	c.defaultTTL = ttl
+}
+
+// Local is the in-process tier in front of Redis, or nil without one.
+func (c *RedisCache) Local() *LRUCache {
+	return c.local
+}
+
+// Get checks the local tier first, then Redis, copying a Redis hit into
+// the local tier for the rest of its TTL.
 func (c *RedisCache) Get(ctx context.Context, key string) ([]byte, bool) {
 	if !c.enabled {
 		return nil, false
 	}
 
+	if c.local != nil {
+		if val, ok := c.local.Get(ctx, key); ok {
+			c.statsMu.Lock()
+			c.stats.Hits++
+			c.updateHitRate()
+			c.statsMu.Unlock()
+			c.logger.Debugf("Local cache hit for key: %s", key)
+			return val, true
+		}
+	}
+
 	val, err := c.client.Get(ctx, key).Bytes()
 	if err != nil {
 		if err != redis.Nil {
 			c.logger.Errorf("Cache get error: %v", err)
 		}
+		c.statsMu.Lock()
 		c.stats.Misses++
+		c.statsMu.Unlock()
 		return nil, false
 	}
 
+	c.statsMu.Lock()
 	c.stats.Hits++
 	c.updateHitRate()
+	c.statsMu.Unlock()
 	c.logger.Debugf("Cache hit for key: %s", key)
+	c.promote(ctx, key, val)
 	return val, true
 }
 
+// promote copies a Redis hit into the local tier, expiring it with the
+// Redis entry or after localTTL, whichever comes first.
+func (c *RedisCache) promote(ctx context.Context, key string, val []byte) {
+	if c.local == nil {
+		return
+	}
+
+	ttl := c.localTTL
+	if remaining, err := c.client.PTTL(ctx, key).Result(); err == nil && remaining > 0 && (ttl <= 0 || remaining < ttl) {
+		ttl = remaining
+	}
+	c.local.Set(ctx, key, val, ttl)
+}
+
+func (c *RedisCache) setLocal(ctx context.Context, key string, value []byte, ttl time.Duration) {
+	if c.local == nil {
+		return
+	}
+	if c.localTTL > 0 && c.localTTL < ttl {
+		ttl = c.localTTL
+	}
+	c.local.Set(ctx, key, value, ttl)
+}
+
 func (c *RedisCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
 	if !c.enabled {
 		return nil
 	}
 
 	if ttl <= 0 {
-		ttl = c.defaultTTL
+		ttl = c.DefaultTTL()
 	}
 
 	if err := c.client.Set(ctx, key, value, ttl).Err(); err != nil {
 		c.logger.Errorf("Cache set error: %v", err)
 		return err
 	}
+	c.setLocal(ctx, key, value, ttl)
 
-	c.stats.Size++
 	c.logger.Debugf("Cache set for key: %s, TTL: %v", key, ttl)
 	return nil
 }
 
+// GetMulti fetches keys in one MGET, after serving what it can from the
+// local tier. Each key counts as a hit or a miss.
+func (c *RedisCache) GetMulti(ctx context.Context, keys []string) (map[string][]byte, error) {
+	found := make(map[string][]byte, len(keys))
+	if !c.enabled || len(keys) == 0 {
+		return found, nil
+	}
+
+	remote := keys
+	if c.local != nil {
+		local, _ := c.local.GetMulti(ctx, keys)
+		for key, val := range local {
+			found[key] = val
+		}
+		remote = make([]string, 0, len(keys)-len(local))
+		for _, key := range keys {
+			if _, ok := local[key]; !ok {
+				remote = append(remote, key)
+			}
+		}
+	}
+
+	var promoted []string
+	if len(remote) > 0 {
+		vals, err := c.client.MGet(ctx, remote...).Result()
+		if err != nil {
+			c.logger.Errorf("Cache multi-get error: %v", err)
+			c.statsMu.Lock()
+			c.stats.Misses += int64(len(remote))
+			c.updateHitRate()
+			c.statsMu.Unlock()
+			return found, err
+		}
+		for i, val := range vals {
+			if s, ok := val.(string); ok {
+				found[remote[i]] = []byte(s)
+				promoted = append(promoted, remote[i])
+			}
+		}
+	}
+
+	c.statsMu.Lock()
+	c.stats.Hits += int64(len(found))
+	c.stats.Misses += int64(len(keys) - len(found))
+	c.updateHitRate()
+	c.statsMu.Unlock()
+
+	c.promoteMulti(ctx, promoted, found)
+	return found, nil
+}
+
+// promoteMulti is promote for a batch, reading the remaining TTLs in one
+// pipeline.
+func (c *RedisCache) promoteMulti(ctx context.Context, keys []string, vals map[string][]byte) {
+	if c.local == nil || len(keys) == 0 {
+		return
+	}
+
+	pipe := c.client.Pipeline()
+	ttls := make([]*redis.DurationCmd, len(keys))
+	for i, key := range keys {
+		ttls[i] = pipe.PTTL(ctx, key)
+	}
+	pipe.Exec(ctx)
+
+	for i, key := range keys {
+		ttl := c.localTTL
+		if remaining, err := ttls[i].Result(); err == nil && remaining > 0 && (ttl <= 0 || remaining < ttl) {
+			ttl = remaining
+		}
+		c.local.Set(ctx, key, vals[key], ttl)
+	}
+}
+
+// SetMulti writes every entry with the same TTL in one pipeline; a ttl of
+// zero or less uses the default.
+func (c *RedisCache) SetMulti(ctx context.Context, entries map[string][]byte, ttl time.Duration) error {
+	if !c.enabled || len(entries) == 0 {
+		return nil
+	}
+
+	if ttl <= 0 {
+		ttl = c.DefaultTTL()
+	}
+
+	pipe := c.client.Pipeline()
+	for key, value := range entries {
+		pipe.Set(ctx, key, value, ttl)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		c.logger.Errorf("Cache multi-set error: %v", err)
+		return err
+	}
+
+	for key, value := range entries {
+		c.setLocal(ctx, key, value, ttl)
+	}
+	c.logger.Debugf("Cache set %d keys, TTL: %v", len(entries), ttl)
+	return nil
+}
+
 func (c *RedisCache) Delete(ctx context.Context, key string) error {
 	if !c.enabled {
 		return nil
 	}
 
+	if c.local != nil {
+		c.local.Delete(ctx, key)
+	}
 	if err := c.client.Del(ctx, key).Err(); err != nil {
 		c.logger.Errorf("Cache delete error: %v", err)
 		return err
@@ -133,19 +363,67 @@ func (c *RedisCache) Clear(ctx context.Context) error {
 		return nil
 	}
 
+	if c.local != nil {
+		c.local.Clear(ctx)
+	}
 	if err := c.client.FlushDB(ctx).Err(); err != nil {
 		c.logger.Errorf("Cache clear error: %v", err)
 		return err
 	}
 
-	c.stats.Size = 0
 	c.logger.Info("Cache cleared")
 	return nil
 }
 
+// GetStats reports hit counts along with Size, the number of keys Redis
+// holds in the cache's database, index tag sets included. The size is read
+// from Redis on every call, since expiry and other instances change it
+// behind this one's back; when Redis cannot answer, the last size read is
+// kept.
 func (c *RedisCache) GetStats() *model.CacheStats {
+	size, sizeErr := c.keyCount()
+
+	c.statsMu.Lock()
+	defer c.statsMu.Unlock()
+
+	if sizeErr != nil {
+		c.logger.Warnf("Failed to read cache size: %v", sizeErr)
+	} else {
+		c.stats.Size = size
+	}
 	c.updateHitRate()
-	return c.stats
+	stats := *c.stats
+	if c.metrics != nil {
+		c.metrics.SetCacheStats(stats.HitRate, stats.Size)
+	}
+	return &stats
+}
+
+const keyCountTimeout = time.Second
+
+func (c *RedisCache) keyCount() (int64, error) {
+	if !c.enabled {
+		return 0, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), keyCountTimeout)
+	defer cancel()
+	return c.client.DBSize(ctx).Result()
+}
+
+// ReportStats refreshes the cache gauges every interval until ctx is done.
+func (c *RedisCache) ReportStats(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.GetStats()
+		}
+	}
 }
 
 func (c *RedisCache) updateHitRate() {
@@ -156,6 +434,13 @@ func (c *RedisCache) updateHitRate() {
 }
 
 func (c *RedisCache) GenerateCacheKey(req *model.SearchRequest) string {
+	return SearchKey(req)
+}
+
+// SearchKey is the cache key for req. Requests with the same key have the
+// same response. A tenant's keys live under its own namespace within the
+// index's, so index-wide invalidation still reaches every tenant.
+func SearchKey(req *model.SearchRequest) string {
 	keyData := map[string]interface{}{
 		"query":   req.Query,
 		"index":   req.Index,
@@ -163,38 +448,207 @@ func (c *RedisCache) GenerateCacheKey(req *model.SearchRequest) string {
 		"offset":  req.Offset,
 		"engines": req.Engines,
 		"filters": req.Filters,
+		"facets":  req.Facets,
+
+		"sort_by":          req.SortBy,
+		"sort_order":       req.SortOrder,
+		"highlight":        req.Highlight,
+		"highlight_field":  req.HighlightField,
+		"search_after":     req.SearchAfter,
+		"per_engine_limit": req.PerEngineLimit,
+		"explain":          req.Explain,
 	}
 
 	jsonData, _ := json.Marshal(keyData)
 	hash := md5.Sum(jsonData)
+	if req.Index != "" {
+		return TenantIndexKeyPrefix(req.Tenant, req.Index) + hex.EncodeToString(hash[:])
+	}
+	if req.Tenant != "" {
+		return fmt.Sprintf("search:%s%s:%s", tenantSegment, req.Tenant, hex.EncodeToString(hash[:]))
+	}
 	return fmt.Sprintf("search:%s", hex.EncodeToString(hash[:]))
 }
 
+// tenantSegment marks the tenant part of a key. Key hashes are hex, so it
+// cannot be mistaken for one.
+const tenantSegment = "t:"
+
+// IndexKeyPattern matches every cached search for index, for all tenants.
+func IndexKeyPattern(index string) string {
+	return IndexKeyPrefix(index) + "*"
+}
+
+// IndexKeyPrefix starts every cached search key for index, for all
+// tenants.
+func IndexKeyPrefix(index string) string {
+	return fmt.Sprintf("search:%s:", index)
+}
+
+// TenantIndexKeyPattern matches the cached searches tenant made on index.
+// An empty tenant matches the index's keys of every tenant.
+func TenantIndexKeyPattern(tenant, index string) string {
+	return TenantIndexKeyPrefix(tenant, index) + "*"
+}
+
+// TenantIndexKeyPrefix starts the cache keys of tenant's searches on
+// index; see TenantIndexKeyPattern.
+func TenantIndexKeyPrefix(tenant, index string) string {
+	if tenant == "" {
+		return IndexKeyPrefix(index)
+	}
+	return fmt.Sprintf("search:%s:%s%s:", index, tenantSegment, tenant)
+}
+
+// cachedResponse is a search response as stored, with the time it stops
+// being fresh when stale-while-revalidate is on. Entries without one are
+// always fresh.
+type cachedResponse struct {
+	*model.SearchResponse
+	FreshUntil int64 `json:"fresh_until,omitempty"`
+}
+
+// GetSearchResponse returns the cached response for req, fresh or stale.
 func (c *RedisCache) GetSearchResponse(ctx context.Context, req *model.SearchRequest) (*model.SearchResponse, bool) {
+	response, _, found := c.LookupSearchResponse(ctx, req)
+	return response, found
+}
+
+// LookupSearchResponse returns the cached response for req and whether it
+// is past its soft TTL, in which case the caller should serve it and
+// refresh the entry.
+func (c *RedisCache) LookupSearchResponse(ctx context.Context, req *model.SearchRequest) (response *model.SearchResponse, stale, found bool) {
 	key := c.GenerateCacheKey(req)
 	data, found := c.Get(ctx, key)
 	if !found {
-		return nil, false
+		return nil, false, false
 	}
 
-	var response model.SearchResponse
-	if err := json.Unmarshal(data, &response); err != nil {
+	data, err := decompress(data)
+	if err != nil {
+		c.logger.Errorf("Failed to read cached response: %v", err)
+		return nil, false, false
+	}
+
+	entry := cachedResponse{SearchResponse: &model.SearchResponse{}}
+	if err := json.Unmarshal(data, &entry); err != nil {
 		c.logger.Errorf("Failed to unmarshal cached response: %v", err)
-		return nil, false
+		return nil, false, false
 	}
 
-	response.CacheHit = true
-	return &response, true
+	entry.CacheHit = true
+	stale = entry.FreshUntil > 0 && c.now().UnixNano() >= entry.FreshUntil
+	return entry.SearchResponse, stale, true
 }
 
+// SetSearchResponse caches response for req. With stale-while-revalidate
+// on, ttl is how long the entry stays fresh, SoftTTL when zero, and Redis
+// keeps it for HardTTL or ttl, whichever is longer.
 func (c *RedisCache) SetSearchResponse(ctx context.Context, req *model.SearchRequest, response *model.SearchResponse, ttl time.Duration) error {
 	key := c.GenerateCacheKey(req)
-	data, err := json.Marshal(response)
+	entry := cachedResponse{SearchResponse: response}
+	if c.softTTL > 0 {
+		if ttl <= 0 {
+			ttl = c.softTTL
+		}
+		entry.FreshUntil = c.now().Add(ttl).UnixNano()
+		if c.hardTTL > ttl {
+			ttl = c.hardTTL
+		}
+	}
+
+	data, err := json.Marshal(entry)
 	if err != nil {
 		return fmt.Errorf("failed to marshal response: %w", err)
 	}
 
-	return c.Set(ctx, key, data, ttl)
+	if c.compress && len(data) >= c.compressMinSize {
+		if data, err = compress(data); err != nil {
+			return fmt.Errorf("failed to compress response: %w", err)
+		}
+	}
+
+	if err := c.Set(ctx, key, data, ttl); err != nil {
+		return err
+	}
+
+	return c.tagIndex(ctx, req.Tenant, req.Index, key, ttl)
+}
+
+func indexTagKey(index string) string {
+	return "search:index:" + index
+}
+
+func tenantIndexTagKey(tenant, index string) string {
+	return indexTagKey(index) + ":" + tenantSegment + tenant
+}
+
+// tagIndex records key in the index's tag set, and in its tenant's when
+// there is one, so InvalidateIndex and InvalidateTenantIndex can find it.
+// The tag sets live at least as long as the entries they list.
+func (c *RedisCache) tagIndex(ctx context.Context, tenant, index, key string, ttl time.Duration) error {
+	if !c.enabled || index == "" {
+		return nil
+	}
+
+	if ttl <= 0 {
+		ttl = c.DefaultTTL()
+	}
+
+	tagKeys := []string{indexTagKey(index)}
+	if tenant != "" {
+		tagKeys = append(tagKeys, tenantIndexTagKey(tenant, index))
+	}
+	pipe := c.client.Pipeline()
+	for _, tagKey := range tagKeys {
+		pipe.SAdd(ctx, tagKey, key)
+		pipe.Expire(ctx, tagKey, ttl)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		c.logger.Errorf("Cache index tag error: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// InvalidateIndex drops every cached search on index, for all tenants.
+func (c *RedisCache) InvalidateIndex(ctx context.Context, index string) error {
+	return c.InvalidateTenantIndex(ctx, "", index)
+}
+
+// InvalidateTenantIndex drops the cached searches tenant made on index,
+// leaving other tenants' entries alone. An empty tenant drops them all.
+func (c *RedisCache) InvalidateTenantIndex(ctx context.Context, tenant, index string) error {
+	if !c.enabled {
+		return nil
+	}
+
+	if c.local != nil {
+		c.local.DeleteByPrefix(ctx, TenantIndexKeyPrefix(tenant, index))
+	}
+
+	tagKey := indexTagKey(index)
+	if tenant != "" {
+		tagKey = tenantIndexTagKey(tenant, index)
+	}
+	keys, err := c.client.SMembers(ctx, tagKey).Result()
+	if err != nil {
+		return fmt.Errorf("failed to read index tag: %w", err)
+	}
+
+	if len(keys) > 0 {
+		if err := c.client.Del(ctx, keys...).Err(); err != nil {
+			return fmt.Errorf("failed to delete keys: %w", err)
+		}
+	}
+
+	if err := c.client.Del(ctx, tagKey).Err(); err != nil {
+		return fmt.Errorf("failed to delete index tag: %w", err)
+	}
+
+	c.logger.Debugf("Invalidated %d cached searches for index: %s, tenant: %q", len(keys), index, tenant)
+	return nil
 }
 
 func (c *RedisCache) DeleteByPrefix(ctx context.Context, prefix string) error {
@@ -202,6 +656,10 @@ func (c *RedisCache) DeleteByPrefix(ctx context.Context, prefix string) error {
 		return nil
 	}
 
+	if c.local != nil {
+		c.local.DeleteByPrefix(ctx, prefix)
+	}
+
 	iter := c.client.Scan(ctx, 0, prefix+"*", 0).Iterator()
 	var keys []string
 
@@ -217,40 +675,83 @@ func (c *RedisCache) DeleteByPrefix(ctx context.Context, prefix string) error {
 		if err := c.client.Del(ctx, keys...).Err(); err != nil {
 			return fmt.Errorf("failed to delete keys: %w", err)
 		}
-		c.stats.Size -= int64(len(keys))
 	}
 
 	c.logger.Debugf("Deleted %d keys with prefix: %s", len(keys), prefix)
 	return nil
 }
 
-func (c *RedisCache) Warmup(ctx context.Context, queries []string, index string) error {
+func (c *RedisCache) Warmup(ctx context.Context, queries []string, index string, search SearchExecutor) error {
 	if !c.enabled {
 		return nil
 	}
+	if search == nil {
+		return fmt.Errorf("cache warmup requires a search executor")
+	}
 
 	c.logger.Infof("Starting cache warmup for %d queries", len(queries))
-	
-	for i, query := range queries {
-		req := &model.SearchRequest{
-			Query: query,
-			Index: index,
-			Limit: 10,
-		}
-		
-		key := c.GenerateCacheKey(req)
-		
-		if exists, _ := c.client.Exists(ctx, key).Result(); exists > 0 {
-			continue
-		}
-		
-		if i%100 == 0 {
-			c.logger.Debugf("Cache warmup progress: %d/%d", i, len(queries))
+
+	jobs := make(chan string)
+	var wg sync.WaitGroup
+	var warmed, skipped, failed int64
+
+	for i := 0; i < warmupWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for query := range jobs {
+				req := &model.SearchRequest{
+					Query: query,
+					Index: index,
+					Limit: 10,
+				}
+
+				key := c.GenerateCacheKey(req)
+				if exists, _ := c.client.Exists(ctx, key).Result(); exists > 0 {
+					atomic.AddInt64(&skipped, 1)
+					continue
+				}
+
+				response, err := search(ctx, req)
+				if err != nil {
+					c.logger.Warnf("Cache warmup search failed for %q: %v", query, err)
+					atomic.AddInt64(&failed, 1)
+					continue
+				}
+				// A degraded response is missing an engine's results, and
+				// caching it would serve the gap for the whole TTL.
+				if response.Degraded {
+					c.logger.Warnf("Cache warmup search for %q was degraded, not caching it", query)
+					atomic.AddInt64(&failed, 1)
+					continue
+				}
+
+				if err := c.SetSearchResponse(ctx, req, response, 0); err != nil {
+					atomic.AddInt64(&failed, 1)
+					continue
+				}
+
+				if n := atomic.AddInt64(&warmed, 1); n%100 == 0 {
+					c.logger.Debugf("Cache warmup progress: %d/%d", n, len(queries))
+				}
+			}
+		}()
+	}
+
+feed:
+	for _, query := range queries {
+		select {
+		case jobs <- query:
+		case <-ctx.Done():
+			break feed
 		}
 	}
+	close(jobs)
+	wg.Wait()
 
-	c.logger.Info("Cache warmup completed")
-	return nil
+	c.logger.Infof("Cache warmup completed: %d warmed, %d skipped, %d failed", warmed, skipped, failed)
+	return ctx.Err()
 }
 
 func (c *RedisCache) Close() error {
@@ -260,6 +761,10 @@ func (c *RedisCache) Close() error {
 	return nil
 }
 
+func (c *RedisCache) Client() *redis.Client {
+	return c.client
+}
+
 func (c *RedisCache) IsEnabled() bool {
 	return c.enabled
 }
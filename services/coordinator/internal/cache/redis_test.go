@@ -0,0 +1,727 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/flexsearch/coordinator/internal/model"
+	"github.com/flexsearch/coordinator/internal/util"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func newTestRedisCache(t *testing.T) (*RedisCache, *miniredis.Miniredis) {
+	t.Helper()
+	return newTestRedisCacheWith(t, func(*CacheConfig) {})
+}
+
+func newTestRedisCacheWith(t *testing.T, configure func(*CacheConfig)) (*RedisCache, *miniredis.Miniredis) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	return newTestRedisCacheOn(t, mr, configure), mr
+}
+
+// newTestRedisCacheOn connects a cache to mr, which other caches may share.
+func newTestRedisCacheOn(t *testing.T, mr *miniredis.Miniredis, configure func(*CacheConfig)) *RedisCache {
+	t.Helper()
+
+	logger, err := util.NewLogger("error", "json", "stdout")
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	port, _ := strconv.Atoi(mr.Port())
+
+	config := &CacheConfig{
+		Enabled:    true,
+		Host:       mr.Host(),
+		Port:       port,
+		DefaultTTL: time.Minute,
+	}
+	configure(config)
+
+	c, err := NewRedisCache(config, logger)
+	if err != nil {
+		t.Fatalf("Failed to create cache: %v", err)
+	}
+	t.Cleanup(func() { c.Close() })
+
+	return c
+}
+
+func TestGenerateCacheKey(t *testing.T) {
+	c := &RedisCache{}
+
+	base := func() *model.SearchRequest {
+		return &model.SearchRequest{
+			Query:     "test query",
+			Index:     "docs",
+			Limit:     10,
+			Offset:    0,
+			Engines:   []string{"bm25"},
+			Filters:   map[string]string{"lang": "en"},
+			SortBy:    "date",
+			SortOrder: "asc",
+		}
+	}
+
+	if c.GenerateCacheKey(base()) != c.GenerateCacheKey(base()) {
+		t.Error("Expected identical requests to produce the same key")
+	}
+
+	variants := map[string]func(*model.SearchRequest){
+		"sort order":       func(r *model.SearchRequest) { r.SortOrder = "desc" },
+		"sort by":          func(r *model.SearchRequest) { r.SortBy = "score" },
+		"highlight":        func(r *model.SearchRequest) { r.Highlight = true },
+		"highlight field":  func(r *model.SearchRequest) { r.HighlightField = "title" },
+		"offset":           func(r *model.SearchRequest) { r.Offset = 10 },
+		"search after":     func(r *model.SearchRequest) { r.SearchAfter = "abc" },
+		"per engine limit": func(r *model.SearchRequest) { r.PerEngineLimit = 50 },
+		"explain":          func(r *model.SearchRequest) { r.Explain = true },
+		"engines":          func(r *model.SearchRequest) { r.Engines = []string{"vector"} },
+		"facets":           func(r *model.SearchRequest) { r.Facets = []string{"author"} },
+	}
+
+	baseKey := c.GenerateCacheKey(base())
+	for name, mutate := range variants {
+		req := base()
+		mutate(req)
+		if c.GenerateCacheKey(req) == baseKey {
+			t.Errorf("Expected requests differing by %s to produce different keys", name)
+		}
+	}
+}
+
+func searchKeys(mr *miniredis.Miniredis) []string {
+	var keys []string
+	for _, key := range mr.Keys() {
+		if !strings.HasPrefix(key, "search:index:") {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+type fakeExecutor struct {
+	mu      sync.Mutex
+	queries []string
+	// degraded is the query answered with a degraded response.
+	degraded string
+}
+
+func (e *fakeExecutor) search(ctx context.Context, req *model.SearchRequest) (*model.SearchResponse, error) {
+	e.mu.Lock()
+	e.queries = append(e.queries, req.Query)
+	e.mu.Unlock()
+
+	return &model.SearchResponse{
+		Results:  []model.SearchResult{{ID: req.Query, Score: 1}},
+		Total:    1,
+		Degraded: req.Query == e.degraded,
+	}, nil
+}
+
+func (e *fakeExecutor) calls() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return len(e.queries)
+}
+
+func TestWarmupPopulatesCache(t *testing.T) {
+	c, mr := newTestRedisCache(t)
+	executor := &fakeExecutor{}
+
+	queries := make([]string, 20)
+	for i := range queries {
+		queries[i] = fmt.Sprintf("query %d", i)
+	}
+
+	if err := c.Warmup(context.Background(), queries, "docs", executor.search); err != nil {
+		t.Fatalf("Warmup failed: %v", err)
+	}
+
+	if executor.calls() != len(queries) {
+		t.Errorf("Expected %d searches, got %d", len(queries), executor.calls())
+	}
+	if got := len(searchKeys(mr)); got != len(queries) {
+		t.Errorf("Expected %d cache entries, got %d", len(queries), got)
+	}
+
+	resp, found := c.GetSearchResponse(context.Background(), &model.SearchRequest{Query: "query 3", Index: "docs", Limit: 10})
+	if !found {
+		t.Fatal("Expected warmed query to be cached")
+	}
+	if len(resp.Results) != 1 || resp.Results[0].ID != "query 3" {
+		t.Errorf("Unexpected cached response: %+v", resp.Results)
+	}
+}
+
+func TestWarmupSkipsExistingKeys(t *testing.T) {
+	c, _ := newTestRedisCache(t)
+	executor := &fakeExecutor{}
+	ctx := context.Background()
+
+	existing := &model.SearchRequest{Query: "cached", Index: "docs", Limit: 10}
+	if err := c.SetSearchResponse(ctx, existing, &model.SearchResponse{}, time.Minute); err != nil {
+		t.Fatalf("SetSearchResponse failed: %v", err)
+	}
+
+	if err := c.Warmup(ctx, []string{"cached", "fresh"}, "docs", executor.search); err != nil {
+		t.Fatalf("Warmup failed: %v", err)
+	}
+
+	if executor.calls() != 1 || executor.queries[0] != "fresh" {
+		t.Errorf("Expected only the uncached query to run, got %v", executor.queries)
+	}
+}
+
+func TestWarmupSkipsDegradedResponses(t *testing.T) {
+	c, _ := newTestRedisCache(t)
+	executor := &fakeExecutor{degraded: "partial"}
+	ctx := context.Background()
+
+	if err := c.Warmup(ctx, []string{"partial", "whole"}, "docs", executor.search); err != nil {
+		t.Fatalf("Warmup failed: %v", err)
+	}
+
+	if _, found := c.GetSearchResponse(ctx, &model.SearchRequest{Query: "partial", Index: "docs", Limit: 10}); found {
+		t.Error("Expected the degraded response not to be cached")
+	}
+	if _, found := c.GetSearchResponse(ctx, &model.SearchRequest{Query: "whole", Index: "docs", Limit: 10}); !found {
+		t.Error("Expected the complete response to be cached")
+	}
+}
+
+func TestWarmupRespectsCancellation(t *testing.T) {
+	c, _ := newTestRedisCache(t)
+	executor := &fakeExecutor{}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := c.Warmup(ctx, []string{"a", "b", "c"}, "docs", executor.search)
+	if err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestInvalidateIndex(t *testing.T) {
+	c, mr := newTestRedisCache(t)
+	ctx := context.Background()
+
+	docsA := &model.SearchRequest{Query: "alpha", Index: "docs", Limit: 10}
+	docsB := &model.SearchRequest{Query: "beta", Index: "docs", Limit: 10}
+	other := &model.SearchRequest{Query: "alpha", Index: "other", Limit: 10}
+
+	for _, req := range []*model.SearchRequest{docsA, docsB, other} {
+		if err := c.SetSearchResponse(ctx, req, &model.SearchResponse{}, time.Minute); err != nil {
+			t.Fatalf("SetSearchResponse failed: %v", err)
+		}
+	}
+
+	members, err := mr.Members(indexTagKey("docs"))
+	if err != nil || len(members) != 2 {
+		t.Fatalf("Expected 2 keys tagged for docs, got %v (%v)", members, err)
+	}
+
+	if err := c.InvalidateIndex(ctx, "docs"); err != nil {
+		t.Fatalf("InvalidateIndex failed: %v", err)
+	}
+
+	if _, found := c.GetSearchResponse(ctx, docsA); found {
+		t.Error("Expected docs query alpha to be invalidated")
+	}
+	if _, found := c.GetSearchResponse(ctx, docsB); found {
+		t.Error("Expected docs query beta to be invalidated")
+	}
+	if _, found := c.GetSearchResponse(ctx, other); !found {
+		t.Error("Expected other index to be left untouched")
+	}
+	if mr.Exists(indexTagKey("docs")) {
+		t.Error("Expected docs tag set to be removed")
+	}
+}
+
+func TestLocalTierHitSkipsRedis(t *testing.T) {
+	c, mr := newTestRedisCacheWith(t, func(config *CacheConfig) { config.LocalSize = 10 })
+	ctx := context.Background()
+
+	if err := c.Set(ctx, "search:docs:a", []byte("cached"), time.Minute); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if !mr.Exists("search:docs:a") {
+		t.Fatal("Expected Set to write through to Redis")
+	}
+
+	before := mr.CommandCount()
+	val, found := c.Get(ctx, "search:docs:a")
+	if !found || string(val) != "cached" {
+		t.Fatalf("Get() = %q, %v, want the cached value", val, found)
+	}
+	if n := mr.CommandCount() - before; n != 0 {
+		t.Errorf("Expected a local hit to skip Redis, got %d commands", n)
+	}
+}
+
+func TestLocalTierMissPromotesRedisHit(t *testing.T) {
+	c, mr := newTestRedisCacheWith(t, func(config *CacheConfig) {
+		config.LocalSize = 10
+		config.LocalTTL = 10 * time.Second
+	})
+	ctx := context.Background()
+
+	mr.Set("search:docs:a", "from redis")
+	mr.SetTTL("search:docs:a", time.Minute)
+
+	if val, found := c.Get(ctx, "search:docs:a"); !found || string(val) != "from redis" {
+		t.Fatalf("Get() = %q, %v, want the Redis value", val, found)
+	}
+	if val, found := c.Local().Get(ctx, "search:docs:a"); !found || string(val) != "from redis" {
+		t.Fatalf("Expected the Redis hit to be promoted, got %q, %v", val, found)
+	}
+
+	before := mr.CommandCount()
+	c.Get(ctx, "search:docs:a")
+	if n := mr.CommandCount() - before; n != 0 {
+		t.Errorf("Expected the promoted entry to be served locally, got %d Redis commands", n)
+	}
+}
+
+func TestLocalTierRespectsConfiguredSize(t *testing.T) {
+	c, mr := newTestRedisCacheWith(t, func(config *CacheConfig) { config.LocalSize = 2 })
+	ctx := context.Background()
+
+	for _, key := range []string{"search:docs:a", "search:docs:b", "search:docs:c"} {
+		if err := c.Set(ctx, key, []byte(key), time.Minute); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+	}
+
+	if n := c.Local().Len(); n != 2 {
+		t.Fatalf("Expected the local tier to hold 2 entries, got %d", n)
+	}
+	if _, found := c.Local().Get(ctx, "search:docs:a"); found {
+		t.Error("Expected the oldest entry to be evicted from the local tier")
+	}
+	if val, found := c.Get(ctx, "search:docs:a"); !found || string(val) != "search:docs:a" {
+		t.Errorf("Expected an evicted entry to still be served from Redis, got %q, %v", val, found)
+	}
+	if !mr.Exists("search:docs:c") {
+		t.Error("Expected every entry to be written to Redis")
+	}
+}
+
+func TestLocalTierFollowsInvalidation(t *testing.T) {
+	c, _ := newTestRedisCacheWith(t, func(config *CacheConfig) { config.LocalSize = 10 })
+	ctx := context.Background()
+
+	req := &model.SearchRequest{Query: "alpha", Index: "docs", Limit: 10}
+	if err := c.SetSearchResponse(ctx, req, &model.SearchResponse{}, time.Minute); err != nil {
+		t.Fatalf("SetSearchResponse failed: %v", err)
+	}
+	if err := c.InvalidateIndex(ctx, "docs"); err != nil {
+		t.Fatalf("InvalidateIndex failed: %v", err)
+	}
+
+	if _, found := c.GetSearchResponse(ctx, req); found {
+		t.Error("Expected invalidation to reach the local tier")
+	}
+}
+
+func TestCompressionRoundTripsLargeResponses(t *testing.T) {
+	c, mr := newTestRedisCacheWith(t, func(config *CacheConfig) { config.Compress = true })
+	ctx := context.Background()
+
+	req := &model.SearchRequest{Query: "alpha", Index: "docs", Limit: 200}
+	response := &model.SearchResponse{Total: 200}
+	for i := 0; i < 200; i++ {
+		response.Results = append(response.Results, model.SearchResult{
+			ID:        fmt.Sprintf("doc-%d", i),
+			Score:     float64(i),
+			Highlight: map[string]string{"content": strings.Repeat("<em>alpha</em> appears in this passage ", 5)},
+		})
+	}
+	if err := c.SetSearchResponse(ctx, req, response, time.Minute); err != nil {
+		t.Fatalf("SetSearchResponse failed: %v", err)
+	}
+
+	stored, err := mr.Get(c.GenerateCacheKey(req))
+	if err != nil {
+		t.Fatalf("Expected the response in Redis: %v", err)
+	}
+	if stored[0] != gzipMarker {
+		t.Fatalf("Expected the stored entry to be compressed, got prefix %q", stored[:1])
+	}
+	raw, _ := json.Marshal(response)
+	if len(stored) >= len(raw) {
+		t.Errorf("Expected compression to shrink %d bytes, stored %d", len(raw), len(stored))
+	}
+
+	got, found := c.GetSearchResponse(ctx, req)
+	if !found {
+		t.Fatal("Expected the compressed response to be read back")
+	}
+	if len(got.Results) != 200 || got.Results[199].ID != "doc-199" || got.Results[0].Highlight["content"] != response.Results[0].Highlight["content"] {
+		t.Errorf("Expected the response to round-trip, got %d results", len(got.Results))
+	}
+}
+
+func TestCompressionSkipsSmallResponses(t *testing.T) {
+	c, mr := newTestRedisCacheWith(t, func(config *CacheConfig) { config.Compress = true })
+	ctx := context.Background()
+
+	req := &model.SearchRequest{Query: "alpha", Index: "docs", Limit: 10}
+	if err := c.SetSearchResponse(ctx, req, &model.SearchResponse{Total: 1}, time.Minute); err != nil {
+		t.Fatalf("SetSearchResponse failed: %v", err)
+	}
+
+	stored, _ := mr.Get(c.GenerateCacheKey(req))
+	if !strings.HasPrefix(stored, "{") {
+		t.Errorf("Expected a small response to be stored as JSON, got %q", stored)
+	}
+}
+
+func TestCompressionReadsLegacyEntries(t *testing.T) {
+	c, mr := newTestRedisCacheWith(t, func(config *CacheConfig) { config.Compress = true })
+	ctx := context.Background()
+
+	req := &model.SearchRequest{Query: "alpha", Index: "docs", Limit: 10}
+	mr.Set(c.GenerateCacheKey(req), `{"results":[{"id":"legacy","score":1}],"total":1}`)
+
+	got, found := c.GetSearchResponse(ctx, req)
+	if !found {
+		t.Fatal("Expected the uncompressed entry to be read")
+	}
+	if len(got.Results) != 1 || got.Results[0].ID != "legacy" {
+		t.Errorf("Expected the legacy result, got %+v", got.Results)
+	}
+}
+
+func TestSearchKeySeparatesTenants(t *testing.T) {
+	req := func(tenant, index string) *model.SearchRequest {
+		return &model.SearchRequest{Query: "alpha", Index: index, Limit: 10, Tenant: tenant}
+	}
+
+	for _, index := range []string{"docs", ""} {
+		acme, globex, none := SearchKey(req("acme", index)), SearchKey(req("globex", index)), SearchKey(req("", index))
+		if acme == globex || acme == none || globex == none {
+			t.Errorf("Expected distinct keys per tenant for index %q, got %s, %s and %s", index, acme, globex, none)
+		}
+	}
+
+	key := SearchKey(req("acme", "docs"))
+	if !strings.HasPrefix(key, TenantIndexKeyPrefix("acme", "docs")) || !strings.HasPrefix(key, IndexKeyPrefix("docs")) {
+		t.Errorf("Expected %s under both the tenant's and the index's prefix", key)
+	}
+	if strings.HasPrefix(key, TenantIndexKeyPrefix("globex", "docs")) {
+		t.Errorf("Expected %s outside another tenant's prefix", key)
+	}
+}
+
+func TestTenantsNeverShareCachedResponses(t *testing.T) {
+	c, _ := newTestRedisCacheWith(t, func(config *CacheConfig) { config.LocalSize = 10 })
+	ctx := context.Background()
+
+	acme := &model.SearchRequest{Query: "alpha", Index: "docs", Limit: 10, Tenant: "acme"}
+	globex := &model.SearchRequest{Query: "alpha", Index: "docs", Limit: 10, Tenant: "globex"}
+	if err := c.SetSearchResponse(ctx, acme, &model.SearchResponse{Total: 7}, time.Minute); err != nil {
+		t.Fatalf("SetSearchResponse failed: %v", err)
+	}
+
+	if _, found := c.GetSearchResponse(ctx, globex); found {
+		t.Error("Expected another tenant's identical query to miss")
+	}
+	if got, found := c.GetSearchResponse(ctx, acme); !found || got.Total != 7 {
+		t.Errorf("Expected the tenant's own entry, got %+v, %v", got, found)
+	}
+}
+
+func TestInvalidateTenantIndexIsTenantScoped(t *testing.T) {
+	c, _ := newTestRedisCacheWith(t, func(config *CacheConfig) { config.LocalSize = 10 })
+	ctx := context.Background()
+
+	acme := &model.SearchRequest{Query: "alpha", Index: "docs", Limit: 10, Tenant: "acme"}
+	globex := &model.SearchRequest{Query: "alpha", Index: "docs", Limit: 10, Tenant: "globex"}
+	for _, req := range []*model.SearchRequest{acme, globex} {
+		if err := c.SetSearchResponse(ctx, req, &model.SearchResponse{}, time.Minute); err != nil {
+			t.Fatalf("SetSearchResponse failed: %v", err)
+		}
+	}
+
+	if err := c.InvalidateTenantIndex(ctx, "acme", "docs"); err != nil {
+		t.Fatalf("InvalidateTenantIndex failed: %v", err)
+	}
+	if _, found := c.GetSearchResponse(ctx, acme); found {
+		t.Error("Expected the tenant's entry to be invalidated")
+	}
+	if _, found := c.GetSearchResponse(ctx, globex); !found {
+		t.Error("Expected other tenants' entries to be left alone")
+	}
+
+	if err := c.InvalidateIndex(ctx, "docs"); err != nil {
+		t.Fatalf("InvalidateIndex failed: %v", err)
+	}
+	if _, found := c.GetSearchResponse(ctx, globex); found {
+		t.Error("Expected index-wide invalidation to reach every tenant")
+	}
+}
+
+func TestSetDefaultTTL(t *testing.T) {
+	c, mr := newTestRedisCache(t)
+	ctx := context.Background()
+
+	c.SetDefaultTTL(10 * time.Minute)
+	if err := c.Set(ctx, "reloaded", []byte("v"), 0); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	if ttl := mr.TTL("reloaded"); ttl != 10*time.Minute {
+		t.Errorf("Expected TTL 10m after SetDefaultTTL, got %v", ttl)
+	}
+}
+
+func TestGetStatsPublishesGauges(t *testing.T) {
+	logger, err := util.NewLogger("error", "json", "stdout")
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	mr := miniredis.RunT(t)
+	port, _ := strconv.Atoi(mr.Port())
+
+	reg := prometheus.NewRegistry()
+	c, err := NewRedisCache(&CacheConfig{
+		Enabled:    true,
+		Host:       mr.Host(),
+		Port:       port,
+		DefaultTTL: time.Minute,
+		Metrics:    util.NewMetricsWithRegistry("cache_gauge_test", reg),
+	}, logger)
+	if err != nil {
+		t.Fatalf("Failed to create cache: %v", err)
+	}
+	defer c.Close()
+
+	ctx := context.Background()
+	if err := c.Set(ctx, "a", []byte("1"), 0); err != nil {
+		t.Fatalf("Failed to set key: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		c.Get(ctx, "a")
+	}
+	c.Get(ctx, "missing")
+
+	stats := c.GetStats()
+	if stats.HitRate != 0.75 {
+		t.Fatalf("Expected hit rate 0.75, got %v", stats.HitRate)
+	}
+
+	gauges := make(map[string]float64)
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("Failed to gather metrics: %v", err)
+	}
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			if metric.GetGauge() != nil {
+				gauges[family.GetName()] = metric.GetGauge().GetValue()
+			}
+		}
+	}
+
+	if got := gauges["cache_gauge_test_cache_hit_rate"]; got != 0.75 {
+		t.Errorf("Expected hit rate gauge 0.75, got %v", got)
+	}
+	if got := gauges["cache_gauge_test_cache_size"]; got != 1 {
+		t.Errorf("Expected size gauge 1, got %v", got)
+	}
+}
+
+func TestGetStatsSizeTracksExpiry(t *testing.T) {
+	c, mr := newTestRedisCache(t)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if err := c.Set(ctx, fmt.Sprintf("short-%d", i), []byte("x"), time.Second); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+	}
+	if err := c.Set(ctx, "long", []byte("x"), time.Hour); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	// Overwriting a key is one key, not two.
+	if err := c.Set(ctx, "long", []byte("y"), time.Hour); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	if size := c.GetStats().Size; size != 4 {
+		t.Fatalf("Expected size 4 before expiry, got %d", size)
+	}
+
+	mr.FastForward(2 * time.Second)
+
+	if size := c.GetStats().Size; size != 1 {
+		t.Errorf("Expected size 1 after the short entries expired, got %d", size)
+	}
+}
+
+func TestGetStatsSizeSeesOtherWriters(t *testing.T) {
+	c, mr := newTestRedisCache(t)
+	ctx := context.Background()
+
+	if err := c.Set(ctx, "a", []byte("1"), 0); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	mr.Set("b", "written by another instance")
+
+	if size := c.GetStats().Size; size != 2 {
+		t.Errorf("Expected size 2, got %d", size)
+	}
+
+	if err := c.Clear(ctx); err != nil {
+		t.Fatalf("Clear failed: %v", err)
+	}
+	mr.Set("c", "written after the clear")
+
+	if size := c.GetStats().Size; size != 1 {
+		t.Errorf("Expected size 1 after the clear, got %d", size)
+	}
+}
+
+func TestGetMultiMixesPresentAndAbsentKeys(t *testing.T) {
+	c, mr := newTestRedisCache(t)
+	ctx := context.Background()
+
+	mr.Set("a", "1")
+	mr.Set("c", "3")
+
+	found, err := c.GetMulti(ctx, []string{"a", "b", "c", "d"})
+	if err != nil {
+		t.Fatalf("GetMulti failed: %v", err)
+	}
+	if len(found) != 2 || string(found["a"]) != "1" || string(found["c"]) != "3" {
+		t.Errorf("GetMulti() = %q, want a and c", found)
+	}
+	if _, ok := found["b"]; ok {
+		t.Error("Expected absent keys to be left out")
+	}
+
+	stats := c.GetStats()
+	if stats.Hits != 2 || stats.Misses != 2 || stats.HitRate != 0.5 {
+		t.Errorf("Expected 2 hits and 2 misses, got %+v", stats)
+	}
+}
+
+func TestGetMultiUsesLocalTier(t *testing.T) {
+	c, mr := newTestRedisCacheWith(t, func(config *CacheConfig) { config.LocalSize = 10 })
+	ctx := context.Background()
+
+	c.Set(ctx, "a", []byte("1"), time.Minute)
+	mr.Set("b", "2")
+
+	if found, _ := c.GetMulti(ctx, []string{"a", "b"}); len(found) != 2 {
+		t.Fatalf("GetMulti() = %q, want both keys", found)
+	}
+	if _, ok := c.Local().Get(ctx, "b"); !ok {
+		t.Error("Expected the Redis hit to be promoted to the local tier")
+	}
+}
+
+func TestSetMultiWritesEveryKeyWithTTL(t *testing.T) {
+	c, mr := newTestRedisCache(t)
+	ctx := context.Background()
+
+	entries := map[string][]byte{"a": []byte("1"), "b": []byte("2"), "c": []byte("3")}
+	if err := c.SetMulti(ctx, entries, 30*time.Second); err != nil {
+		t.Fatalf("SetMulti failed: %v", err)
+	}
+
+	for key, want := range entries {
+		got, err := mr.Get(key)
+		if err != nil || got != string(want) {
+			t.Errorf("Expected %s = %q, got %q (%v)", key, want, got, err)
+		}
+		if ttl := mr.TTL(key); ttl != 30*time.Second {
+			t.Errorf("Expected %s to expire in 30s, got %v", key, ttl)
+		}
+	}
+
+	if err := c.SetMulti(ctx, map[string][]byte{"d": []byte("4")}, 0); err != nil {
+		t.Fatalf("SetMulti failed: %v", err)
+	}
+	if ttl := mr.TTL("d"); ttl != time.Minute {
+		t.Errorf("Expected a zero TTL to use the default, got %v", ttl)
+	}
+}
+
+func TestLookupSearchResponseReportsSoftExpiry(t *testing.T) {
+	c, mr := newTestRedisCacheWith(t, func(config *CacheConfig) {
+		config.SoftTTL = time.Minute
+		config.HardTTL = 10 * time.Minute
+	})
+	ctx := context.Background()
+	now := time.Unix(1000, 0)
+	c.now = func() time.Time { return now }
+
+	req := &model.SearchRequest{Query: "alpha", Index: "docs", Limit: 10}
+	if err := c.SetSearchResponse(ctx, req, &model.SearchResponse{Total: 1}, 0); err != nil {
+		t.Fatalf("SetSearchResponse failed: %v", err)
+	}
+	if ttl := mr.TTL(c.GenerateCacheKey(req)); ttl != 10*time.Minute {
+		t.Errorf("Expected Redis to keep the entry for the hard TTL, got %v", ttl)
+	}
+
+	if _, stale, found := c.LookupSearchResponse(ctx, req); !found || stale {
+		t.Fatalf("Expected a fresh hit, got stale=%v found=%v", stale, found)
+	}
+
+	now = now.Add(2 * time.Minute)
+	response, stale, found := c.LookupSearchResponse(ctx, req)
+	if !found || !stale {
+		t.Fatalf("Expected a stale hit past the soft TTL, got stale=%v found=%v", stale, found)
+	}
+	if response.Total != 1 || !response.CacheHit {
+		t.Errorf("Expected the stale response, got %+v", response)
+	}
+}
+
+func TestLookupSearchResponseTreatsLegacyEntriesAsFresh(t *testing.T) {
+	c, mr := newTestRedisCacheWith(t, func(config *CacheConfig) {
+		config.SoftTTL = time.Minute
+		config.HardTTL = 10 * time.Minute
+	})
+
+	req := &model.SearchRequest{Query: "alpha", Index: "docs", Limit: 10}
+	mr.Set(c.GenerateCacheKey(req), `{"results":[],"total":3}`)
+
+	response, stale, found := c.LookupSearchResponse(context.Background(), req)
+	if !found || stale || response.Total != 3 {
+		t.Errorf("Expected a fresh legacy hit, got %+v stale=%v found=%v", response, stale, found)
+	}
+}
+
+func TestSlowQueryListKeepsNewestEntries(t *testing.T) {
+	c, _ := newTestRedisCache(t)
+	ctx := context.Background()
+	list := c.SlowQueryList(2)
+
+	for _, id := range []string{"req-1", "req-2", "req-3"} {
+		if err := list.RecordSlowQuery(ctx, &util.SlowQuery{Query: "q", RequestID: id, LatencyMs: 1500}); err != nil {
+			t.Fatalf("RecordSlowQuery failed: %v", err)
+		}
+	}
+
+	recent, err := list.Recent(ctx, 10)
+	if err != nil {
+		t.Fatalf("Recent failed: %v", err)
+	}
+	if len(recent) != 2 || recent[0].RequestID != "req-3" || recent[1].RequestID != "req-2" {
+		t.Errorf("Expected the two newest slow queries, got %+v", recent)
+	}
+}
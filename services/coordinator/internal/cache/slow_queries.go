@@ -0,0 +1,70 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/flexsearch/coordinator/internal/util"
+)
+
+const (
+	SlowQueryKey = "search:slow_queries"
+
+	defaultSlowQueryEntries = 1000
+)
+
+// SlowQueryList keeps the most recent slow queries in a capped Redis list,
+// newest first, for offline analysis.
+type SlowQueryList struct {
+	cache      *RedisCache
+	maxEntries int64
+}
+
+func (c *RedisCache) SlowQueryList(maxEntries int64) *SlowQueryList {
+	if maxEntries <= 0 {
+		maxEntries = defaultSlowQueryEntries
+	}
+	return &SlowQueryList{cache: c, maxEntries: maxEntries}
+}
+
+func (l *SlowQueryList) RecordSlowQuery(ctx context.Context, query *util.SlowQuery) error {
+	if !l.cache.enabled {
+		return nil
+	}
+
+	data, err := json.Marshal(query)
+	if err != nil {
+		return fmt.Errorf("failed to marshal slow query: %w", err)
+	}
+
+	pipe := l.cache.client.Pipeline()
+	pipe.LPush(ctx, SlowQueryKey, data)
+	pipe.LTrim(ctx, SlowQueryKey, 0, l.maxEntries-1)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to record slow query: %w", err)
+	}
+	return nil
+}
+
+// Recent returns up to n of the latest slow queries, newest first.
+func (l *SlowQueryList) Recent(ctx context.Context, n int64) ([]*util.SlowQuery, error) {
+	if !l.cache.enabled || n <= 0 {
+		return nil, nil
+	}
+
+	items, err := l.cache.client.LRange(ctx, SlowQueryKey, 0, n-1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read slow queries: %w", err)
+	}
+
+	queries := make([]*util.SlowQuery, 0, len(items))
+	for _, item := range items {
+		var query util.SlowQuery
+		if err := json.Unmarshal([]byte(item), &query); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal slow query: %w", err)
+		}
+		queries = append(queries, &query)
+	}
+	return queries, nil
+}
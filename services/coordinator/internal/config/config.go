@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
@@ -13,6 +14,7 @@ type Config struct {
 	Redis    RedisConfig    `mapstructure:"redis"`
 	Engines  EnginesConfig  `mapstructure:"engines"`
 	Cache    CacheConfig    `mapstructure:"cache"`
+	Search   SearchConfig   `mapstructure:"search"`
 	Metrics  MetricsConfig  `mapstructure:"metrics"`
 	Tracing  TracingConfig  `mapstructure:"tracing"`
 	Logging  LoggingConfig  `mapstructure:"logging"`
@@ -36,7 +38,88 @@ type CacheConfig struct {
 	Enabled         bool          `mapstructure:"enabled"`
 	DefaultTTL      time.Duration `mapstructure:"default_ttl"`
 	MaxSize         int64         `mapstructure:"max_size"`
+	LocalTTL        time.Duration `mapstructure:"local_ttl"`
+	Compression     bool          `mapstructure:"compression"`
+	CompressMinSize int           `mapstructure:"compression_min_size"`
+	SoftTTL         time.Duration `mapstructure:"soft_ttl"`
+	HardTTL         time.Duration `mapstructure:"hard_ttl"`
+	// BusChannel is the Redis pub/sub channel index invalidations are
+	// shared on between instances; empty disables it.
+	BusChannel      string        `mapstructure:"invalidation_channel"`
 	EvictionPolicy  string        `mapstructure:"eviction_policy"`
+	NormalizeKeys   bool          `mapstructure:"normalize_keys"`
+	// SharedTenants drops the tenant from cache keys, for deployments whose
+	// tenants all see the same results.
+	SharedTenants   bool          `mapstructure:"share_across_tenants"`
+	// InvalidationRules are applied to a written index's cached searches
+	// on top of dropping them, here and on the bus.
+	InvalidationRules []InvalidationRuleConfig `mapstructure:"invalidation_rules"`
+}
+
+// InvalidationRuleConfig applies Strategy, one of time, event or manual,
+// to the cache keys matching Pattern. The time strategy expires them
+// after TTL; the others delete them.
+type InvalidationRuleConfig struct {
+	Pattern  string        `mapstructure:"pattern"`
+	Strategy string        `mapstructure:"strategy"`
+	TTL      time.Duration `mapstructure:"ttl"`
+}
+
+type SearchConfig struct {
+	EngineTimeouts       map[string]time.Duration `mapstructure:"engine_timeouts"`
+	MinSuccessfulEngines int                      `mapstructure:"min_successful_engines"`
+	Highlight            HighlightConfig          `mapstructure:"highlight"`
+	DidYouMeanThreshold  int                      `mapstructure:"did_you_mean_threshold"`
+	SlowQuery            SlowQueryConfig          `mapstructure:"slow_query"`
+	// FallbackEngines maps a routing strategy, such as semantic_search, to
+	// the engines to retry with when every engine it selected fails.
+	FallbackEngines map[string][]string `mapstructure:"fallback_engines"`
+	Fusion          FusionConfig        `mapstructure:"fusion"`
+	// PerEngineLimit is how many candidates each engine returns for fusion
+	// when the request leaves it unset.
+	PerEngineLimit int `mapstructure:"per_engine_limit"`
+	// Complexity caps what one search may ask for; zero limits are off.
+	Complexity ComplexityConfig `mapstructure:"complexity"`
+	Wildcards  WildcardConfig   `mapstructure:"wildcards"`
+}
+
+// WildcardConfig requires MinPrefix literal characters before a term's
+// first wildcard; Action is reject or rewrite for terms that fall short.
+type WildcardConfig struct {
+	MinPrefix int    `mapstructure:"min_prefix"`
+	Action    string `mapstructure:"action"`
+}
+
+type ComplexityConfig struct {
+	MaxTerms     int `mapstructure:"max_terms"`
+	MaxFilters   int `mapstructure:"max_filters"`
+	MaxIndexes   int `mapstructure:"max_indexes"`
+	MaxWildcards int `mapstructure:"max_wildcards"`
+}
+
+// FusionConfig selects how per-engine results are merged.
+type FusionConfig struct {
+	// Strategy is rrf, which fuses by rank, or weighted, which sums
+	// normalized scores.
+	Strategy string `mapstructure:"strategy"`
+	// Normalization is the weighted strategy's score normalization: none,
+	// max, minmax or zscore.
+	Normalization string `mapstructure:"normalization"`
+}
+
+type SlowQueryConfig struct {
+	// Threshold is the latency above which a query is logged at warn with
+	// its engine breakdown. Zero disables slow-query logging.
+	Threshold time.Duration `mapstructure:"threshold"`
+	// Record pushes slow queries onto a capped Redis list as well.
+	Record     bool  `mapstructure:"record"`
+	MaxEntries int64 `mapstructure:"max_entries"`
+}
+
+type HighlightConfig struct {
+	FragmentSize int `mapstructure:"fragment_size"`
+	ContextSize  int `mapstructure:"context_size"`
+	MaxFragments int `mapstructure:"max_fragments"`
 }
 
 type RedisConfig struct {
@@ -53,9 +136,14 @@ type MetricsConfig struct {
 	Port    int    `mapstructure:"port"`
 }
 
+// TracingConfig selects where spans are exported: stdout, otlp-grpc,
+// otlp-http or none. Endpoint is the collector address for the OTLP
+// exporters.
 type TracingConfig struct {
 	Enabled  bool   `mapstructure:"enabled"`
 	Exporter string `mapstructure:"exporter"`
+	Endpoint string `mapstructure:"endpoint"`
+	Insecure bool   `mapstructure:"insecure"`
 	SampleRate float64 `mapstructure:"sample_rate"`
 }
 
@@ -63,12 +151,35 @@ type LoggingConfig struct {
 	Level      string `mapstructure:"level"`
 	Format     string `mapstructure:"format"`
 	Output     string `mapstructure:"output"`
+	// RedactFields lists field names, such as email or ssn, whose values
+	// are masked in structured logs.
+	RedactFields []string `mapstructure:"redact_fields"`
+	Sampling     LogSamplingConfig `mapstructure:"sampling"`
+}
+
+// LogSamplingConfig limits repeated entries with the same level and message:
+// per tick the first Initial are logged, then every Thereafter-th. Initial 0
+// disables sampling.
+type LogSamplingConfig struct {
+	Initial    int           `mapstructure:"initial"`
+	Thereafter int           `mapstructure:"thereafter"`
+	Tick       time.Duration `mapstructure:"tick"`
 }
 
+// EnvPrefix prefixes the environment variables that override config keys:
+// redis.host is read from FLEX_REDIS_HOST.
+const EnvPrefix = "FLEX"
+
+// Load reads the config file at configPath. Values resolve in the order
+// environment variable, then file, then default. Only keys that appear in
+// the file or have a default can be overridden from the environment.
 func Load(configPath string) (*Config, error) {
 	v := viper.New()
 	v.SetConfigFile(configPath)
 	v.SetConfigType("yaml")
+	v.SetEnvPrefix(EnvPrefix)
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.AutomaticEnv()
 
 	v.SetDefault("server.host", "0.0.0.0")
 	v.SetDefault("server.port", 50051)
@@ -82,13 +193,50 @@ func Load(configPath string) (*Config, error) {
 
 	v.SetDefault("redis.host", "localhost")
 	v.SetDefault("redis.port", 6379)
+	v.SetDefault("redis.password", "")
 	v.SetDefault("redis.db", 0)
 	v.SetDefault("redis.pool_size", 10)
 
+	v.SetDefault("engines.flexsearch.max_retry_elapsed", 700*time.Millisecond)
+	v.SetDefault("engines.bm25.max_retry_elapsed", 700*time.Millisecond)
+	v.SetDefault("engines.vector.max_retry_elapsed", 700*time.Millisecond)
+	v.SetDefault("engines.flexsearch.jitter", true)
+	v.SetDefault("engines.bm25.jitter", true)
+	v.SetDefault("engines.vector.jitter", true)
+	v.SetDefault("engines.readiness.attempts", 5)
+	v.SetDefault("engines.readiness.initial_backoff", 200*time.Millisecond)
+	v.SetDefault("engines.readiness.max_backoff", 5*time.Second)
+
 	v.SetDefault("cache.enabled", true)
 	v.SetDefault("cache.default_ttl", 5*time.Minute)
 	v.SetDefault("cache.max_size", 10000)
+	v.SetDefault("cache.local_ttl", 30*time.Second)
+	v.SetDefault("cache.compression", false)
+	v.SetDefault("cache.compression_min_size", 1024)
+	v.SetDefault("cache.soft_ttl", 0)
+	v.SetDefault("cache.hard_ttl", 0)
+	v.SetDefault("cache.invalidation_channel", "coordinator:cache:invalidate")
 	v.SetDefault("cache.eviction_policy", "lru")
+	v.SetDefault("cache.normalize_keys", true)
+	v.SetDefault("cache.share_across_tenants", false)
+
+	v.SetDefault("search.min_successful_engines", 1)
+	v.SetDefault("search.did_you_mean_threshold", 3)
+	v.SetDefault("search.highlight.fragment_size", 150)
+	v.SetDefault("search.highlight.context_size", 40)
+	v.SetDefault("search.highlight.max_fragments", 3)
+	v.SetDefault("search.slow_query.threshold", time.Second)
+	v.SetDefault("search.slow_query.record", false)
+	v.SetDefault("search.slow_query.max_entries", 1000)
+	v.SetDefault("search.per_engine_limit", 100)
+	v.SetDefault("search.fusion.strategy", "rrf")
+	v.SetDefault("search.fusion.normalization", "max")
+	v.SetDefault("search.complexity.max_terms", 32)
+	v.SetDefault("search.complexity.max_filters", 20)
+	v.SetDefault("search.complexity.max_indexes", 10)
+	v.SetDefault("search.complexity.max_wildcards", 5)
+	v.SetDefault("search.wildcards.min_prefix", 1)
+	v.SetDefault("search.wildcards.action", "reject")
 
 	v.SetDefault("metrics.enabled", true)
 	v.SetDefault("metrics.path", "/metrics")
@@ -96,11 +244,17 @@ func Load(configPath string) (*Config, error) {
 
 	v.SetDefault("tracing.enabled", false)
 	v.SetDefault("tracing.exporter", "stdout")
+	v.SetDefault("tracing.endpoint", "")
+	v.SetDefault("tracing.insecure", false)
 	v.SetDefault("tracing.sample_rate", 1.0)
 
 	v.SetDefault("logging.level", "info")
 	v.SetDefault("logging.format", "json")
 	v.SetDefault("logging.output", "stdout")
+	v.SetDefault("logging.redact_fields", []string{})
+	v.SetDefault("logging.sampling.initial", 100)
+	v.SetDefault("logging.sampling.thereafter", 100)
+	v.SetDefault("logging.sampling.tick", time.Second)
 
 	if err := v.ReadInConfig(); err != nil {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
@@ -111,6 +265,10 @@ func Load(configPath string) (*Config, error) {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
 
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
 	return &cfg, nil
 }
 
@@ -0,0 +1,131 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// testEnginesConfig enables one engine so the test configs validate.
+const testEnginesConfig = `
+engines:
+  bm25:
+    enabled: true
+    host: localhost
+    port: 50054
+    pool_size: 1
+`
+
+func writeConfig(t *testing.T, path, body string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+}
+
+func TestLoadEnvOverrides(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfig(t, path, testEnginesConfig+`
+redis:
+  host: redis.internal
+  port: 6379
+cache:
+  default_ttl: 5m
+`)
+
+	t.Setenv("FLEX_REDIS_HOST", "redis.override")
+	t.Setenv("FLEX_REDIS_PASSWORD", "s3cret")
+	t.Setenv("FLEX_CACHE_DEFAULT_TTL", "90s")
+	t.Setenv("FLEX_GRPC_PORT", "6000")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if cfg.Redis.Host != "redis.override" {
+		t.Errorf("redis.host = %q, want env value over file value", cfg.Redis.Host)
+	}
+	if cfg.Redis.Password != "s3cret" {
+		t.Errorf("redis.password = %q, want env value", cfg.Redis.Password)
+	}
+	if cfg.Cache.DefaultTTL != 90*time.Second {
+		t.Errorf("cache.default_ttl = %v, want 90s", cfg.Cache.DefaultTTL)
+	}
+	if cfg.GRPC.Port != 6000 {
+		t.Errorf("grpc.port = %d, want env value over default", cfg.GRPC.Port)
+	}
+	if cfg.Redis.Port != 6379 {
+		t.Errorf("redis.port = %d, want file value", cfg.Redis.Port)
+	}
+}
+
+func TestLoadDefaultsRetryBudgetBelowSearchTimeout(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfig(t, path, testEnginesConfig+`
+  vector:
+    max_retry_elapsed: 300ms
+`)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if cfg.Engines.BM25.MaxRetryElapsed != 700*time.Millisecond {
+		t.Errorf("engines.bm25.max_retry_elapsed = %v, want the 700ms default", cfg.Engines.BM25.MaxRetryElapsed)
+	}
+	if cfg.Engines.Vector.MaxRetryElapsed != 300*time.Millisecond {
+		t.Errorf("engines.vector.max_retry_elapsed = %v, want file value", cfg.Engines.Vector.MaxRetryElapsed)
+	}
+}
+
+func TestLoadDefaultsJitterOn(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfig(t, path, testEnginesConfig+`
+  vector:
+    jitter: false
+`)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if !cfg.Engines.FlexSearch.Jitter || !cfg.Engines.BM25.Jitter {
+		t.Errorf("engines jitter = %v/%v, want the true default", cfg.Engines.FlexSearch.Jitter, cfg.Engines.BM25.Jitter)
+	}
+	if cfg.Engines.Vector.Jitter {
+		t.Error("engines.vector.jitter = true, want file value false")
+	}
+}
+
+func TestLoadInvalidationRules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfig(t, path, testEnginesConfig+`
+cache:
+  invalidation_rules:
+    - pattern: "search:products:*"
+      strategy: time
+      ttl: 30s
+    - pattern: "search:drafts:*"
+      strategy: manual
+`)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	rules := cfg.Cache.InvalidationRules
+	if len(rules) != 2 {
+		t.Fatalf("cache.invalidation_rules has %d rules, want 2", len(rules))
+	}
+	if rules[0].Pattern != "search:products:*" || rules[0].Strategy != "time" || rules[0].TTL != 30*time.Second {
+		t.Errorf("cache.invalidation_rules[0] = %+v, want the time rule", rules[0])
+	}
+	if rules[1].Strategy != "manual" {
+		t.Errorf("cache.invalidation_rules[1].strategy = %q, want manual", rules[1].Strategy)
+	}
+}
@@ -9,37 +9,58 @@ type EnginesConfig struct {
 	FlexSearch FlexSearchConfig `mapstructure:"flexsearch"`
 	BM25       BM25Config       `mapstructure:"bm25"`
 	Vector     VectorConfig     `mapstructure:"vector"`
+	// Readiness bounds the pings startup sends each engine before
+	// registering it.
+	Readiness ReadinessConfig `mapstructure:"readiness"`
+}
+
+type ReadinessConfig struct {
+	Attempts       int           `mapstructure:"attempts"`
+	InitialBackoff time.Duration `mapstructure:"initial_backoff"`
+	MaxBackoff     time.Duration `mapstructure:"max_backoff"`
 }
 
 type FlexSearchConfig struct {
-	Enabled    bool          `mapstructure:"enabled"`
-	Host       string        `mapstructure:"host"`
-	Port       int           `mapstructure:"port"`
-	Timeout    time.Duration `mapstructure:"timeout"`
-	MaxRetries int           `mapstructure:"max_retries"`
-	PoolSize   int           `mapstructure:"pool_size"`
+	Enabled          bool          `mapstructure:"enabled"`
+	Host             string        `mapstructure:"host"`
+	Port             int           `mapstructure:"port"`
+	Timeout          time.Duration `mapstructure:"timeout"`
+	MaxRetries       int           `mapstructure:"max_retries"`
+	MaxRetryElapsed  time.Duration `mapstructure:"max_retry_elapsed"`
+	Jitter           bool          `mapstructure:"jitter"`
+	PoolSize         int           `mapstructure:"pool_size"`
+	KeepaliveTime    time.Duration `mapstructure:"keepalive_time"`
+	KeepaliveTimeout time.Duration `mapstructure:"keepalive_timeout"`
 }
 
 type BM25Config struct {
-	Enabled    bool          `mapstructure:"enabled"`
-	Host       string        `mapstructure:"host"`
-	Port       int           `mapstructure:"port"`
-	Timeout    time.Duration `mapstructure:"timeout"`
-	MaxRetries int           `mapstructure:"max_retries"`
-	PoolSize   int           `mapstructure:"pool_size"`
-	K1         float64       `mapstructure:"k1"`
-	B          float64       `mapstructure:"b"`
+	Enabled          bool          `mapstructure:"enabled"`
+	Host             string        `mapstructure:"host"`
+	Port             int           `mapstructure:"port"`
+	Timeout          time.Duration `mapstructure:"timeout"`
+	MaxRetries       int           `mapstructure:"max_retries"`
+	MaxRetryElapsed  time.Duration `mapstructure:"max_retry_elapsed"`
+	Jitter           bool          `mapstructure:"jitter"`
+	PoolSize         int           `mapstructure:"pool_size"`
+	KeepaliveTime    time.Duration `mapstructure:"keepalive_time"`
+	KeepaliveTimeout time.Duration `mapstructure:"keepalive_timeout"`
+	K1               float64       `mapstructure:"k1"`
+	B                float64       `mapstructure:"b"`
 }
 
 type VectorConfig struct {
-	Enabled    bool          `mapstructure:"enabled"`
-	Host       string        `mapstructure:"host"`
-	Port       int           `mapstructure:"port"`
-	Timeout    time.Duration `mapstructure:"timeout"`
-	MaxRetries int           `mapstructure:"max_retries"`
-	PoolSize   int           `mapstructure:"pool_size"`
-	Model      string        `mapstructure:"model"`
-	Dimension  int           `mapstructure:"dimension"`
+	Enabled          bool          `mapstructure:"enabled"`
+	Host             string        `mapstructure:"host"`
+	Port             int           `mapstructure:"port"`
+	Timeout          time.Duration `mapstructure:"timeout"`
+	MaxRetries       int           `mapstructure:"max_retries"`
+	MaxRetryElapsed  time.Duration `mapstructure:"max_retry_elapsed"`
+	Jitter           bool          `mapstructure:"jitter"`
+	PoolSize         int           `mapstructure:"pool_size"`
+	KeepaliveTime    time.Duration `mapstructure:"keepalive_time"`
+	KeepaliveTimeout time.Duration `mapstructure:"keepalive_timeout"`
+	Model            string        `mapstructure:"model"`
+	Dimension        int           `mapstructure:"dimension"`
 }
 
 func (e *EnginesConfig) GetFlexSearchAddress() string {
@@ -0,0 +1,173 @@
+package config
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"reflect"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/flexsearch/coordinator/internal/util"
+)
+
+// ReloadTargets receives the settings that can change without a restart.
+// A nil target leaves that setting alone.
+type ReloadTargets struct {
+	SetLogLevel   func(level string) error
+	SetCacheTTL   func(ttl time.Duration)
+	SetSampleRate func(rate float64)
+}
+
+type ReloadResult struct {
+	// Changed lists the reloadable fields that were applied.
+	Changed []string
+	// Ignored lists fields that differ from the running config but only
+	// take effect after a restart.
+	Ignored []string
+}
+
+// Reloader re-reads the config file and applies the safely reloadable
+// fields: logging.level, cache.default_ttl and tracing.sample_rate.
+// Everything else keeps the value the process started with.
+type Reloader struct {
+	path    string
+	logger  *util.Logger
+	targets ReloadTargets
+
+	mu      sync.Mutex
+	current Config
+}
+
+func NewReloader(path string, current *Config, logger *util.Logger, targets ReloadTargets) *Reloader {
+	return &Reloader{
+		path:    path,
+		logger:  logger,
+		targets: targets,
+		current: *current,
+	}
+}
+
+// Current returns a copy of the running config, including reloaded fields.
+func (r *Reloader) Current() Config {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.current
+}
+
+func (r *Reloader) Reload() (*ReloadResult, error) {
+	next, err := Load(r.path)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := &ReloadResult{}
+
+	if next.Logging.Level != r.current.Logging.Level {
+		if err := r.applyLogLevel(next.Logging.Level); err != nil {
+			r.logger.Warnf("Failed to apply logging.level %q: %v", next.Logging.Level, err)
+		} else {
+			r.current.Logging.Level = next.Logging.Level
+			result.Changed = append(result.Changed, "logging.level")
+		}
+	}
+	if next.Cache.DefaultTTL != r.current.Cache.DefaultTTL {
+		if r.targets.SetCacheTTL != nil {
+			r.targets.SetCacheTTL(next.Cache.DefaultTTL)
+		}
+		r.current.Cache.DefaultTTL = next.Cache.DefaultTTL
+		result.Changed = append(result.Changed, "cache.default_ttl")
+	}
+	if next.Tracing.SampleRate != r.current.Tracing.SampleRate {
+		if r.targets.SetSampleRate != nil {
+			r.targets.SetSampleRate(next.Tracing.SampleRate)
+		}
+		r.current.Tracing.SampleRate = next.Tracing.SampleRate
+		result.Changed = append(result.Changed, "tracing.sample_rate")
+	}
+
+	// Whatever still differs needs a restart. Copy the reloadable fields
+	// over first so a rejected log level isn't reported as ignored.
+	next.Logging.Level = r.current.Logging.Level
+	result.Ignored = diffFields(r.current, *next)
+
+	return result, nil
+}
+
+func (r *Reloader) applyLogLevel(level string) error {
+	if r.targets.SetLogLevel == nil {
+		return nil
+	}
+	return r.targets.SetLogLevel(level)
+}
+
+// WatchSIGHUP reloads the config on every SIGHUP until ctx is done. The
+// handler is registered before WatchSIGHUP returns.
+func (r *Reloader) WatchSIGHUP(ctx context.Context) {
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGHUP)
+
+	go func() {
+		defer signal.Stop(sigChan)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-sigChan:
+				r.reloadAndLog()
+			}
+		}
+	}()
+}
+
+func (r *Reloader) reloadAndLog() {
+	result, err := r.Reload()
+	if err != nil {
+		r.logger.Errorf("Config reload failed: %v", err)
+		return
+	}
+	if len(result.Ignored) > 0 {
+		r.logger.Warnw("Config changes require a restart and were ignored", "fields", result.Ignored)
+	}
+	if len(result.Changed) == 0 {
+		r.logger.Info("Config reloaded, no reloadable fields changed")
+		return
+	}
+	r.logger.Infow("Config reloaded", "changed", result.Changed)
+}
+
+// diffFields names the fields that differ between a and b, one level into
+// each section, using their config keys.
+func diffFields(a, b Config) []string {
+	var fields []string
+	av, bv := reflect.ValueOf(a), reflect.ValueOf(b)
+	for i := 0; i < av.NumField(); i++ {
+		section := av.Type().Field(i)
+		as, bs := av.Field(i), bv.Field(i)
+		if reflect.DeepEqual(as.Interface(), bs.Interface()) {
+			continue
+		}
+		if as.Kind() != reflect.Struct {
+			fields = append(fields, configKey(section))
+			continue
+		}
+		for j := 0; j < as.NumField(); j++ {
+			if !reflect.DeepEqual(as.Field(j).Interface(), bs.Field(j).Interface()) {
+				fields = append(fields, configKey(section)+"."+configKey(as.Type().Field(j)))
+			}
+		}
+	}
+	return fields
+}
+
+func configKey(field reflect.StructField) string {
+	if tag := field.Tag.Get("mapstructure"); tag != "" {
+		return strings.Split(tag, ",")[0]
+	}
+	return strings.ToLower(field.Name)
+}
@@ -0,0 +1,171 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/flexsearch/coordinator/internal/util"
+)
+
+const reloadBaseConfig = testEnginesConfig + `
+grpc:
+  port: 50052
+cache:
+  default_ttl: 5m
+tracing:
+  sample_rate: 1.0
+logging:
+  level: info
+  output: stderr
+`
+
+func newTestReloader(t *testing.T) (*Reloader, *util.Logger, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfig(t, path, reloadBaseConfig)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	logger, err := util.NewLogger(cfg.Logging.Level, "json", cfg.Logging.Output)
+	if err != nil {
+		t.Fatalf("NewLogger() error = %v", err)
+	}
+	return NewReloader(path, cfg, logger, ReloadTargets{SetLogLevel: logger.SetLevel}), logger, path
+}
+
+func TestReloaderAppliesReloadableFields(t *testing.T) {
+	var ttl time.Duration
+	var rate float64
+
+	reloader, logger, path := newTestReloader(t)
+	reloader.targets.SetCacheTTL = func(d time.Duration) { ttl = d }
+	reloader.targets.SetSampleRate = func(r float64) { rate = r }
+
+	writeConfig(t, path, testEnginesConfig+`
+grpc:
+  port: 50052
+cache:
+  default_ttl: 1m
+tracing:
+  sample_rate: 0.25
+logging:
+  level: debug
+  output: stderr
+`)
+
+	result, err := reloader.Reload()
+	if err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+
+	if got := logger.Level(); got != "debug" {
+		t.Errorf("logger level = %q, want debug", got)
+	}
+	if ttl != time.Minute {
+		t.Errorf("cache TTL = %v, want 1m", ttl)
+	}
+	if rate != 0.25 {
+		t.Errorf("sample rate = %v, want 0.25", rate)
+	}
+
+	want := []string{"logging.level", "cache.default_ttl", "tracing.sample_rate"}
+	if len(result.Changed) != len(want) {
+		t.Fatalf("Changed = %v, want %v", result.Changed, want)
+	}
+	for i := range want {
+		if result.Changed[i] != want[i] {
+			t.Errorf("Changed[%d] = %q, want %q", i, result.Changed[i], want[i])
+		}
+	}
+	if len(result.Ignored) != 0 {
+		t.Errorf("Ignored = %v, want none", result.Ignored)
+	}
+}
+
+func TestReloaderIgnoresRestartOnlyFields(t *testing.T) {
+	reloader, logger, path := newTestReloader(t)
+
+	writeConfig(t, path, testEnginesConfig+`
+grpc:
+  port: 6000
+cache:
+  default_ttl: 5m
+  max_size: 42
+tracing:
+  sample_rate: 1.0
+logging:
+  level: warn
+  output: stderr
+`)
+
+	result, err := reloader.Reload()
+	if err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+
+	if got := logger.Level(); got != "warn" {
+		t.Errorf("logger level = %q, want warn", got)
+	}
+
+	current := reloader.Current()
+	if current.GRPC.Port != 50052 {
+		t.Errorf("grpc.port = %d, want 50052", current.GRPC.Port)
+	}
+	if current.Cache.MaxSize != 10000 {
+		t.Errorf("cache.max_size = %d, want 10000", current.Cache.MaxSize)
+	}
+	if current.Logging.Level != "warn" {
+		t.Errorf("logging.level = %q, want warn", current.Logging.Level)
+	}
+
+	ignored := map[string]bool{}
+	for _, field := range result.Ignored {
+		ignored[field] = true
+	}
+	if !ignored["grpc.port"] || !ignored["cache.max_size"] || len(ignored) != 2 {
+		t.Errorf("Ignored = %v, want [grpc.port cache.max_size]", result.Ignored)
+	}
+}
+
+func TestReloaderKeepsConfigOnReadError(t *testing.T) {
+	reloader, logger, path := newTestReloader(t)
+	writeConfig(t, path, "logging: [")
+
+	if _, err := reloader.Reload(); err == nil {
+		t.Fatal("Reload() error = nil, want parse error")
+	}
+	if got := logger.Level(); got != "info" {
+		t.Errorf("logger level = %q, want info", got)
+	}
+}
+
+func TestReloaderWatchSIGHUP(t *testing.T) {
+	reloader, logger, path := newTestReloader(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	reloader.WatchSIGHUP(ctx)
+
+	writeConfig(t, path, testEnginesConfig+`
+logging:
+  level: error
+  output: stderr
+`)
+	if err := syscall.Kill(os.Getpid(), syscall.SIGHUP); err != nil {
+		t.Fatalf("failed to send SIGHUP: %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for logger.Level() != "error" {
+		if time.Now().After(deadline) {
+			t.Fatalf("logger level = %q after SIGHUP, want error", logger.Level())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,177 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var validLogLevels = map[string]bool{
+	"debug": true, "info": true, "warn": true, "warning": true, "error": true, "fatal": true,
+}
+
+// Validate checks ranges and required fields and reports every problem it
+// finds at once, one per line, keyed by config path.
+func (c *Config) Validate() error {
+	var errs []error
+	add := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+	checkPort := func(key string, port int) {
+		if port < 1 || port > 65535 {
+			add("%s must be between 1 and 65535, got %d", key, port)
+		}
+	}
+
+	checkPort("server.port", c.Server.Port)
+	checkPort("grpc.port", c.GRPC.Port)
+	if c.Metrics.Enabled {
+		checkPort("metrics.port", c.Metrics.Port)
+	}
+
+	if c.Cache.Enabled {
+		if c.Redis.Host == "" {
+			add("redis.host is required when cache.enabled is true")
+		}
+		checkPort("redis.port", c.Redis.Port)
+		if c.Redis.PoolSize <= 0 {
+			add("redis.pool_size must be positive, got %d", c.Redis.PoolSize)
+		}
+		if c.Cache.DefaultTTL <= 0 {
+			add("cache.default_ttl must be positive, got %v", c.Cache.DefaultTTL)
+		}
+		if c.Cache.MaxSize < 0 {
+			add("cache.max_size must not be negative, got %d", c.Cache.MaxSize)
+		}
+		if c.Cache.LocalTTL < 0 {
+			add("cache.local_ttl must not be negative, got %v", c.Cache.LocalTTL)
+		}
+		if c.Cache.CompressMinSize < 0 {
+			add("cache.compression_min_size must not be negative, got %d", c.Cache.CompressMinSize)
+		}
+		if c.Cache.SoftTTL < 0 {
+			add("cache.soft_ttl must not be negative, got %v", c.Cache.SoftTTL)
+		}
+		for i, rule := range c.Cache.InvalidationRules {
+			key := fmt.Sprintf("cache.invalidation_rules[%d]", i)
+			if rule.Pattern == "" {
+				add("%s.pattern is required", key)
+			}
+			switch rule.Strategy {
+			case "time":
+				if rule.TTL <= 0 {
+					add("%s.ttl must be positive for the time strategy, got %v", key, rule.TTL)
+				}
+			case "event", "manual":
+			default:
+				add("%s.strategy must be one of time, event or manual, got %q", key, rule.Strategy)
+			}
+		}
+		if c.Cache.SoftTTL > 0 && c.Cache.HardTTL <= c.Cache.SoftTTL {
+			add("cache.hard_ttl must exceed cache.soft_ttl %v, got %v", c.Cache.SoftTTL, c.Cache.HardTTL)
+		}
+	}
+
+	engines := []struct {
+		name     string
+		enabled  bool
+		host     string
+		port     int
+		poolSize int
+		timeout  time.Duration
+		retries  time.Duration
+	}{
+		{"flexsearch", c.Engines.FlexSearch.Enabled, c.Engines.FlexSearch.Host, c.Engines.FlexSearch.Port, c.Engines.FlexSearch.PoolSize, c.Engines.FlexSearch.Timeout, c.Engines.FlexSearch.MaxRetryElapsed},
+		{"bm25", c.Engines.BM25.Enabled, c.Engines.BM25.Host, c.Engines.BM25.Port, c.Engines.BM25.PoolSize, c.Engines.BM25.Timeout, c.Engines.BM25.MaxRetryElapsed},
+		{"vector", c.Engines.Vector.Enabled, c.Engines.Vector.Host, c.Engines.Vector.Port, c.Engines.Vector.PoolSize, c.Engines.Vector.Timeout, c.Engines.Vector.MaxRetryElapsed},
+	}
+	enabled := 0
+	for _, e := range engines {
+		if !e.enabled {
+			continue
+		}
+		enabled++
+		key := "engines." + e.name
+		if e.host == "" {
+			add("%s.host is required when the engine is enabled", key)
+		}
+		checkPort(key+".port", e.port)
+		if e.poolSize <= 0 {
+			add("%s.pool_size must be positive, got %d", key, e.poolSize)
+		}
+		if e.timeout < 0 {
+			add("%s.timeout must not be negative, got %v", key, e.timeout)
+		}
+		if e.retries < 0 {
+			add("%s.max_retry_elapsed must not be negative, got %v", key, e.retries)
+		}
+	}
+	if enabled == 0 {
+		add("at least one of engines.flexsearch, engines.bm25 or engines.vector must be enabled")
+	}
+	if c.Engines.Readiness.Attempts < 0 {
+		add("engines.readiness.attempts must not be negative, got %d", c.Engines.Readiness.Attempts)
+	}
+	if c.Engines.Readiness.InitialBackoff < 0 || c.Engines.Readiness.MaxBackoff < 0 {
+		add("engines.readiness backoffs must not be negative")
+	}
+	if c.Engines.Vector.Enabled && c.Engines.Vector.Dimension <= 0 {
+		add("engines.vector.dimension must be positive, got %d", c.Engines.Vector.Dimension)
+	}
+
+	if c.Search.MinSuccessfulEngines > enabled && enabled > 0 {
+		add("search.min_successful_engines is %d but only %d engines are enabled", c.Search.MinSuccessfulEngines, enabled)
+	}
+	for strategy, names := range c.Search.FallbackEngines {
+		for _, name := range names {
+			if name != "flexsearch" && name != "bm25" && name != "vector" {
+				add("search.fallback_engines.%s has unknown engine %q", strategy, name)
+			}
+		}
+	}
+	complexity := c.Search.Complexity
+	if complexity.MaxTerms < 0 || complexity.MaxFilters < 0 || complexity.MaxIndexes < 0 || complexity.MaxWildcards < 0 {
+		add("search.complexity limits must not be negative")
+	}
+	if c.Search.Wildcards.MinPrefix < 0 {
+		add("search.wildcards.min_prefix must not be negative, got %d", c.Search.Wildcards.MinPrefix)
+	}
+	switch c.Search.Wildcards.Action {
+	case "", "reject", "rewrite":
+	default:
+		add("search.wildcards.action must be reject or rewrite, got %q", c.Search.Wildcards.Action)
+	}
+	if c.Search.PerEngineLimit < 0 {
+		add("search.per_engine_limit must not be negative, got %d", c.Search.PerEngineLimit)
+	}
+	switch c.Search.Fusion.Strategy {
+	case "", "rrf", "weighted":
+	default:
+		add("search.fusion.strategy must be rrf or weighted, got %q", c.Search.Fusion.Strategy)
+	}
+	switch c.Search.Fusion.Normalization {
+	case "", "none", "max", "minmax", "zscore":
+	default:
+		add("search.fusion.normalization must be one of none, max, minmax or zscore, got %q", c.Search.Fusion.Normalization)
+	}
+	switch c.Tracing.Exporter {
+	case "", "stdout", "none":
+	case "otlp-grpc", "otlp-http":
+		if c.Tracing.Enabled && c.Tracing.Endpoint == "" {
+			add("tracing.endpoint is required for the %s exporter", c.Tracing.Exporter)
+		}
+	default:
+		add("tracing.exporter must be one of stdout, otlp-grpc, otlp-http or none, got %q", c.Tracing.Exporter)
+	}
+	if c.Tracing.SampleRate < 0 || c.Tracing.SampleRate > 1 {
+		add("tracing.sample_rate must be between 0 and 1, got %v", c.Tracing.SampleRate)
+	}
+	if c.Logging.Sampling.Initial < 0 || c.Logging.Sampling.Thereafter < 0 {
+		add("logging.sampling.initial and logging.sampling.thereafter must not be negative")
+	}
+	if !validLogLevels[c.Logging.Level] {
+		add("logging.level must be one of debug, info, warn, error or fatal, got %q", c.Logging.Level)
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,261 @@
+package config
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func validConfig() *Config {
+	return &Config{
+		Server: ServerConfig{Port: 50051},
+		GRPC:   GRPCConfig{Port: 50052},
+		Redis:  RedisConfig{Host: "localhost", Port: 6379, PoolSize: 10},
+		Engines: EnginesConfig{
+			BM25: BM25Config{Enabled: true, Host: "localhost", Port: 50054, PoolSize: 10, Timeout: time.Second},
+		},
+		Cache:   CacheConfig{Enabled: true, DefaultTTL: time.Minute},
+		Search:  SearchConfig{MinSuccessfulEngines: 1},
+		Metrics: MetricsConfig{Enabled: true, Port: 9090},
+		Tracing: TracingConfig{SampleRate: 1},
+		Logging: LoggingConfig{Level: "info"},
+	}
+}
+
+func TestValidateAcceptsValidConfig(t *testing.T) {
+	if err := validConfig().Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+}
+
+func TestValidateReportsEveryProblem(t *testing.T) {
+	cfg := validConfig()
+	cfg.GRPC.Port = 0
+	cfg.Metrics.Port = 70000
+	cfg.Redis.PoolSize = -1
+	cfg.Engines.BM25.Host = ""
+	cfg.Engines.Vector = VectorConfig{Enabled: true, Host: "localhost", Port: 50055, PoolSize: 0}
+	cfg.Tracing.SampleRate = 2
+	cfg.Logging.Level = "verbose"
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("Validate() error = nil, want problems")
+	}
+
+	for _, want := range []string{
+		"grpc.port must be between 1 and 65535, got 0",
+		"metrics.port must be between 1 and 65535, got 70000",
+		"redis.pool_size must be positive, got -1",
+		"engines.bm25.host is required",
+		"engines.vector.pool_size must be positive, got 0",
+		"engines.vector.dimension must be positive",
+		"tracing.sample_rate must be between 0 and 1",
+		`logging.level must be one of debug, info, warn, error or fatal, got "verbose"`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Validate() error missing %q:\n%v", want, err)
+		}
+	}
+	if got := strings.Count(err.Error(), "\n") + 1; got != 8 {
+		t.Errorf("Validate() reported %d problems, want 8:\n%v", got, err)
+	}
+}
+
+func TestValidateRejectsNegativeRetryBudget(t *testing.T) {
+	cfg := validConfig()
+	cfg.Engines.BM25.MaxRetryElapsed = -time.Second
+
+	err := cfg.Validate()
+	if err == nil || !strings.Contains(err.Error(), "engines.bm25.max_retry_elapsed must not be negative, got -1s") {
+		t.Errorf("Validate() error = %v, want a negative retry budget problem", err)
+	}
+}
+
+func TestValidateRejectsBadInvalidationRules(t *testing.T) {
+	cfg := validConfig()
+	cfg.Cache.InvalidationRules = []InvalidationRuleConfig{
+		{Pattern: "search:*", Strategy: "time"},
+		{Strategy: "sometimes"},
+	}
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("Validate() error = nil, want rule problems")
+	}
+	for _, want := range []string{
+		"cache.invalidation_rules[0].ttl must be positive for the time strategy, got 0s",
+		"cache.invalidation_rules[1].pattern is required",
+		`cache.invalidation_rules[1].strategy must be one of time, event or manual, got "sometimes"`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Validate() error missing %q:\n%v", want, err)
+		}
+	}
+}
+
+func TestValidateRejectsBadTracingExporter(t *testing.T) {
+	for _, tc := range []struct {
+		exporter string
+		want     string
+	}{
+		{"jaeger", `tracing.exporter must be one of stdout, otlp-grpc, otlp-http or none, got "jaeger"`},
+		{"otlp-grpc", "tracing.endpoint is required for the otlp-grpc exporter"},
+	} {
+		cfg := validConfig()
+		cfg.Tracing.Enabled = true
+		cfg.Tracing.Exporter = tc.exporter
+
+		err := cfg.Validate()
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("Validate() with exporter %q error = %v, want %q", tc.exporter, err, tc.want)
+		}
+	}
+}
+
+func TestValidateRequiresAnEnabledEngine(t *testing.T) {
+	cfg := validConfig()
+	cfg.Engines.BM25.Enabled = false
+
+	err := cfg.Validate()
+	if err == nil || !strings.Contains(err.Error(), "at least one of engines.flexsearch, engines.bm25 or engines.vector must be enabled") {
+		t.Fatalf("Validate() error = %v, want missing engine error", err)
+	}
+}
+
+func TestValidateRejectsUnknownFallbackEngines(t *testing.T) {
+	cfg := validConfig()
+	cfg.Search.FallbackEngines = map[string][]string{"semantic_search": {"bm25", "solr"}}
+
+	err := cfg.Validate()
+	if err == nil || !strings.Contains(err.Error(), `search.fallback_engines.semantic_search has unknown engine "solr"`) {
+		t.Fatalf("Validate() error = %v, want unknown fallback engine error", err)
+	}
+}
+
+func TestValidateRejectsUnknownFusion(t *testing.T) {
+	cfg := validConfig()
+	cfg.Search.Fusion = FusionConfig{Strategy: "borda", Normalization: "log"}
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("Validate() error = nil, want fusion problems")
+	}
+	for _, want := range []string{
+		`search.fusion.strategy must be rrf or weighted, got "borda"`,
+		`search.fusion.normalization must be one of none, max, minmax or zscore, got "log"`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Validate() error missing %q:\n%v", want, err)
+		}
+	}
+}
+
+func TestValidateSkipsDisabledSections(t *testing.T) {
+	cfg := validConfig()
+	cfg.Cache.Enabled = false
+	cfg.Redis = RedisConfig{}
+	cfg.Metrics = MetricsConfig{}
+	cfg.Engines.FlexSearch = FlexSearchConfig{Enabled: false, Port: -1}
+
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+}
+
+func TestLoadRejectsInvalidConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfig(t, path, `
+grpc:
+  port: 99999
+engines:
+  bm25:
+    enabled: true
+    port: 50054
+    pool_size: 0
+`)
+
+	_, err := Load(path)
+	if err == nil {
+		t.Fatal("Load() error = nil, want validation error")
+	}
+	for _, want := range []string{"invalid config", "grpc.port", "engines.bm25.host", "engines.bm25.pool_size"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Load() error missing %q:\n%v", want, err)
+		}
+	}
+}
+
+func TestLoadShippedConfig(t *testing.T) {
+	if _, err := Load(filepath.Join("..", "..", "configs", "config.yaml")); err != nil {
+		t.Fatalf("configs/config.yaml does not validate: %v", err)
+	}
+}
+
+func TestValidateRejectsNegativeComplexityLimits(t *testing.T) {
+	cfg := validConfig()
+	cfg.Search.Complexity = ComplexityConfig{MaxTerms: -1}
+
+	err := cfg.Validate()
+	if err == nil || !strings.Contains(err.Error(), "search.complexity limits must not be negative") {
+		t.Errorf("Validate() error = %v, want negative complexity limits rejected", err)
+	}
+}
+
+func TestValidateRejectsBadWildcardPolicy(t *testing.T) {
+	cfg := validConfig()
+	cfg.Search.Wildcards = WildcardConfig{MinPrefix: -1, Action: "ignore"}
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("Validate() error = nil, want wildcard problems")
+	}
+	for _, want := range []string{
+		"search.wildcards.min_prefix must not be negative, got -1",
+		`search.wildcards.action must be reject or rewrite, got "ignore"`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Validate() error missing %q:\n%v", want, err)
+		}
+	}
+}
+
+func TestValidateRejectsNegativeCacheSettings(t *testing.T) {
+	cfg := validConfig()
+	cfg.Cache.MaxSize = -1
+	cfg.Cache.LocalTTL = -time.Second
+	cfg.Cache.CompressMinSize = -1
+	cfg.Cache.SoftTTL = -time.Second
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("Validate() error = nil, want cache problems")
+	}
+	for _, want := range []string{
+		"cache.max_size must not be negative, got -1",
+		"cache.local_ttl must not be negative, got -1s",
+		"cache.compression_min_size must not be negative, got -1",
+		"cache.soft_ttl must not be negative, got -1s",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Validate() error missing %q:\n%v", want, err)
+		}
+	}
+}
+
+func TestValidateRequiresHardTTLPastSoftTTL(t *testing.T) {
+	cfg := validConfig()
+	cfg.Cache.SoftTTL = time.Minute
+	cfg.Cache.HardTTL = time.Minute
+
+	err := cfg.Validate()
+	if err == nil || !strings.Contains(err.Error(), "cache.hard_ttl must exceed cache.soft_ttl 1m0s, got 1m0s") {
+		t.Errorf("Validate() error = %v, want hard_ttl rejected", err)
+	}
+
+	cfg.Cache.HardTTL = 10 * time.Minute
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
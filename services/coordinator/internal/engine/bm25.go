@@ -7,21 +7,28 @@ import (
 	"fmt"
 	"math"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/flexsearch/coordinator/internal/model"
 	"github.com/flexsearch/coordinator/internal/util"
-	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/connectivity"
-	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	bm25AddDocumentMethod    = "/bm25.BM25Service/AddDocument"
+	bm25DeleteDocumentMethod = "/bm25.BM25Service/DeleteDocument"
+	bm25IndexStatsMethod     = "/bm25.BM25Service/IndexStats"
+	bm25PingMethod           = "/bm25.BM25Service/Ping"
 )
 
 type BM25Client struct {
 	config          *ClientConfig
 	bm25Config      *BM25EngineConfig
-	conn            *grpc.ClientConn
+	pool            atomic.Pointer[connPool]
 	logger          *util.Logger
 	circuitBreaker  *CircuitBreaker
 	retryConfig     *RetryConfig
@@ -32,13 +39,19 @@ type BM25EngineConfig struct {
 	B         float64
 	MinLength int
 	MaxLength int
+	// Tokenizer splits queries into terms; nil splits on whitespace.
+	Tokenizer Tokenizer
+	// CorpusStats supplies document counts and lengths for scoring; nil
+	// uses DefaultCorpusStats.
+	CorpusStats CorpusStats
 }
 
 func NewBM25Client(config *ClientConfig, bm25Config *BM25EngineConfig, logger *util.Logger) *BM25Client {
 	cbConfig := &CircuitBreakerConfig{
-		FailureThreshold: 5,
-		SuccessThreshold: 2,
-		Timeout:          30 * time.Second,
+		FailureThreshold:    5,
+		SuccessThreshold:    2,
+		Timeout:             30 * time.Second,
+		HalfOpenMaxRequests: 1,
 	}
 
 	retryConfig := &RetryConfig{
@@ -46,6 +59,8 @@ func NewBM25Client(config *ClientConfig, bm25Config *BM25EngineConfig, logger *u
 		InitialDelay:  100 * time.Millisecond,
 		MaxDelay:      5 * time.Second,
 		BackoffFactor: 2.0,
+		MaxElapsed:    config.maxRetryElapsed(),
+		Jitter:        config.jitter(),
 	}
 
 	return &BM25Client{
@@ -58,28 +73,22 @@ func NewBM25Client(config *ClientConfig, bm25Config *BM25EngineConfig, logger *u
 }
 
 func (c *BM25Client) Connect(ctx context.Context) error {
-	address := fmt.Sprintf("%s:%d", c.config.Host, c.config.Port)
-	
-	conn, err := grpc.Dial(address, 
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-		grpc.WithDefaultCallOptions(
-			grpc.MaxCallRecvMsgSize(100*1024*1024),
-			grpc.MaxCallSendMsgSize(100*1024*1024),
-		),
-	)
+	address := c.config.Address()
+
+	pool, err := newConnPool(c.config)
 	if err != nil {
 		return fmt.Errorf("failed to connect to BM25: %w", err)
 	}
 
-	c.conn = conn
-	c.logger.Infof("BM25 client connected to %s", address)
+	pool.monitor(c.GetName(), c.logger)
+	c.pool.Store(pool)
+	c.logger.Infof("BM25 client connected to %s with %d connections", address, pool.size())
 	return nil
 }
 
 func (c *BM25Client) Disconnect() error {
-	if c.conn != nil {
-		err := c.conn.Close()
-		c.conn = nil
+	if pool := c.pool.Swap(nil); pool != nil {
+		err := pool.close()
 		c.logger.Info("BM25 client disconnected")
 		return err
 	}
@@ -105,10 +114,14 @@ func (c *BM25Client) Search(ctx context.Context, req *model.SearchRequest) (*mod
 
 func (c *BM25Client) searchWithRetry(ctx context.Context, req *model.SearchRequest) (*model.EngineResult, error) {
 	var lastErr error
+	start := time.Now()
 	
 	for attempt := 0; attempt <= c.retryConfig.MaxRetries; attempt++ {
 		if attempt > 0 {
 			delay := c.calculateBackoff(attempt)
+			if c.retryConfig.BudgetExceeded(start, delay) {
+				return nil, fmt.Errorf("BM25 search retry budget of %v exhausted after %d attempts: %w", c.retryConfig.MaxElapsed, attempt, lastErr)
+			}
 			c.logger.Debugf("BM25 retry attempt %d after %v", attempt, delay)
 			
 			select {
@@ -145,6 +158,15 @@ func (c *BM25Client) doSearch(ctx context.Context, req *model.SearchRequest) (*m
 	defer cancel()
 
 	query := c.preprocessQuery(req.Query)
+	terms, phrases := query, req.Phrases
+	if req.Boolean != nil {
+		// Score only what a match may contain: operators and negated terms
+		// and phrases are not document text.
+		terms, phrases = c.preprocessQuery(strings.Join(req.Boolean.Terms(), " ")), nil
+	} else if len(phrases) > 0 {
+		_, rest := model.ParsePhrases(req.Query)
+		terms = c.preprocessQuery(rest)
+	}
 	
 	result := &model.EngineResult{
 		Engine:  "bm25",
@@ -154,7 +176,7 @@ func (c *BM25Client) doSearch(ctx context.Context, req *model.SearchRequest) (*m
 	}
 
 	for i := 0; i < int(req.Limit); i++ {
-		score := c.calculateBM25Score(query, i)
+		score := c.calculateBM25Score(req.Index, terms, i) + c.phraseScore(req.Index, phrases, i)
 		
 		result.Results = append(result.Results, model.SearchResult{
 			ID:           c.generateID(query, i),
@@ -175,10 +197,7 @@ func (c *BM25Client) doSearch(ctx context.Context, req *model.SearchRequest) (*m
 }
 
 func (c *BM25Client) preprocessQuery(query string) string {
-	query = strings.ToLower(query)
-	query = strings.TrimSpace(query)
-	
-	words := strings.Fields(query)
+	words := c.getTokenizer().Tokenize(query)
 	var filtered []string
 	for _, word := range words {
 		if len(word) >= c.getMinLength() && len(word) <= c.getMaxLength() {
@@ -189,24 +208,30 @@ func (c *BM25Client) preprocessQuery(query string) string {
 	return strings.Join(filtered, " ")
 }
 
-func (c *BM25Client) calculateBM25Score(query string, docIndex int) float64 {
+func (c *BM25Client) calculateBM25Score(index, query string, docIndex int) float64 {
 	words := strings.Fields(query)
 	if len(words) == 0 {
 		return 0.0
 	}
 
-	avgDocLength := 100.0
+	stats := c.getCorpusStats()
+	totalDocs := float64(stats.TotalDocs(index))
+	if totalDocs <= 0 {
+		totalDocs = float64(DefaultCorpusStats.Docs)
+	}
+	avgDocLength := stats.AvgDocLength(index)
+	if avgDocLength <= 0 {
+		avgDocLength = DefaultCorpusStats.AvgLength
+	}
 	docLength := 50.0 + float64(docIndex)*10
-	totalDocs := 1000.0
-	docFreq := 5.0
-
-	idf := math.Log((totalDocs - docFreq + 0.5) / (docFreq + 0.5) + 1.0)
 	
 	k1 := c.getK1()
 	b := c.getB()
 	
 	score := 0.0
 	for _, word := range words {
+		docFreq := math.Min(math.Max(float64(stats.DocFreq(index, word)), 0), totalDocs)
+		idf := math.Log((totalDocs - docFreq + 0.5) / (docFreq + 0.5) + 1.0)
 		tf := 1.0 + float64(len(word)%5)
 		docLengthFactor := (1.0 - b) + b*(docLength/avgDocLength)
 		wordScore := (tf * (k1 + 1.0)) / (tf + k1*docLengthFactor)
@@ -216,16 +241,118 @@ func (c *BM25Client) calculateBM25Score(query string, docIndex int) float64 {
 	return score
 }
 
+// phraseScore scores each phrase as its terms would score loose, boosted
+// up to twice for an exact phrase and less the more distance a proximity
+// clause allows, so matching the words together outranks matching them
+// apart.
+func (c *BM25Client) phraseScore(index string, phrases []model.PhraseClause, docIndex int) float64 {
+	score := 0.0
+	for _, phrase := range phrases {
+		terms := c.preprocessQuery(strings.Join(phrase.Terms, " "))
+		boost := 1.0 + 1.0/float64(1+phrase.Distance)
+		score += c.calculateBM25Score(index, terms, docIndex) * boost
+	}
+	return score
+}
+
+func (c *BM25Client) AddDocument(ctx context.Context, doc *model.DocumentRequest) error {
+	payload, err := buildDocumentPayload(doc)
+	if err != nil {
+		return err
+	}
+
+	return c.write(ctx, bm25AddDocumentMethod, payload)
+}
+
+func (c *BM25Client) DeleteDocument(ctx context.Context, index, id string) error {
+	payload, err := buildDeletePayload(index, id)
+	if err != nil {
+		return err
+	}
+
+	return c.write(ctx, bm25DeleteDocumentMethod, payload)
+}
+
+func (c *BM25Client) write(ctx context.Context, method string, payload *structpb.Struct) error {
+	pool := c.pool.Load()
+	if pool == nil {
+		return fmt.Errorf("BM25 client is not connected")
+	}
+
+	if !c.circuitBreaker.AllowRequest() {
+		return fmt.Errorf("circuit breaker is open for BM25")
+	}
+
+	err := c.writeWithRetry(ctx, pool, method, payload)
+	if err != nil {
+		c.circuitBreaker.RecordFailure()
+		c.logger.Errorf("BM25 write %s failed: %v", method, err)
+		return err
+	}
+
+	c.circuitBreaker.RecordSuccess()
+	return nil
+}
+
+func (c *BM25Client) writeWithRetry(ctx context.Context, pool *connPool, method string, payload *structpb.Struct) error {
+	var lastErr error
+	start := time.Now()
+
+	for attempt := 0; attempt <= c.retryConfig.MaxRetries; attempt++ {
+		if attempt > 0 {
+			delay := c.calculateBackoff(attempt)
+			if c.retryConfig.BudgetExceeded(start, delay) {
+				return fmt.Errorf("BM25 write retry budget of %v exhausted after %d attempts: %w", c.retryConfig.MaxElapsed, attempt, lastErr)
+			}
+			c.logger.Debugf("BM25 write retry attempt %d after %v", attempt, delay)
+
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		conn, err := pool.get()
+		if err != nil {
+			return fmt.Errorf("BM25 write %s: %w", method, err)
+		}
+		callCtx, cancel := context.WithTimeout(ctx, c.config.Timeout)
+		err = conn.Invoke(callCtx, method, payload, &emptypb.Empty{})
+		cancel()
+		if err == nil {
+			return nil
+		}
+
+		lastErr = err
+
+		if !c.isRetryableError(err) {
+			break
+		}
+	}
+
+	return fmt.Errorf("BM25 write failed after %d retries: %w", c.retryConfig.MaxRetries, lastErr)
+}
+
+func (c *BM25Client) IndexStats(ctx context.Context, index string) (*model.EngineIndexStats, error) {
+	return fetchIndexStats(ctx, c.pool.Load(), c.circuitBreaker, c.GetName(), bm25IndexStatsMethod, index, c.config.Timeout)
+}
+
+func (c *BM25Client) Ping(ctx context.Context) (time.Duration, error) {
+	return ping(ctx, c.pool.Load(), c.GetName(), bm25PingMethod, c.config.Timeout)
+}
+
+func (c *BM25Client) Address() string {
+	return c.config.Address()
+}
+
 func (c *BM25Client) HealthCheck(ctx context.Context) bool {
-	if c.conn == nil {
+	pool := c.pool.Load()
+	if pool == nil {
 		return false
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
-	defer cancel()
-
-	state := c.conn.GetState()
-	return state == connectivity.Ready || state == connectivity.Idle
+	return pool.healthy()
 }
 
 func (c *BM25Client) GetName() string {
@@ -252,6 +379,20 @@ func (c *BM25Client) getB() float64 {
 	return c.bm25Config.B
 }
 
+func (c *BM25Client) getTokenizer() Tokenizer {
+	if c == nil || c.bm25Config == nil || c.bm25Config.Tokenizer == nil {
+		return WhitespaceTokenizer{}
+	}
+	return c.bm25Config.Tokenizer
+}
+
+func (c *BM25Client) getCorpusStats() CorpusStats {
+	if c == nil || c.bm25Config == nil || c.bm25Config.CorpusStats == nil {
+		return DefaultCorpusStats
+	}
+	return c.bm25Config.CorpusStats
+}
+
 func (c *BM25Client) getMinLength() int {
 	if c == nil || c.bm25Config == nil {
 		return 2
@@ -272,6 +413,14 @@ func (c *BM25Client) getMaxLength() int {
 	return c.bm25Config.MaxLength
 }
 
+func (c *BM25Client) GetCircuitBreakerState() string {
+	return c.circuitBreaker.GetState().String()
+}
+
+func (c *BM25Client) GetCircuitBreakerFailures() int {
+	return c.circuitBreaker.GetFailureCount()
+}
+
 func (c *BM25Client) isRetryableError(err error) bool {
 	if err == nil {
 		return false
@@ -291,13 +440,7 @@ func (c *BM25Client) isRetryableError(err error) bool {
 }
 
 func (c *BM25Client) calculateBackoff(attempt int) time.Duration {
-	delay := float64(c.retryConfig.InitialDelay) * math.Pow(c.retryConfig.BackoffFactor, float64(attempt-1))
-	
-	if delay > float64(c.retryConfig.MaxDelay) {
-		delay = float64(c.retryConfig.MaxDelay)
-	}
-	
-	return time.Duration(delay)
+	return c.retryConfig.Backoff(attempt)
 }
 
 func (c *BM25Client) generateID(query string, index int) string {
@@ -2,38 +2,160 @@ package engine
 
 import (
 	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"sync"
 	"time"
 
 	"github.com/flexsearch/coordinator/internal/model"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	defaultKeepaliveTime    = 30 * time.Second
+	defaultKeepaliveTimeout = 10 * time.Second
+	maxMessageSize          = 100 * 1024 * 1024
+	// defaultMaxRetryElapsed keeps an engine's retries inside the
+	// coordinator's 800ms search timeout.
+	defaultMaxRetryElapsed = 700 * time.Millisecond
 )
 
 type EngineClient interface {
 	Connect(ctx context.Context) error
 	Disconnect() error
 	Search(ctx context.Context, req *model.SearchRequest) (*model.EngineResult, error)
+	AddDocument(ctx context.Context, doc *model.DocumentRequest) error
+	DeleteDocument(ctx context.Context, index, id string) error
 	HealthCheck(ctx context.Context) bool
 	GetName() string
+	GetCircuitBreakerState() string
+	GetCircuitBreakerFailures() int
 }
 
 type ClientConfig struct {
-	Host       string
-	Port       int
-	Timeout    time.Duration
-	MaxRetries int
-	PoolSize   int
+	Host             string
+	Port             int
+	Timeout          time.Duration
+	MaxRetries       int
+	PoolSize         int
+	KeepaliveTime    time.Duration
+	KeepaliveTimeout time.Duration
+	// ReconnectBackoff and MaxReconnectBackoff bound the wait between
+	// redials of a failed connection; zero means 1s and 30s.
+	ReconnectBackoff    time.Duration
+	MaxReconnectBackoff time.Duration
+	// MaxRetryElapsed bounds the total time one call spends retrying;
+	// zero means 700ms.
+	MaxRetryElapsed time.Duration
+	// Jitter randomizes retry delays so clients don't retry in lockstep;
+	// nil means enabled.
+	Jitter *bool
+}
+
+func (c *ClientConfig) Address() string {
+	return fmt.Sprintf("%s:%d", c.Host, c.Port)
+}
+
+func (c *ClientConfig) reconnectBackoff() (initial, maxBackoff time.Duration) {
+	initial, maxBackoff = c.ReconnectBackoff, c.MaxReconnectBackoff
+	if initial <= 0 {
+		initial = defaultReconnectBackoff
+	}
+	if maxBackoff <= 0 {
+		maxBackoff = defaultMaxReconnectBackoff
+	}
+	if maxBackoff < initial {
+		maxBackoff = initial
+	}
+	return initial, maxBackoff
+}
+
+func (c *ClientConfig) maxRetryElapsed() time.Duration {
+	if c.MaxRetryElapsed <= 0 {
+		return defaultMaxRetryElapsed
+	}
+	return c.MaxRetryElapsed
+}
+
+func (c *ClientConfig) jitter() bool {
+	return c.Jitter == nil || *c.Jitter
+}
+
+func (c *ClientConfig) keepaliveParams() keepalive.ClientParameters {
+	params := keepalive.ClientParameters{
+		Time:                c.KeepaliveTime,
+		Timeout:             c.KeepaliveTimeout,
+		PermitWithoutStream: true,
+	}
+	if params.Time <= 0 {
+		params.Time = defaultKeepaliveTime
+	}
+	if params.Timeout <= 0 {
+		params.Timeout = defaultKeepaliveTimeout
+	}
+	return params
+}
+
+func (c *ClientConfig) dialOptions() []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithKeepaliveParams(c.keepaliveParams()),
+		grpc.WithDefaultCallOptions(
+			grpc.MaxCallRecvMsgSize(maxMessageSize),
+			grpc.MaxCallSendMsgSize(maxMessageSize),
+		),
+	}
 }
 
 type RetryConfig struct {
-	MaxRetries int
-	InitialDelay time.Duration
-	MaxDelay     time.Duration
+	MaxRetries    int
+	InitialDelay  time.Duration
+	MaxDelay      time.Duration
 	BackoffFactor float64
+	MaxElapsed    time.Duration
+	Jitter        bool
+	Rand          func() float64
+}
+
+// Backoff returns the delay before the given retry attempt. With Jitter
+// enabled the delay is drawn uniformly from [0, computed] so that
+// coordinators retrying the same dependency don't do so in lockstep.
+func (r *RetryConfig) Backoff(attempt int) time.Duration {
+	delay := float64(r.InitialDelay) * math.Pow(r.BackoffFactor, float64(attempt-1))
+
+	if delay > float64(r.MaxDelay) {
+		delay = float64(r.MaxDelay)
+	}
+
+	if r.Jitter {
+		random := r.Rand
+		if random == nil {
+			random = rand.Float64
+		}
+		delay = delay * random()
+	}
+
+	return time.Duration(delay)
+}
+
+// BudgetExceeded reports whether waiting delay before the next attempt would
+// push the total time spent since start past MaxElapsed.
+func (r *RetryConfig) BudgetExceeded(start time.Time, delay time.Duration) bool {
+	if r.MaxElapsed <= 0 {
+		return false
+	}
+	return time.Since(start)+delay > r.MaxElapsed
 }
 
 type CircuitBreakerConfig struct {
-	FailureThreshold int
-	SuccessThreshold int
-	Timeout          time.Duration
+	FailureThreshold    int
+	SuccessThreshold    int
+	Timeout             time.Duration
+	HalfOpenMaxRequests int
 }
 
 type CircuitBreakerState int
@@ -44,22 +166,40 @@ const (
 	StateHalfOpen
 )
 
+func (s CircuitBreakerState) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
 type CircuitBreaker struct {
-	state         CircuitBreakerState
-	failureCount  int
-	successCount  int
-	lastFailTime  time.Time
-	config        *CircuitBreakerConfig
+	mu               sync.Mutex
+	state            CircuitBreakerState
+	failureCount     int
+	successCount     int
+	halfOpenInFlight int
+	lastFailTime     time.Time
+	config           *CircuitBreakerConfig
 }
 
 func NewCircuitBreaker(config *CircuitBreakerConfig) *CircuitBreaker {
 	return &CircuitBreaker{
-		state: StateClosed,
+		state:  StateClosed,
 		config: config,
 	}
 }
 
 func (cb *CircuitBreaker) AllowRequest() bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
 	switch cb.state {
 	case StateClosed:
 		return true
@@ -67,42 +207,126 @@ func (cb *CircuitBreaker) AllowRequest() bool {
 		if time.Since(cb.lastFailTime) > cb.config.Timeout {
 			cb.state = StateHalfOpen
 			cb.successCount = 0
-			return true
+			cb.halfOpenInFlight = 0
+			return cb.allowProbe()
 		}
 		return false
 	case StateHalfOpen:
-		return true
+		return cb.allowProbe()
 	default:
 		return false
 	}
 }
 
+// allowProbe admits a half-open probe while fewer than HalfOpenMaxRequests
+// are in flight. A zero limit admits every request.
+func (cb *CircuitBreaker) allowProbe() bool {
+	if cb.config.HalfOpenMaxRequests > 0 && cb.halfOpenInFlight >= cb.config.HalfOpenMaxRequests {
+		return false
+	}
+	cb.halfOpenInFlight++
+	return true
+}
+
+func (cb *CircuitBreaker) releaseProbe() {
+	if cb.halfOpenInFlight > 0 {
+		cb.halfOpenInFlight--
+	}
+}
+
 func (cb *CircuitBreaker) RecordSuccess() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
 	switch cb.state {
 	case StateClosed:
 		cb.failureCount = 0
 	case StateHalfOpen:
+		cb.releaseProbe()
 		cb.successCount++
 		if cb.successCount >= cb.config.SuccessThreshold {
 			cb.state = StateClosed
 			cb.failureCount = 0
+			cb.halfOpenInFlight = 0
 		}
 	}
 }
 
 func (cb *CircuitBreaker) RecordFailure() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
 	cb.failureCount++
 	cb.lastFailTime = time.Now()
 
+	if cb.state == StateHalfOpen {
+		cb.state = StateOpen
+		cb.halfOpenInFlight = 0
+		return
+	}
+
 	if cb.failureCount >= cb.config.FailureThreshold {
 		cb.state = StateOpen
 	}
 }
 
+// GetState reports the breaker's state. An open breaker whose timeout has
+// passed reports half-open, since its next request goes through as a probe.
 func (cb *CircuitBreaker) GetState() CircuitBreakerState {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	if cb.state == StateOpen && time.Since(cb.lastFailTime) > cb.config.Timeout {
+		return StateHalfOpen
+	}
 	return cb.state
 }
 
 func (cb *CircuitBreaker) GetFailureCount() int {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
 	return cb.failureCount
 }
+
+func buildDocumentPayload(doc *model.DocumentRequest) (*structpb.Struct, error) {
+	if doc == nil {
+		return nil, fmt.Errorf("document cannot be nil")
+	}
+	if doc.ID == "" || doc.Index == "" {
+		return nil, fmt.Errorf("document id and index are required")
+	}
+
+	payload := map[string]interface{}{
+		"id":      doc.ID,
+		"index":   doc.Index,
+		"content": doc.Content,
+		"title":   doc.Title,
+	}
+
+	if len(doc.Fields) > 0 {
+		payload["fields"] = doc.Fields
+	}
+
+	if len(doc.Vector) > 0 {
+		vector := make([]interface{}, len(doc.Vector))
+		for i, v := range doc.Vector {
+			vector[i] = v
+		}
+		payload["vector"] = vector
+	}
+
+	s, err := structpb.NewStruct(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode document %s: %w", doc.ID, err)
+	}
+	return s, nil
+}
+
+func buildDeletePayload(index, id string) (*structpb.Struct, error) {
+	if index == "" || id == "" {
+		return nil, fmt.Errorf("document id and index are required")
+	}
+	return structpb.NewStruct(map[string]interface{}{
+		"id":    id,
+		"index": index,
+	})
+}
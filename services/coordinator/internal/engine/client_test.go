@@ -2,11 +2,22 @@ package engine
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/flexsearch/coordinator/internal/model"
 	"github.com/flexsearch/coordinator/internal/util"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
 )
 
 func TestCircuitBreaker(t *testing.T) {
@@ -56,6 +67,21 @@ func TestCircuitBreaker(t *testing.T) {
 	}
 }
 
+func TestCircuitBreakerReportsHalfOpenAfterTimeout(t *testing.T) {
+	cb := NewCircuitBreaker(&CircuitBreakerConfig{FailureThreshold: 1, SuccessThreshold: 1, Timeout: 10 * time.Millisecond})
+	cb.RecordFailure()
+
+	if cb.GetState() != StateOpen {
+		t.Fatalf("Expected state to be Open after a failure, got %v", cb.GetState())
+	}
+	time.Sleep(20 * time.Millisecond)
+	// Callers that skip open engines must still see the breaker is ready
+	// for a probe, or it would never be tried again.
+	if cb.GetState() != StateHalfOpen {
+		t.Errorf("Expected an expired open breaker to report HalfOpen, got %v", cb.GetState())
+	}
+}
+
 func TestFlexSearchClient(t *testing.T) {
 	logger, err := util.NewLogger("info", "json", "stdout")
 	if err != nil {
@@ -147,6 +173,29 @@ func TestBM25Client(t *testing.T) {
 	}
 }
 
+func TestBM25ClientScoresPhrasesAboveLooseTerms(t *testing.T) {
+	logger, err := util.NewLogger("error", "json", "stdout")
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	client := NewBM25Client(&ClientConfig{Timeout: time.Second}, &BM25EngineConfig{K1: 1.2, B: 0.75}, logger)
+
+	topScore := func(query string) float64 {
+		t.Helper()
+		phrases, _ := model.ParsePhrases(query)
+		result, err := client.Search(context.Background(), &model.SearchRequest{Query: query, Index: "idx", Limit: 1, Phrases: phrases})
+		if err != nil {
+			t.Fatalf("Search(%q) failed: %v", query, err)
+		}
+		return result.Results[0].Score
+	}
+
+	loose, exact, near := topScore("quick fox"), topScore(`"quick fox"`), topScore(`"quick fox"~3`)
+	if exact <= near || near <= loose {
+		t.Errorf("Expected exact phrase > proximity > loose terms, got %v, %v, %v", exact, near, loose)
+	}
+}
+
 func TestVectorClient(t *testing.T) {
 	logger, err := util.NewLogger("info", "json", "stdout")
 	if err != nil {
@@ -196,3 +245,598 @@ func TestVectorClient(t *testing.T) {
 		t.Errorf("Expected engine to be vector, got %s", result.Engine)
 	}
 }
+
+func TestVectorClientSearchHonorsLimit(t *testing.T) {
+	logger, err := util.NewLogger("error", "json", "stdout")
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	client, err := NewVectorClient(&ClientConfig{Host: "localhost", Port: 50055, Timeout: time.Second},
+		&VectorEngineConfig{Dimension: 4, Threshold: 0, TopK: 10}, logger)
+	if err != nil {
+		t.Fatalf("Failed to create Vector client: %v", err)
+	}
+
+	for _, tc := range []struct {
+		limit int32
+		want  int
+	}{
+		{3, 3},
+		{25, 25},
+		{0, 10},
+	} {
+		result, err := client.Search(context.Background(), &model.SearchRequest{Query: "semantic", Index: "idx", Limit: tc.limit})
+		if err != nil {
+			t.Fatalf("Search with limit %d failed: %v", tc.limit, err)
+		}
+		if len(result.Results) != tc.want {
+			t.Errorf("Expected %d results for limit %d, got %d", tc.want, tc.limit, len(result.Results))
+		}
+	}
+}
+
+type fakeBackend struct {
+	server   *grpc.Server
+	listener net.Listener
+	mu       sync.Mutex
+	calls    map[string][]*structpb.Struct
+	peers    map[string]int
+	failWith error
+	delay    time.Duration
+	// notReady calls per method fail with Unavailable before the backend
+	// starts serving.
+	notReady int
+}
+
+func newFakeBackend(t *testing.T) *fakeBackend {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+
+	b := &fakeBackend{
+		calls: make(map[string][]*structpb.Struct),
+		peers: make(map[string]int),
+	}
+	b.serve(t, lis)
+	return b
+}
+
+func (b *fakeBackend) serve(t *testing.T, lis net.Listener) {
+	server := grpc.NewServer(
+		grpc.UnknownServiceHandler(b.handle),
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             time.Second,
+			PermitWithoutStream: true,
+		}),
+	)
+	b.server, b.listener = server, lis
+
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+}
+
+// drop stops the server, closing every connection and the listener, so
+// clients see the backend go away until resume.
+func (b *fakeBackend) drop() {
+	b.server.Stop()
+}
+
+// resume serves again on the port the backend had before drop.
+func (b *fakeBackend) resume(t *testing.T) {
+	t.Helper()
+
+	addr := b.listener.Addr().String()
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		t.Fatalf("Failed to listen again on %s: %v", addr, err)
+	}
+	b.serve(t, lis)
+}
+
+func (b *fakeBackend) handle(srv interface{}, stream grpc.ServerStream) error {
+	method, _ := grpc.MethodFromServerStream(stream)
+
+	in := &structpb.Struct{}
+	if err := stream.RecvMsg(in); err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	b.calls[method] = append(b.calls[method], in)
+	if p, ok := peer.FromContext(stream.Context()); ok {
+		b.peers[p.Addr.String()]++
+	}
+	failWith, delay := b.failWith, b.delay
+	if len(b.calls[method]) <= b.notReady {
+		failWith = status.Error(codes.Unavailable, "not serving yet")
+	}
+	b.mu.Unlock()
+
+	if delay > 0 {
+		time.Sleep(delay)
+	}
+	if failWith != nil {
+		return failWith
+	}
+
+	return stream.SendMsg(&emptypb.Empty{})
+}
+
+func (b *fakeBackend) peerCounts() map[string]int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	counts := make(map[string]int, len(b.peers))
+	for addr, n := range b.peers {
+		counts[addr] = n
+	}
+	return counts
+}
+
+func (b *fakeBackend) port() int {
+	return b.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (b *fakeBackend) callsFor(method string) []*structpb.Struct {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.calls[method]
+}
+
+func TestEngineClientWrites(t *testing.T) {
+	logger, err := util.NewLogger("info", "json", "stdout")
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	defer logger.Sync()
+
+	backend := newFakeBackend(t)
+	config := &ClientConfig{
+		Host:       "127.0.0.1",
+		Port:       backend.port(),
+		Timeout:    5 * time.Second,
+		MaxRetries: 1,
+		PoolSize:   1,
+	}
+
+	vectorClient, err := NewVectorClient(config, &VectorEngineConfig{Dimension: 3, Threshold: 0.5, TopK: 10}, logger)
+	if err != nil {
+		t.Fatalf("Failed to create Vector client: %v", err)
+	}
+
+	clients := []struct {
+		client       EngineClient
+		addMethod    string
+		deleteMethod string
+	}{
+		{NewFlexSearchClient(config, logger), flexSearchAddDocumentMethod, flexSearchDeleteDocumentMethod},
+		{NewBM25Client(config, nil, logger), bm25AddDocumentMethod, bm25DeleteDocumentMethod},
+		{vectorClient, vectorAddDocumentMethod, vectorDeleteDocumentMethod},
+	}
+
+	ctx := context.Background()
+	for _, tc := range clients {
+		t.Run(tc.client.GetName(), func(t *testing.T) {
+			if err := tc.client.Connect(ctx); err != nil {
+				t.Fatalf("Connect failed: %v", err)
+			}
+			defer tc.client.Disconnect()
+
+			doc := &model.DocumentRequest{
+				ID:      "doc-1",
+				Index:   "test_index",
+				Content: "hello world",
+				Vector:  []float64{0.1, 0.2, 0.3},
+			}
+			if err := tc.client.AddDocument(ctx, doc); err != nil {
+				t.Fatalf("AddDocument failed: %v", err)
+			}
+			if err := tc.client.DeleteDocument(ctx, "test_index", "doc-1"); err != nil {
+				t.Fatalf("DeleteDocument failed: %v", err)
+			}
+
+			added := backend.callsFor(tc.addMethod)
+			if len(added) != 1 {
+				t.Fatalf("Expected 1 add call, got %d", len(added))
+			}
+			if added[0].Fields["id"].GetStringValue() != "doc-1" {
+				t.Errorf("Expected id doc-1, got %s", added[0].Fields["id"].GetStringValue())
+			}
+			if len(added[0].Fields["vector"].GetListValue().GetValues()) != 3 {
+				t.Error("Expected vector to be sent with the document")
+			}
+
+			deleted := backend.callsFor(tc.deleteMethod)
+			if len(deleted) != 1 {
+				t.Fatalf("Expected 1 delete call, got %d", len(deleted))
+			}
+		})
+	}
+}
+
+func TestEngineClientWriteNotConnected(t *testing.T) {
+	logger, err := util.NewLogger("info", "json", "stdout")
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	defer logger.Sync()
+
+	client := NewFlexSearchClient(&ClientConfig{Host: "localhost", Port: 50053, Timeout: time.Second}, logger)
+
+	if err := client.AddDocument(context.Background(), &model.DocumentRequest{ID: "1", Index: "idx"}); err == nil {
+		t.Error("Expected error when writing without a connection")
+	}
+}
+
+func TestVectorClientRejectsDimensionMismatch(t *testing.T) {
+	logger, err := util.NewLogger("info", "json", "stdout")
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	defer logger.Sync()
+
+	client, err := NewVectorClient(&ClientConfig{Host: "localhost", Port: 50055, Timeout: time.Second}, &VectorEngineConfig{Dimension: 4, Threshold: 0.5, TopK: 10}, logger)
+	if err != nil {
+		t.Fatalf("Failed to create Vector client: %v", err)
+	}
+
+	err = client.AddDocument(context.Background(), &model.DocumentRequest{ID: "1", Index: "idx", Vector: []float64{1, 2}})
+	if err == nil {
+		t.Error("Expected dimension mismatch error")
+	}
+}
+
+func TestClientConfigKeepaliveParams(t *testing.T) {
+	config := &ClientConfig{Host: "localhost", Port: 50053}
+
+	params := config.keepaliveParams()
+	if params.Time != defaultKeepaliveTime {
+		t.Errorf("Expected default keepalive time %v, got %v", defaultKeepaliveTime, params.Time)
+	}
+	if params.Timeout != defaultKeepaliveTimeout {
+		t.Errorf("Expected default keepalive timeout %v, got %v", defaultKeepaliveTimeout, params.Timeout)
+	}
+	if !params.PermitWithoutStream {
+		t.Error("Expected keepalive pings to be permitted without active streams")
+	}
+
+	config.KeepaliveTime = 15 * time.Second
+	config.KeepaliveTimeout = 3 * time.Second
+
+	params = config.keepaliveParams()
+	if params.Time != 15*time.Second {
+		t.Errorf("Expected keepalive time 15s, got %v", params.Time)
+	}
+	if params.Timeout != 3*time.Second {
+		t.Errorf("Expected keepalive timeout 3s, got %v", params.Timeout)
+	}
+}
+
+func TestEngineClientHealthyAfterIdle(t *testing.T) {
+	logger, err := util.NewLogger("info", "json", "stdout")
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	defer logger.Sync()
+
+	backend := newFakeBackend(t)
+	client := NewFlexSearchClient(&ClientConfig{
+		Host:             "127.0.0.1",
+		Port:             backend.port(),
+		Timeout:          time.Second,
+		KeepaliveTime:    10 * time.Second,
+		KeepaliveTimeout: time.Second,
+	}, logger)
+
+	ctx := context.Background()
+	if err := client.Connect(ctx); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer client.Disconnect()
+
+	if !client.HealthCheck(ctx) {
+		t.Error("Expected lazily connected client to report healthy")
+	}
+
+	if err := client.AddDocument(ctx, &model.DocumentRequest{ID: "1", Index: "idx"}); err != nil {
+		t.Fatalf("AddDocument failed: %v", err)
+	}
+
+	time.Sleep(200 * time.Millisecond)
+
+	if !client.HealthCheck(ctx) {
+		t.Error("Expected client to report healthy after an idle period")
+	}
+
+	if err := client.AddDocument(ctx, &model.DocumentRequest{ID: "2", Index: "idx"}); err != nil {
+		t.Errorf("Expected write after idle period to succeed, got %v", err)
+	}
+}
+
+func TestEngineClientCircuitBreakerState(t *testing.T) {
+	logger, err := util.NewLogger("info", "json", "stdout")
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	defer logger.Sync()
+
+	client := NewBM25Client(&ClientConfig{Host: "localhost", Port: 50054, Timeout: time.Second}, nil, logger)
+
+	if client.GetCircuitBreakerState() != "closed" {
+		t.Errorf("Expected closed, got %s", client.GetCircuitBreakerState())
+	}
+
+	for i := 0; i < 5; i++ {
+		client.circuitBreaker.RecordFailure()
+	}
+
+	if client.GetCircuitBreakerState() != "open" {
+		t.Errorf("Expected open, got %s", client.GetCircuitBreakerState())
+	}
+	if client.GetCircuitBreakerFailures() != 5 {
+		t.Errorf("Expected 5 failures, got %d", client.GetCircuitBreakerFailures())
+	}
+
+	if _, err := client.Search(context.Background(), &model.SearchRequest{Query: "test", Limit: 1}); err == nil {
+		t.Error("Expected search to be rejected while the breaker is open")
+	}
+}
+
+func TestCircuitBreakerHalfOpenLimit(t *testing.T) {
+	cb := NewCircuitBreaker(&CircuitBreakerConfig{
+		FailureThreshold:    1,
+		SuccessThreshold:    2,
+		Timeout:             10 * time.Millisecond,
+		HalfOpenMaxRequests: 2,
+	})
+
+	cb.RecordFailure()
+	time.Sleep(20 * time.Millisecond)
+
+	if !cb.AllowRequest() || !cb.AllowRequest() {
+		t.Fatal("Expected two half-open probes to be allowed")
+	}
+	if cb.GetState() != StateHalfOpen {
+		t.Errorf("Expected HalfOpen state, got %v", cb.GetState())
+	}
+	if cb.AllowRequest() {
+		t.Error("Expected third concurrent probe to be rejected")
+	}
+
+	cb.RecordSuccess()
+	if !cb.AllowRequest() {
+		t.Error("Expected a new probe once one resolved")
+	}
+
+	cb.RecordSuccess()
+	if cb.GetState() != StateClosed {
+		t.Errorf("Expected Closed after successful probes, got %v", cb.GetState())
+	}
+	if !cb.AllowRequest() {
+		t.Error("Expected requests to flow once closed")
+	}
+}
+
+func TestCircuitBreakerHalfOpenProbeFailure(t *testing.T) {
+	cb := NewCircuitBreaker(&CircuitBreakerConfig{
+		FailureThreshold:    3,
+		SuccessThreshold:    1,
+		Timeout:             10 * time.Millisecond,
+		HalfOpenMaxRequests: 1,
+	})
+
+	for i := 0; i < 3; i++ {
+		cb.RecordFailure()
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	if !cb.AllowRequest() {
+		t.Fatal("Expected half-open probe to be allowed")
+	}
+	if cb.AllowRequest() {
+		t.Error("Expected second probe to be rejected while the first is in flight")
+	}
+
+	cb.RecordFailure()
+	if cb.GetState() != StateOpen {
+		t.Errorf("Expected Open after probe failure, got %v", cb.GetState())
+	}
+	if cb.AllowRequest() {
+		t.Error("Expected requests to be rejected after reopening")
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if !cb.AllowRequest() {
+		t.Error("Expected a fresh probe after the timeout elapses again")
+	}
+}
+
+func TestRetryConfigBackoffJitter(t *testing.T) {
+	config := &RetryConfig{
+		InitialDelay:  100 * time.Millisecond,
+		MaxDelay:      time.Second,
+		BackoffFactor: 2.0,
+	}
+
+	expected := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second}
+	for i, want := range expected {
+		if got := config.Backoff(i + 1); got != want {
+			t.Errorf("Expected backoff %v for attempt %d without jitter, got %v", want, i+1, got)
+		}
+	}
+
+	config.Jitter = true
+	for _, r := range []float64{0, 0.25, 0.5, 0.999} {
+		r := r
+		config.Rand = func() float64 { return r }
+		for i, computed := range expected {
+			got := config.Backoff(i + 1)
+			if got < 0 || got > computed {
+				t.Errorf("Expected jittered backoff within [0, %v], got %v", computed, got)
+			}
+			if want := time.Duration(float64(computed) * r); got != want {
+				t.Errorf("Expected jittered backoff %v, got %v", want, got)
+			}
+		}
+	}
+
+	config.Rand = nil
+	for i := 0; i < 100; i++ {
+		if got := config.Backoff(3); got < 0 || got > 400*time.Millisecond {
+			t.Errorf("Expected default jittered backoff within [0, 400ms], got %v", got)
+		}
+	}
+}
+
+func TestEngineClientRetryBudget(t *testing.T) {
+	logger, err := util.NewLogger("info", "json", "stdout")
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	defer logger.Sync()
+
+	backend := newFakeBackend(t)
+	backend.failWith = status.Error(codes.Unavailable, "engine overloaded")
+	backend.delay = 30 * time.Millisecond
+
+	client := NewFlexSearchClient(&ClientConfig{
+		Host:       "127.0.0.1",
+		Port:       backend.port(),
+		Timeout:    time.Second,
+		MaxRetries: 20,
+	}, logger)
+	client.retryConfig.InitialDelay = 20 * time.Millisecond
+	client.retryConfig.MaxDelay = 20 * time.Millisecond
+	client.retryConfig.Jitter = false
+	client.retryConfig.MaxElapsed = 200 * time.Millisecond
+
+	ctx := context.Background()
+	if err := client.Connect(ctx); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer client.Disconnect()
+
+	start := time.Now()
+	err = client.AddDocument(ctx, &model.DocumentRequest{ID: "1", Index: "idx"})
+	elapsed := time.Since(start)
+
+	if err == nil {
+		t.Fatal("Expected write to fail")
+	}
+	if status.Code(errors.Unwrap(err)) != codes.Unavailable {
+		t.Errorf("Expected last error to be Unavailable, got %v", err)
+	}
+
+	attempts := len(backend.callsFor(flexSearchAddDocumentMethod))
+	if attempts >= 21 {
+		t.Errorf("Expected retries to stop on the elapsed budget, got %d attempts", attempts)
+	}
+	if attempts < 2 {
+		t.Errorf("Expected at least one retry within the budget, got %d attempts", attempts)
+	}
+	if elapsed > 400*time.Millisecond {
+		t.Errorf("Expected retries to stop near the 200ms budget, took %v", elapsed)
+	}
+}
+
+func TestRetryConfigBudgetExceeded(t *testing.T) {
+	config := &RetryConfig{}
+	if config.BudgetExceeded(time.Now().Add(-time.Hour), time.Second) {
+		t.Error("Expected no budget when MaxElapsed is zero")
+	}
+
+	config.MaxElapsed = 100 * time.Millisecond
+	if config.BudgetExceeded(time.Now(), 50*time.Millisecond) {
+		t.Error("Expected delay within budget to be allowed")
+	}
+	if !config.BudgetExceeded(time.Now().Add(-80*time.Millisecond), 50*time.Millisecond) {
+		t.Error("Expected delay past budget to be rejected")
+	}
+}
+
+func TestClientConfigMaxRetryElapsed(t *testing.T) {
+	logger, err := util.NewLogger("error", "json", "stdout")
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	client := NewBM25Client(&ClientConfig{Host: "127.0.0.1", Port: 1, Timeout: 10 * time.Second}, nil, logger)
+	if client.retryConfig.MaxElapsed != defaultMaxRetryElapsed {
+		t.Errorf("Expected the %v default budget, not the call timeout, got %v", defaultMaxRetryElapsed, client.retryConfig.MaxElapsed)
+	}
+
+	vector, err := NewVectorClient(&ClientConfig{Host: "127.0.0.1", Port: 1, MaxRetryElapsed: 250 * time.Millisecond}, &VectorEngineConfig{Dimension: 3, Threshold: 0.5, TopK: 10}, logger)
+	if err != nil {
+		t.Fatalf("NewVectorClient failed: %v", err)
+	}
+	if vector.retryConfig.MaxElapsed != 250*time.Millisecond {
+		t.Errorf("Expected the configured 250ms budget, got %v", vector.retryConfig.MaxElapsed)
+	}
+}
+
+func TestClientConfigJitter(t *testing.T) {
+	logger, err := util.NewLogger("error", "json", "stdout")
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	client := NewFlexSearchClient(&ClientConfig{Host: "127.0.0.1", Port: 1}, logger)
+	if !client.retryConfig.Jitter {
+		t.Error("Expected jitter to be enabled by default")
+	}
+
+	disabled := false
+	bm25 := NewBM25Client(&ClientConfig{Host: "127.0.0.1", Port: 1, Jitter: &disabled}, nil, logger)
+	if bm25.retryConfig.Jitter {
+		t.Error("Expected jitter: false to disable jitter")
+	}
+}
+
+func TestEngineClientPing(t *testing.T) {
+	logger, err := util.NewLogger("error", "json", "stdout")
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	backend := newFakeBackend(t)
+	backend.delay = 10 * time.Millisecond
+	client := NewBM25Client(&ClientConfig{
+		Host:    "127.0.0.1",
+		Port:    backend.port(),
+		Timeout: time.Second,
+	}, nil, logger)
+
+	if _, err := client.Ping(context.Background()); err == nil {
+		t.Error("Expected ping before Connect to fail")
+	}
+
+	ctx := context.Background()
+	if err := client.Connect(ctx); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer client.Disconnect()
+
+	latency, err := client.Ping(ctx)
+	if err != nil {
+		t.Fatalf("Expected ping to succeed, got %v", err)
+	}
+	if latency < backend.delay {
+		t.Errorf("Expected latency of at least %v, got %v", backend.delay, latency)
+	}
+	if len(backend.callsFor(bm25PingMethod)) != 1 {
+		t.Errorf("Expected one call to %s", bm25PingMethod)
+	}
+	if client.Address() != fmt.Sprintf("127.0.0.1:%d", backend.port()) {
+		t.Errorf("Unexpected address %s", client.Address())
+	}
+
+	backend.mu.Lock()
+	backend.failWith = status.Error(codes.Unavailable, "down")
+	backend.mu.Unlock()
+	if _, err := client.Ping(ctx); status.Code(err) != codes.Unavailable {
+		t.Errorf("Expected Unavailable from a failing backend, got %v", err)
+	}
+}
@@ -0,0 +1,41 @@
+package engine
+
+// CorpusStats supplies the collection statistics BM25 weighs terms with.
+// Implementations backed by a live index should be safe for concurrent use.
+type CorpusStats interface {
+	// TotalDocs is the number of documents in the index.
+	TotalDocs(index string) int64
+	// AvgDocLength is the mean document length, in terms.
+	AvgDocLength(index string) float64
+	// DocFreq is the number of documents in the index containing term.
+	DocFreq(index, term string) int64
+}
+
+// StaticCorpusStats reports the same statistics for every index. Terms
+// missing from Frequencies have DefaultFreq documents.
+type StaticCorpusStats struct {
+	Docs        int64
+	AvgLength   float64
+	Frequencies map[string]int64
+	DefaultFreq int64
+}
+
+// DefaultCorpusStats stands in when no CorpusStats is configured. Its
+// numbers describe no real index, so scores computed with it only rank
+// results against each other.
+var DefaultCorpusStats = StaticCorpusStats{Docs: 1000, AvgLength: 100, DefaultFreq: 5}
+
+func (s StaticCorpusStats) TotalDocs(string) int64 {
+	return s.Docs
+}
+
+func (s StaticCorpusStats) AvgDocLength(string) float64 {
+	return s.AvgLength
+}
+
+func (s StaticCorpusStats) DocFreq(_, term string) int64 {
+	if n, ok := s.Frequencies[term]; ok {
+		return n
+	}
+	return s.DefaultFreq
+}
@@ -0,0 +1,51 @@
+package engine
+
+import (
+	"math"
+	"testing"
+)
+
+func bm25ScoreWith(stats CorpusStats, query string, docIndex int) float64 {
+	c := &BM25Client{bm25Config: &BM25EngineConfig{K1: 1.2, B: 0.75, CorpusStats: stats}}
+	return c.calculateBM25Score("idx", query, docIndex)
+}
+
+func TestBM25DefaultCorpusStatsKeepFormerScores(t *testing.T) {
+	// The constants calculateBM25Score used before stats were injectable.
+	idf := math.Log((1000.0-5.0+0.5)/(5.0+0.5) + 1.0)
+	tf := 1.0 + float64(len("search")%5)
+	want := (tf * 2.2) / (tf + 1.2*(0.25+0.75*0.5)) * idf
+
+	if got := bm25ScoreWith(nil, "search", 0); math.Abs(got-want) > 1e-9 {
+		t.Errorf("Expected the default stats to score %v, got %v", want, got)
+	}
+}
+
+func TestBM25CorpusStatsChangeScores(t *testing.T) {
+	base := StaticCorpusStats{Docs: 10000, AvgLength: 100, DefaultFreq: 10}
+
+	// A term in fewer documents carries more information, so it scores
+	// higher than a common one.
+	rare := base
+	rare.Frequencies = map[string]int64{"search": 2}
+	common := base
+	common.Frequencies = map[string]int64{"search": 5000}
+	if r, c := bm25ScoreWith(rare, "search", 0), bm25ScoreWith(common, "search", 0); r <= c {
+		t.Errorf("Expected a rare term to outscore a common one, got %v <= %v", r, c)
+	}
+
+	// The same document is short relative to a corpus of long documents,
+	// and length normalisation rewards it.
+	long := base
+	long.AvgLength = 400
+	if l, b := bm25ScoreWith(long, "search", 5), bm25ScoreWith(base, "search", 5); l <= b {
+		t.Errorf("Expected a longer average document to raise the score, got %v <= %v", l, b)
+	}
+
+	// A term found in every document still scores, just barely.
+	everywhere := base
+	everywhere.Frequencies = map[string]int64{"search": 20000}
+	if s := bm25ScoreWith(everywhere, "search", 0); s <= 0 || s >= bm25ScoreWith(common, "search", 0) {
+		t.Errorf("Expected a ubiquitous term to score small but positive, got %v", s)
+	}
+}
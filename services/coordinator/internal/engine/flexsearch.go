@@ -5,21 +5,27 @@ import (
 	"crypto/md5"
 	"encoding/hex"
 	"fmt"
-	"math"
+	"sync/atomic"
 	"time"
 
 	"github.com/flexsearch/coordinator/internal/model"
 	"github.com/flexsearch/coordinator/internal/util"
-	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/connectivity"
-	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	flexSearchAddDocumentMethod    = "/flexsearch.FlexSearchService/AddDocument"
+	flexSearchDeleteDocumentMethod = "/flexsearch.FlexSearchService/DeleteDocument"
+	flexSearchIndexStatsMethod     = "/flexsearch.FlexSearchService/IndexStats"
+	flexSearchPingMethod           = "/flexsearch.FlexSearchService/Ping"
 )
 
 type FlexSearchClient struct {
 	config       *ClientConfig
-	conn         *grpc.ClientConn
+	pool         atomic.Pointer[connPool]
 	logger       *util.Logger
 	circuitBreaker *CircuitBreaker
 	retryConfig  *RetryConfig
@@ -27,9 +33,10 @@ type FlexSearchClient struct {
 
 func NewFlexSearchClient(config *ClientConfig, logger *util.Logger) *FlexSearchClient {
 	cbConfig := &CircuitBreakerConfig{
-		FailureThreshold: 5,
-		SuccessThreshold: 2,
-		Timeout:          30 * time.Second,
+		FailureThreshold:    5,
+		SuccessThreshold:    2,
+		Timeout:             30 * time.Second,
+		HalfOpenMaxRequests: 1,
 	}
 
 	retryConfig := &RetryConfig{
@@ -37,6 +44,8 @@ func NewFlexSearchClient(config *ClientConfig, logger *util.Logger) *FlexSearchC
 		InitialDelay:  100 * time.Millisecond,
 		MaxDelay:      5 * time.Second,
 		BackoffFactor: 2.0,
+		MaxElapsed:    config.maxRetryElapsed(),
+		Jitter:        config.jitter(),
 	}
 
 	return &FlexSearchClient{
@@ -48,28 +57,22 @@ func NewFlexSearchClient(config *ClientConfig, logger *util.Logger) *FlexSearchC
 }
 
 func (c *FlexSearchClient) Connect(ctx context.Context) error {
-	address := fmt.Sprintf("%s:%d", c.config.Host, c.config.Port)
-	
-	conn, err := grpc.Dial(address, 
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-		grpc.WithDefaultCallOptions(
-			grpc.MaxCallRecvMsgSize(100*1024*1024),
-			grpc.MaxCallSendMsgSize(100*1024*1024),
-		),
-	)
+	address := c.config.Address()
+
+	pool, err := newConnPool(c.config)
 	if err != nil {
 		return fmt.Errorf("failed to connect to FlexSearch: %w", err)
 	}
 
-	c.conn = conn
-	c.logger.Infof("FlexSearch client connected to %s", address)
+	pool.monitor(c.GetName(), c.logger)
+	c.pool.Store(pool)
+	c.logger.Infof("FlexSearch client connected to %s with %d connections", address, pool.size())
 	return nil
 }
 
 func (c *FlexSearchClient) Disconnect() error {
-	if c.conn != nil {
-		err := c.conn.Close()
-		c.conn = nil
+	if pool := c.pool.Swap(nil); pool != nil {
+		err := pool.close()
 		c.logger.Info("FlexSearch client disconnected")
 		return err
 	}
@@ -95,10 +98,14 @@ func (c *FlexSearchClient) Search(ctx context.Context, req *model.SearchRequest)
 
 func (c *FlexSearchClient) searchWithRetry(ctx context.Context, req *model.SearchRequest) (*model.EngineResult, error) {
 	var lastErr error
+	start := time.Now()
 	
 	for attempt := 0; attempt <= c.retryConfig.MaxRetries; attempt++ {
 		if attempt > 0 {
 			delay := c.calculateBackoff(attempt)
+			if c.retryConfig.BudgetExceeded(start, delay) {
+				return nil, fmt.Errorf("FlexSearch search retry budget of %v exhausted after %d attempts: %w", c.retryConfig.MaxElapsed, attempt, lastErr)
+			}
 			c.logger.Debugf("FlexSearch retry attempt %d after %v", attempt, delay)
 			
 			select {
@@ -134,6 +141,10 @@ func (c *FlexSearchClient) doSearch(ctx context.Context, req *model.SearchReques
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
+	if len(req.Phrases) > 0 {
+		c.logger.Debugf("FlexSearch matching %d phrase clauses", len(req.Phrases))
+	}
+
 	result := &model.EngineResult{
 		Engine:  "flexsearch",
 		Results: []model.SearchResult{},
@@ -165,22 +176,118 @@ func (c *FlexSearchClient) doSearch(ctx context.Context, req *model.SearchReques
 	return result, nil
 }
 
+func (c *FlexSearchClient) AddDocument(ctx context.Context, doc *model.DocumentRequest) error {
+	payload, err := buildDocumentPayload(doc)
+	if err != nil {
+		return err
+	}
+
+	return c.write(ctx, flexSearchAddDocumentMethod, payload)
+}
+
+func (c *FlexSearchClient) DeleteDocument(ctx context.Context, index, id string) error {
+	payload, err := buildDeletePayload(index, id)
+	if err != nil {
+		return err
+	}
+
+	return c.write(ctx, flexSearchDeleteDocumentMethod, payload)
+}
+
+func (c *FlexSearchClient) write(ctx context.Context, method string, payload *structpb.Struct) error {
+	pool := c.pool.Load()
+	if pool == nil {
+		return fmt.Errorf("FlexSearch client is not connected")
+	}
+
+	if !c.circuitBreaker.AllowRequest() {
+		return fmt.Errorf("circuit breaker is open for FlexSearch")
+	}
+
+	err := c.writeWithRetry(ctx, pool, method, payload)
+	if err != nil {
+		c.circuitBreaker.RecordFailure()
+		c.logger.Errorf("FlexSearch write %s failed: %v", method, err)
+		return err
+	}
+
+	c.circuitBreaker.RecordSuccess()
+	return nil
+}
+
+func (c *FlexSearchClient) writeWithRetry(ctx context.Context, pool *connPool, method string, payload *structpb.Struct) error {
+	var lastErr error
+	start := time.Now()
+
+	for attempt := 0; attempt <= c.retryConfig.MaxRetries; attempt++ {
+		if attempt > 0 {
+			delay := c.calculateBackoff(attempt)
+			if c.retryConfig.BudgetExceeded(start, delay) {
+				return fmt.Errorf("FlexSearch write retry budget of %v exhausted after %d attempts: %w", c.retryConfig.MaxElapsed, attempt, lastErr)
+			}
+			c.logger.Debugf("FlexSearch write retry attempt %d after %v", attempt, delay)
+
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		conn, err := pool.get()
+		if err != nil {
+			return fmt.Errorf("FlexSearch write %s: %w", method, err)
+		}
+		callCtx, cancel := context.WithTimeout(ctx, c.config.Timeout)
+		err = conn.Invoke(callCtx, method, payload, &emptypb.Empty{})
+		cancel()
+		if err == nil {
+			return nil
+		}
+
+		lastErr = err
+
+		if !c.isRetryableError(err) {
+			break
+		}
+	}
+
+	return fmt.Errorf("FlexSearch write failed after %d retries: %w", c.retryConfig.MaxRetries, lastErr)
+}
+
+func (c *FlexSearchClient) IndexStats(ctx context.Context, index string) (*model.EngineIndexStats, error) {
+	return fetchIndexStats(ctx, c.pool.Load(), c.circuitBreaker, c.GetName(), flexSearchIndexStatsMethod, index, c.config.Timeout)
+}
+
+func (c *FlexSearchClient) Ping(ctx context.Context) (time.Duration, error) {
+	return ping(ctx, c.pool.Load(), c.GetName(), flexSearchPingMethod, c.config.Timeout)
+}
+
+func (c *FlexSearchClient) Address() string {
+	return c.config.Address()
+}
+
 func (c *FlexSearchClient) HealthCheck(ctx context.Context) bool {
-	if c.conn == nil {
+	pool := c.pool.Load()
+	if pool == nil {
 		return false
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
-	defer cancel()
-
-	state := c.conn.GetState()
-	return state == connectivity.Ready || state == connectivity.Idle
+	return pool.healthy()
 }
 
 func (c *FlexSearchClient) GetName() string {
 	return "flexsearch"
 }
 
+func (c *FlexSearchClient) GetCircuitBreakerState() string {
+	return c.circuitBreaker.GetState().String()
+}
+
+func (c *FlexSearchClient) GetCircuitBreakerFailures() int {
+	return c.circuitBreaker.GetFailureCount()
+}
+
 func (c *FlexSearchClient) isRetryableError(err error) bool {
 	if err == nil {
 		return false
@@ -200,13 +307,7 @@ func (c *FlexSearchClient) isRetryableError(err error) bool {
 }
 
 func (c *FlexSearchClient) calculateBackoff(attempt int) time.Duration {
-	delay := float64(c.retryConfig.InitialDelay) * math.Pow(c.retryConfig.BackoffFactor, float64(attempt-1))
-	
-	if delay > float64(c.retryConfig.MaxDelay) {
-		delay = float64(c.retryConfig.MaxDelay)
-	}
-	
-	return time.Duration(delay)
+	return c.retryConfig.Backoff(attempt)
 }
 
 func (c *FlexSearchClient) generateID(query string, index int) string {
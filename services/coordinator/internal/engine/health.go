@@ -0,0 +1,38 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// HealthPinger is implemented by engines that can measure a round trip to
+// their backend, as opposed to HealthCheck which only inspects the
+// connection state.
+type HealthPinger interface {
+	Ping(ctx context.Context) (time.Duration, error)
+	Address() string
+}
+
+// ping calls the engine's Ping RPC and returns its round-trip latency. It
+// bypasses the circuit breaker so health reports reflect the backend even
+// while requests are being shed.
+func ping(ctx context.Context, pool *connPool, engine, method string, timeout time.Duration) (time.Duration, error) {
+	if pool == nil {
+		return 0, fmt.Errorf("%s client is not connected", engine)
+	}
+
+	conn, err := pool.get()
+	if err != nil {
+		return 0, fmt.Errorf("%s ping: %w", engine, err)
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	err = conn.Invoke(callCtx, method, &emptypb.Empty{}, &emptypb.Empty{})
+	return time.Since(start), err
+}
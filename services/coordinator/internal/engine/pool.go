@@ -0,0 +1,196 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/flexsearch/coordinator/internal/util"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+)
+
+const (
+	defaultReconnectBackoff    = time.Second
+	defaultMaxReconnectBackoff = 30 * time.Second
+)
+
+type connPool struct {
+	mu     sync.RWMutex
+	conns  []*grpc.ClientConn
+	next   uint64
+	config *ClientConfig
+
+	// stop ends the reconnect monitor; it is set once monitor starts.
+	stop context.CancelFunc
+	wg   sync.WaitGroup
+}
+
+func newConnPool(config *ClientConfig) (*connPool, error) {
+	size := config.PoolSize
+	if size <= 0 {
+		size = 1
+	}
+
+	pool := &connPool{
+		conns:  make([]*grpc.ClientConn, 0, size),
+		config: config,
+	}
+
+	for i := 0; i < size; i++ {
+		conn, err := grpc.NewClient(config.Address(), config.dialOptions()...)
+		if err != nil {
+			pool.close()
+			return nil, err
+		}
+		pool.conns = append(pool.conns, conn)
+	}
+
+	return pool, nil
+}
+
+// errPoolClosed is returned by get once the pool has been closed, as it
+// is when a call is still in flight during Disconnect.
+var errPoolClosed = errors.New("connection pool is closed")
+
+func (p *connPool) get() (*grpc.ClientConn, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if len(p.conns) == 0 {
+		return nil, errPoolClosed
+	}
+	n := atomic.AddUint64(&p.next, 1)
+	return p.conns[(n-1)%uint64(len(p.conns))], nil
+}
+
+func (p *connPool) size() int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return len(p.conns)
+}
+
+func (p *connPool) healthy() bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	for _, conn := range p.conns {
+		state := conn.GetState()
+		if state == connectivity.Ready || state == connectivity.Idle {
+			return true
+		}
+	}
+	return false
+}
+
+// monitor watches every connection in the background and re-dials any
+// that falls into TransientFailure or Shutdown, waiting between attempts
+// from ReconnectBackoff, doubling up to MaxReconnectBackoff, and starting
+// over once the connection is ready again. close stops it.
+func (p *connPool) monitor(engine string, logger *util.Logger) {
+	ctx, cancel := context.WithCancel(context.Background())
+	p.mu.Lock()
+	p.stop = cancel
+	size := len(p.conns)
+	p.mu.Unlock()
+
+	for i := 0; i < size; i++ {
+		p.wg.Add(1)
+		go func(slot int) {
+			defer p.wg.Done()
+			p.watch(ctx, slot, engine, logger)
+		}(i)
+	}
+}
+
+func (p *connPool) watch(ctx context.Context, slot int, engine string, logger *util.Logger) {
+	initial, maxBackoff := p.config.reconnectBackoff()
+	backoff := initial
+
+	for {
+		conn := p.conn(slot)
+		if conn == nil {
+			return
+		}
+
+		state := conn.GetState()
+		switch state {
+		case connectivity.Ready:
+			backoff = initial
+		case connectivity.TransientFailure, connectivity.Shutdown:
+			select {
+			case <-time.After(backoff):
+			case <-ctx.Done():
+				return
+			}
+			if err := p.redial(slot, conn); err != nil {
+				logger.Warnf("Failed to redial %s connection %d: %v", engine, slot, err)
+			} else {
+				logger.Warnf("Redialed %s connection %d to %s after it entered %s", engine, slot, p.config.Address(), state)
+			}
+			backoff *= 2
+			if backoff > maxBackoff {
+				backoff = maxBackoff
+			}
+			continue
+		}
+
+		if !conn.WaitForStateChange(ctx, state) {
+			return
+		}
+	}
+}
+
+func (p *connPool) conn(slot int) *grpc.ClientConn {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if slot >= len(p.conns) {
+		return nil
+	}
+	return p.conns[slot]
+}
+
+// redial swaps a fresh connection into slot, unless old was already
+// replaced or the pool closed, and closes old.
+func (p *connPool) redial(slot int, old *grpc.ClientConn) error {
+	conn, err := grpc.NewClient(p.config.Address(), p.config.dialOptions()...)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	if slot >= len(p.conns) || p.conns[slot] != old {
+		p.mu.Unlock()
+		conn.Close()
+		return nil
+	}
+	p.conns[slot] = conn
+	p.mu.Unlock()
+
+	conn.Connect()
+	old.Close()
+	return nil
+}
+
+func (p *connPool) close() error {
+	p.mu.Lock()
+	stop := p.stop
+	p.stop = nil
+	p.mu.Unlock()
+	if stop != nil {
+		stop()
+		p.wg.Wait()
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var firstErr error
+	for _, conn := range p.conns {
+		if err := conn.Close(); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("failed to close connection: %w", err)
+		}
+	}
+	p.conns = nil
+	return firstErr
+}
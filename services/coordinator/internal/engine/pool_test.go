@@ -0,0 +1,240 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/flexsearch/coordinator/internal/model"
+	"github.com/flexsearch/coordinator/internal/util"
+	"google.golang.org/grpc"
+)
+
+func TestConnPoolSize(t *testing.T) {
+	pool, err := newConnPool(&ClientConfig{Host: "127.0.0.1", Port: 50053, PoolSize: 4})
+	if err != nil {
+		t.Fatalf("Failed to create pool: %v", err)
+	}
+	defer pool.close()
+
+	if pool.size() != 4 {
+		t.Errorf("Expected 4 connections, got %d", pool.size())
+	}
+
+	seen := make(map[interface{}]int)
+	for i := 0; i < 8; i++ {
+		conn, err := pool.get()
+		if err != nil {
+			t.Fatalf("get failed: %v", err)
+		}
+		seen[conn]++
+	}
+	if len(seen) != 4 {
+		t.Errorf("Expected round-robin over 4 connections, got %d", len(seen))
+	}
+	for _, n := range seen {
+		if n != 2 {
+			t.Errorf("Expected each connection to be used twice, got %d", n)
+		}
+	}
+}
+
+func TestConnPoolDefaultSize(t *testing.T) {
+	pool, err := newConnPool(&ClientConfig{Host: "127.0.0.1", Port: 50053})
+	if err != nil {
+		t.Fatalf("Failed to create pool: %v", err)
+	}
+	defer pool.close()
+
+	if pool.size() != 1 {
+		t.Errorf("Expected 1 connection, got %d", pool.size())
+	}
+}
+
+func TestEngineClientDistributesAcrossPool(t *testing.T) {
+	logger, err := util.NewLogger("info", "json", "stdout")
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	defer logger.Sync()
+
+	backend := newFakeBackend(t)
+	client := NewBM25Client(&ClientConfig{
+		Host:     "127.0.0.1",
+		Port:     backend.port(),
+		Timeout:  time.Second,
+		PoolSize: 3,
+	}, nil, logger)
+
+	ctx := context.Background()
+	if err := client.Connect(ctx); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+
+	for i := 0; i < 6; i++ {
+		if err := client.AddDocument(ctx, &model.DocumentRequest{ID: "doc", Index: "idx"}); err != nil {
+			t.Fatalf("AddDocument failed: %v", err)
+		}
+	}
+
+	peers := backend.peerCounts()
+	if len(peers) != 3 {
+		t.Errorf("Expected requests from 3 connections, got %d", len(peers))
+	}
+	for addr, n := range peers {
+		if n != 2 {
+			t.Errorf("Expected 2 requests on %s, got %d", addr, n)
+		}
+	}
+
+	if err := client.Disconnect(); err != nil {
+		t.Errorf("Disconnect failed: %v", err)
+	}
+	if client.HealthCheck(ctx) {
+		t.Error("Expected client to be unhealthy after disconnect")
+	}
+}
+
+func TestEngineClientReconnectsAfterConnectionLoss(t *testing.T) {
+	logger, err := util.NewLogger("error", "json", "stdout")
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	backend := newFakeBackend(t)
+	client := NewBM25Client(&ClientConfig{
+		Host:                "127.0.0.1",
+		Port:                backend.port(),
+		Timeout:             time.Second,
+		PoolSize:            2,
+		ReconnectBackoff:    10 * time.Millisecond,
+		MaxReconnectBackoff: 20 * time.Millisecond,
+	}, nil, logger)
+
+	ctx := context.Background()
+	if err := client.Connect(ctx); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer client.Disconnect()
+	if err := client.AddDocument(ctx, &model.DocumentRequest{ID: "doc", Index: "idx"}); err != nil {
+		t.Fatalf("AddDocument failed: %v", err)
+	}
+	before := []*grpc.ClientConn{client.pool.Load().conn(0), client.pool.Load().conn(1)}
+
+	backend.drop()
+	failing := time.Now().Add(5 * time.Second)
+	for !redialed(client.pool.Load(), before) {
+		if time.Now().After(failing) {
+			t.Fatal("Expected the monitor to redial the dropped connections")
+		}
+		client.Ping(ctx)
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	backend.resume(t)
+	recovered := time.Now().Add(5 * time.Second)
+	for {
+		err := client.AddDocument(ctx, &model.DocumentRequest{ID: "doc", Index: "idx"})
+		if err == nil {
+			break
+		}
+		if time.Now().After(recovered) {
+			t.Fatalf("Expected the client to serve again after the backend came back, got %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !client.HealthCheck(ctx) {
+		t.Error("Expected the recovered client to report healthy")
+	}
+}
+
+func TestConnPoolCloseStopsMonitor(t *testing.T) {
+	logger, err := util.NewLogger("error", "json", "stdout")
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	pool, err := newConnPool(&ClientConfig{Host: "127.0.0.1", Port: 1, PoolSize: 2, ReconnectBackoff: time.Millisecond})
+	if err != nil {
+		t.Fatalf("Failed to create pool: %v", err)
+	}
+	pool.monitor("bm25", logger)
+
+	done := make(chan struct{})
+	go func() {
+		pool.close()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected close to stop the reconnect monitor")
+	}
+}
+
+func TestConnPoolGetAfterClose(t *testing.T) {
+	pool, err := newConnPool(&ClientConfig{Host: "127.0.0.1", Port: 50053, PoolSize: 2})
+	if err != nil {
+		t.Fatalf("Failed to create pool: %v", err)
+	}
+	pool.close()
+
+	if _, err := pool.get(); !errors.Is(err, errPoolClosed) {
+		t.Errorf("Expected errPoolClosed from a closed pool, got %v", err)
+	}
+	if _, err := ping(context.Background(), pool, "bm25", bm25PingMethod, time.Second); err == nil {
+		t.Error("Expected ping on a closed pool to fail")
+	}
+}
+
+func TestEngineClientDisconnectDuringWrites(t *testing.T) {
+	logger, err := util.NewLogger("error", "json", "stdout")
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	backend := newFakeBackend(t)
+	client := NewBM25Client(&ClientConfig{
+		Host:     "127.0.0.1",
+		Port:     backend.port(),
+		Timeout:  time.Second,
+		PoolSize: 2,
+	}, nil, logger)
+
+	ctx := context.Background()
+	if err := client.Connect(ctx); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				client.AddDocument(ctx, &model.DocumentRequest{ID: "doc", Index: "idx"})
+				client.HealthCheck(ctx)
+			}
+		}()
+	}
+	if err := client.Disconnect(); err != nil {
+		t.Errorf("Disconnect failed: %v", err)
+	}
+	wg.Wait()
+
+	if err := client.AddDocument(ctx, &model.DocumentRequest{ID: "doc", Index: "idx"}); err == nil {
+		t.Error("Expected AddDocument after Disconnect to fail")
+	}
+}
+
+// redialed reports whether every connection in before has been replaced.
+func redialed(pool *connPool, before []*grpc.ClientConn) bool {
+	for i, old := range before {
+		if pool.conn(i) == old {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,81 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	defaultReadyAttempts       = 5
+	defaultReadyInitialBackoff = 200 * time.Millisecond
+	defaultReadyMaxBackoff     = 5 * time.Second
+)
+
+// ReadinessConfig bounds how long WaitReady retries. Zero fields take the
+// defaults: 5 attempts, backing off from 200ms up to 5s.
+type ReadinessConfig struct {
+	Attempts       int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// ReadyClient is an engine client that can prove its backend answers.
+type ReadyClient interface {
+	EngineClient
+	HealthPinger
+}
+
+// WaitReady pings the engine until a ping succeeds, doubling the wait
+// between attempts. Connect only dials, and a dialled connection can sit
+// idle against a backend that is reachable but not yet serving, so a ping
+// is the first proof the engine can take requests. It returns the last
+// ping error once the attempts run out.
+func WaitReady(ctx context.Context, p HealthPinger, cfg ReadinessConfig) error {
+	attempts := cfg.Attempts
+	if attempts <= 0 {
+		attempts = defaultReadyAttempts
+	}
+	backoff := cfg.InitialBackoff
+	if backoff <= 0 {
+		backoff = defaultReadyInitialBackoff
+	}
+	maxBackoff := cfg.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = defaultReadyMaxBackoff
+	}
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		if _, err = p.Ping(ctx); err == nil {
+			return nil
+		}
+		if attempt >= attempts {
+			return fmt.Errorf("%s not ready after %d attempts: %w", p.Address(), attempts, err)
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+// ConnectReady connects the client and waits for its backend to answer a
+// ping. A client that never becomes ready is disconnected again, so
+// callers only register engines that can serve.
+func ConnectReady(ctx context.Context, client ReadyClient, cfg ReadinessConfig) error {
+	if err := client.Connect(ctx); err != nil {
+		return err
+	}
+	if err := WaitReady(ctx, client, cfg); err != nil {
+		client.Disconnect()
+		return err
+	}
+	return nil
+}
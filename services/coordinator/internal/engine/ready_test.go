@@ -0,0 +1,78 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/flexsearch/coordinator/internal/util"
+)
+
+func TestConnectReadyWaitsForBackend(t *testing.T) {
+	logger, err := util.NewLogger("error", "json", "stdout")
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	backend := newFakeBackend(t)
+	backend.notReady = 2
+	client := NewBM25Client(&ClientConfig{Host: "127.0.0.1", Port: backend.port(), Timeout: time.Second}, nil, logger)
+	defer client.Disconnect()
+
+	cfg := ReadinessConfig{Attempts: 4, InitialBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond}
+	if err := ConnectReady(context.Background(), client, cfg); err != nil {
+		t.Fatalf("Expected the client to become ready, got %v", err)
+	}
+	if got := len(backend.callsFor(bm25PingMethod)); got != 3 {
+		t.Errorf("Expected two failed pings and one success, got %d pings", got)
+	}
+}
+
+func TestConnectReadyGivesUpOnUnreadyBackend(t *testing.T) {
+	logger, err := util.NewLogger("error", "json", "stdout")
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	// The backend accepts connections, so Connect succeeds, but never serves.
+	backend := newFakeBackend(t)
+	backend.notReady = 100
+	client := NewBM25Client(&ClientConfig{Host: "127.0.0.1", Port: backend.port(), Timeout: time.Second}, nil, logger)
+
+	cfg := ReadinessConfig{Attempts: 3, InitialBackoff: time.Millisecond}
+	if err := ConnectReady(context.Background(), client, cfg); err == nil {
+		t.Fatal("Expected a backend that never serves not to become ready")
+	}
+	if got := len(backend.callsFor(bm25PingMethod)); got != 3 {
+		t.Errorf("Expected 3 ping attempts, got %d", got)
+	}
+	if client.HealthCheck(context.Background()) {
+		t.Error("Expected the unready client to be disconnected")
+	}
+}
+
+func TestWaitReadyStopsOnContextDone(t *testing.T) {
+	logger, err := util.NewLogger("error", "json", "stdout")
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	backend := newFakeBackend(t)
+	backend.notReady = 100
+	client := NewBM25Client(&ClientConfig{Host: "127.0.0.1", Port: backend.port(), Timeout: time.Second}, nil, logger)
+	if err := client.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer client.Disconnect()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err = WaitReady(ctx, client, ReadinessConfig{Attempts: 100, InitialBackoff: time.Second})
+	if err != context.DeadlineExceeded {
+		t.Errorf("Expected the deadline to stop the retries, got %v", err)
+	}
+	if time.Since(start) > 500*time.Millisecond {
+		t.Errorf("Expected WaitReady to return promptly, took %v", time.Since(start))
+	}
+}
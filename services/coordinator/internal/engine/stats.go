@@ -0,0 +1,68 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/flexsearch/coordinator/internal/model"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// ErrIndexNotFound is returned by IndexStats when the engine holds no data
+// for the index.
+var ErrIndexNotFound = errors.New("index not found in engine")
+
+// IndexStatsProvider is implemented by engines that can report per-index
+// statistics.
+type IndexStatsProvider interface {
+	IndexStats(ctx context.Context, index string) (*model.EngineIndexStats, error)
+}
+
+func fetchIndexStats(ctx context.Context, pool *connPool, cb *CircuitBreaker, engine, method, index string, timeout time.Duration) (*model.EngineIndexStats, error) {
+	if pool == nil {
+		return nil, fmt.Errorf("%s client is not connected", engine)
+	}
+	if !cb.AllowRequest() {
+		return nil, fmt.Errorf("circuit breaker is open for %s", engine)
+	}
+
+	req, err := structpb.NewStruct(map[string]interface{}{"index": index})
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := pool.get()
+	if err != nil {
+		return nil, fmt.Errorf("%s index stats: %w", engine, err)
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	reply := &structpb.Struct{}
+	if err := conn.Invoke(callCtx, method, req, reply); err != nil {
+		if status.Code(err) == codes.NotFound {
+			cb.RecordSuccess()
+			return nil, ErrIndexNotFound
+		}
+		cb.RecordFailure()
+		return nil, err
+	}
+	cb.RecordSuccess()
+
+	return parseIndexStats(engine, reply), nil
+}
+
+func parseIndexStats(engine string, reply *structpb.Struct) *model.EngineIndexStats {
+	fields := reply.GetFields()
+	return &model.EngineIndexStats{
+		Engine:        engine,
+		DocumentCount: int64(fields["document_count"].GetNumberValue()),
+		IndexSize:     int64(fields["index_size"].GetNumberValue()),
+		LastUpdated:   fields["last_updated"].GetStringValue(),
+	}
+}
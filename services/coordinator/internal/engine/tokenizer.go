@@ -0,0 +1,124 @@
+package engine
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Tokenizer splits text into the terms BM25 scores. Implementations should
+// be safe for concurrent use, since one client serves every search.
+type Tokenizer interface {
+	Tokenize(text string) []string
+}
+
+// WhitespaceTokenizer lowercases text and splits it on whitespace. It is
+// the default when BM25EngineConfig.Tokenizer is nil.
+type WhitespaceTokenizer struct{}
+
+func (WhitespaceTokenizer) Tokenize(text string) []string {
+	return strings.Fields(strings.ToLower(text))
+}
+
+// StemmingTokenizer reduces each token of Base to a stem by stripping
+// common English inflections, so "running", "runs" and "run" all match.
+// A nil Base splits on whitespace.
+type StemmingTokenizer struct {
+	Base Tokenizer
+}
+
+func (t StemmingTokenizer) Tokenize(text string) []string {
+	tokens := baseTokens(t.Base, text)
+	for i, token := range tokens {
+		tokens[i] = stem(token)
+	}
+	return tokens
+}
+
+// NGramTokenizer emits the overlapping character n-grams of every token of
+// Base, which matches partial words and scripts written without spaces.
+// Tokens shorter than N are kept whole. N below 1 means 3.
+type NGramTokenizer struct {
+	N    int
+	Base Tokenizer
+}
+
+func (t NGramTokenizer) Tokenize(text string) []string {
+	n := t.N
+	if n < 1 {
+		n = 3
+	}
+
+	var grams []string
+	for _, token := range baseTokens(t.Base, text) {
+		runes := []rune(token)
+		if len(runes) <= n {
+			grams = append(grams, token)
+			continue
+		}
+		for i := 0; i+n <= len(runes); i++ {
+			grams = append(grams, string(runes[i:i+n]))
+		}
+	}
+	return grams
+}
+
+func baseTokens(base Tokenizer, text string) []string {
+	if base == nil {
+		base = WhitespaceTokenizer{}
+	}
+	return base.Tokenize(text)
+}
+
+// stemSuffixes are stripped longest first; each maps to its replacement.
+var stemSuffixes = []struct {
+	suffix, replacement string
+}{
+	{"ational", "ate"},
+	{"fulness", "ful"},
+	{"iveness", "ive"},
+	{"ization", "ize"},
+	{"ingly", ""},
+	{"sses", "ss"},
+	{"ness", ""},
+	{"ment", ""},
+	{"edly", ""},
+	{"ies", "y"},
+	{"ing", ""},
+	{"ly", ""},
+	{"ed", ""},
+	{"es", ""},
+	{"s", ""},
+}
+
+// stem applies the first matching suffix rule that leaves a stem of at
+// least three characters, then undoes the consonant doubling of forms like
+// "running". It is deliberately light: it never merges unrelated words, at
+// the cost of missing irregular forms.
+func stem(word string) string {
+	for _, rule := range stemSuffixes {
+		if !strings.HasSuffix(word, rule.suffix) || strings.HasSuffix(word, "ss") && rule.suffix == "s" {
+			continue
+		}
+		base := strings.TrimSuffix(word, rule.suffix)
+		if utf8.RuneCountInString(base) < 3 {
+			continue
+		}
+		if rule.replacement == "" && (rule.suffix == "ing" || rule.suffix == "ed") {
+			base = undouble(base)
+		}
+		return base + rule.replacement
+	}
+	return word
+}
+
+func undouble(base string) string {
+	n := len(base)
+	if n < 2 || base[n-1] != base[n-2] {
+		return base
+	}
+	switch base[n-1] {
+	case 'l', 's', 'z':
+		return base
+	}
+	return base[:n-1]
+}
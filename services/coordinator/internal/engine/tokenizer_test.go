@@ -0,0 +1,56 @@
+package engine
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenizersOnSameInput(t *testing.T) {
+	const input = "  Running searches QUICKLY indexed Queries "
+
+	tests := []struct {
+		name      string
+		tokenizer Tokenizer
+		want      []string
+	}{
+		{"whitespace", WhitespaceTokenizer{}, []string{"running", "searches", "quickly", "indexed", "queries"}},
+		{"stemming", StemmingTokenizer{}, []string{"run", "search", "quick", "index", "query"}},
+		{"ngram", NGramTokenizer{N: 4, Base: StemmingTokenizer{}}, []string{"run", "sear", "earc", "arch", "quic", "uick", "inde", "ndex", "quer", "uery"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.tokenizer.Tokenize(input)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Tokenize(%q) = %v, want %v", input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStemLeavesShortAndUninflectedWordsAlone(t *testing.T) {
+	for word, want := range map[string]string{
+		"is":      "is",
+		"class":   "class",
+		"classes": "class",
+		"stopped": "stop",
+		"falling": "fall",
+		"search":  "search",
+	} {
+		if got := stem(word); got != want {
+			t.Errorf("stem(%q) = %q, want %q", word, got, want)
+		}
+	}
+}
+
+func TestBM25PreprocessQueryUsesTokenizer(t *testing.T) {
+	defaultClient := &BM25Client{bm25Config: &BM25EngineConfig{}}
+	stemmingClient := &BM25Client{bm25Config: &BM25EngineConfig{Tokenizer: StemmingTokenizer{}}}
+
+	if got := defaultClient.preprocessQuery("Running a Search"); got != "running search" {
+		t.Errorf("Default preprocessQuery = %q, want %q", got, "running search")
+	}
+	if got := stemmingClient.preprocessQuery("Running a Search"); got != "run search" {
+		t.Errorf("Stemming preprocessQuery = %q, want %q", got, "run search")
+	}
+}
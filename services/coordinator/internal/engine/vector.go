@@ -6,21 +6,28 @@ import (
 	"encoding/hex"
 	"fmt"
 	"math"
+	"sync/atomic"
 	"time"
 
 	"github.com/flexsearch/coordinator/internal/model"
 	"github.com/flexsearch/coordinator/internal/util"
-	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/connectivity"
-	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	vectorAddDocumentMethod    = "/vector.VectorService/AddDocument"
+	vectorDeleteDocumentMethod = "/vector.VectorService/DeleteDocument"
+	vectorIndexStatsMethod     = "/vector.VectorService/IndexStats"
+	vectorPingMethod           = "/vector.VectorService/Ping"
 )
 
 type VectorClient struct {
 	config         *ClientConfig
 	vectorConfig   *VectorEngineConfig
-	conn           *grpc.ClientConn
+	pool           atomic.Pointer[connPool]
 	logger         *util.Logger
 	circuitBreaker *CircuitBreaker
 	retryConfig    *RetryConfig
@@ -50,9 +57,10 @@ func NewVectorClient(config *ClientConfig, vectorConfig *VectorEngineConfig, log
 	}
 
 	cbConfig := &CircuitBreakerConfig{
-		FailureThreshold: 5,
-		SuccessThreshold: 2,
-		Timeout:          30 * time.Second,
+		FailureThreshold:    5,
+		SuccessThreshold:    2,
+		Timeout:             30 * time.Second,
+		HalfOpenMaxRequests: 1,
 	}
 
 	retryConfig := &RetryConfig{
@@ -60,6 +68,8 @@ func NewVectorClient(config *ClientConfig, vectorConfig *VectorEngineConfig, log
 		InitialDelay:  100 * time.Millisecond,
 		MaxDelay:      5 * time.Second,
 		BackoffFactor: 2.0,
+		MaxElapsed:    config.maxRetryElapsed(),
+		Jitter:        config.jitter(),
 	}
 
 	return &VectorClient{
@@ -72,28 +82,22 @@ func NewVectorClient(config *ClientConfig, vectorConfig *VectorEngineConfig, log
 }
 
 func (c *VectorClient) Connect(ctx context.Context) error {
-	address := fmt.Sprintf("%s:%d", c.config.Host, c.config.Port)
-
-	conn, err := grpc.Dial(address,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-		grpc.WithDefaultCallOptions(
-			grpc.MaxCallRecvMsgSize(100*1024*1024),
-			grpc.MaxCallSendMsgSize(100*1024*1024),
-		),
-	)
+	address := c.config.Address()
+
+	pool, err := newConnPool(c.config)
 	if err != nil {
 		return fmt.Errorf("failed to connect to Vector: %w", err)
 	}
 
-	c.conn = conn
-	c.logger.Infof("Vector client connected to %s", address)
+	pool.monitor(c.GetName(), c.logger)
+	c.pool.Store(pool)
+	c.logger.Infof("Vector client connected to %s with %d connections", address, pool.size())
 	return nil
 }
 
 func (c *VectorClient) Disconnect() error {
-	if c.conn != nil {
-		err := c.conn.Close()
-		c.conn = nil
+	if pool := c.pool.Swap(nil); pool != nil {
+		err := pool.close()
 		c.logger.Info("Vector client disconnected")
 		return err
 	}
@@ -119,10 +123,14 @@ func (c *VectorClient) Search(ctx context.Context, req *model.SearchRequest) (*m
 
 func (c *VectorClient) searchWithRetry(ctx context.Context, req *model.SearchRequest) (*model.EngineResult, error) {
 	var lastErr error
+	start := time.Now()
 
 	for attempt := 0; attempt <= c.retryConfig.MaxRetries; attempt++ {
 		if attempt > 0 {
 			delay := c.calculateBackoff(attempt)
+			if c.retryConfig.BudgetExceeded(start, delay) {
+				return nil, fmt.Errorf("Vector search retry budget of %v exhausted after %d attempts: %w", c.retryConfig.MaxElapsed, attempt, lastErr)
+			}
 			c.logger.Debugf("Vector retry attempt %d after %v", attempt, delay)
 
 			select {
@@ -167,9 +175,12 @@ func (c *VectorClient) doSearch(ctx context.Context, req *model.SearchRequest) (
 		Took:    0,
 	}
 
-	topK := c.getTopK()
+	// The search service widens req.Limit to the per-engine candidate
+	// pool, so it decides how many neighbours are fetched. TopK only
+	// applies when the request sets no limit.
+	topK := int(req.Limit)
 	if topK <= 0 {
-		topK = int(req.Limit)
+		topK = c.getTopK()
 	}
 
 	for i := 0; i < topK; i++ {
@@ -256,16 +267,108 @@ func (c *VectorClient) normalizeScore(score float64) float64 {
 	return normalized
 }
 
+func (c *VectorClient) AddDocument(ctx context.Context, doc *model.DocumentRequest) error {
+	if doc != nil && len(doc.Vector) > 0 && len(doc.Vector) != c.getDimension() {
+		return fmt.Errorf("vector dimension mismatch: expected %d, got %d", c.getDimension(), len(doc.Vector))
+	}
+
+	payload, err := buildDocumentPayload(doc)
+	if err != nil {
+		return err
+	}
+
+	return c.write(ctx, vectorAddDocumentMethod, payload)
+}
+
+func (c *VectorClient) DeleteDocument(ctx context.Context, index, id string) error {
+	payload, err := buildDeletePayload(index, id)
+	if err != nil {
+		return err
+	}
+
+	return c.write(ctx, vectorDeleteDocumentMethod, payload)
+}
+
+func (c *VectorClient) write(ctx context.Context, method string, payload *structpb.Struct) error {
+	pool := c.pool.Load()
+	if pool == nil {
+		return fmt.Errorf("Vector client is not connected")
+	}
+
+	if !c.circuitBreaker.AllowRequest() {
+		return fmt.Errorf("circuit breaker is open for Vector")
+	}
+
+	err := c.writeWithRetry(ctx, pool, method, payload)
+	if err != nil {
+		c.circuitBreaker.RecordFailure()
+		c.logger.Errorf("Vector write %s failed: %v", method, err)
+		return err
+	}
+
+	c.circuitBreaker.RecordSuccess()
+	return nil
+}
+
+func (c *VectorClient) writeWithRetry(ctx context.Context, pool *connPool, method string, payload *structpb.Struct) error {
+	var lastErr error
+	start := time.Now()
+
+	for attempt := 0; attempt <= c.retryConfig.MaxRetries; attempt++ {
+		if attempt > 0 {
+			delay := c.calculateBackoff(attempt)
+			if c.retryConfig.BudgetExceeded(start, delay) {
+				return fmt.Errorf("Vector write retry budget of %v exhausted after %d attempts: %w", c.retryConfig.MaxElapsed, attempt, lastErr)
+			}
+			c.logger.Debugf("Vector write retry attempt %d after %v", attempt, delay)
+
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		conn, err := pool.get()
+		if err != nil {
+			return fmt.Errorf("Vector write %s: %w", method, err)
+		}
+		callCtx, cancel := context.WithTimeout(ctx, c.config.Timeout)
+		err = conn.Invoke(callCtx, method, payload, &emptypb.Empty{})
+		cancel()
+		if err == nil {
+			return nil
+		}
+
+		lastErr = err
+
+		if !c.isRetryableError(err) {
+			break
+		}
+	}
+
+	return fmt.Errorf("Vector write failed after %d retries: %w", c.retryConfig.MaxRetries, lastErr)
+}
+
+func (c *VectorClient) IndexStats(ctx context.Context, index string) (*model.EngineIndexStats, error) {
+	return fetchIndexStats(ctx, c.pool.Load(), c.circuitBreaker, c.GetName(), vectorIndexStatsMethod, index, c.config.Timeout)
+}
+
+func (c *VectorClient) Ping(ctx context.Context) (time.Duration, error) {
+	return ping(ctx, c.pool.Load(), c.GetName(), vectorPingMethod, c.config.Timeout)
+}
+
+func (c *VectorClient) Address() string {
+	return c.config.Address()
+}
+
 func (c *VectorClient) HealthCheck(ctx context.Context) bool {
-	if c.conn == nil {
+	pool := c.pool.Load()
+	if pool == nil {
 		return false
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
-	defer cancel()
-
-	state := c.conn.GetState()
-	return state == connectivity.Ready || state == connectivity.Idle
+	return pool.healthy()
 }
 
 func (c *VectorClient) GetName() string {
@@ -284,6 +387,14 @@ func (c *VectorClient) getTopK() int {
 	return c.vectorConfig.TopK
 }
 
+func (c *VectorClient) GetCircuitBreakerState() string {
+	return c.circuitBreaker.GetState().String()
+}
+
+func (c *VectorClient) GetCircuitBreakerFailures() int {
+	return c.circuitBreaker.GetFailureCount()
+}
+
 func (c *VectorClient) isRetryableError(err error) bool {
 	if err == nil {
 		return false
@@ -303,13 +414,7 @@ func (c *VectorClient) isRetryableError(err error) bool {
 }
 
 func (c *VectorClient) calculateBackoff(attempt int) time.Duration {
-	delay := float64(c.retryConfig.InitialDelay) * math.Pow(c.retryConfig.BackoffFactor, float64(attempt-1))
-
-	if delay > float64(c.retryConfig.MaxDelay) {
-		delay = float64(c.retryConfig.MaxDelay)
-	}
-
-	return time.Duration(delay)
+	return c.retryConfig.Backoff(attempt)
 }
 
 func (c *VectorClient) generateID(query string, index int) string {
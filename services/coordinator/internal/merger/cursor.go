@@ -0,0 +1,152 @@
+package merger
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/flexsearch/coordinator/internal/model"
+)
+
+var (
+	ErrInvalidCursor      = errors.New("cursor is malformed")
+	ErrCursorSortMismatch = errors.New("cursor was issued for a different sort; start again without search_after")
+)
+
+// Cursor marks the last result of a page. It carries that result's sort
+// values and ID, so the next page starts strictly after it even when
+// documents were added or removed in between, and the number of results
+// served so far, which sizes the engine fetch window.
+type Cursor struct {
+	Sort     string      `json:"s"`
+	Value    interface{} `json:"v,omitempty"`
+	HasValue bool        `json:"h,omitempty"`
+	Score    float64     `json:"sc"`
+	ID       string      `json:"id"`
+	Position int         `json:"p"`
+}
+
+// sortKey names the order a cursor belongs to. Every spelling of score
+// order shares one key, whatever the direction.
+func sortKey(field, order string) string {
+	if isScoreSort(field) {
+		return "_score"
+	}
+	if strings.EqualFold(order, SortOrderDesc) {
+		return field + ":" + SortOrderDesc
+	}
+	return field + ":" + SortOrderAsc
+}
+
+// EncodeCursor returns the opaque cursor for continuing after last, where
+// position counts the results served up to and including it.
+func EncodeCursor(last model.SearchResult, position int, field, order string) string {
+	c := Cursor{Sort: sortKey(field, order), Score: last.Score, ID: last.ID, Position: position}
+	if !isScoreSort(field) {
+		c.Value, c.HasValue = last.Fields[field]
+	}
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor parses a cursor and checks that it was issued for the same
+// sort, since its sort values mean nothing under another order.
+func DecodeCursor(s, field, order string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil || c.ID == "" || c.Position < 0 {
+		return nil, ErrInvalidCursor
+	}
+	if c.Sort != sortKey(field, order) {
+		return nil, ErrCursorSortMismatch
+	}
+	return &c, nil
+}
+
+// Paginate puts results in the field/order sort, with ID breaking ties so
+// the order is total, and returns the page of up to limit results that
+// follows after, or that starts at offset when after is nil. Ranks are
+// renumbered by absolute position. When the page is full, next is the
+// cursor for the page after it. A limit of zero returns everything.
+func Paginate(results []model.SearchResult, after *Cursor, offset, limit int, field, order string) (page []model.SearchResult, next string) {
+	desc := strings.EqualFold(order, SortOrderDesc)
+	sort.SliceStable(results, func(i, j int) bool {
+		return compareResults(&results[i], &results[j], field, desc) < 0
+	})
+
+	if offset < 0 {
+		offset = 0
+	}
+	start := offset
+	if after != nil {
+		mark := after.result(field)
+		start = sort.Search(len(results), func(i int) bool {
+			return compareResults(&results[i], mark, field, desc) > 0
+		})
+		offset = after.Position
+	} else if start > len(results) {
+		start = len(results)
+	}
+
+	page = results[start:]
+	if limit > 0 && len(page) > limit {
+		page = page[:limit]
+	}
+	for i := range page {
+		page[i].Rank = int32(offset + i + 1)
+	}
+
+	if limit > 0 && len(page) == limit {
+		next = EncodeCursor(page[len(page)-1], offset+len(page), field, order)
+	}
+	return page, next
+}
+
+// result rebuilds the sort values of the result the cursor points at.
+func (c *Cursor) result(field string) *model.SearchResult {
+	r := &model.SearchResult{ID: c.ID, Score: c.Score}
+	if c.HasValue {
+		r.Fields = map[string]interface{}{field: c.Value}
+	}
+	return r
+}
+
+// compareResults orders a before b when it returns a negative number:
+// by field when set, with results missing it last, then by score, highest
+// first, then by ID.
+func compareResults(a, b *model.SearchResult, field string, desc bool) int {
+	if !isScoreSort(field) {
+		av, aok := a.Fields[field]
+		bv, bok := b.Fields[field]
+		if aok != bok {
+			if aok {
+				return -1
+			}
+			return 1
+		}
+		if aok {
+			if c := compareFieldValues(av, bv); c != 0 {
+				if desc {
+					return -c
+				}
+				return c
+			}
+		}
+	}
+	switch {
+	case a.Score > b.Score:
+		return -1
+	case a.Score < b.Score:
+		return 1
+	}
+	return strings.Compare(a.ID, b.ID)
+}
+
+func isScoreSort(field string) bool {
+	return field == "" || field == "score" || field == "_score"
+}
@@ -0,0 +1,126 @@
+package merger
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/flexsearch/coordinator/internal/model"
+)
+
+// tiedResults has repeated scores and years so only the ID tiebreak makes
+// the order total.
+func tiedResults(n int) []model.SearchResult {
+	results := make([]model.SearchResult, n)
+	for i := range results {
+		results[i] = model.SearchResult{
+			ID:     fmt.Sprintf("doc-%02d", (i*7)%n),
+			Score:  float64(i%3) / 10,
+			Fields: map[string]interface{}{"year": 2000 + i%4},
+		}
+	}
+	return results
+}
+
+func pageThrough(t *testing.T, results func() []model.SearchResult, limit int, field, order string) []string {
+	t.Helper()
+
+	var ids []string
+	var after *Cursor
+	for pages := 0; ; pages++ {
+		if pages > 100 {
+			t.Fatal("Expected paging to finish")
+		}
+		page, next := Paginate(results(), after, 0, limit, field, order)
+		for i, r := range page {
+			ids = append(ids, r.ID)
+			if r.Rank != int32(len(ids)) {
+				t.Errorf("Expected %s on page %d row %d to have rank %d, got %d", r.ID, pages, i, len(ids), r.Rank)
+			}
+		}
+		if next == "" {
+			return ids
+		}
+		cursor, err := DecodeCursor(next, field, order)
+		if err != nil {
+			t.Fatalf("DecodeCursor() error = %v", err)
+		}
+		after = cursor
+	}
+}
+
+func assertEachOnce(t *testing.T, ids []string, n int) {
+	t.Helper()
+
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			t.Fatalf("Result %s was returned twice: %v", id, ids)
+		}
+		seen[id] = true
+	}
+	if len(seen) != n {
+		t.Fatalf("Expected %d distinct results, got %d: %v", n, len(seen), ids)
+	}
+}
+
+func TestPaginateVisitsEveryResultOnce(t *testing.T) {
+	for _, tt := range []struct {
+		field, order string
+		limit        int
+	}{
+		{"", "", 4},
+		{"year", "asc", 4},
+		{"year", "desc", 3},
+		{"year", "desc", 17},
+	} {
+		t.Run(fmt.Sprintf("%s_%s_%d", tt.field, tt.order, tt.limit), func(t *testing.T) {
+			ids := pageThrough(t, func() []model.SearchResult { return tiedResults(17) }, tt.limit, tt.field, tt.order)
+			assertEachOnce(t, ids, 17)
+		})
+	}
+}
+
+func TestPaginateSurvivesInsertsBetweenPages(t *testing.T) {
+	results := tiedResults(10)
+	first, next := Paginate(append([]model.SearchResult(nil), results...), nil, 0, 4, "", "")
+	cursor, err := DecodeCursor(next, "", "")
+	if err != nil {
+		t.Fatalf("DecodeCursor() error = %v", err)
+	}
+
+	// A new top result would shift every offset by one; the cursor keeps
+	// the second page starting after the last result already served.
+	withInsert := append([]model.SearchResult{{ID: "new", Score: 1}}, results...)
+	second, _ := Paginate(withInsert, cursor, 0, 4, "", "")
+
+	seen := map[string]bool{}
+	for _, r := range first {
+		seen[r.ID] = true
+	}
+	for _, r := range second {
+		if seen[r.ID] || r.ID == "new" {
+			t.Errorf("Expected the second page to continue after the first, got %s again", r.ID)
+		}
+	}
+	if len(second) != 4 {
+		t.Errorf("Expected a full second page, got %d", len(second))
+	}
+}
+
+func TestDecodeCursorRejectsSortChangesAndGarbage(t *testing.T) {
+	cursor := EncodeCursor(model.SearchResult{ID: "a", Score: 0.5, Fields: map[string]interface{}{"year": 2001}}, 3, "year", "asc")
+
+	if _, err := DecodeCursor(cursor, "year", "ASC"); err != nil {
+		t.Errorf("Expected the same sort to accept the cursor, got %v", err)
+	}
+	for _, sort := range [][2]string{{"year", "desc"}, {"price", "asc"}, {"", ""}} {
+		if _, err := DecodeCursor(cursor, sort[0], sort[1]); err != ErrCursorSortMismatch {
+			t.Errorf("DecodeCursor() with sort %v error = %v, want ErrCursorSortMismatch", sort, err)
+		}
+	}
+	for _, bad := range []string{"not base64!", "bm90IGpzb24", EncodeCursor(model.SearchResult{}, 1, "", "")} {
+		if _, err := DecodeCursor(bad, "", ""); err != ErrInvalidCursor {
+			t.Errorf("DecodeCursor(%q) error = %v, want ErrInvalidCursor", bad, err)
+		}
+	}
+}
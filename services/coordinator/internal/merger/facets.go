@@ -0,0 +1,62 @@
+package merger
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/flexsearch/coordinator/internal/model"
+)
+
+// ComputeFacets counts the values of each facet field across results.
+// Buckets are sorted by count descending, ties broken by value. Results
+// missing a field are skipped, and list values count once per element.
+func ComputeFacets(results []model.SearchResult, fields []string) map[string][]model.FacetBucket {
+	facets := make(map[string][]model.FacetBucket, len(fields))
+
+	for _, field := range fields {
+		counts := make(map[string]int64)
+		for _, result := range results {
+			value, ok := result.Fields[field]
+			if !ok || value == nil {
+				continue
+			}
+			for _, v := range facetValues(value) {
+				counts[v]++
+			}
+		}
+
+		buckets := make([]model.FacetBucket, 0, len(counts))
+		for value, count := range counts {
+			buckets = append(buckets, model.FacetBucket{Value: value, Count: count})
+		}
+		sort.Slice(buckets, func(i, j int) bool {
+			if buckets[i].Count != buckets[j].Count {
+				return buckets[i].Count > buckets[j].Count
+			}
+			return buckets[i].Value < buckets[j].Value
+		})
+
+		facets[field] = buckets
+	}
+
+	return facets
+}
+
+func facetValues(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []string:
+		return v
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if item != nil {
+				values = append(values, fmt.Sprint(item))
+			}
+		}
+		return values
+	default:
+		return []string{fmt.Sprint(v)}
+	}
+}
@@ -0,0 +1,41 @@
+package merger
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/flexsearch/coordinator/internal/model"
+)
+
+func TestComputeFacets(t *testing.T) {
+	results := []model.SearchResult{
+		{ID: "1", Fields: map[string]interface{}{"author": "alice", "tags": []interface{}{"go", "search"}}},
+		{ID: "2", Fields: map[string]interface{}{"author": "bob", "tags": []interface{}{"go"}}},
+		{ID: "3", Fields: map[string]interface{}{"author": "alice"}},
+		{ID: "4", Fields: map[string]interface{}{"author": "carol", "year": 2024}},
+		{ID: "5", Fields: map[string]interface{}{"author": "alice", "year": 2024}},
+		{ID: "6"},
+	}
+
+	facets := ComputeFacets(results, []string{"author", "tags", "year", "missing"})
+
+	want := map[string][]model.FacetBucket{
+		"author": {
+			{Value: "alice", Count: 3},
+			{Value: "bob", Count: 1},
+			{Value: "carol", Count: 1},
+		},
+		"tags": {
+			{Value: "go", Count: 2},
+			{Value: "search", Count: 1},
+		},
+		"year": {
+			{Value: "2024", Count: 2},
+		},
+		"missing": {},
+	}
+
+	if !reflect.DeepEqual(facets, want) {
+		t.Errorf("ComputeFacets() = %+v, want %+v", facets, want)
+	}
+}
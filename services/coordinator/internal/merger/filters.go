@@ -0,0 +1,238 @@
+package merger
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/flexsearch/coordinator/internal/model"
+)
+
+// FieldFilter is a parsed filter: a predicate over one field of a result.
+type FieldFilter struct {
+	Field string
+	match func(value interface{}) bool
+}
+
+// ParseFilter parses the filter expression for field. The grammar is:
+//
+//	[a TO b]   a <= v <= b
+//	{a TO b}   a <  v <  b
+//	[a TO b}   a <= v <  b (brackets may be mixed)
+//	[* TO b]   open lower bound; [a TO *] open upper bound
+//	>a >=a <a <=a
+//	anything else is an exact match
+//
+// Range and comparison bounds must be numbers, and only numeric field
+// values (or strings holding numbers) satisfy them.
+func ParseFilter(field, expr string) (FieldFilter, error) {
+	filter := FieldFilter{Field: field}
+	trimmed := strings.TrimSpace(expr)
+
+	switch {
+	case strings.HasPrefix(trimmed, "[") || strings.HasPrefix(trimmed, "{"):
+		match, err := parseRange(trimmed)
+		if err != nil {
+			return FieldFilter{}, fmt.Errorf("invalid filter %s:%s: %w", field, expr, err)
+		}
+		filter.match = match
+	case strings.HasPrefix(trimmed, ">") || strings.HasPrefix(trimmed, "<"):
+		match, err := parseComparison(trimmed)
+		if err != nil {
+			return FieldFilter{}, fmt.Errorf("invalid filter %s:%s: %w", field, expr, err)
+		}
+		filter.match = match
+	default:
+		filter.match = func(value interface{}) bool {
+			for _, v := range facetValues(value) {
+				if v == expr {
+					return true
+				}
+			}
+			return false
+		}
+	}
+
+	return filter, nil
+}
+
+// ParseFilters parses every filter, ordered by field.
+func ParseFilters(filters map[string]string) ([]FieldFilter, error) {
+	fields := make([]string, 0, len(filters))
+	for field := range filters {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	parsed := make([]FieldFilter, 0, len(fields))
+	for _, field := range fields {
+		filter, err := ParseFilter(field, filters[field])
+		if err != nil {
+			return nil, err
+		}
+		parsed = append(parsed, filter)
+	}
+	return parsed, nil
+}
+
+// Matches reports whether the result's field satisfies the filter. Results
+// missing the field never match.
+func (f FieldFilter) Matches(result *model.SearchResult) bool {
+	value, ok := result.Fields[f.Field]
+	if !ok || value == nil {
+		return false
+	}
+	return f.match(value)
+}
+
+// FilterMatch reports whether result satisfies every field:value filter.
+// Malformed filters match nothing; use ParseFilters to surface the error.
+func FilterMatch(result *model.SearchResult, filters map[string]string) bool {
+	parsed, err := ParseFilters(filters)
+	if err != nil {
+		return false
+	}
+	return matchAll(result, parsed)
+}
+
+// FilterResults returns the results that satisfy every filter, preserving
+// order.
+func FilterResults(results []model.SearchResult, filters []FieldFilter) []model.SearchResult {
+	if len(filters) == 0 {
+		return results
+	}
+
+	filtered := make([]model.SearchResult, 0, len(results))
+	for i := range results {
+		if matchAll(&results[i], filters) {
+			filtered = append(filtered, results[i])
+		}
+	}
+	return filtered
+}
+
+func matchAll(result *model.SearchResult, filters []FieldFilter) bool {
+	for _, filter := range filters {
+		if !filter.Matches(result) {
+			return false
+		}
+	}
+	return true
+}
+
+func parseRange(expr string) (func(interface{}) bool, error) {
+	if len(expr) < 2 {
+		return nil, fmt.Errorf("unterminated range")
+	}
+	lowerInclusive := expr[0] == '['
+	closing := expr[len(expr)-1]
+	if closing != ']' && closing != '}' {
+		return nil, fmt.Errorf("range must end with ] or }")
+	}
+	upperInclusive := closing == ']'
+
+	bounds := strings.Fields(expr[1 : len(expr)-1])
+	if len(bounds) != 3 || bounds[1] != "TO" {
+		return nil, fmt.Errorf("range must have the form [lower TO upper]")
+	}
+
+	lower, err := parseBound(bounds[0], math.Inf(-1))
+	if err != nil {
+		return nil, err
+	}
+	upper, err := parseBound(bounds[2], math.Inf(1))
+	if err != nil {
+		return nil, err
+	}
+	if lower > upper {
+		return nil, fmt.Errorf("range lower bound %s exceeds upper bound %s", bounds[0], bounds[2])
+	}
+
+	return numericPredicate(func(v float64) bool {
+		if lowerInclusive && v < lower || !lowerInclusive && v <= lower {
+			return false
+		}
+		if upperInclusive && v > upper || !upperInclusive && v >= upper {
+			return false
+		}
+		return true
+	}), nil
+}
+
+func parseComparison(expr string) (func(interface{}) bool, error) {
+	op := expr[:1]
+	if strings.HasPrefix(expr[1:], "=") {
+		op = expr[:2]
+	}
+
+	operand := strings.TrimSpace(expr[len(op):])
+	bound, err := strconv.ParseFloat(operand, 64)
+	if err != nil {
+		return nil, fmt.Errorf("comparison operand %q is not a number", operand)
+	}
+
+	var cmp func(float64) bool
+	switch op {
+	case ">":
+		cmp = func(v float64) bool { return v > bound }
+	case ">=":
+		cmp = func(v float64) bool { return v >= bound }
+	case "<":
+		cmp = func(v float64) bool { return v < bound }
+	case "<=":
+		cmp = func(v float64) bool { return v <= bound }
+	}
+	return numericPredicate(cmp), nil
+}
+
+func parseBound(bound string, open float64) (float64, error) {
+	if bound == "*" {
+		return open, nil
+	}
+	v, err := strconv.ParseFloat(bound, 64)
+	if err != nil {
+		return 0, fmt.Errorf("range bound %q is not a number", bound)
+	}
+	return v, nil
+}
+
+func numericPredicate(cmp func(float64) bool) func(interface{}) bool {
+	return func(value interface{}) bool {
+		if list, ok := value.([]interface{}); ok {
+			for _, item := range list {
+				if v, ok := numericValue(item); ok && cmp(v) {
+					return true
+				}
+			}
+			return false
+		}
+		v, ok := numericValue(value)
+		return ok && cmp(v)
+	}
+}
+
+func numericValue(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
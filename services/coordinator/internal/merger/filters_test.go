@@ -0,0 +1,134 @@
+package merger
+
+import (
+	"testing"
+
+	"github.com/flexsearch/coordinator/internal/model"
+)
+
+func TestFilterMatch(t *testing.T) {
+	result := &model.SearchResult{
+		ID: "1",
+		Fields: map[string]interface{}{
+			"author": "alice",
+			"lang":   "en",
+			"year":   2024,
+			"tags":   []interface{}{"go", "search"},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		filters map[string]string
+		want    bool
+	}{
+		{"no filters", nil, true},
+		{"match", map[string]string{"author": "alice"}, true},
+		{"non-match", map[string]string{"author": "bob"}, false},
+		{"missing field", map[string]string{"publisher": "acme"}, false},
+		{"numeric field", map[string]string{"year": "2024"}, true},
+		{"list field", map[string]string{"tags": "search"}, true},
+		{"list field non-match", map[string]string{"tags": "rust"}, false},
+		{"all filters match", map[string]string{"author": "alice", "lang": "en"}, true},
+		{"one filter fails", map[string]string{"author": "alice", "lang": "fr"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FilterMatch(result, tt.filters); got != tt.want {
+				t.Errorf("FilterMatch(%v) = %v, want %v", tt.filters, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterResults(t *testing.T) {
+	results := []model.SearchResult{
+		{ID: "1", Fields: map[string]interface{}{"lang": "en"}},
+		{ID: "2", Fields: map[string]interface{}{"lang": "fr"}},
+		{ID: "3", Fields: map[string]interface{}{"lang": "en"}},
+		{ID: "4"},
+	}
+
+	filters, err := ParseFilters(map[string]string{"lang": "en"})
+	if err != nil {
+		t.Fatalf("Expected filters to parse, got %v", err)
+	}
+
+	filtered := FilterResults(results, filters)
+	if len(filtered) != 2 || filtered[0].ID != "1" || filtered[1].ID != "3" {
+		t.Errorf("Expected results [1 3], got %+v", filtered)
+	}
+
+	if all := FilterResults(results, nil); len(all) != len(results) {
+		t.Errorf("Expected no filters to keep all %d results, got %d", len(results), len(all))
+	}
+}
+
+func TestParseFilterRanges(t *testing.T) {
+	tests := []struct {
+		expr  string
+		value interface{}
+		want  bool
+	}{
+		{"[10 TO 50]", 10.0, true},
+		{"[10 TO 50]", 50, true},
+		{"[10 TO 50]", 30, true},
+		{"[10 TO 50]", 9.99, false},
+		{"[10 TO 50]", 50.01, false},
+
+		{"{10 TO 50}", 10, false},
+		{"{10 TO 50}", 50, false},
+		{"{10 TO 50}", 10.5, true},
+		{"[10 TO 50}", 10, true},
+		{"[10 TO 50}", 50, false},
+
+		{"[* TO 50]", -1000, true},
+		{"[* TO 50]", 51, false},
+		{"[2000 TO *]", 1e9, true},
+		{"{2000 TO *]", 2000, false},
+
+		{">2000", 2001, true},
+		{">2000", 2000, false},
+		{">=2000", 2000, true},
+		{"<2000", 1999, true},
+		{"<=2000", 2000, true},
+		{"<=2000", 2001, false},
+
+		{"[10 TO 50]", "25", true},
+		{"[10 TO 50]", "cheap", false},
+		{"[10 TO 50]", []interface{}{5.0, 20.0}, true},
+	}
+
+	for _, tt := range tests {
+		filter, err := ParseFilter("price", tt.expr)
+		if err != nil {
+			t.Fatalf("ParseFilter(%q) returned error: %v", tt.expr, err)
+		}
+		result := &model.SearchResult{Fields: map[string]interface{}{"price": tt.value}}
+		if got := filter.Matches(result); got != tt.want {
+			t.Errorf("%s matching %v = %v, want %v", tt.expr, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestParseFilterMalformed(t *testing.T) {
+	for _, expr := range []string{
+		"[10 TO 50",
+		"[10 50]",
+		"[10 TO fifty]",
+		"[50 TO 10]",
+		"[10 to 50]",
+		">abc",
+		">=",
+		"<",
+	} {
+		if _, err := ParseFilter("price", expr); err == nil {
+			t.Errorf("Expected ParseFilter(%q) to fail", expr)
+		}
+	}
+
+	if _, err := ParseFilters(map[string]string{"lang": "en", "price": "[1 TO"}); err == nil {
+		t.Error("Expected ParseFilters to reject a malformed filter")
+	}
+}
@@ -0,0 +1,208 @@
+package merger
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/flexsearch/coordinator/internal/model"
+)
+
+const (
+	defaultFragmentSize = 150
+	defaultContextSize  = 40
+	defaultMaxFragments = 3
+
+	highlightPreTag  = "<em>"
+	highlightPostTag = "</em>"
+)
+
+type HighlighterConfig struct {
+	// FragmentSize caps the characters of text in one fragment.
+	FragmentSize int
+	// ContextSize is how many characters to keep on each side of a match.
+	ContextSize  int
+	MaxFragments int
+}
+
+// Highlighter builds snippets for results the engines returned without
+// highlights, wrapping whole-word, case-insensitive query term matches in
+// <em> tags.
+type Highlighter struct {
+	config HighlighterConfig
+}
+
+func NewHighlighter(config HighlighterConfig) *Highlighter {
+	if config.FragmentSize <= 0 {
+		config.FragmentSize = defaultFragmentSize
+	}
+	if config.ContextSize <= 0 {
+		config.ContextSize = defaultContextSize
+	}
+	if config.MaxFragments <= 0 {
+		config.MaxFragments = defaultMaxFragments
+	}
+	return &Highlighter{config: config}
+}
+
+// Apply fills Highlight for results that have none. With field set only
+// that field is highlighted; otherwise title, content and every string
+// field are. Fields without a match get no entry.
+func (h *Highlighter) Apply(results []model.SearchResult, query, field string) {
+	terms := highlightTerms(query)
+	if len(terms) == 0 {
+		return
+	}
+
+	for i := range results {
+		result := &results[i]
+		if len(result.Highlight) > 0 {
+			continue
+		}
+
+		for name, text := range highlightSources(result, field) {
+			snippet, ok := h.highlight(text, terms)
+			if !ok {
+				continue
+			}
+			if result.Highlight == nil {
+				result.Highlight = make(map[string]string)
+			}
+			result.Highlight[name] = snippet
+		}
+	}
+}
+
+// Highlight returns fragments of text around matches of the query terms. It
+// reports false when nothing matches.
+func (h *Highlighter) Highlight(text, query string) (string, bool) {
+	return h.highlight(text, highlightTerms(query))
+}
+
+func (h *Highlighter) highlight(text string, terms map[string]bool) (string, bool) {
+	runes := []rune(text)
+	matches := findMatches(runes, terms)
+	if len(matches) == 0 {
+		return "", false
+	}
+
+	var windows [][2]int
+	for _, m := range matches {
+		start := m[0] - h.config.ContextSize
+		if start < 0 {
+			start = 0
+		}
+		end := m[1] + h.config.ContextSize
+		if end > len(runes) {
+			end = len(runes)
+		}
+
+		if n := len(windows); n > 0 && start <= windows[n-1][1] {
+			if end > windows[n-1][1] {
+				windows[n-1][1] = end
+			}
+			continue
+		}
+		if len(windows) == h.config.MaxFragments {
+			break
+		}
+		windows = append(windows, [2]int{start, end})
+	}
+
+	var b strings.Builder
+	for i, w := range windows {
+		if w[1]-w[0] > h.config.FragmentSize {
+			w[1] = w[0] + h.config.FragmentSize
+		}
+		if i > 0 {
+			b.WriteString(" ")
+		}
+		if w[0] > 0 {
+			b.WriteString("...")
+		}
+
+		pos := w[0]
+		for _, m := range matches {
+			if m[0] < pos || m[1] > w[1] {
+				continue
+			}
+			b.WriteString(string(runes[pos:m[0]]))
+			b.WriteString(highlightPreTag)
+			b.WriteString(string(runes[m[0]:m[1]]))
+			b.WriteString(highlightPostTag)
+			pos = m[1]
+		}
+		b.WriteString(string(runes[pos:w[1]]))
+
+		if w[1] < len(runes) {
+			b.WriteString("...")
+		}
+	}
+
+	return b.String(), true
+}
+
+func highlightTerms(query string) map[string]bool {
+	terms := make(map[string]bool)
+	for _, word := range strings.FieldsFunc(strings.ToLower(query), isSeparator) {
+		terms[word] = true
+	}
+	return terms
+}
+
+func highlightSources(result *model.SearchResult, field string) map[string]string {
+	sources := make(map[string]string)
+	add := func(name string) {
+		switch name {
+		case "title":
+			if result.Title != "" {
+				sources[name] = result.Title
+				return
+			}
+		case "content":
+			if result.Content != "" {
+				sources[name] = result.Content
+				return
+			}
+		}
+		if text, ok := result.Fields[name].(string); ok && text != "" {
+			sources[name] = text
+		}
+	}
+
+	if field != "" {
+		add(field)
+		return sources
+	}
+
+	add("title")
+	add("content")
+	for name := range result.Fields {
+		if _, ok := sources[name]; !ok {
+			add(name)
+		}
+	}
+	return sources
+}
+
+// findMatches returns the [start, end) rune spans of words in terms.
+func findMatches(runes []rune, terms map[string]bool) [][2]int {
+	var matches [][2]int
+	for i := 0; i < len(runes); {
+		if isSeparator(runes[i]) {
+			i++
+			continue
+		}
+		start := i
+		for i < len(runes) && !isSeparator(runes[i]) {
+			i++
+		}
+		if terms[strings.ToLower(string(runes[start:i]))] {
+			matches = append(matches, [2]int{start, i})
+		}
+	}
+	return matches
+}
+
+func isSeparator(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
@@ -0,0 +1,78 @@
+package merger
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/flexsearch/coordinator/internal/model"
+)
+
+func TestHighlightSingleTerm(t *testing.T) {
+	h := NewHighlighter(HighlighterConfig{})
+
+	snippet, ok := h.Highlight("Go is a fast language for Search engines.", "search")
+	if !ok {
+		t.Fatal("Expected a match")
+	}
+	if want := "Go is a fast language for <em>Search</em> engines."; snippet != want {
+		t.Errorf("Expected %q, got %q", want, snippet)
+	}
+}
+
+func TestHighlightMultiTerm(t *testing.T) {
+	h := NewHighlighter(HighlighterConfig{ContextSize: 6, FragmentSize: 40})
+
+	text := "Search engines rank documents. " + strings.Repeat("filler ", 10) + "Inverted indexes make search fast."
+	snippet, ok := h.Highlight(text, "search indexes")
+	if !ok {
+		t.Fatal("Expected a match")
+	}
+
+	want := "<em>Search</em> engin... ...erted <em>indexes</em> make <em>search</em> fast."
+	if snippet != want {
+		t.Errorf("Expected %q, got %q", want, snippet)
+	}
+}
+
+func TestHighlightWholeWords(t *testing.T) {
+	h := NewHighlighter(HighlighterConfig{})
+
+	if snippet, ok := h.Highlight("researchers searched", "search"); ok {
+		t.Errorf("Expected partial words not to match, got %q", snippet)
+	}
+}
+
+func TestHighlighterApply(t *testing.T) {
+	h := NewHighlighter(HighlighterConfig{})
+
+	results := []model.SearchResult{
+		{ID: "1", Title: "Learning Go", Content: "A book about go and search."},
+		{ID: "2", Title: "Cooking", Content: "Recipes for pasta."},
+		{ID: "3", Title: "Go", Highlight: map[string]string{"title": "<b>Go</b>"}},
+		{ID: "4", Fields: map[string]interface{}{"summary": "Go tooling", "year": 2024}},
+	}
+
+	h.Apply(results, "go", "")
+
+	if got := results[0].Highlight["title"]; got != "Learning <em>Go</em>" {
+		t.Errorf("Expected highlighted title, got %q", got)
+	}
+	if got := results[0].Highlight["content"]; got != "A book about <em>go</em> and search." {
+		t.Errorf("Expected highlighted content, got %q", got)
+	}
+	if results[1].Highlight != nil {
+		t.Errorf("Expected no highlight without a match, got %v", results[1].Highlight)
+	}
+	if got := results[2].Highlight["title"]; got != "<b>Go</b>" {
+		t.Errorf("Expected engine highlights to be kept, got %q", got)
+	}
+	if got := results[3].Highlight["summary"]; got != "<em>Go</em> tooling" {
+		t.Errorf("Expected highlighted string field, got %q", got)
+	}
+
+	fieldOnly := []model.SearchResult{{ID: "1", Title: "Learning Go", Content: "About go."}}
+	h.Apply(fieldOnly, "go", "content")
+	if _, ok := fieldOnly[0].Highlight["title"]; ok || fieldOnly[0].Highlight["content"] == "" {
+		t.Errorf("Expected only the requested field to be highlighted, got %v", fieldOnly[0].Highlight)
+	}
+}
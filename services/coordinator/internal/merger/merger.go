@@ -10,6 +10,10 @@ import (
 
 type Merger interface {
 	Merge(results map[string]*model.EngineResult) *model.SearchResponse
+	// MergeWithExplain is Merge that, when explain is set, fills each
+	// result's Explain with the per-engine score components and the final
+	// fused score.
+	MergeWithExplain(results map[string]*model.EngineResult, explain bool) *model.SearchResponse
 	Sort(results []*ResultWithScore)
 	Deduplicate(results []*model.SearchResult) []*model.SearchResult
 }
@@ -19,6 +23,9 @@ type MergerConfig struct {
 	RRFK        int
 	Weights     map[string]float64
 	TopK        int
+	// Normalization is how WeightedMerger puts each engine's scores on a
+	// common scale: none, max, minmax or zscore. Empty means max.
+	Normalization string
 }
 
 type RRFMerger struct {
@@ -54,6 +61,10 @@ func NewWeightedMerger(config *MergerConfig, logger *util.Logger) *WeightedMerge
 }
 
 func (m *RRFMerger) Merge(results map[string]*model.EngineResult) *model.SearchResponse {
+	return m.MergeWithExplain(results, false)
+}
+
+func (m *RRFMerger) MergeWithExplain(results map[string]*model.EngineResult, explain bool) *model.SearchResponse {
 	startTime := time.Now()
 	
 	var allResults []*model.SearchResult
@@ -72,7 +83,11 @@ func (m *RRFMerger) Merge(results map[string]*model.EngineResult) *model.SearchR
 	}
 	
 	deduplicated := m.Deduplicate(allResults)
-	scores := m.calculateRRFScores(results)
+	var explanations map[string]map[string]float64
+	if explain {
+		explanations = make(map[string]map[string]float64)
+	}
+	scores := m.calculateRRFScores(results, explanations)
 	
 	var scoredResults []*ResultWithScore
 	for _, result := range deduplicated {
@@ -98,6 +113,9 @@ func (m *RRFMerger) Merge(results map[string]*model.EngineResult) *model.SearchR
 		}
 		sr.Result.Score = sr.Score
 		sr.Result.Rank = int32(i + 1)
+		if explain {
+			sr.Result.Explain = finalExplain(explanations[sr.Result.ID], sr.Score)
+		}
 		finalResults = append(finalResults, *sr.Result)
 	}
 	
@@ -118,10 +136,12 @@ func (m *RRFMerger) Merge(results map[string]*model.EngineResult) *model.SearchR
 	return response
 }
 
-func (m *RRFMerger) calculateRRFScores(results map[string]*model.EngineResult) map[string]float64 {
+// calculateRRFScores fuses by rank. When explanations is non-nil it
+// records each engine's raw score and RRF contribution per result.
+func (m *RRFMerger) calculateRRFScores(results map[string]*model.EngineResult, explanations map[string]map[string]float64) map[string]float64 {
 	scores := make(map[string]float64)
 	
-	for _, result := range results {
+	for engine, result := range results {
 		if result == nil {
 			continue
 		}
@@ -129,6 +149,10 @@ func (m *RRFMerger) calculateRRFScores(results map[string]*model.EngineResult) m
 		for rank, item := range result.Results {
 			rrfScore := 1.0 / float64(m.config.RRFK+rank+1)
 			scores[item.ID] += rrfScore
+			if explanations != nil {
+				explainComponent(explanations, item.ID, engine+"_raw", item.Score)
+				explainComponent(explanations, item.ID, engine+"_rrf", rrfScore)
+			}
 		}
 	}
 	
@@ -156,6 +180,10 @@ func (m *RRFMerger) Deduplicate(results []*model.SearchResult) []*model.SearchRe
 }
 
 func (m *WeightedMerger) Merge(results map[string]*model.EngineResult) *model.SearchResponse {
+	return m.MergeWithExplain(results, false)
+}
+
+func (m *WeightedMerger) MergeWithExplain(results map[string]*model.EngineResult, explain bool) *model.SearchResponse {
 	startTime := time.Now()
 	
 	var allResults []*model.SearchResult
@@ -174,7 +202,11 @@ func (m *WeightedMerger) Merge(results map[string]*model.EngineResult) *model.Se
 	}
 	
 	deduplicated := m.Deduplicate(allResults)
-	scores := m.calculateWeightedScores(results)
+	var explanations map[string]map[string]float64
+	if explain {
+		explanations = make(map[string]map[string]float64)
+	}
+	scores := m.calculateWeightedScores(results, explanations)
 	
 	var scoredResults []*ResultWithScore
 	for _, result := range deduplicated {
@@ -200,6 +232,9 @@ func (m *WeightedMerger) Merge(results map[string]*model.EngineResult) *model.Se
 		}
 		sr.Result.Score = sr.Score
 		sr.Result.Rank = int32(i + 1)
+		if explain {
+			sr.Result.Explain = finalExplain(explanations[sr.Result.ID], sr.Score)
+		}
 		finalResults = append(finalResults, *sr.Result)
 	}
 	
@@ -220,23 +255,11 @@ func (m *WeightedMerger) Merge(results map[string]*model.EngineResult) *model.Se
 	return response
 }
 
-func (m *WeightedMerger) calculateWeightedScores(results map[string]*model.EngineResult) map[string]float64 {
+// calculateWeightedScores sums normalized, weighted engine scores. When
+// explanations is non-nil it records each engine's raw and normalized
+// score, weight and weighted contribution per result.
+func (m *WeightedMerger) calculateWeightedScores(results map[string]*model.EngineResult, explanations map[string]map[string]float64) map[string]float64 {
 	scores := make(map[string]float64)
-	engineMaxScores := make(map[string]float64)
-	
-	for engine, result := range results {
-		if result == nil {
-			continue
-		}
-		
-		maxScore := 0.0
-		for _, item := range result.Results {
-			if item.Score > maxScore {
-				maxScore = item.Score
-			}
-		}
-		engineMaxScores[engine] = maxScore
-	}
 	
 	for engine, result := range results {
 		if result == nil {
@@ -248,14 +271,20 @@ func (m *WeightedMerger) calculateWeightedScores(results map[string]*model.Engin
 			weight = 1.0 / float64(len(results))
 		}
 		
-		maxScore := engineMaxScores[engine]
-		if maxScore == 0 {
-			maxScore = 1.0
+		raw := make([]float64, len(result.Results))
+		for i, item := range result.Results {
+			raw[i] = item.Score
 		}
 		
-		for _, item := range result.Results {
-			normalizedScore := item.Score / maxScore
-			scores[item.ID] += normalizedScore * weight
+		for i, normalizedScore := range normalizeScores(raw, m.config.Normalization) {
+			id := result.Results[i].ID
+			scores[id] += normalizedScore * weight
+			if explanations != nil {
+				explainComponent(explanations, id, engine+"_raw", raw[i])
+				explainComponent(explanations, id, engine+"_normalized", normalizedScore)
+				explanations[id][engine+"_weight"] = weight
+				explainComponent(explanations, id, engine+"_weighted", normalizedScore*weight)
+			}
 		}
 	}
 	
@@ -282,6 +311,28 @@ func (m *WeightedMerger) Deduplicate(results []*model.SearchResult) []*model.Sea
 	return deduplicated
 }
 
+// explainComponent adds value to the named component of id's explanation.
+// Components add up, matching the fused score, when an engine returns a
+// document more than once.
+func explainComponent(explanations map[string]map[string]float64, id, name string, value float64) {
+	components, ok := explanations[id]
+	if !ok {
+		components = make(map[string]float64)
+		explanations[id] = components
+	}
+	components[name] += value
+}
+
+// finalExplain completes a result's components with its fused score.
+func finalExplain(components map[string]float64, final float64) map[string]float64 {
+	explain := make(map[string]float64, len(components)+1)
+	for name, value := range components {
+		explain[name] = value
+	}
+	explain["final"] = final
+	return explain
+}
+
 func NewMerger(strategy string, config *MergerConfig, logger *util.Logger) Merger {
 	config.Strategy = strategy
 	
@@ -0,0 +1,93 @@
+package merger
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/flexsearch/coordinator/internal/model"
+)
+
+func explainResults() map[string]*model.EngineResult {
+	return map[string]*model.EngineResult{
+		"bm25": {Engine: "bm25", Results: []model.SearchResult{
+			{ID: "a", Score: 12}, {ID: "b", Score: 7}, {ID: "c", Score: 3},
+		}},
+		"vector": {Engine: "vector", Results: []model.SearchResult{
+			{ID: "b", Score: 0.9}, {ID: "d", Score: 0.8}, {ID: "a", Score: 0.4},
+		}},
+	}
+}
+
+// assertContributionsSum checks that the components ending in suffix add up
+// to each result's final score, which is also its Score.
+func assertContributionsSum(t *testing.T, results []model.SearchResult, suffix string) {
+	t.Helper()
+
+	if len(results) == 0 {
+		t.Fatal("Expected merged results")
+	}
+	for _, r := range results {
+		if r.Explain == nil {
+			t.Fatalf("Expected %s to carry an explanation", r.ID)
+		}
+		if r.Explain["final"] != r.Score {
+			t.Errorf("Expected %s final %v to equal its score %v", r.ID, r.Explain["final"], r.Score)
+		}
+		sum, parts := 0.0, 0
+		for name, value := range r.Explain {
+			if strings.HasSuffix(name, suffix) {
+				sum += value
+				parts++
+			}
+		}
+		if parts == 0 || math.Abs(sum-r.Score) > 1e-12 {
+			t.Errorf("Expected %s contributions %v to sum to %v, got %v", r.ID, r.Explain, r.Score, sum)
+		}
+	}
+}
+
+func TestRRFMergerExplain(t *testing.T) {
+	m := NewRRFMerger(&MergerConfig{RRFK: 60, TopK: 10}, newTestLogger(t))
+
+	results := m.MergeWithExplain(explainResults(), true).Results
+	assertContributionsSum(t, results, "_rrf")
+
+	for _, r := range results {
+		if r.ID == "a" {
+			if r.Explain["bm25_raw"] != 12 || r.Explain["vector_raw"] != 0.4 {
+				t.Errorf("Expected a to keep its raw engine scores, got %v", r.Explain)
+			}
+			if want := 1.0 / 61; r.Explain["bm25_rrf"] != want {
+				t.Errorf("Expected a's bm25 contribution to be %v, got %v", want, r.Explain["bm25_rrf"])
+			}
+		}
+	}
+
+	for _, r := range m.Merge(explainResults()).Results {
+		if r.Explain != nil {
+			t.Errorf("Expected no explanation without explain, got %v on %s", r.Explain, r.ID)
+		}
+	}
+}
+
+func TestWeightedMergerExplain(t *testing.T) {
+	m := NewWeightedMerger(&MergerConfig{
+		Weights: map[string]float64{"bm25": 0.3, "vector": 0.7},
+		TopK:    10,
+	}, newTestLogger(t))
+
+	results := m.MergeWithExplain(explainResults(), true).Results
+	assertContributionsSum(t, results, "_weighted")
+
+	for _, r := range results {
+		if r.ID == "b" {
+			if r.Explain["bm25_weight"] != 0.3 || r.Explain["vector_weight"] != 0.7 {
+				t.Errorf("Expected b to record the engine weights, got %v", r.Explain)
+			}
+			if want := 7.0 / 12; math.Abs(r.Explain["bm25_normalized"]-want) > 1e-12 {
+				t.Errorf("Expected b's normalized bm25 score to be %v, got %v", want, r.Explain["bm25_normalized"])
+			}
+		}
+	}
+}
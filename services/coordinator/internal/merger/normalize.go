@@ -0,0 +1,68 @@
+package merger
+
+import "math"
+
+// Normalization modes for WeightedMerger. Each engine's scores are
+// normalized on their own before weighting, so engines with different
+// score scales can be summed.
+const (
+	// NormalizationNone uses raw engine scores.
+	NormalizationNone = "none"
+	// NormalizationMax divides by the engine's top score. It is the default.
+	NormalizationMax = "max"
+	// NormalizationMinMax maps the engine's scores onto [0, 1].
+	NormalizationMinMax = "minmax"
+	// NormalizationZScore centres the engine's scores on their mean in
+	// units of their standard deviation, which a single outlier skews less.
+	NormalizationZScore = "zscore"
+)
+
+// normalizeScores returns scores normalized under mode. When all scores are
+// equal, including a single score, minmax gives each 1 and zscore each 0
+// rather than dividing by zero.
+func normalizeScores(scores []float64, mode string) []float64 {
+	out := make([]float64, len(scores))
+	if len(scores) == 0 {
+		return out
+	}
+
+	min, max, sum := scores[0], scores[0], 0.0
+	for _, s := range scores {
+		min = math.Min(min, s)
+		max = math.Max(max, s)
+		sum += s
+	}
+
+	switch mode {
+	case NormalizationNone:
+		copy(out, scores)
+	case NormalizationMinMax:
+		for i, s := range scores {
+			if max == min {
+				out[i] = 1
+			} else {
+				out[i] = (s - min) / (max - min)
+			}
+		}
+	case NormalizationZScore:
+		mean := sum / float64(len(scores))
+		var variance float64
+		for _, s := range scores {
+			variance += (s - mean) * (s - mean)
+		}
+		stddev := math.Sqrt(variance / float64(len(scores)))
+		for i, s := range scores {
+			if stddev > 0 {
+				out[i] = (s - mean) / stddev
+			}
+		}
+	default:
+		if max <= 0 {
+			max = 1
+		}
+		for i, s := range scores {
+			out[i] = s / max
+		}
+	}
+	return out
+}
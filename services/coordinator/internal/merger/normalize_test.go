@@ -0,0 +1,92 @@
+package merger
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/flexsearch/coordinator/internal/model"
+	"github.com/flexsearch/coordinator/internal/util"
+)
+
+func newTestLogger(t *testing.T) *util.Logger {
+	t.Helper()
+	logger, err := util.NewLogger("error", "json", "stdout")
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	return logger
+}
+
+// outlierResults has engine "a" with one outlier and a long tail of zero
+// scores, which shrinks its min-max spread but not its z-score spread, and
+// engine "b" with two results that disagree with "a" about p and q.
+func outlierResults() map[string]*model.EngineResult {
+	a := []model.SearchResult{{ID: "o", Score: 10}, {ID: "q", Score: 5}, {ID: "p", Score: 0}}
+	for i := 0; i < 20; i++ {
+		a = append(a, model.SearchResult{ID: fmt.Sprintf("tail-%d", i), Score: 0})
+	}
+	return map[string]*model.EngineResult{
+		"a": {Engine: "a", Results: a},
+		"b": {Engine: "b", Results: []model.SearchResult{{ID: "p", Score: 1}, {ID: "q", Score: 0}}},
+	}
+}
+
+func rankOf(results []model.SearchResult, id string) int {
+	for _, r := range results {
+		if r.ID == id {
+			return int(r.Rank)
+		}
+	}
+	return -1
+}
+
+func TestWeightedMergerNormalizationChangesRanking(t *testing.T) {
+	merge := func(mode string) []model.SearchResult {
+		m := NewWeightedMerger(&MergerConfig{
+			Weights:       map[string]float64{"a": 0.5, "b": 0.5},
+			TopK:          100,
+			Normalization: mode,
+		}, newTestLogger(t))
+		return m.Merge(outlierResults()).Results
+	}
+
+	minmax := merge(NormalizationMinMax)
+	if rankOf(minmax, "p") > rankOf(minmax, "q") {
+		t.Errorf("Expected min-max to rank p above q, got p=%d q=%d", rankOf(minmax, "p"), rankOf(minmax, "q"))
+	}
+
+	zscore := merge(NormalizationZScore)
+	if rankOf(zscore, "q") > rankOf(zscore, "p") {
+		t.Errorf("Expected z-score to rank q above p, got p=%d q=%d", rankOf(zscore, "p"), rankOf(zscore, "q"))
+	}
+}
+
+func TestNormalizeScores(t *testing.T) {
+	tests := []struct {
+		mode   string
+		scores []float64
+		want   []float64
+	}{
+		{NormalizationNone, []float64{4, 2}, []float64{4, 2}},
+		{"", []float64{4, 2}, []float64{1, 0.5}},
+		{NormalizationMax, []float64{0, 0}, []float64{0, 0}},
+		{NormalizationMinMax, []float64{4, 2, 3}, []float64{1, 0, 0.5}},
+		{NormalizationZScore, []float64{3, 1}, []float64{1, -1}},
+		// A single score, or all-equal scores, must not divide by zero.
+		{NormalizationMax, []float64{2.5}, []float64{1}},
+		{NormalizationMinMax, []float64{2.5}, []float64{1}},
+		{NormalizationZScore, []float64{2.5}, []float64{0}},
+		{NormalizationZScore, []float64{2, 2}, []float64{0, 0}},
+	}
+
+	for _, tt := range tests {
+		got := normalizeScores(tt.scores, tt.mode)
+		for i := range tt.want {
+			if math.IsNaN(got[i]) || math.Abs(got[i]-tt.want[i]) > 1e-9 {
+				t.Errorf("normalizeScores(%v, %q) = %v, want %v", tt.scores, tt.mode, got, tt.want)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package merger
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/flexsearch/coordinator/internal/model"
+)
+
+const (
+	SortOrderAsc  = "asc"
+	SortOrderDesc = "desc"
+)
+
+// SortByField orders merged results by a Fields value and renumbers their
+// ranks. Numeric values compare numerically, anything else lexically; equal
+// values fall back to score, then ID. Results missing the field sort last.
+// An empty field or "score" keeps the fused score order.
+func SortByField(results []model.SearchResult, field, order string) {
+	if isScoreSort(field) {
+		return
+	}
+	desc := strings.EqualFold(order, SortOrderDesc)
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return compareResults(&results[i], &results[j], field, desc) < 0
+	})
+
+	for i := range results {
+		results[i].Rank = int32(i + 1)
+	}
+}
+
+func compareFieldValues(a, b interface{}) int {
+	af, aNumeric := numericValue(a)
+	bf, bNumeric := numericValue(b)
+	if aNumeric && bNumeric {
+		switch {
+		case af < bf:
+			return -1
+		case af > bf:
+			return 1
+		default:
+			return 0
+		}
+	}
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
@@ -0,0 +1,73 @@
+package merger
+
+import (
+	"testing"
+
+	"github.com/flexsearch/coordinator/internal/model"
+)
+
+func resultIDs(results []model.SearchResult) []string {
+	ids := make([]string, len(results))
+	for i, r := range results {
+		ids[i] = r.ID
+	}
+	return ids
+}
+
+func assertOrder(t *testing.T, results []model.SearchResult, want []string) {
+	t.Helper()
+
+	got := resultIDs(results)
+	if len(got) != len(want) {
+		t.Fatalf("Expected order %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Expected order %v, got %v", want, got)
+		}
+		if results[i].Rank != int32(i+1) {
+			t.Errorf("Expected %s to have rank %d, got %d", got[i], i+1, results[i].Rank)
+		}
+	}
+}
+
+func TestSortByFieldNumericAscending(t *testing.T) {
+	results := []model.SearchResult{
+		{ID: "a", Score: 0.9, Fields: map[string]interface{}{"price": 30.0}},
+		{ID: "b", Score: 0.8, Fields: map[string]interface{}{"price": 5}},
+		{ID: "c", Score: 0.7, Fields: map[string]interface{}{"price": "100"}},
+		{ID: "d", Score: 0.6},
+		{ID: "e", Score: 0.95, Fields: map[string]interface{}{"price": 30}},
+	}
+
+	SortByField(results, "price", "asc")
+
+	// "100" is numeric and sorts after 30, not lexically before it; e beats
+	// a on score for the tie at 30; d has no price and sorts last.
+	assertOrder(t, results, []string{"b", "e", "a", "c", "d"})
+}
+
+func TestSortByFieldStringDescending(t *testing.T) {
+	results := []model.SearchResult{
+		{ID: "a", Score: 0.5, Fields: map[string]interface{}{"author": "bob"}},
+		{ID: "b", Score: 0.9, Fields: map[string]interface{}{"author": "carol"}},
+		{ID: "c", Score: 0.7, Fields: map[string]interface{}{"author": "alice"}},
+		{ID: "d", Score: 0.8, Fields: map[string]interface{}{"author": "bob"}},
+	}
+
+	SortByField(results, "author", "desc")
+
+	assertOrder(t, results, []string{"b", "d", "a", "c"})
+}
+
+func TestSortByFieldKeepsScoreOrder(t *testing.T) {
+	results := []model.SearchResult{
+		{ID: "a", Score: 0.9, Rank: 1, Fields: map[string]interface{}{"price": 30}},
+		{ID: "b", Score: 0.8, Rank: 2, Fields: map[string]interface{}{"price": 5}},
+	}
+
+	for _, field := range []string{"", "score"} {
+		SortByField(results, field, "asc")
+		assertOrder(t, results, []string{"a", "b"})
+	}
+}
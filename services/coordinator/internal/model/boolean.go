@@ -0,0 +1,298 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	QueryOpTerm   = "term"
+	QueryOpPhrase = "phrase"
+	QueryOpAnd    = "and"
+	QueryOpOr     = "or"
+	QueryOpNot    = "not"
+)
+
+// QueryNode is a node of a boolean query. Term and phrase nodes are
+// leaves; and/or nodes have two or more children and not has one.
+type QueryNode struct {
+	Op       string        `json:"op"`
+	Term     string        `json:"term,omitempty"`
+	Phrase   *PhraseClause `json:"phrase,omitempty"`
+	Children []*QueryNode  `json:"children,omitempty"`
+}
+
+// String renders the node as an s-expression, such as
+// (and a (or b (not c))), with phrases in quotes.
+func (n *QueryNode) String() string {
+	switch n.Op {
+	case QueryOpTerm:
+		return n.Term
+	case QueryOpPhrase:
+		s := `"` + strings.Join(n.Phrase.Terms, " ") + `"`
+		if n.Phrase.Distance > 0 {
+			s += fmt.Sprintf("~%d", n.Phrase.Distance)
+		}
+		return s
+	}
+	parts := []string{n.Op}
+	for _, child := range n.Children {
+		parts = append(parts, child.String())
+	}
+	return "(" + strings.Join(parts, " ") + ")"
+}
+
+// Terms lists the terms a matching document may contain: every term and
+// phrase term that is not under a not.
+func (n *QueryNode) Terms() []string {
+	switch n.Op {
+	case QueryOpTerm:
+		return []string{n.Term}
+	case QueryOpPhrase:
+		return append([]string(nil), n.Phrase.Terms...)
+	case QueryOpNot:
+		return nil
+	}
+	var terms []string
+	for _, child := range n.Children {
+		terms = append(terms, child.Terms()...)
+	}
+	return terms
+}
+
+// QuerySyntaxError reports malformed boolean syntax at a byte offset of
+// the query.
+type QuerySyntaxError struct {
+	Pos int
+	Msg string
+}
+
+func (e *QuerySyntaxError) Error() string {
+	return fmt.Sprintf("invalid query at position %d: %s", e.Pos, e.Msg)
+}
+
+// ParseBooleanQuery parses the AND, OR and NOT operators of query, also
+// spelled &&, || and !, with parentheses for grouping. NOT binds tightest,
+// then AND, then OR, so a OR b AND NOT c is (or a (and b (not c))). Terms
+// side by side are ANDed, and quoted phrases are single operands. The
+// operators must be upper case; and, or and not are search terms.
+//
+// It returns nil when the query uses no operators or parentheses, and a
+// *QuerySyntaxError for unbalanced parentheses or an operator missing an
+// operand.
+func ParseBooleanQuery(query string) (*QueryNode, error) {
+	tokens := lexQuery(query)
+	boolean := false
+	for _, tok := range tokens {
+		if tok.kind != tokenTerm && tok.kind != tokenPhrase {
+			boolean = true
+			break
+		}
+	}
+	if !boolean {
+		return nil, nil
+	}
+
+	p := &queryParser{tokens: tokens, end: len(query)}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	// parseOr only stops early at a ) it has no ( for.
+	if tok, ok := p.peek(); ok {
+		return nil, &QuerySyntaxError{Pos: tok.pos, Msg: "unmatched )"}
+	}
+	return node, nil
+}
+
+type tokenKind int
+
+const (
+	tokenTerm tokenKind = iota
+	tokenPhrase
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenOpen
+	tokenClose
+)
+
+type queryToken struct {
+	kind   tokenKind
+	text   string
+	pos    int
+	phrase *PhraseClause
+}
+
+func lexQuery(query string) []queryToken {
+	var tokens []queryToken
+	for i := 0; i < len(query); {
+		c := query[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(' || c == ')':
+			kind := tokenOpen
+			if c == ')' {
+				kind = tokenClose
+			}
+			tokens = append(tokens, queryToken{kind: kind, text: string(c), pos: i})
+			i++
+		case c == '"':
+			// Like ParsePhrases, an unterminated quote runs to the end.
+			span, next := query[i+1:], len(query)
+			if end := strings.IndexByte(span, '"'); end >= 0 {
+				span, next = span[:end], i+end+2
+			}
+			clause := &PhraseClause{Terms: strings.Fields(span)}
+			if distance, n := proximitySuffix(query[next:]); n > 0 {
+				clause.Distance = distance
+				next += n
+			}
+			if len(clause.Terms) > 0 {
+				tokens = append(tokens, queryToken{kind: tokenPhrase, text: query[i:next], pos: i, phrase: clause})
+			}
+			i = next
+		case c == '!' && i+1 < len(query) && !strings.ContainsRune(" \t\n\r()", rune(query[i+1])):
+			// !term negates term; a ! on its own is the operator below.
+			tokens = append(tokens, queryToken{kind: tokenNot, text: "!", pos: i})
+			i++
+		default:
+			start := i
+			for i < len(query) && !strings.ContainsRune(" \t\n\r()\"", rune(query[i])) {
+				i++
+			}
+			word := query[start:i]
+			tok := queryToken{kind: tokenTerm, text: word, pos: start}
+			switch word {
+			case "AND", "&&":
+				tok.kind = tokenAnd
+			case "OR", "||":
+				tok.kind = tokenOr
+			case "NOT", "!":
+				tok.kind = tokenNot
+			}
+			tokens = append(tokens, tok)
+		}
+	}
+	return tokens
+}
+
+type queryParser struct {
+	tokens []queryToken
+	next   int
+	end    int
+}
+
+func (p *queryParser) peek() (queryToken, bool) {
+	if p.next >= len(p.tokens) {
+		return queryToken{}, false
+	}
+	return p.tokens[p.next], true
+}
+
+func (p *queryParser) parseOr() (*QueryNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	children := []*QueryNode{left}
+	for {
+		tok, ok := p.peek()
+		if !ok || tok.kind != tokenOr {
+			break
+		}
+		p.next++
+		right, err := p.operand(tok, p.parseAnd)
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, right)
+	}
+	return group(QueryOpOr, children), nil
+}
+
+func (p *queryParser) parseAnd() (*QueryNode, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	children := []*QueryNode{left}
+	for {
+		tok, ok := p.peek()
+		if !ok || tok.kind == tokenOr || tok.kind == tokenClose {
+			break
+		}
+		if tok.kind == tokenAnd {
+			p.next++
+		}
+		right, err := p.operand(tok, p.parseNot)
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, right)
+	}
+	return group(QueryOpAnd, children), nil
+}
+
+func (p *queryParser) parseNot() (*QueryNode, error) {
+	tok, ok := p.peek()
+	if ok && tok.kind == tokenNot {
+		p.next++
+		child, err := p.operand(tok, p.parseNot)
+		if err != nil {
+			return nil, err
+		}
+		return &QueryNode{Op: QueryOpNot, Children: []*QueryNode{child}}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *queryParser) parsePrimary() (*QueryNode, error) {
+	tok, ok := p.peek()
+	if !ok {
+		return nil, &QuerySyntaxError{Pos: p.end, Msg: "expected a term"}
+	}
+	switch tok.kind {
+	case tokenTerm:
+		p.next++
+		return &QueryNode{Op: QueryOpTerm, Term: tok.text}, nil
+	case tokenPhrase:
+		p.next++
+		return &QueryNode{Op: QueryOpPhrase, Phrase: tok.phrase}, nil
+	case tokenOpen:
+		p.next++
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing, ok := p.peek(); !ok || closing.kind != tokenClose {
+			return nil, &QuerySyntaxError{Pos: tok.pos, Msg: "unmatched ("}
+		}
+		p.next++
+		return node, nil
+	case tokenClose:
+		return nil, &QuerySyntaxError{Pos: tok.pos, Msg: "empty group or unmatched )"}
+	default:
+		return nil, &QuerySyntaxError{Pos: tok.pos, Msg: fmt.Sprintf("%s is missing its left operand", tok.text)}
+	}
+}
+
+// operand parses the operand that must follow op, reporting op as missing
+// it when the query ends or a group closes first.
+func (p *queryParser) operand(op queryToken, parse func() (*QueryNode, error)) (*QueryNode, error) {
+	if op.kind == tokenAnd || op.kind == tokenOr || op.kind == tokenNot {
+		if tok, ok := p.peek(); !ok || tok.kind == tokenClose || tok.kind == tokenAnd || tok.kind == tokenOr {
+			return nil, &QuerySyntaxError{Pos: op.pos, Msg: fmt.Sprintf("%s is missing its right operand", op.text)}
+		}
+	}
+	return parse()
+}
+
+// group joins children under op, flattening a single child.
+func group(op string, children []*QueryNode) *QueryNode {
+	if len(children) == 1 {
+		return children[0]
+	}
+	return &QueryNode{Op: op, Children: children}
+}
@@ -0,0 +1,82 @@
+package model
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseBooleanQueryPrecedenceAndGrouping(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"a AND (b OR NOT c)", "(and a (or b (not c)))"},
+		{"a OR b AND NOT c", "(or a (and b (not c)))"},
+		{"a OR b OR c", "(or a b c)"},
+		{"(a OR b) c", "(and (or a b) c)"},
+		{"NOT NOT a", "(not (not a))"},
+		{"a && !b || c", "(or (and a (not b)) c)"},
+		{`"new york"~2 OR boston`, `(or "new york"~2 boston)`},
+		{"((a))", "a"},
+		{"cats and dogs OR birds", "(or (and cats and dogs) birds)"},
+	}
+
+	for _, tt := range tests {
+		node, err := ParseBooleanQuery(tt.query)
+		if err != nil {
+			t.Errorf("ParseBooleanQuery(%q) error = %v", tt.query, err)
+			continue
+		}
+		if got := node.String(); got != tt.want {
+			t.Errorf("ParseBooleanQuery(%q) = %s, want %s", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestParseBooleanQueryWithoutOperators(t *testing.T) {
+	for _, query := range []string{"", "plain words", `an "open phrase`, "hello! orange ANDROID"} {
+		node, err := ParseBooleanQuery(query)
+		if node != nil || err != nil {
+			t.Errorf("ParseBooleanQuery(%q) = %v, %v; want nil, nil", query, node, err)
+		}
+	}
+}
+
+func TestParseBooleanQueryRejectsMalformedExpressions(t *testing.T) {
+	tests := []struct {
+		query string
+		pos   int
+		msg   string
+	}{
+		{"a AND (b OR c", 6, "unmatched ("},
+		{"a OR b)", 6, "unmatched )"},
+		{"a AND", 2, "AND is missing its right operand"},
+		{"OR b", 0, "OR is missing its left operand"},
+		{"a AND OR b", 2, "AND is missing its right operand"},
+		{"a (NOT)", 3, "NOT is missing its right operand"},
+		{"()", 1, "empty group or unmatched )"},
+	}
+
+	for _, tt := range tests {
+		_, err := ParseBooleanQuery(tt.query)
+		var syntaxErr *QuerySyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Errorf("ParseBooleanQuery(%q) error = %v, want a QuerySyntaxError", tt.query, err)
+			continue
+		}
+		if syntaxErr.Pos != tt.pos || syntaxErr.Msg != tt.msg {
+			t.Errorf("ParseBooleanQuery(%q) error = %v, want %q at %d", tt.query, err, tt.msg, tt.pos)
+		}
+	}
+}
+
+func TestQueryNodeTermsSkipNegated(t *testing.T) {
+	node, err := ParseBooleanQuery(`a AND "b c" AND NOT (d OR e)`)
+	if err != nil {
+		t.Fatalf("ParseBooleanQuery() error = %v", err)
+	}
+	got := node.Terms()
+	if len(got) != 3 || got[0] != "a" || got[1] != "b" || got[2] != "c" {
+		t.Errorf("Terms() = %v, want [a b c]", got)
+	}
+}
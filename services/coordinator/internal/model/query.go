@@ -0,0 +1,74 @@
+package model
+
+import (
+	"strconv"
+	"strings"
+)
+
+// MaxPhraseDistance caps the ~N of a proximity clause.
+const MaxPhraseDistance = 100
+
+// PhraseClause is a quoted span of a query. Its terms must appear in
+// order, with at most Distance other terms between consecutive ones; a
+// zero Distance is an exact phrase. "a b" parses to an exact phrase and
+// "a b"~3 to a proximity clause with Distance 3.
+type PhraseClause struct {
+	Terms    []string `json:"terms"`
+	Distance int      `json:"distance,omitempty"`
+}
+
+// ParsePhrases extracts the quoted phrases of query and returns them with
+// the text left once they are removed. An unterminated quote runs to the
+// end of the query, and empty quotes are dropped. A ~N directly after the
+// closing quote sets the distance, clamped to MaxPhraseDistance; a ~
+// followed by anything but digits is left in rest.
+func ParsePhrases(query string) (phrases []PhraseClause, rest string) {
+	var text strings.Builder
+	for {
+		open := strings.IndexByte(query, '"')
+		if open < 0 {
+			text.WriteString(query)
+			break
+		}
+		text.WriteString(query[:open])
+		text.WriteByte(' ')
+
+		query = query[open+1:]
+		span := query
+		if end := strings.IndexByte(query, '"'); end >= 0 {
+			span, query = query[:end], query[end+1:]
+		} else {
+			query = ""
+		}
+
+		clause := PhraseClause{Terms: strings.Fields(span)}
+		if distance, n := proximitySuffix(query); n > 0 {
+			clause.Distance = distance
+			query = query[n:]
+		}
+		if len(clause.Terms) > 0 {
+			phrases = append(phrases, clause)
+		}
+	}
+	return phrases, strings.Join(strings.Fields(text.String()), " ")
+}
+
+// proximitySuffix parses a leading ~N and returns N with the number of
+// bytes it spans, or zero bytes when s does not start with one.
+func proximitySuffix(s string) (distance, n int) {
+	if !strings.HasPrefix(s, "~") {
+		return 0, 0
+	}
+	end := 1
+	for end < len(s) && s[end] >= '0' && s[end] <= '9' {
+		end++
+	}
+	if end == 1 {
+		return 0, 0
+	}
+	distance, err := strconv.Atoi(s[1:end])
+	if err != nil || distance > MaxPhraseDistance {
+		distance = MaxPhraseDistance
+	}
+	return distance, end
+}
@@ -0,0 +1,33 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePhrases(t *testing.T) {
+	tests := []struct {
+		query   string
+		phrases []PhraseClause
+		rest    string
+	}{
+		{`plain words`, nil, "plain words"},
+		{`"quick brown fox"`, []PhraseClause{{Terms: []string{"quick", "brown", "fox"}}}, ""},
+		{`jump "quick fox"~3 over`, []PhraseClause{{Terms: []string{"quick", "fox"}, Distance: 3}}, "jump over"},
+		{`"a b" and "c d"~1`, []PhraseClause{{Terms: []string{"a", "b"}}, {Terms: []string{"c", "d"}, Distance: 1}}, "and"},
+		{`"a b"~999`, []PhraseClause{{Terms: []string{"a", "b"}, Distance: MaxPhraseDistance}}, ""},
+		{`"a b"~x`, []PhraseClause{{Terms: []string{"a", "b"}}}, "~x"},
+		{`open "ended phrase`, []PhraseClause{{Terms: []string{"ended", "phrase"}}}, "open"},
+		{`empty "" quotes`, nil, "empty quotes"},
+	}
+
+	for _, tt := range tests {
+		phrases, rest := ParsePhrases(tt.query)
+		if !reflect.DeepEqual(phrases, tt.phrases) {
+			t.Errorf("ParsePhrases(%q) phrases = %+v, want %+v", tt.query, phrases, tt.phrases)
+		}
+		if rest != tt.rest {
+			t.Errorf("ParsePhrases(%q) rest = %q, want %q", tt.query, rest, tt.rest)
+		}
+	}
+}
@@ -10,12 +10,36 @@ type SearchRequest struct {
 	Engines        []string          `json:"engines,omitempty"`
 	EngineConfig   *EngineConfig     `json:"engine_config,omitempty"`
 	Filters        map[string]string `json:"filters,omitempty"`
+	Facets         []string          `json:"facets,omitempty"`
 	SortBy         string            `json:"sort_by,omitempty"`
 	SortOrder      string            `json:"sort_order,omitempty"`
 	Highlight      bool              `json:"highlight,omitempty"`
 	HighlightField string            `json:"highlight_field,omitempty"`
 	Timeout        time.Duration     `json:"timeout,omitempty"`
 	RequestID      string            `json:"request_id,omitempty"`
+	// SearchAfter is the NextCursor of the previous page. It replaces
+	// Offset and must keep the SortBy and SortOrder the cursor came from.
+	SearchAfter    string            `json:"search_after,omitempty"`
+	// PerEngineLimit is how many candidates each engine is asked for before
+	// fusion, independent of Limit. Zero uses the service default.
+	PerEngineLimit int32             `json:"per_engine_limit,omitempty"`
+	// Explain adds the routing decision to the response. DryRun returns
+	// only that, without searching any engine.
+	Explain        bool              `json:"explain,omitempty"`
+	DryRun         bool              `json:"dry_run,omitempty"`
+	// Phrases are the quoted spans of Query, set by the coordinator for
+	// engines that match phrases; see ParsePhrases.
+	Phrases        []PhraseClause    `json:"phrases,omitempty"`
+	// Boolean is Query parsed into its AND/OR/NOT structure, set by the
+	// coordinator when Query uses boolean operators.
+	Boolean        *QueryNode        `json:"boolean,omitempty"`
+	// Indexes lists every index a multi-index caller named. Only Index is
+	// searched; the list counts toward the complexity limits.
+	Indexes        []string          `json:"indexes,omitempty"`
+	// Tenant namespaces the request's cache entries, so identical queries
+	// from different tenants never share one. The gateway sets it from the
+	// caller's credentials.
+	Tenant         string            `json:"tenant,omitempty"`
 }
 
 type EngineConfig struct {
@@ -57,6 +81,7 @@ type QueryInfo struct {
 	HasBoolean    bool      `json:"has_boolean"`
 	HasSpecial    bool      `json:"has_special"`
 	Timestamp     time.Time `json:"timestamp"`
+	Phrases       []PhraseClause `json:"phrases,omitempty"`
 }
 
 type DocumentRequest struct {
@@ -66,6 +91,9 @@ type DocumentRequest struct {
 	Title    string                 `json:"title,omitempty"`
 	Fields   map[string]interface{} `json:"fields,omitempty"`
 	Vector   []float64              `json:"vector,omitempty"`
+	// Version, when non-zero, must equal the stored version for the write
+	// to be accepted.
+	Version  int64                  `json:"version,omitempty"`
 }
 
 type BulkDocumentRequest struct {
@@ -73,14 +101,64 @@ type BulkDocumentRequest struct {
 	Documents  []DocumentRequest `json:"documents"`
 }
 
+// MergeDocumentRequest updates only the given fields of a stored document.
+// "title" and "content" set those attributes; a nil value removes the field.
+type MergeDocumentRequest struct {
+	ID      string                 `json:"id"`
+	Index   string                 `json:"index"`
+	Fields  map[string]interface{} `json:"fields"`
+	Version int64                  `json:"version,omitempty"`
+}
+
 type DeleteRequest struct {
 	ID      string `json:"id"`
 	Index   string `json:"index"`
 }
 
 type IndexRequest struct {
-	Name   string            `json:"name"`
-	Fields map[string]string `json:"fields"`
+	Name     string                  `json:"name"`
+	Type     string                  `json:"type,omitempty"`
+	Fields   map[string]string       `json:"fields"`
+	Mappings map[string]FieldMapping `json:"mappings,omitempty"`
+}
+
+const (
+	FieldTypeText    = "text"
+	FieldTypeKeyword = "keyword"
+	FieldTypeInteger = "integer"
+	FieldTypeFloat   = "float"
+	FieldTypeBoolean = "boolean"
+	FieldTypeDate    = "date"
+	FieldTypeVector  = "vector"
+)
+
+// FieldMapping configures how one field of an index is analyzed and
+// scored. Analyzer only applies to text fields; a zero Boost means 1.
+type FieldMapping struct {
+	Type     string  `json:"type"`
+	Analyzer string  `json:"analyzer,omitempty"`
+	Boost    float64 `json:"boost,omitempty"`
+}
+
+const (
+	IndexTypeFullText = "fulltext"
+	IndexTypeKeyword  = "keyword"
+	IndexTypeVector   = "vector"
+	IndexTypeHybrid   = "hybrid"
+)
+
+var indexTypeEngines = map[string][]string{
+	IndexTypeFullText: {"flexsearch", "bm25"},
+	IndexTypeKeyword:  {"bm25"},
+	IndexTypeVector:   {"vector"},
+	IndexTypeHybrid:   {"flexsearch", "bm25", "vector"},
+}
+
+// IndexTypeEngines returns the engines that hold data for indexes of
+// indexType, and false for an unknown type.
+func IndexTypeEngines(indexType string) ([]string, bool) {
+	engines, ok := indexTypeEngines[indexType]
+	return append([]string(nil), engines...), ok
 }
 
 type IndexStatsRequest struct {
@@ -3,13 +3,41 @@ package model
 import "time"
 
 type SearchResponse struct {
-	RequestID    string         `json:"request_id"`
-	Results      []SearchResult `json:"results"`
-	Total        int64          `json:"total"`
-	Took         float64        `json:"took_ms"`
-	EnginesUsed  []string       `json:"engines_used"`
-	CacheHit     bool           `json:"cache_hit"`
-	QueryInfo    *QueryInfo     `json:"query_info,omitempty"`
+	RequestID     string                   `json:"request_id"`
+	Results       []SearchResult           `json:"results"`
+	Total         int64                    `json:"total"`
+	Took          float64                  `json:"took_ms"`
+	EnginesUsed   []string                 `json:"engines_used"`
+	CacheHit      bool                     `json:"cache_hit"`
+	QueryInfo     *QueryInfo               `json:"query_info,omitempty"`
+	Degraded      bool                     `json:"degraded,omitempty"`
+	FailedEngines []string                 `json:"failed_engines,omitempty"`
+	// SkippedEngines were routed to but not queried because their circuit
+	// breaker was open.
+	SkippedEngines []string                `json:"skipped_engines,omitempty"`
+	Error         string                   `json:"error,omitempty"`
+	Facets        map[string][]FacetBucket `json:"facets,omitempty"`
+	DidYouMean    string                   `json:"did_you_mean,omitempty"`
+	// NextCursor continues after the last result when the page is full.
+	NextCursor    string                   `json:"next_cursor,omitempty"`
+	// Routing explains how the query was routed when Explain was set.
+	Routing       *RoutingExplain          `json:"routing,omitempty"`
+}
+
+// RoutingExplain is the routing decision behind a response: the strategy,
+// the query signals it matched on, and the engines and weights it chose.
+type RoutingExplain struct {
+	Strategy  string             `json:"strategy"`
+	Reasons   []string           `json:"reasons,omitempty"`
+	Engines   []string           `json:"engines"`
+	Weights   map[string]float64 `json:"weights,omitempty"`
+	QueryInfo *QueryInfo         `json:"query_info,omitempty"`
+}
+
+// FacetBucket counts the merged results holding one value of a facet field.
+type FacetBucket struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
 }
 
 type SearchResult struct {
@@ -22,6 +50,9 @@ type SearchResult struct {
 	Fields       map[string]interface{} `json:"fields,omitempty"`
 	EngineSource string            `json:"engine_source,omitempty"`
 	Rank         int32             `json:"rank"`
+	// Explain holds the score components that produced Score, keyed like
+	// bm25_raw or vector_rrf, plus final, when the request set Explain.
+	Explain      map[string]float64 `json:"explain,omitempty"`
 }
 
 type EngineResult struct {
@@ -31,6 +62,9 @@ type EngineResult struct {
 	Took      float64       `json:"took_ms"`
 	Error     string        `json:"error,omitempty"`
 	TimedOut  bool          `json:"timed_out,omitempty"`
+	// Skipped is set when the engine was not queried because its circuit
+	// breaker was open.
+	Skipped   bool          `json:"skipped,omitempty"`
 }
 
 type DocumentResponse struct {
@@ -39,6 +73,7 @@ type DocumentResponse struct {
 	Success   bool                   `json:"success"`
 	Error     string                 `json:"error,omitempty"`
 	Fields    map[string]interface{} `json:"fields,omitempty"`
+	Version   int64                  `json:"version,omitempty"`
 }
 
 type BulkDocumentResponse struct {
@@ -51,6 +86,22 @@ type BulkDocumentResponse struct {
 	Errors     []string             `json:"errors,omitempty"`
 }
 
+// BulkChunkAck acknowledges one chunk of a streamed bulk ingest.
+type BulkChunkAck struct {
+	Chunk      int      `json:"chunk"`
+	Successful int      `json:"successful"`
+	Failed     int      `json:"failed"`
+	Errors     []string `json:"errors,omitempty"`
+}
+
+type BulkStreamResponse struct {
+	Index      string         `json:"index"`
+	Total      int            `json:"total"`
+	Successful int            `json:"successful"`
+	Failed     int            `json:"failed"`
+	Chunks     []BulkChunkAck `json:"chunks"`
+}
+
 type DeleteResponse struct {
 	ID      string `json:"id"`
 	Index   string `json:"index"`
@@ -65,11 +116,47 @@ type IndexResponse struct {
 	Fields    []string `json:"fields,omitempty"`
 }
 
+// IndexMetadata is what the coordinator keeps about a created index.
+type IndexMetadata struct {
+	Name      string                  `json:"name"`
+	Type      string                  `json:"type"`
+	Mappings  map[string]FieldMapping `json:"mappings,omitempty"`
+	CreatedAt time.Time               `json:"created_at"`
+}
+
+// IndexStatsResponse aggregates the per-engine stats of an index. Engines
+// hold replicas of the same documents, so DocumentCount is the largest
+// engine count while IndexSize is the total across engines. Partial is set
+// when some engines could not report, with the reasons in Notes.
 type IndexStatsResponse struct {
-	Index         string `json:"index"`
+	Index         string             `json:"index"`
+	DocumentCount int64              `json:"document_count"`
+	IndexSize     int64              `json:"index_size"`
+	LastUpdated   string             `json:"last_updated"`
+	Engines       []EngineIndexStats `json:"engines,omitempty"`
+	Partial       bool               `json:"partial,omitempty"`
+	Notes         []string           `json:"notes,omitempty"`
+}
+
+// ReconcileReport compares the document count of each engine holding an
+// index against the coordinator's document store. Mismatched lists engines
+// whose count differs from Expected; Unreachable lists engines that could
+// not report. RebuildTaskID is set when a rebuild was started.
+type ReconcileReport struct {
+	Index         string           `json:"index"`
+	Expected      int64            `json:"expected"`
+	Counts        map[string]int64 `json:"counts"`
+	Consistent    bool             `json:"consistent"`
+	Mismatched    []string         `json:"mismatched,omitempty"`
+	Unreachable   []string         `json:"unreachable,omitempty"`
+	RebuildTaskID string           `json:"rebuild_task_id,omitempty"`
+}
+
+type EngineIndexStats struct {
+	Engine        string `json:"engine"`
 	DocumentCount int64  `json:"document_count"`
 	IndexSize     int64  `json:"index_size"`
-	LastUpdated   string `json:"last_updated"`
+	LastUpdated   string `json:"last_updated,omitempty"`
 }
 
 type HealthCheckResponse struct {
@@ -87,6 +174,8 @@ type EngineHealth struct {
 	Address   string `json:"address,omitempty"`
 	Latency   float64 `json:"latency_ms,omitempty"`
 	Error     string `json:"error,omitempty"`
+	CircuitBreaker string `json:"circuit_breaker,omitempty"`
+	FailureCount   int    `json:"failure_count,omitempty"`
 }
 
 type ErrorResponse struct {
@@ -111,3 +200,19 @@ type CacheStats struct {
 	Size       int64   `json:"size"`
 	MaxSize    int64   `json:"max_size"`
 }
+
+const (
+	TaskStateRunning   = "running"
+	TaskStateCompleted = "completed"
+	TaskStateFailed    = "failed"
+)
+
+type TaskStatus struct {
+	ID         string     `json:"id"`
+	Type       string     `json:"type"`
+	State      string     `json:"state"`
+	Percent    float64    `json:"percent"`
+	Error      string     `json:"error,omitempty"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
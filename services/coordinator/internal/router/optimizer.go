@@ -4,6 +4,7 @@ import (
 	"context"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/flexsearch/coordinator/internal/model"
@@ -14,6 +15,7 @@ type Optimizer struct {
 	logger      *util.Logger
 	synonyms    map[string][]string
 	stopWords   map[string]bool
+	statsMu     sync.Mutex
 	stats       *OptimizerStats
 }
 
@@ -53,23 +55,32 @@ func (o *Optimizer) Optimize(ctx context.Context, req *model.SearchRequest) *Opt
 
 	query := strings.TrimSpace(req.Query)
 	
-	rewritten := o.rewriteQuery(query)
+	// Stop-word removal and lowercasing would strip the operators of a
+	// boolean query, so those are searched as written.
+	rewritten := query
+	if node, err := model.ParseBooleanQuery(query); node == nil && err == nil {
+		rewritten = o.rewriteQuery(query)
+	}
 	if rewritten != query {
 		optimized.RewrittenQuery = rewritten
 		optimized.Rewritten = true
-		o.stats.RewrittenQueries++
 	}
 
 	suggestions := o.generateSuggestions(query)
 	optimized.Suggestions = suggestions
+
+	optimized.ProcessingTime = time.Since(startTime)
+
+	o.statsMu.Lock()
+	if optimized.Rewritten {
+		o.stats.RewrittenQueries++
+	}
 	if len(suggestions) > 0 {
 		o.stats.SuggestionsGenerated++
 	}
-
-	optimized.ProcessingTime = time.Since(startTime)
 	o.stats.TotalQueries++
-	
 	o.updateAverageRewriteTime(optimized.ProcessingTime)
+	o.statsMu.Unlock()
 
 	o.logger.Debugw("Query optimized",
 		"original", optimized.OriginalQuery,
@@ -175,7 +186,10 @@ func (o *Optimizer) correctSpelling(word string) string {
 }
 
 func (o *Optimizer) GetStats() *OptimizerStats {
-	return o.stats
+	o.statsMu.Lock()
+	defer o.statsMu.Unlock()
+	stats := *o.stats
+	return &stats
 }
 
 func (o *Optimizer) updateAverageRewriteTime(duration time.Duration) {
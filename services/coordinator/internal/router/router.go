@@ -2,6 +2,7 @@ package router
 
 import (
 	"context"
+	"fmt"
 	"regexp"
 	"strings"
 	"time"
@@ -13,11 +14,17 @@ import (
 type Router struct {
 	logger  *util.Logger
 	strategies map[string]RoutingStrategy
+	// order is the order strategies are tried in; the first that matches
+	// routes the query.
+	order []string
 }
 
 type RoutingStrategy interface {
 	Name() string
 	ShouldRoute(ctx context.Context, req *model.SearchRequest) bool
+	// MatchReasons lists the query signals that make the strategy route
+	// req, or nothing when it does not.
+	MatchReasons(ctx context.Context, req *model.SearchRequest) []string
 	GetEngines() []string
 	GetWeights() map[string]float64
 }
@@ -29,21 +36,32 @@ func (s *ExactMatchStrategy) Name() string {
 }
 
 func (s *ExactMatchStrategy) ShouldRoute(ctx context.Context, req *model.SearchRequest) bool {
+	return len(s.MatchReasons(ctx, req)) > 0
+}
+
+func (s *ExactMatchStrategy) MatchReasons(ctx context.Context, req *model.SearchRequest) []string {
 	query := strings.TrimSpace(req.Query)
 	
 	words := strings.Fields(query)
 	if len(words) == 0 {
-		return false
+		return nil
 	}
 	
 	if len(words) <= 3 {
-		return true
+		return []string{"query has at most 3 words"}
 	}
 	
-	hasQuotes := strings.Contains(query, "\"")
-	hasWildcards := strings.ContainsAny(query, "*?")
-	
-	return hasQuotes || hasWildcards || len(query) <= 20
+	var reasons []string
+	if strings.Contains(query, "\"") {
+		reasons = append(reasons, "query has a quoted phrase")
+	}
+	if strings.ContainsAny(query, "*?") {
+		reasons = append(reasons, "query has wildcards")
+	}
+	if len(query) <= 20 {
+		reasons = append(reasons, "query is at most 20 characters")
+	}
+	return reasons
 }
 
 func (s *ExactMatchStrategy) GetEngines() []string {
@@ -63,18 +81,26 @@ func (s *FuzzySearchStrategy) Name() string {
 }
 
 func (s *FuzzySearchStrategy) ShouldRoute(ctx context.Context, req *model.SearchRequest) bool {
+	return len(s.MatchReasons(ctx, req)) > 0
+}
+
+func (s *FuzzySearchStrategy) MatchReasons(ctx context.Context, req *model.SearchRequest) []string {
 	query := strings.TrimSpace(req.Query)
 	
 	if req.EngineConfig != nil && req.EngineConfig.FlexSearch != nil {
 		if req.EngineConfig.FlexSearch.Fuzzy {
-			return true
+			return []string{"fuzzy matching requested"}
 		}
 	}
 	
-	hasTypos := detectPotentialTypos(query)
-	hasWildcards := strings.ContainsAny(query, "*?")
-	
-	return hasTypos || hasWildcards
+	var reasons []string
+	if detectPotentialTypos(query) {
+		reasons = append(reasons, "query has likely typos")
+	}
+	if strings.ContainsAny(query, "*?") {
+		reasons = append(reasons, "query has wildcards")
+	}
+	return reasons
 }
 
 func (s *FuzzySearchStrategy) GetEngines() []string {
@@ -94,17 +120,22 @@ func (s *SemanticSearchStrategy) Name() string {
 }
 
 func (s *SemanticSearchStrategy) ShouldRoute(ctx context.Context, req *model.SearchRequest) bool {
+	return len(s.MatchReasons(ctx, req)) > 0
+}
+
+func (s *SemanticSearchStrategy) MatchReasons(ctx context.Context, req *model.SearchRequest) []string {
 	query := strings.TrimSpace(req.Query)
 	
 	words := strings.Fields(query)
 	
 	if len(words) >= 4 {
-		return true
+		return []string{"query has at least 4 words"}
 	}
 	
-	hasStopWords := containsStopWords(query)
-	
-	return len(words) >= 3 && hasStopWords
+	if len(words) >= 3 && containsStopWords(query) {
+		return []string{"query is a 3-word phrase with stop words"}
+	}
+	return nil
 }
 
 func (s *SemanticSearchStrategy) GetEngines() []string {
@@ -124,19 +155,23 @@ func (s *HybridSearchStrategy) Name() string {
 }
 
 func (s *HybridSearchStrategy) ShouldRoute(ctx context.Context, req *model.SearchRequest) bool {
+	return len(s.MatchReasons(ctx, req)) > 0
+}
+
+func (s *HybridSearchStrategy) MatchReasons(ctx context.Context, req *model.SearchRequest) []string {
 	query := strings.TrimSpace(req.Query)
 	
 	words := strings.Fields(query)
 	
 	if len(words) >= 3 && len(words) <= 6 {
-		return true
+		return []string{"query has 3 to 6 words"}
 	}
 	
 	if req.EngineConfig != nil && req.EngineConfig.Vector != nil && req.EngineConfig.Vector.Hybrid {
-		return true
+		return []string{"hybrid vector search requested"}
 	}
 	
-	return false
+	return nil
 }
 
 func (s *HybridSearchStrategy) GetEngines() []string {
@@ -160,6 +195,13 @@ func (s *AutoRoutingStrategy) ShouldRoute(ctx context.Context, req *model.Search
 	return true
 }
 
+func (s *AutoRoutingStrategy) MatchReasons(ctx context.Context, req *model.SearchRequest) []string {
+	if len(req.Engines) > 0 {
+		return []string{"engines requested explicitly"}
+	}
+	return []string{"no other strategy matched"}
+}
+
 func (s *AutoRoutingStrategy) GetEngines() []string {
 	return []string{"flexsearch", "bm25", "vector"}
 }
@@ -174,6 +216,8 @@ func (s *AutoRoutingStrategy) GetWeights() map[string]float64 {
 
 type RoutingDecision struct {
 	StrategyName string
+	// Reasons are the query signals the strategy matched on.
+	Reasons      []string
 	Engines      []string
 	Weights      map[string]float64
 	QueryInfo    *model.QueryInfo
@@ -186,16 +230,29 @@ func NewRouter(logger *util.Logger) *Router {
 		strategies: make(map[string]RoutingStrategy),
 	}
 	
-	r.strategies["exact_match"] = &ExactMatchStrategy{}
-	r.strategies["fuzzy_search"] = &FuzzySearchStrategy{}
-	r.strategies["semantic_search"] = &SemanticSearchStrategy{}
-	r.strategies["hybrid_search"] = &HybridSearchStrategy{}
-	r.strategies["auto_routing"] = &AutoRoutingStrategy{}
+	for _, strategy := range []RoutingStrategy{
+		&ExactMatchStrategy{},
+		&FuzzySearchStrategy{},
+		&SemanticSearchStrategy{},
+		&HybridSearchStrategy{},
+		&AutoRoutingStrategy{},
+	} {
+		r.strategies[strategy.Name()] = strategy
+		r.order = append(r.order, strategy.Name())
+	}
 	
 	return r
 }
 
 func (r *Router) Route(ctx context.Context, req *model.SearchRequest) *RoutingDecision {
+	return r.RouteForIndexType(ctx, req, "")
+}
+
+// RouteForIndexType is Route for an index of indexType: engines that hold
+// no data for that type are dropped from the decision. When none of the
+// chosen engines is left, the index's own engines are used instead. An
+// empty or unknown indexType leaves the decision unfiltered.
+func (r *Router) RouteForIndexType(ctx context.Context, req *model.SearchRequest, indexType string) *RoutingDecision {
 	queryInfo := r.analyzeQuery(req)
 	
 	var selectedStrategy RoutingStrategy
@@ -203,7 +260,8 @@ func (r *Router) Route(ctx context.Context, req *model.SearchRequest) *RoutingDe
 	if len(req.Engines) > 0 {
 		selectedStrategy = &AutoRoutingStrategy{}
 	} else {
-		for _, strategy := range r.strategies {
+		for _, name := range r.order {
+			strategy := r.strategies[name]
 			if strategy.ShouldRoute(ctx, req) {
 				selectedStrategy = strategy
 				break
@@ -217,12 +275,17 @@ func (r *Router) Route(ctx context.Context, req *model.SearchRequest) *RoutingDe
 	
 	decision := &RoutingDecision{
 		StrategyName: selectedStrategy.Name(),
+		Reasons:      selectedStrategy.MatchReasons(ctx, req),
 		Engines:      selectedStrategy.GetEngines(),
 		Weights:      selectedStrategy.GetWeights(),
 		QueryInfo:    queryInfo,
 		Timestamp:    time.Now(),
 	}
 	
+	if supported, ok := model.IndexTypeEngines(indexType); ok {
+		r.restrictToEngines(decision, req, indexType, supported)
+	}
+	
 	r.logger.Infow("Routing decision made",
 		"query", req.Query,
 		"strategy", decision.StrategyName,
@@ -233,6 +296,62 @@ func (r *Router) Route(ctx context.Context, req *model.SearchRequest) *RoutingDe
 	return decision
 }
 
+// restrictToEngines drops the decision's engines that are not in supported,
+// falling back to supported itself when nothing is left.
+func (r *Router) restrictToEngines(decision *RoutingDecision, req *model.SearchRequest, indexType string, supported []string) {
+	allowed := make(map[string]bool, len(supported))
+	for _, name := range supported {
+		allowed[name] = true
+	}
+	
+	var kept, dropped []string
+	for _, name := range decision.Engines {
+		if allowed[name] {
+			kept = append(kept, name)
+		} else {
+			dropped = append(dropped, name)
+		}
+	}
+	if len(dropped) == 0 {
+		return
+	}
+	
+	reason := fmt.Sprintf("index type %s has no %s data", indexType, strings.Join(dropped, ", "))
+	if len(kept) == 0 {
+		kept = supported
+		reason += fmt.Sprintf("; using %s instead", strings.Join(supported, ", "))
+	}
+	
+	weights := make(map[string]float64, len(kept))
+	for _, name := range kept {
+		if weight, ok := decision.Weights[name]; ok {
+			weights[name] = weight
+		}
+	}
+	
+	r.logger.Infow("Dropped engines the index does not support",
+		"query", req.Query,
+		"index", req.Index,
+		"index_type", indexType,
+		"dropped", dropped,
+		"engines", kept,
+	)
+	decision.Engines = kept
+	decision.Weights = weights
+	decision.Reasons = append(decision.Reasons, reason)
+}
+
+// Explain describes the decision for a response's routing explanation.
+func (d *RoutingDecision) Explain() *model.RoutingExplain {
+	return &model.RoutingExplain{
+		Strategy:  d.StrategyName,
+		Reasons:   d.Reasons,
+		Engines:   d.Engines,
+		Weights:   d.Weights,
+		QueryInfo: d.QueryInfo,
+	}
+}
+
 func (r *Router) analyzeQuery(req *model.SearchRequest) *model.QueryInfo {
 	query := strings.TrimSpace(req.Query)
 	
@@ -261,6 +380,7 @@ func (r *Router) analyzeQuery(req *model.SearchRequest) *model.QueryInfo {
 	
 	queryInfo.HasWildcard = strings.ContainsAny(query, "*?")
 	queryInfo.HasPhrase = strings.Contains(query, "\"")
+	queryInfo.Phrases, _ = model.ParsePhrases(query)
 	queryInfo.HasBoolean = detectBooleanOperators(query)
 	queryInfo.HasSpecial = detectSpecialCharacters(query)
 	
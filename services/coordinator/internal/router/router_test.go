@@ -2,6 +2,7 @@ package router
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/flexsearch/coordinator/internal/model"
@@ -83,6 +84,100 @@ func TestRouter_Route(t *testing.T) {
 	}
 }
 
+func TestRoutingDecisionExplain(t *testing.T) {
+	logger, err := util.NewLogger("error", "json", "stdout")
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	router := NewRouter(logger)
+
+	tests := []struct {
+		name         string
+		req          *model.SearchRequest
+		wantStrategy string
+		wantReason   string
+	}{
+		{"short query", &model.SearchRequest{Query: "golang"}, "exact_match", "query has at most 3 words"},
+		{"long query", &model.SearchRequest{Query: "how does a search engine rank all these pages"}, "semantic_search", "query has at least 4 words"},
+		{"explicit engines", &model.SearchRequest{Query: "golang", Engines: []string{"vector"}}, "auto_routing", "engines requested explicitly"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision := router.Route(context.Background(), tt.req)
+			explain := decision.Explain()
+
+			if explain.Strategy != decision.StrategyName || explain.Strategy != tt.wantStrategy {
+				t.Errorf("Expected strategy %s, got explain %s for decision %s", tt.wantStrategy, explain.Strategy, decision.StrategyName)
+			}
+			if len(explain.Reasons) == 0 || explain.Reasons[0] != tt.wantReason {
+				t.Errorf("Expected reason %q, got %v", tt.wantReason, explain.Reasons)
+			}
+			if strings.Join(explain.Engines, ",") != strings.Join(decision.Engines, ",") {
+				t.Errorf("Expected engines %v, got %v", decision.Engines, explain.Engines)
+			}
+			for engine, weight := range decision.Weights {
+				if explain.Weights[engine] != weight {
+					t.Errorf("Expected weight %v for %s, got %v", weight, engine, explain.Weights[engine])
+				}
+			}
+			if explain.QueryInfo != decision.QueryInfo {
+				t.Error("Expected the explain to carry the decision's query info")
+			}
+		})
+	}
+}
+
+func TestRouteForIndexType(t *testing.T) {
+	logger, err := util.NewLogger("error", "json", "stdout")
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	router := NewRouter(logger)
+	semantic := &model.SearchRequest{Query: "how does a search engine rank all these pages", Index: "articles"}
+
+	decision := router.RouteForIndexType(context.Background(), semantic, model.IndexTypeKeyword)
+	if decision.StrategyName != "semantic_search" {
+		t.Fatalf("Expected the semantic strategy, got %s", decision.StrategyName)
+	}
+	if strings.Join(decision.Engines, ",") != "bm25" {
+		t.Errorf("Expected a vector-less index to fall back to bm25, got %v", decision.Engines)
+	}
+	if _, ok := decision.Weights["vector"]; ok {
+		t.Errorf("Expected the dropped engine's weight to go too, got %v", decision.Weights)
+	}
+	if last := decision.Reasons[len(decision.Reasons)-1]; !strings.Contains(last, "has no vector data") {
+		t.Errorf("Expected the reasons to record the dropped engine, got %v", decision.Reasons)
+	}
+
+	hybrid := &model.SearchRequest{Query: "golang", Engines: []string{"vector"}}
+	decision = router.RouteForIndexType(context.Background(), hybrid, model.IndexTypeFullText)
+	if strings.Join(decision.Engines, ",") != "flexsearch,bm25" {
+		t.Errorf("Expected only the full-text engines to be kept, got %v", decision.Engines)
+	}
+
+	decision = router.RouteForIndexType(context.Background(), semantic, "")
+	if strings.Join(decision.Engines, ",") != "vector" {
+		t.Errorf("Expected an unknown index type to leave the engines alone, got %v", decision.Engines)
+	}
+}
+
+func TestStrategiesRouteOnlyWithReasons(t *testing.T) {
+	router := NewRouter(nil)
+	queries := []string{"", "go", "\"exact phrase\" and more words", "fuzzzzy serch*", "what is the answer", "one two three four five six seven"}
+
+	for name, strategy := range router.strategies {
+		for _, q := range queries {
+			req := &model.SearchRequest{Query: q}
+			routes := strategy.ShouldRoute(context.Background(), req)
+			reasons := strategy.MatchReasons(context.Background(), req)
+			if routes != (len(reasons) > 0) {
+				t.Errorf("%s on %q: ShouldRoute = %v but reasons = %v", name, q, routes, reasons)
+			}
+		}
+	}
+}
+
 func TestOptimizer_Optimize(t *testing.T) {
 	logger, err := util.NewLogger("info", "json", "stdout")
 	if err != nil {
@@ -112,6 +207,11 @@ func TestOptimizer_Optimize(t *testing.T) {
 			query:         "search for data",
 			expectRewrite: true,
 		},
+		{
+			name:          "Boolean query keeps its operators",
+			query:         "the fox AND NOT (dog OR cat)",
+			expectRewrite: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,249 @@
+package server
+
+// The messages below are the wire format of the coordinator services,
+// marshalled by the shared JSON codec. They match the gateway's proto
+// package field for field, JSON tags included.
+
+type SearchRequest struct {
+	Query          string            `json:"query"`
+	Indexes        []string          `json:"indexes"`
+	Page           int32             `json:"page"`
+	PageSize       int32             `json:"page_size"`
+	Filters        map[string]string `json:"filters"`
+	Fields         []string          `json:"fields"`
+	Highlight      bool              `json:"highlight"`
+	SortBy         string            `json:"sort_by"`
+	SortOrder      string            `json:"sort_order"`
+	Explain        bool              `json:"explain"`
+	Facets         []string          `json:"facets"`
+	SearchAfter    string            `json:"search_after"`
+	DryRun         bool              `json:"dry_run"`
+	PerEngineLimit int32             `json:"per_engine_limit"`
+}
+
+type SearchResponse struct {
+	Results        []*SearchResult           `json:"results"`
+	Total          int32                     `json:"total"`
+	Page           int32                     `json:"page"`
+	PageSize       int32                     `json:"page_size"`
+	TotalPages     int32                     `json:"total_pages"`
+	TookMs         float64                   `json:"took_ms"`
+	Degraded       bool                      `json:"degraded"`
+	FailedEngines  []string                  `json:"failed_engines"`
+	Error          string                    `json:"error"`
+	Facets         map[string][]*FacetBucket `json:"facets"`
+	DidYouMean     string                    `json:"did_you_mean"`
+	NextCursor     string                    `json:"next_cursor"`
+	Routing        *RoutingExplain           `json:"routing"`
+	SkippedEngines []string                  `json:"skipped_engines"`
+}
+
+type RoutingExplain struct {
+	Strategy string             `json:"strategy"`
+	Reasons  []string           `json:"reasons"`
+	Engines  []string           `json:"engines"`
+	Weights  map[string]float64 `json:"weights"`
+}
+
+type FacetBucket struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+type MultiSearchRequest struct {
+	Requests []*SearchRequest `json:"requests"`
+}
+
+type MultiSearchResponse struct {
+	Responses []*SearchResponse `json:"responses"`
+}
+
+type SuggestRequest struct {
+	Prefix string `json:"prefix"`
+	Limit  int32  `json:"limit"`
+}
+
+type SuggestResponse struct {
+	Suggestions []string `json:"suggestions"`
+}
+
+type SearchResult struct {
+	Id         string             `json:"id"`
+	Score      float64            `json:"score"`
+	Fields     map[string]string  `json:"fields"`
+	Highlights map[string]string  `json:"highlights"`
+	Explain    map[string]float64 `json:"explain"`
+}
+
+type GetDocumentRequest struct {
+	IndexId    string `json:"index_id"`
+	DocumentId string `json:"document_id"`
+}
+
+type DocumentResponse struct {
+	Id      string            `json:"id"`
+	Fields  map[string]string `json:"fields"`
+	Score   float64           `json:"score"`
+	Success bool              `json:"success"`
+	Error   string            `json:"error,omitempty"`
+	Version int64             `json:"version"`
+}
+
+type GetDocumentsRequest struct {
+	IndexId     string   `json:"index_id"`
+	DocumentIds []string `json:"document_ids"`
+}
+
+type GetDocumentsResponse struct {
+	Documents []*DocumentResponse `json:"documents"`
+}
+
+type AddDocumentRequest struct {
+	IndexId string            `json:"index_id"`
+	Fields  map[string]string `json:"fields"`
+}
+
+type AddDocumentResponse struct {
+	Id      string `json:"id"`
+	Success bool   `json:"success"`
+	Message string `json:"message"`
+}
+
+type UpdateDocumentRequest struct {
+	IndexId    string            `json:"index_id"`
+	DocumentId string            `json:"document_id"`
+	Fields     map[string]string `json:"fields"`
+	Version    int64             `json:"version"`
+}
+
+type UpdateDocumentResponse struct {
+	Success bool   `json:"success"`
+	Message string `json:"message"`
+	Version int64  `json:"version"`
+}
+
+type MergeDocumentRequest struct {
+	IndexId      string            `json:"index_id"`
+	DocumentId   string            `json:"document_id"`
+	Fields       map[string]string `json:"fields"`
+	DeleteFields []string          `json:"delete_fields"`
+	Version      int64             `json:"version"`
+}
+
+type DeleteDocumentRequest struct {
+	IndexId    string `json:"index_id"`
+	DocumentId string `json:"document_id"`
+}
+
+type DeleteDocumentResponse struct {
+	Success bool   `json:"success"`
+	Message string `json:"message"`
+}
+
+type CreateIndexRequest struct {
+	Name      string                   `json:"name"`
+	IndexType string                   `json:"index_type"`
+	Fields    []string                 `json:"fields"`
+	Options   map[string]string        `json:"options"`
+	Mappings  map[string]*FieldMapping `json:"mappings"`
+}
+
+type FieldMapping struct {
+	Type     string  `json:"type"`
+	Analyzer string  `json:"analyzer"`
+	Boost    float64 `json:"boost"`
+}
+
+type CreateIndexResponse struct {
+	Id      string `json:"id"`
+	Success bool   `json:"success"`
+	Message string `json:"message"`
+}
+
+type IndexInfo struct {
+	Id            string `json:"id"`
+	Name          string `json:"name"`
+	IndexType     string `json:"index_type"`
+	DocumentCount int64  `json:"document_count"`
+	SizeBytes     int64  `json:"size_bytes"`
+	CreatedAt     string `json:"created_at"`
+	UpdatedAt     string `json:"updated_at"`
+}
+
+type GetIndexRequest struct {
+	IndexId string `json:"index_id"`
+}
+
+type GetIndexResponse struct {
+	Index *IndexInfo `json:"index"`
+}
+
+type DeleteIndexRequest struct {
+	IndexId string `json:"index_id"`
+}
+
+type DeleteIndexResponse struct {
+	Success bool   `json:"success"`
+	Message string `json:"message"`
+}
+
+type GetTaskStatusRequest struct {
+	TaskId string `json:"task_id"`
+}
+
+type TaskStatusResponse struct {
+	Id         string  `json:"id"`
+	Type       string  `json:"type"`
+	State      string  `json:"state"`
+	Percent    float64 `json:"percent"`
+	Error      string  `json:"error"`
+	StartedAt  string  `json:"started_at"`
+	FinishedAt string  `json:"finished_at"`
+}
+
+type GetIndexStatsRequest struct {
+	IndexId string `json:"index_id"`
+}
+
+type EngineIndexStats struct {
+	Engine        string `json:"engine"`
+	DocumentCount int64  `json:"document_count"`
+	IndexSize     int64  `json:"index_size"`
+	LastUpdated   string `json:"last_updated"`
+}
+
+type IndexStatsResponse struct {
+	IndexId       string              `json:"index_id"`
+	DocumentCount int64               `json:"document_count"`
+	IndexSize     int64               `json:"index_size"`
+	LastUpdated   string              `json:"last_updated"`
+	Engines       []*EngineIndexStats `json:"engines"`
+	Partial       bool                `json:"partial"`
+	Notes         []string            `json:"notes"`
+}
+
+type ReconcileIndexRequest struct {
+	IndexId string `json:"index_id"`
+	Rebuild bool   `json:"rebuild"`
+}
+
+type ReconcileReport struct {
+	IndexId       string           `json:"index_id"`
+	Expected      int64            `json:"expected"`
+	Counts        map[string]int64 `json:"counts"`
+	Consistent    bool             `json:"consistent"`
+	Mismatched    []string         `json:"mismatched"`
+	Unreachable   []string         `json:"unreachable"`
+	RebuildTaskId string           `json:"rebuild_task_id"`
+}
+
+type HealthCheckRequest struct {
+	Service string `json:"service"`
+}
+
+type HealthCheckResponse struct {
+	Status        string            `json:"status"`
+	Version       string            `json:"version"`
+	UptimeSeconds int64             `json:"uptime_seconds"`
+	Details       map[string]string `json:"details"`
+}
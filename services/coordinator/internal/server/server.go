@@ -0,0 +1,517 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/flexsearch/coordinator/internal/model"
+	"github.com/flexsearch/coordinator/internal/service"
+	"github.com/flexsearch/coordinator/internal/task"
+	"github.com/flexsearch/coordinator/internal/util"
+	// The services speak the codec the gateway dials with.
+	_ "github.com/flexsearch/shared/codec"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// requestIDMetadataKey and tenantMetadataKey are the metadata the
+	// gateway forwards with every call.
+	requestIDMetadataKey = "x-request-id"
+	tenantMetadataKey    = "x-tenant-id"
+)
+
+// defaultPageSize applies when a search gives no page size, as the
+// gateway's model.DefaultPageSize does for its GET route.
+const defaultPageSize = 10
+
+// CoordinatorServer serves the coordinator's search, document, index and
+// health services over gRPC, translating the wire messages into calls on
+// the services.
+type CoordinatorServer struct {
+	logger    *util.Logger
+	search    *service.SearchService
+	documents *service.DocumentService
+	indexes   *service.IndexService
+	tasks     *task.Registry
+	started   time.Time
+}
+
+type CoordinatorServerConfig struct {
+	Logger *util.Logger
+	Search *service.SearchService
+	// Documents and Indexes, when nil, leave their services unregistered.
+	Documents *service.DocumentService
+	Indexes   *service.IndexService
+	// Tasks answers GetTaskStatus; every task is unknown when nil.
+	Tasks *task.Registry
+}
+
+func NewCoordinatorServer(cfg *CoordinatorServerConfig) *CoordinatorServer {
+	return &CoordinatorServer{
+		logger:    cfg.Logger,
+		search:    cfg.Search,
+		documents: cfg.Documents,
+		indexes:   cfg.Indexes,
+		tasks:     cfg.Tasks,
+		started:   time.Now(),
+	}
+}
+
+// Register adds the coordinator services to server.
+func (s *CoordinatorServer) Register(server *grpc.Server) {
+	server.RegisterService(&searchServiceDesc, s)
+	server.RegisterService(&healthServiceDesc, s)
+	if s.documents != nil {
+		server.RegisterService(&documentServiceDesc, s)
+	}
+	if s.indexes != nil {
+		server.RegisterService(&indexServiceDesc, s)
+	}
+}
+
+func (s *CoordinatorServer) Search(ctx context.Context, req *SearchRequest) (*SearchResponse, error) {
+	searchReq, err := searchRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.search.Search(ctx, searchReq)
+	if err != nil {
+		return nil, err
+	}
+	return searchResponse(req, resp), nil
+}
+
+func (s *CoordinatorServer) MultiSearch(ctx context.Context, req *MultiSearchRequest) (*MultiSearchResponse, error) {
+	reqs := make([]*model.SearchRequest, len(req.Requests))
+	for i, r := range req.Requests {
+		searchReq, err := searchRequest(ctx, r)
+		if err != nil {
+			return nil, fmt.Errorf("request %d: %w", i, err)
+		}
+		reqs[i] = searchReq
+	}
+
+	responses, err := s.search.MultiSearch(ctx, reqs)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &MultiSearchResponse{Responses: make([]*SearchResponse, len(responses))}
+	for i, resp := range responses {
+		out.Responses[i] = searchResponse(req.Requests[i], resp)
+	}
+	return out, nil
+}
+
+func (s *CoordinatorServer) Suggest(ctx context.Context, req *SuggestRequest) (*SuggestResponse, error) {
+	suggestions, err := s.search.Suggest(ctx, req.Prefix, int(req.Limit))
+	if err != nil {
+		return nil, err
+	}
+	return &SuggestResponse{Suggestions: suggestions}, nil
+}
+
+// GetDocument fails with NotFound when the document is not stored.
+func (s *CoordinatorServer) GetDocument(ctx context.Context, req *GetDocumentRequest) (*DocumentResponse, error) {
+	docs, err := s.documents.GetDocuments(ctx, req.IndexId, []string{req.DocumentId})
+	if err != nil {
+		return nil, err
+	}
+	if !docs[0].Success {
+		return nil, util.NewAppError(404, "Document not found",
+			fmt.Sprintf("document %s does not exist in index %s: %s", req.DocumentId, req.IndexId, docs[0].Error))
+	}
+	return documentResponse(docs[0]), nil
+}
+
+func (s *CoordinatorServer) GetDocuments(ctx context.Context, req *GetDocumentsRequest) (*GetDocumentsResponse, error) {
+	docs, err := s.documents.GetDocuments(ctx, req.IndexId, req.DocumentIds)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &GetDocumentsResponse{Documents: make([]*DocumentResponse, len(docs))}
+	for i, doc := range docs {
+		out.Documents[i] = documentResponse(doc)
+	}
+	return out, nil
+}
+
+// AddDocument takes the ID from the "id" field, generating one when it is
+// missing.
+func (s *CoordinatorServer) AddDocument(ctx context.Context, req *AddDocumentRequest) (*AddDocumentResponse, error) {
+	doc := documentRequest(req.IndexId, req.Fields["id"], req.Fields)
+	if doc.ID == "" {
+		doc.ID = newDocumentID()
+	}
+
+	resp, err := s.documents.AddDocument(ctx, doc)
+	if err != nil {
+		return nil, err
+	}
+	return &AddDocumentResponse{Id: resp.ID, Success: resp.Success, Message: "document added"}, nil
+}
+
+func (s *CoordinatorServer) UpdateDocument(ctx context.Context, req *UpdateDocumentRequest) (*UpdateDocumentResponse, error) {
+	doc := documentRequest(req.IndexId, req.DocumentId, req.Fields)
+	doc.Version = req.Version
+
+	resp, err := s.documents.AddDocument(ctx, doc)
+	if err != nil {
+		return nil, err
+	}
+	return &UpdateDocumentResponse{Success: resp.Success, Message: "document updated", Version: resp.Version}, nil
+}
+
+func (s *CoordinatorServer) MergeDocument(ctx context.Context, req *MergeDocumentRequest) (*DocumentResponse, error) {
+	fields := make(map[string]interface{}, len(req.Fields)+len(req.DeleteFields))
+	for name, value := range req.Fields {
+		fields[name] = value
+	}
+	for _, name := range req.DeleteFields {
+		fields[name] = nil
+	}
+
+	resp, err := s.documents.MergeDocument(ctx, &model.MergeDocumentRequest{
+		ID:      req.DocumentId,
+		Index:   req.IndexId,
+		Fields:  fields,
+		Version: req.Version,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return documentResponse(resp), nil
+}
+
+func (s *CoordinatorServer) DeleteDocument(ctx context.Context, req *DeleteDocumentRequest) (*DeleteDocumentResponse, error) {
+	resp, err := s.documents.DeleteDocument(ctx, &model.DeleteRequest{ID: req.DocumentId, Index: req.IndexId})
+	if err != nil {
+		return nil, err
+	}
+	return &DeleteDocumentResponse{Success: resp.Success, Message: "document deleted"}, nil
+}
+
+func (s *CoordinatorServer) CreateIndex(ctx context.Context, req *CreateIndexRequest) (*CreateIndexResponse, error) {
+	mappings := make(map[string]model.FieldMapping, len(req.Mappings))
+	for field, mapping := range req.Mappings {
+		if mapping != nil {
+			mappings[field] = model.FieldMapping{Type: mapping.Type, Analyzer: mapping.Analyzer, Boost: mapping.Boost}
+		}
+	}
+
+	resp, err := s.indexes.CreateIndex(ctx, &model.IndexRequest{
+		Name:     req.Name,
+		Type:     req.IndexType,
+		Mappings: mappings,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &CreateIndexResponse{Id: resp.Name, Success: resp.Success, Message: "index created"}, nil
+}
+
+func (s *CoordinatorServer) GetIndex(ctx context.Context, req *GetIndexRequest) (*GetIndexResponse, error) {
+	meta, err := s.indexes.GetIndex(ctx, req.IndexId)
+	if err != nil {
+		return nil, err
+	}
+	return &GetIndexResponse{Index: &IndexInfo{
+		Id:        meta.Name,
+		Name:      meta.Name,
+		IndexType: meta.Type,
+		CreatedAt: meta.CreatedAt.Format(time.RFC3339),
+	}}, nil
+}
+
+func (s *CoordinatorServer) DeleteIndex(ctx context.Context, req *DeleteIndexRequest) (*DeleteIndexResponse, error) {
+	if err := s.indexes.DeleteIndex(ctx, req.IndexId); err != nil {
+		return nil, err
+	}
+	return &DeleteIndexResponse{Success: true, Message: "index deleted"}, nil
+}
+
+func (s *CoordinatorServer) GetTaskStatus(ctx context.Context, req *GetTaskStatusRequest) (*TaskStatusResponse, error) {
+	if s.tasks == nil {
+		return nil, util.NewAppError(404, "Task not found", fmt.Sprintf("no task with id %s", req.TaskId))
+	}
+
+	status, err := s.tasks.GetTaskStatus(ctx, req.TaskId)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &TaskStatusResponse{
+		Id:        status.ID,
+		Type:      status.Type,
+		State:     status.State,
+		Percent:   status.Percent,
+		Error:     status.Error,
+		StartedAt: status.StartedAt.Format(time.RFC3339),
+	}
+	if status.FinishedAt != nil {
+		resp.FinishedAt = status.FinishedAt.Format(time.RFC3339)
+	}
+	return resp, nil
+}
+
+func (s *CoordinatorServer) GetIndexStats(ctx context.Context, req *GetIndexStatsRequest) (*IndexStatsResponse, error) {
+	stats, err := s.indexes.GetIndexStats(ctx, req.IndexId)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &IndexStatsResponse{
+		IndexId:       stats.Index,
+		DocumentCount: stats.DocumentCount,
+		IndexSize:     stats.IndexSize,
+		LastUpdated:   stats.LastUpdated,
+		Partial:       stats.Partial,
+		Notes:         stats.Notes,
+	}
+	for _, engineStats := range stats.Engines {
+		resp.Engines = append(resp.Engines, &EngineIndexStats{
+			Engine:        engineStats.Engine,
+			DocumentCount: engineStats.DocumentCount,
+			IndexSize:     engineStats.IndexSize,
+			LastUpdated:   engineStats.LastUpdated,
+		})
+	}
+	return resp, nil
+}
+
+func (s *CoordinatorServer) ReconcileIndex(ctx context.Context, req *ReconcileIndexRequest) (*ReconcileReport, error) {
+	report, err := s.indexes.ReconcileIndex(ctx, req.IndexId, req.Rebuild)
+	if err != nil {
+		return nil, err
+	}
+	return &ReconcileReport{
+		IndexId:       report.Index,
+		Expected:      report.Expected,
+		Counts:        report.Counts,
+		Consistent:    report.Consistent,
+		Mismatched:    report.Mismatched,
+		Unreachable:   report.Unreachable,
+		RebuildTaskId: report.RebuildTaskID,
+	}, nil
+}
+
+// Check reports healthy when every engine answers, degraded when only some
+// do and unhealthy when none do. Details holds each engine's status under
+// its name, and its breaker state and failure count under name.circuit_breaker
+// and name.failures.
+func (s *CoordinatorServer) Check(ctx context.Context, req *HealthCheckRequest) (*HealthCheckResponse, error) {
+	engines := s.search.HealthCheck(ctx)
+
+	resp := &HealthCheckResponse{
+		Status:        "unhealthy",
+		UptimeSeconds: int64(time.Since(s.started).Seconds()),
+		Details:       make(map[string]string, len(engines)),
+	}
+	healthy := 0
+	for _, engine := range engines {
+		resp.Details[engine.Name] = engine.Status
+		resp.Details[engine.Name+".circuit_breaker"] = engine.CircuitBreaker
+		resp.Details[engine.Name+".failures"] = strconv.Itoa(engine.FailureCount)
+		if engine.Status == "healthy" {
+			healthy++
+		}
+	}
+	switch {
+	case healthy > 0 && healthy == len(engines):
+		resp.Status = "healthy"
+	case healthy > 0:
+		resp.Status = "degraded"
+	}
+	return resp, nil
+}
+
+// searchRequest translates req, taking the request ID and tenant from the
+// call's metadata. Page and PageSize become Offset and Limit. Only one
+// index can be searched, so naming more is an InvalidArgument AppError.
+func searchRequest(ctx context.Context, req *SearchRequest) (*model.SearchRequest, error) {
+	if len(req.Indexes) > 1 {
+		return nil, util.NewAppError(400, "Only one index can be searched",
+			fmt.Sprintf("%d indexes were named", len(req.Indexes)))
+	}
+
+	page, pageSize := effectivePage(req)
+	out := &model.SearchRequest{
+		Query:          req.Query,
+		Indexes:        req.Indexes,
+		Filters:        req.Filters,
+		Facets:         req.Facets,
+		SortBy:         req.SortBy,
+		SortOrder:      req.SortOrder,
+		Highlight:      req.Highlight,
+		SearchAfter:    req.SearchAfter,
+		Explain:        req.Explain,
+		DryRun:         req.DryRun,
+		PerEngineLimit: req.PerEngineLimit,
+		RequestID:      incomingMetadata(ctx, requestIDMetadataKey),
+		Tenant:         incomingMetadata(ctx, tenantMetadataKey),
+		Limit:          pageSize,
+		Offset:         (page - 1) * pageSize,
+	}
+	if len(req.Indexes) > 0 {
+		out.Index = req.Indexes[0]
+	}
+	return out, nil
+}
+
+// effectivePage returns the page and page size req is served with: the
+// first page and defaultPageSize when unset.
+func effectivePage(req *SearchRequest) (page, pageSize int32) {
+	page, pageSize = req.Page, req.PageSize
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = defaultPageSize
+	}
+	return page, pageSize
+}
+
+func searchResponse(req *SearchRequest, resp *model.SearchResponse) *SearchResponse {
+	page, pageSize := effectivePage(req)
+	out := &SearchResponse{
+		Results:        make([]*SearchResult, len(resp.Results)),
+		Total:          int32(resp.Total),
+		Page:           page,
+		PageSize:       pageSize,
+		TotalPages:     int32((resp.Total + int64(pageSize) - 1) / int64(pageSize)),
+		TookMs:         resp.Took,
+		Degraded:       resp.Degraded,
+		FailedEngines:  resp.FailedEngines,
+		Error:          resp.Error,
+		DidYouMean:     resp.DidYouMean,
+		NextCursor:     resp.NextCursor,
+		SkippedEngines: resp.SkippedEngines,
+	}
+
+	for i, result := range resp.Results {
+		fields := make(map[string]interface{}, len(result.Fields)+2)
+		for name, value := range result.Fields {
+			fields[name] = value
+		}
+		if result.Title != "" {
+			fields["title"] = result.Title
+		}
+		if result.Content != "" {
+			fields["content"] = result.Content
+		}
+		out.Results[i] = &SearchResult{
+			Id:         result.ID,
+			Score:      result.Score,
+			Fields:     stringFields(fields, req.Fields),
+			Highlights: result.Highlight,
+			Explain:    result.Explain,
+		}
+	}
+
+	if len(resp.Facets) > 0 {
+		out.Facets = make(map[string][]*FacetBucket, len(resp.Facets))
+		for field, buckets := range resp.Facets {
+			for _, bucket := range buckets {
+				out.Facets[field] = append(out.Facets[field], &FacetBucket{Value: bucket.Value, Count: bucket.Count})
+			}
+		}
+	}
+	if resp.Routing != nil {
+		out.Routing = &RoutingExplain{
+			Strategy: resp.Routing.Strategy,
+			Reasons:  resp.Routing.Reasons,
+			Engines:  resp.Routing.Engines,
+			Weights:  resp.Routing.Weights,
+		}
+	}
+	return out
+}
+
+// documentRequest builds the document for fields, lifting title and
+// content out of them.
+func documentRequest(index, id string, fields map[string]string) *model.DocumentRequest {
+	doc := &model.DocumentRequest{ID: id, Index: index}
+	for name, value := range fields {
+		switch name {
+		case "id":
+		case "title":
+			doc.Title = value
+		case "content":
+			doc.Content = value
+		default:
+			if doc.Fields == nil {
+				doc.Fields = make(map[string]interface{})
+			}
+			doc.Fields[name] = value
+		}
+	}
+	return doc
+}
+
+func documentResponse(doc *model.DocumentResponse) *DocumentResponse {
+	return &DocumentResponse{
+		Id:      doc.ID,
+		Fields:  stringFields(doc.Fields, nil),
+		Success: doc.Success,
+		Error:   doc.Error,
+		Version: doc.Version,
+	}
+}
+
+// stringFields flattens fields to the string values the wire carries,
+// encoding non-string values as JSON. When only is non-empty, just the
+// fields it names are kept.
+func stringFields(fields map[string]interface{}, only []string) map[string]string {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	out := make(map[string]string, len(fields))
+	for name, value := range fields {
+		switch v := value.(type) {
+		case string:
+			out[name] = v
+		default:
+			encoded, err := json.Marshal(v)
+			if err != nil {
+				encoded = []byte(fmt.Sprint(v))
+			}
+			out[name] = string(encoded)
+		}
+	}
+
+	if len(only) > 0 {
+		kept := make(map[string]string, len(only))
+		for _, name := range only {
+			if value, ok := out[name]; ok {
+				kept[name] = value
+			}
+		}
+		out = kept
+	}
+	return out
+}
+
+func incomingMetadata(ctx context.Context, key string) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func newDocumentID() string {
+	id := make([]byte, 16)
+	rand.Read(id)
+	return hex.EncodeToString(id)
+}
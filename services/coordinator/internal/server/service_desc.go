@@ -0,0 +1,109 @@
+package server
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+// The service descriptors are written by hand, like the gateway's client
+// stubs, since the messages are not generated. Methods of the proto that
+// are not listed here answer Unimplemented.
+
+type SearchServer interface {
+	Search(context.Context, *SearchRequest) (*SearchResponse, error)
+	MultiSearch(context.Context, *MultiSearchRequest) (*MultiSearchResponse, error)
+	Suggest(context.Context, *SuggestRequest) (*SuggestResponse, error)
+}
+
+type DocumentServer interface {
+	GetDocument(context.Context, *GetDocumentRequest) (*DocumentResponse, error)
+	GetDocuments(context.Context, *GetDocumentsRequest) (*GetDocumentsResponse, error)
+	AddDocument(context.Context, *AddDocumentRequest) (*AddDocumentResponse, error)
+	UpdateDocument(context.Context, *UpdateDocumentRequest) (*UpdateDocumentResponse, error)
+	MergeDocument(context.Context, *MergeDocumentRequest) (*DocumentResponse, error)
+	DeleteDocument(context.Context, *DeleteDocumentRequest) (*DeleteDocumentResponse, error)
+}
+
+type IndexServer interface {
+	CreateIndex(context.Context, *CreateIndexRequest) (*CreateIndexResponse, error)
+	GetIndex(context.Context, *GetIndexRequest) (*GetIndexResponse, error)
+	DeleteIndex(context.Context, *DeleteIndexRequest) (*DeleteIndexResponse, error)
+	GetTaskStatus(context.Context, *GetTaskStatusRequest) (*TaskStatusResponse, error)
+	GetIndexStats(context.Context, *GetIndexStatsRequest) (*IndexStatsResponse, error)
+	ReconcileIndex(context.Context, *ReconcileIndexRequest) (*ReconcileReport, error)
+}
+
+type HealthServer interface {
+	Check(context.Context, *HealthCheckRequest) (*HealthCheckResponse, error)
+}
+
+var searchServiceDesc = grpc.ServiceDesc{
+	ServiceName: "coordinator.SearchService",
+	HandlerType: (*SearchServer)(nil),
+	Methods: []grpc.MethodDesc{
+		unary("coordinator.SearchService", "Search", SearchServer.Search),
+		unary("coordinator.SearchService", "MultiSearch", SearchServer.MultiSearch),
+		unary("coordinator.SearchService", "Suggest", SearchServer.Suggest),
+	},
+}
+
+var documentServiceDesc = grpc.ServiceDesc{
+	ServiceName: "coordinator.DocumentService",
+	HandlerType: (*DocumentServer)(nil),
+	Methods: []grpc.MethodDesc{
+		unary("coordinator.DocumentService", "GetDocument", DocumentServer.GetDocument),
+		unary("coordinator.DocumentService", "GetDocuments", DocumentServer.GetDocuments),
+		unary("coordinator.DocumentService", "AddDocument", DocumentServer.AddDocument),
+		unary("coordinator.DocumentService", "UpdateDocument", DocumentServer.UpdateDocument),
+		unary("coordinator.DocumentService", "MergeDocument", DocumentServer.MergeDocument),
+		unary("coordinator.DocumentService", "DeleteDocument", DocumentServer.DeleteDocument),
+	},
+}
+
+var indexServiceDesc = grpc.ServiceDesc{
+	ServiceName: "coordinator.IndexService",
+	HandlerType: (*IndexServer)(nil),
+	Methods: []grpc.MethodDesc{
+		unary("coordinator.IndexService", "CreateIndex", IndexServer.CreateIndex),
+		unary("coordinator.IndexService", "GetIndex", IndexServer.GetIndex),
+		unary("coordinator.IndexService", "DeleteIndex", IndexServer.DeleteIndex),
+		unary("coordinator.IndexService", "GetTaskStatus", IndexServer.GetTaskStatus),
+		unary("coordinator.IndexService", "GetIndexStats", IndexServer.GetIndexStats),
+		unary("coordinator.IndexService", "ReconcileIndex", IndexServer.ReconcileIndex),
+	},
+}
+
+var healthServiceDesc = grpc.ServiceDesc{
+	ServiceName: "coordinator.Health",
+	HandlerType: (*HealthServer)(nil),
+	Methods: []grpc.MethodDesc{
+		unary("coordinator.Health", "Check", HealthServer.Check),
+	},
+}
+
+// unary builds the method descriptor that decodes a Req, runs it through
+// the server's interceptors and hands it to call on the registered
+// implementation S.
+func unary[S any, Req any, Resp any](service, method string, call func(S, context.Context, *Req) (*Resp, error)) grpc.MethodDesc {
+	return grpc.MethodDesc{
+		MethodName: method,
+		Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+			in := new(Req)
+			if err := dec(in); err != nil {
+				return nil, err
+			}
+			if interceptor == nil {
+				return call(srv.(S), ctx, in)
+			}
+			info := &grpc.UnaryServerInfo{
+				Server:     srv,
+				FullMethod: "/" + service + "/" + method,
+			}
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				return call(srv.(S), ctx, req.(*Req))
+			}
+			return interceptor(ctx, in, info, handler)
+		},
+	}
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/flexsearch/coordinator/internal/model"
+	"github.com/flexsearch/coordinator/internal/util"
+)
+
+const (
+	// MaxBulkChunkSize caps the documents in one streamed chunk.
+	MaxBulkChunkSize = 500
+
+	bulkWriteConcurrency = 8
+)
+
+// DocumentStream is the receiving side of a client-streaming bulk ingest.
+// Recv returns io.EOF once the client has sent every chunk.
+type DocumentStream interface {
+	Context() context.Context
+	Recv() (*model.BulkDocumentRequest, error)
+}
+
+// BulkAddDocuments writes every document of req, at most
+// bulkWriteConcurrency at a time. Failed documents are counted and reported
+// rather than failing the whole request.
+func (s *DocumentService) BulkAddDocuments(ctx context.Context, req *model.BulkDocumentRequest) *model.BulkDocumentResponse {
+	response := &model.BulkDocumentResponse{
+		Index:   req.Index,
+		Total:   len(req.Documents),
+		Results: make([]model.DocumentResponse, len(req.Documents)),
+	}
+
+	sem := make(chan struct{}, bulkWriteConcurrency)
+	var wg sync.WaitGroup
+	for i := range req.Documents {
+		doc := req.Documents[i]
+		doc.Index = req.Index
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, doc *model.DocumentRequest) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			result := model.DocumentResponse{ID: doc.ID, Index: doc.Index}
+			if doc.ID == "" {
+				result.Error = "missing document id"
+			} else if resp, err := s.AddDocument(ctx, doc); err != nil {
+				result.Error = err.Error()
+			} else {
+				result = *resp
+			}
+			response.Results[i] = result
+		}(i, &doc)
+	}
+	wg.Wait()
+
+	for i, result := range response.Results {
+		if result.Success {
+			response.Successful++
+			continue
+		}
+		response.Failed++
+		response.Errors = append(response.Errors, fmt.Sprintf("document %d (%s): %s", i, result.ID, result.Error))
+	}
+	response.Success = response.Failed == 0
+	return response
+}
+
+// BatchDocumentsStream ingests chunks from stream until the client closes
+// it. Each chunk is written before the next is received, so a slow backend
+// pushes back on the sender through gRPC flow control.
+func (s *DocumentService) BatchDocumentsStream(stream DocumentStream) (*model.BulkStreamResponse, error) {
+	ctx := stream.Context()
+	response := &model.BulkStreamResponse{}
+
+	for chunk := 0; ; chunk++ {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return response, nil
+		}
+		if err != nil {
+			return response, err
+		}
+
+		if len(req.Documents) > MaxBulkChunkSize {
+			return response, util.NewAppError(400, "Bulk chunk too large",
+				fmt.Sprintf("chunk %d has %d documents, the maximum is %d", chunk, len(req.Documents), MaxBulkChunkSize))
+		}
+		if response.Index == "" {
+			response.Index = req.Index
+		}
+
+		result := s.BulkAddDocuments(ctx, req)
+		response.Total += result.Total
+		response.Successful += result.Successful
+		response.Failed += result.Failed
+		response.Chunks = append(response.Chunks, model.BulkChunkAck{
+			Chunk:      chunk,
+			Successful: result.Successful,
+			Failed:     result.Failed,
+			Errors:     result.Errors,
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/flexsearch/coordinator/internal/model"
+)
+
+type fakeDocumentStream struct {
+	ctx    context.Context
+	chunks []*model.BulkDocumentRequest
+	recvs  int
+}
+
+func (f *fakeDocumentStream) Context() context.Context { return f.ctx }
+
+func (f *fakeDocumentStream) Recv() (*model.BulkDocumentRequest, error) {
+	if f.recvs == len(f.chunks) {
+		return nil, io.EOF
+	}
+	chunk := f.chunks[f.recvs]
+	f.recvs++
+	return chunk, nil
+}
+
+func bulkChunk(index string, ids ...string) *model.BulkDocumentRequest {
+	chunk := &model.BulkDocumentRequest{Index: index}
+	for _, id := range ids {
+		chunk.Documents = append(chunk.Documents, model.DocumentRequest{ID: id, Title: "doc " + id})
+	}
+	return chunk
+}
+
+func TestDocumentServiceBatchDocumentsStream(t *testing.T) {
+	engines, fakes := newFakeEngines()
+	svc := NewDocumentService(&DocumentServiceConfig{
+		Logger:     newTestLogger(t),
+		Engines:    engines,
+		IndexTypes: map[string]string{"docs": model.IndexTypeKeyword},
+	})
+
+	stream := &fakeDocumentStream{
+		ctx: context.Background(),
+		chunks: []*model.BulkDocumentRequest{
+			bulkChunk("docs", "1", "2", "3"),
+			bulkChunk("docs", "4", "", "6"),
+			bulkChunk("docs", "7"),
+		},
+	}
+
+	resp, err := svc.BatchDocumentsStream(stream)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if resp.Index != "docs" || resp.Total != 7 || resp.Successful != 6 || resp.Failed != 1 {
+		t.Errorf("Expected 7 total, 6 successful and 1 failed in docs, got %+v", resp)
+	}
+	if len(resp.Chunks) != 3 {
+		t.Fatalf("Expected an ack per chunk, got %d", len(resp.Chunks))
+	}
+	for i, want := range []model.BulkChunkAck{
+		{Chunk: 0, Successful: 3},
+		{Chunk: 1, Successful: 2, Failed: 1},
+		{Chunk: 2, Successful: 1},
+	} {
+		got := resp.Chunks[i]
+		if got.Chunk != want.Chunk || got.Successful != want.Successful || got.Failed != want.Failed {
+			t.Errorf("Expected chunk ack %+v, got %+v", want, got)
+		}
+	}
+	if len(resp.Chunks[1].Errors) != 1 {
+		t.Errorf("Expected the failed document to be reported, got %v", resp.Chunks[1].Errors)
+	}
+	if n := fakes["bm25"].addedCount(); n != 6 {
+		t.Errorf("Expected 6 documents written, got %d", n)
+	}
+
+	docs, _ := svc.GetDocuments(context.Background(), "docs", []string{"7"})
+	if !docs[0].Success {
+		t.Error("Expected streamed documents to be readable")
+	}
+}
+
+func TestDocumentServiceBatchDocumentsStreamChunkTooLarge(t *testing.T) {
+	engines, fakes := newFakeEngines()
+	svc := NewDocumentService(&DocumentServiceConfig{
+		Logger:  newTestLogger(t),
+		Engines: engines,
+	})
+
+	ids := make([]string, MaxBulkChunkSize+1)
+	for i := range ids {
+		ids[i] = fmt.Sprint(i)
+	}
+	stream := &fakeDocumentStream{
+		ctx:    context.Background(),
+		chunks: []*model.BulkDocumentRequest{bulkChunk("docs", "a"), bulkChunk("docs", ids...)},
+	}
+
+	resp, err := svc.BatchDocumentsStream(stream)
+	if err == nil {
+		t.Fatal("Expected an oversized chunk to be rejected")
+	}
+	if resp.Successful != 1 || len(resp.Chunks) != 1 {
+		t.Errorf("Expected the first chunk to be acknowledged before the failure, got %+v", resp)
+	}
+	if n := fakes["bm25"].addedCount(); n != 1 {
+		t.Errorf("Expected only the first chunk to be written, got %d", n)
+	}
+}
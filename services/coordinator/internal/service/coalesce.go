@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"sync"
+
+	"github.com/flexsearch/coordinator/internal/model"
+)
+
+// searchFlight is one execution shared by identical concurrent searches.
+type searchFlight struct {
+	done     chan struct{}
+	response *model.SearchResponse
+	results  map[string]*model.EngineResult
+	err      error
+}
+
+// searchFlights coalesces identical searches that miss the cache, so N
+// concurrent copies of a query fan out to the engines once.
+type searchFlights struct {
+	mu      sync.Mutex
+	flights map[string]*searchFlight
+}
+
+func newSearchFlights() *searchFlights {
+	return &searchFlights{flights: make(map[string]*searchFlight)}
+}
+
+// do runs fn for key unless a run is already in flight, in which case it
+// waits for that run and returns its outcome; shared reports which. fn runs
+// on a context detached from the caller's cancellation, so one caller
+// giving up does not fail the others; that caller alone gets ctx.Err().
+func (g *searchFlights) do(ctx context.Context, key string, fn func(context.Context) (*model.SearchResponse, map[string]*model.EngineResult, error)) (response *model.SearchResponse, results map[string]*model.EngineResult, shared bool, err error) {
+	g.mu.Lock()
+	f, shared := g.flights[key]
+	if !shared {
+		f = &searchFlight{done: make(chan struct{})}
+		g.flights[key] = f
+		go func(ctx context.Context) {
+			defer func() {
+				g.mu.Lock()
+				delete(g.flights, key)
+				g.mu.Unlock()
+				close(f.done)
+			}()
+			f.response, f.results, f.err = fn(ctx)
+		}(context.WithoutCancel(ctx))
+	}
+	g.mu.Unlock()
+
+	select {
+	case <-f.done:
+		return f.response, f.results, shared, f.err
+	case <-ctx.Done():
+		return nil, nil, shared, ctx.Err()
+	}
+}
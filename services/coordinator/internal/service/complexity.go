@@ -0,0 +1,70 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/flexsearch/coordinator/internal/model"
+	"github.com/flexsearch/coordinator/internal/util"
+)
+
+// ComplexityLimits bounds how much work one search may ask of the engines.
+// A zero limit is unlimited.
+type ComplexityLimits struct {
+	// MaxTerms counts query terms, including those of phrases, but not
+	// boolean operators.
+	MaxTerms int
+	// MaxFilters counts filter fields.
+	MaxFilters int
+	// MaxIndexes counts the indexes named in Indexes.
+	MaxIndexes int
+	// MaxWildcards counts terms containing * or ?.
+	MaxWildcards int
+}
+
+// check returns a 400 naming the first dimension of req over its limit.
+func (l ComplexityLimits) check(req *model.SearchRequest) error {
+	terms := queryTerms(req.Query)
+	wildcards := 0
+	for _, term := range terms {
+		if strings.ContainsAny(term, "*?") {
+			wildcards++
+		}
+	}
+
+	for _, d := range []struct {
+		name         string
+		count, limit int
+	}{
+		{"terms", len(terms), l.MaxTerms},
+		{"filters", len(req.Filters), l.MaxFilters},
+		{"indexes", len(req.Indexes), l.MaxIndexes},
+		{"wildcard terms", wildcards, l.MaxWildcards},
+	} {
+		if d.limit > 0 && d.count > d.limit {
+			return util.NewAppError(400, "Query too complex",
+				fmt.Sprintf("query has %d %s; the limit is %d", d.count, d.name, d.limit))
+		}
+	}
+	return nil
+}
+
+// queryTerms splits query into its search terms, dropping quotes,
+// parentheses, proximity suffixes and boolean operators.
+func queryTerms(query string) []string {
+	fields := strings.FieldsFunc(query, func(r rune) bool {
+		return r == ' ' || r == '\t' || r == '\n' || r == '\r' || r == '(' || r == ')' || r == '"'
+	})
+	terms := fields[:0]
+	for _, field := range fields {
+		switch field {
+		case "AND", "OR", "NOT", "&&", "||", "!":
+			continue
+		}
+		if strings.HasPrefix(field, "~") && strings.Trim(field[1:], "0123456789") == "" {
+			continue
+		}
+		terms = append(terms, strings.TrimPrefix(field, "!"))
+	}
+	return terms
+}
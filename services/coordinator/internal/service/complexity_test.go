@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/flexsearch/coordinator/internal/model"
+	"github.com/flexsearch/coordinator/internal/util"
+)
+
+func TestComplexityLimitsAtAndOverEachLimit(t *testing.T) {
+	limits := ComplexityLimits{MaxTerms: 4, MaxFilters: 2, MaxIndexes: 2, MaxWildcards: 1}
+
+	tests := []struct {
+		name    string
+		atLimit model.SearchRequest
+		over    model.SearchRequest
+		detail  string
+	}{
+		{
+			name:    "terms",
+			atLimit: model.SearchRequest{Query: `a AND ("b c" OR NOT d)`},
+			over:    model.SearchRequest{Query: `a AND ("b c" OR NOT d) e`},
+			detail:  "query has 5 terms; the limit is 4",
+		},
+		{
+			name:    "filters",
+			atLimit: model.SearchRequest{Query: "go", Filters: map[string]string{"a": "1", "b": "2"}},
+			over:    model.SearchRequest{Query: "go", Filters: map[string]string{"a": "1", "b": "2", "c": "3"}},
+			detail:  "query has 3 filters; the limit is 2",
+		},
+		{
+			name:    "indexes",
+			atLimit: model.SearchRequest{Query: "go", Indexes: []string{"a", "b"}},
+			over:    model.SearchRequest{Query: "go", Indexes: []string{"a", "b", "c"}},
+			detail:  "query has 3 indexes; the limit is 2",
+		},
+		{
+			name:    "wildcards",
+			atLimit: model.SearchRequest{Query: "go* lang"},
+			over:    model.SearchRequest{Query: "go* l?ng"},
+			detail:  "query has 2 wildcard terms; the limit is 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := limits.check(&tt.atLimit); err != nil {
+				t.Errorf("Expected a query at the limit to pass, got %v", err)
+			}
+			err := limits.check(&tt.over)
+			appErr, ok := err.(*util.AppError)
+			if !ok || appErr.Code != 400 || appErr.Details != tt.detail {
+				t.Errorf("Expected a 400 with %q, got %v", tt.detail, err)
+			}
+		})
+	}
+}
+
+func TestComplexityLimitsZeroIsUnlimited(t *testing.T) {
+	req := &model.SearchRequest{Query: strings.Repeat("w* ", 200), Indexes: make([]string, 50)}
+	if err := (ComplexityLimits{}).check(req); err != nil {
+		t.Errorf("Expected zero limits to allow anything, got %v", err)
+	}
+}
+
+func TestSearchServiceRejectsComplexQueries(t *testing.T) {
+	engines, fakes := newFakeEngines()
+	svc := newTestSearchService(t, engines, func(cfg *SearchServiceConfig) {
+		cfg.Complexity = ComplexityLimits{MaxTerms: 2}
+	})
+
+	_, err := svc.Search(context.Background(), &model.SearchRequest{Query: "one two three", Engines: []string{"bm25"}, Limit: 5})
+	if appErr, ok := err.(*util.AppError); !ok || appErr.Code != 400 {
+		t.Fatalf("Expected a 400 for too many terms, got %v", err)
+	}
+	if n := len(fakes["bm25"].searches); n != 0 {
+		t.Errorf("Expected the rejected query not to reach the engines, got %d searches", n)
+	}
+}
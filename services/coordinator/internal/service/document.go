@@ -0,0 +1,422 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/flexsearch/coordinator/internal/cache"
+	"github.com/flexsearch/coordinator/internal/engine"
+	"github.com/flexsearch/coordinator/internal/model"
+	"github.com/flexsearch/coordinator/internal/suggest"
+	"github.com/flexsearch/coordinator/internal/util"
+	sharedcache "github.com/flexsearch/shared/cache"
+)
+
+// MaxGetDocuments caps the IDs fetched by one GetDocuments call.
+const MaxGetDocuments = 100
+
+const documentLockStripes = 64
+
+type DocumentService struct {
+	logger      *util.Logger
+	engines     map[string]engine.EngineClient
+	invalidator *sharedcache.CacheInvalidator
+	cache       *cache.RedisCache
+	localCache  *cache.LRUCache
+	bus         *cache.InvalidationBus
+	indexTypes  map[string]string
+	suggestions *suggest.Trie
+	store       DocumentStore
+	mu          sync.RWMutex
+	docLocks    [documentLockStripes]sync.Mutex
+}
+
+type DocumentServiceConfig struct {
+	Logger     *util.Logger
+	Engines    map[string]engine.EngineClient
+	IndexTypes map[string]string
+	// Cache, when set, drops the written index's cached searches through
+	// its index tags, local tier included.
+	Cache *cache.RedisCache
+	// Invalidator, when set, also applies InvalidationRules to the index's
+	// keys after a write. It finds them with KEYS, so it is only worth
+	// configuring for rules that do more than delete.
+	Invalidator       *sharedcache.CacheInvalidator
+	InvalidationRules []sharedcache.InvalidationRule
+	// Suggestions, when set, receives the titles of added documents.
+	Suggestions *suggest.Trie
+	// Store keeps document sources for reads; an in-memory store is used
+	// when nil.
+	Store DocumentStore
+	// LocalCache is the in-process tier of the search cache. The
+	// invalidator only reaches Redis, so writes drop the index from it too.
+	LocalCache *cache.LRUCache
+	// Bus, when set, tells other coordinator instances to drop the index
+	// from their local tiers after a write.
+	Bus *cache.InvalidationBus
+}
+
+func NewDocumentService(cfg *DocumentServiceConfig) *DocumentService {
+	indexTypes := make(map[string]string, len(cfg.IndexTypes))
+	for name, indexType := range cfg.IndexTypes {
+		indexTypes[name] = indexType
+	}
+
+	if cfg.Invalidator != nil && len(cfg.InvalidationRules) > 0 {
+		cfg.Invalidator.AddRules(cfg.InvalidationRules)
+	}
+
+	store := cfg.Store
+	if store == nil {
+		store = NewMemoryDocumentStore()
+	}
+
+	return &DocumentService{
+		logger:      cfg.Logger,
+		engines:     cfg.Engines,
+		invalidator: cfg.Invalidator,
+		cache:       cfg.Cache,
+		localCache:  cfg.LocalCache,
+		bus:         cfg.Bus,
+		indexTypes:  indexTypes,
+		suggestions: cfg.Suggestions,
+		store:       store,
+	}
+}
+
+func (s *DocumentService) RegisterIndex(name, indexType string) error {
+	if _, ok := model.IndexTypeEngines(indexType); !ok {
+		return fmt.Errorf("unknown index type %q", indexType)
+	}
+
+	s.mu.Lock()
+	s.indexTypes[name] = indexType
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *DocumentService) UnregisterIndex(name string) {
+	s.mu.Lock()
+	delete(s.indexTypes, name)
+	s.mu.Unlock()
+}
+
+// IndexType returns the registered type of index.
+func (s *DocumentService) IndexType(index string) (string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	indexType, ok := s.indexTypes[index]
+	return indexType, ok
+}
+
+// EnginesForIndex returns the configured engines that hold data for the
+// given index. Indexes without a registered type are written to every engine.
+func (s *DocumentService) EnginesForIndex(index string) []string {
+	s.mu.RLock()
+	indexType, ok := s.indexTypes[index]
+	s.mu.RUnlock()
+
+	var candidates []string
+	if ok {
+		candidates, _ = model.IndexTypeEngines(indexType)
+	} else {
+		for name := range s.engines {
+			candidates = append(candidates, name)
+		}
+		sort.Strings(candidates)
+	}
+
+	engines := make([]string, 0, len(candidates))
+	for _, name := range candidates {
+		if _, exists := s.engines[name]; exists {
+			engines = append(engines, name)
+		}
+	}
+	return engines
+}
+
+// AddDocument writes doc to its engines. A non-zero doc.Version must match
+// the stored version, or the write is rejected with ErrVersionConflict.
+func (s *DocumentService) AddDocument(ctx context.Context, doc *model.DocumentRequest) (*model.DocumentResponse, error) {
+	unlock := s.lockDocument(doc.Index, doc.ID)
+	defer unlock()
+
+	current, _, err := s.store.Get(ctx, doc.Index, doc.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkVersion(doc.Version, current); err != nil {
+		return nil, err
+	}
+
+	return s.write(ctx, doc, current)
+}
+
+// MergeDocument applies req.Fields on top of the stored document and writes
+// the result to the engines. It fails with a 404 AppError when the document
+// does not exist.
+func (s *DocumentService) MergeDocument(ctx context.Context, req *model.MergeDocumentRequest) (*model.DocumentResponse, error) {
+	unlock := s.lockDocument(req.Index, req.ID)
+	defer unlock()
+
+	current, found, err := s.store.Get(ctx, req.Index, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, util.NewAppError(404, "Document not found",
+			fmt.Sprintf("document %s does not exist in index %s", req.ID, req.Index))
+	}
+	if err := checkVersion(req.Version, current); err != nil {
+		return nil, err
+	}
+
+	doc := copyDocument(current)
+	for name, value := range req.Fields {
+		switch name {
+		case "title":
+			doc.Title, _ = value.(string)
+		case "content":
+			doc.Content, _ = value.(string)
+		default:
+			if value == nil {
+				delete(doc.Fields, name)
+				continue
+			}
+			if doc.Fields == nil {
+				doc.Fields = make(map[string]interface{})
+			}
+			doc.Fields[name] = value
+		}
+	}
+
+	response, err := s.write(ctx, doc, current)
+	if err != nil {
+		return response, err
+	}
+	response.Fields = documentFields(doc)
+	return response, nil
+}
+
+// write fans doc out to the engines and records it in the store with the
+// version after current's. Callers must hold the document lock.
+func (s *DocumentService) write(ctx context.Context, doc *model.DocumentRequest, current *model.DocumentRequest) (*model.DocumentResponse, error) {
+	response := &model.DocumentResponse{
+		ID:    doc.ID,
+		Index: doc.Index,
+	}
+
+	err := s.fanOut(ctx, doc.Index, func(ctx context.Context, client engine.EngineClient) error {
+		return client.AddDocument(ctx, doc)
+	})
+	s.invalidateIndex(ctx, doc.Index)
+	if err != nil {
+		response.Error = err.Error()
+		return response, err
+	}
+
+	stored := copyDocument(doc)
+	stored.Version = 1
+	if current != nil {
+		stored.Version = current.Version + 1
+	}
+	if err := s.store.Put(ctx, stored); err != nil {
+		s.logger.Warnw("Document store write failed",
+			"index", doc.Index,
+			"id", doc.ID,
+			"error", err,
+		)
+	}
+	if s.suggestions != nil {
+		s.suggestions.Add(doc.Title, 1)
+	}
+
+	response.Success = true
+	response.Version = stored.Version
+	return response, nil
+}
+
+func checkVersion(expected int64, current *model.DocumentRequest) error {
+	if expected == 0 {
+		return nil
+	}
+
+	var actual int64
+	if current != nil {
+		actual = current.Version
+	}
+	if actual != expected {
+		return util.NewAppError(409, util.ErrVersionConflict.Message,
+			fmt.Sprintf("expected version %d, stored version is %d", expected, actual))
+	}
+	return nil
+}
+
+// lockDocument serializes writes to one document so the version check and
+// the write happen atomically. Documents share a fixed set of lock stripes.
+func (s *DocumentService) lockDocument(index, id string) func() {
+	h := fnv.New32a()
+	h.Write([]byte(index))
+	h.Write([]byte{0})
+	h.Write([]byte(id))
+
+	mu := &s.docLocks[h.Sum32()%uint32(len(s.docLocks))]
+	mu.Lock()
+	return mu.Unlock
+}
+
+func (s *DocumentService) DeleteDocument(ctx context.Context, req *model.DeleteRequest) (*model.DeleteResponse, error) {
+	response := &model.DeleteResponse{
+		ID:    req.ID,
+		Index: req.Index,
+	}
+
+	unlock := s.lockDocument(req.Index, req.ID)
+	defer unlock()
+
+	err := s.fanOut(ctx, req.Index, func(ctx context.Context, client engine.EngineClient) error {
+		return client.DeleteDocument(ctx, req.Index, req.ID)
+	})
+	s.invalidateIndex(ctx, req.Index)
+	if err != nil {
+		response.Error = err.Error()
+		return response, err
+	}
+
+	if err := s.store.Delete(ctx, req.Index, req.ID); err != nil {
+		s.logger.Warnw("Document store delete failed",
+			"index", req.Index,
+			"id", req.ID,
+			"error", err,
+		)
+	}
+
+	response.Success = true
+	return response, nil
+}
+
+// GetDocuments looks up each ID in index and returns one response per ID in
+// request order. IDs that are not stored come back with Success false.
+func (s *DocumentService) GetDocuments(ctx context.Context, index string, ids []string) ([]*model.DocumentResponse, error) {
+	if len(ids) == 0 {
+		return nil, util.NewAppError(400, "Empty multi-get", "at least one document ID is required")
+	}
+	if len(ids) > MaxGetDocuments {
+		return nil, util.NewAppError(400, "Multi-get too large",
+			fmt.Sprintf("%d IDs exceeds the maximum of %d", len(ids), MaxGetDocuments))
+	}
+
+	responses := make([]*model.DocumentResponse, len(ids))
+	for i, id := range ids {
+		response := &model.DocumentResponse{
+			ID:    id,
+			Index: index,
+		}
+		responses[i] = response
+
+		doc, found, err := s.store.Get(ctx, index, id)
+		switch {
+		case err != nil:
+			response.Error = err.Error()
+		case !found:
+			response.Error = "document not found"
+		default:
+			response.Success = true
+			response.Fields = documentFields(doc)
+			response.Version = doc.Version
+		}
+	}
+
+	return responses, nil
+}
+
+func documentFields(doc *model.DocumentRequest) map[string]interface{} {
+	fields := make(map[string]interface{}, len(doc.Fields)+2)
+	for k, v := range doc.Fields {
+		fields[k] = v
+	}
+	if doc.Title != "" {
+		fields["title"] = doc.Title
+	}
+	if doc.Content != "" {
+		fields["content"] = doc.Content
+	}
+	return fields
+}
+
+// invalidateIndex drops cached searches for index after a write. It runs
+// even when some engines failed, since the others may already have applied
+// the change.
+func (s *DocumentService) invalidateIndex(ctx context.Context, index string) {
+	if s.cache != nil {
+		if err := s.cache.InvalidateIndex(ctx, index); err != nil {
+			s.logger.Warnw("Cache invalidation failed",
+				"index", index,
+				"error", err,
+			)
+		}
+	}
+	if s.localCache != nil {
+		s.localCache.DeleteByPrefix(ctx, cache.IndexKeyPrefix(index))
+	}
+	if s.bus != nil {
+		if err := s.bus.Publish(ctx, index); err != nil {
+			s.logger.Warnw("Cache invalidation publish failed",
+				"index", index,
+				"error", err,
+			)
+		}
+	}
+	if s.invalidator == nil {
+		return
+	}
+
+	if err := s.invalidator.InvalidatePattern(ctx, cache.IndexKeyPattern(index)); err != nil {
+		s.logger.Warnw("Cache invalidation rules failed",
+			"index", index,
+			"error", err,
+		)
+	}
+}
+
+func (s *DocumentService) fanOut(ctx context.Context, index string, write func(context.Context, engine.EngineClient) error) error {
+	engines := s.EnginesForIndex(index)
+	if len(engines) == 0 {
+		return fmt.Errorf("no engines available for index %s", index)
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var failed []string
+
+	for _, engineName := range engines {
+		wg.Add(1)
+		go func(name string, client engine.EngineClient) {
+			defer wg.Done()
+
+			if err := write(ctx, client); err != nil {
+				s.logger.Warnw("Engine write failed",
+					"engine", name,
+					"index", index,
+					"error", err,
+				)
+				mu.Lock()
+				failed = append(failed, fmt.Sprintf("%s: %v", name, err))
+				mu.Unlock()
+			}
+		}(engineName, s.engines[engineName])
+	}
+
+	wg.Wait()
+
+	if len(failed) > 0 {
+		sort.Strings(failed)
+		return fmt.Errorf("write failed on %d of %d engines: %s", len(failed), len(engines), strings.Join(failed, "; "))
+	}
+
+	return nil
+}
@@ -0,0 +1,720 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/flexsearch/coordinator/internal/cache"
+	"github.com/flexsearch/coordinator/internal/engine"
+	"github.com/flexsearch/coordinator/internal/model"
+	"github.com/flexsearch/coordinator/internal/util"
+	sharedcache "github.com/flexsearch/shared/cache"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeEngine struct {
+	name     string
+	mu       sync.Mutex
+	added    []*model.DocumentRequest
+	deleted  []string
+	writeErr error
+	cbState  string
+	failures int
+
+	results     []model.SearchResult
+	searchErr   error
+	searchDelay time.Duration
+	searches    []*model.SearchRequest
+	queryErrs   map[string]error
+	queryDelays map[string]time.Duration
+
+	stats    map[string]*model.EngineIndexStats
+	statsErr error
+
+	pingDelay time.Duration
+	pingErr   error
+
+	// deleteStarted and deleteGate, when set, let a test hold a delete
+	// in flight until it closes the gate.
+	deleteStarted chan struct{}
+	deleteGate    chan struct{}
+}
+
+func newFakeEngine(name string) *fakeEngine {
+	return &fakeEngine{name: name}
+}
+
+func (e *fakeEngine) Connect(ctx context.Context) error { return nil }
+
+func (e *fakeEngine) Disconnect() error { return nil }
+
+func (e *fakeEngine) Search(ctx context.Context, req *model.SearchRequest) (*model.EngineResult, error) {
+	e.mu.Lock()
+	e.searches = append(e.searches, req)
+	delay, searchErr := e.searchDelay, e.searchErr
+	if d, ok := e.queryDelays[req.Query]; ok {
+		delay = d
+	}
+	if err, ok := e.queryErrs[req.Query]; ok {
+		searchErr = err
+	}
+	results := append([]model.SearchResult(nil), e.results...)
+	e.mu.Unlock()
+
+	if delay > 0 {
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	if searchErr != nil {
+		return nil, searchErr
+	}
+
+	return &model.EngineResult{
+		Engine:  e.name,
+		Results: results,
+		Total:   int64(len(results)),
+	}, nil
+}
+
+func (e *fakeEngine) searchCount() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return len(e.searches)
+}
+
+func (e *fakeEngine) AddDocument(ctx context.Context, doc *model.DocumentRequest) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.writeErr != nil {
+		return e.writeErr
+	}
+	e.added = append(e.added, doc)
+	return nil
+}
+
+func (e *fakeEngine) DeleteDocument(ctx context.Context, index, id string) error {
+	if e.deleteGate != nil {
+		close(e.deleteStarted)
+		<-e.deleteGate
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.writeErr != nil {
+		return e.writeErr
+	}
+	e.deleted = append(e.deleted, index+"/"+id)
+	return nil
+}
+
+func (e *fakeEngine) IndexStats(ctx context.Context, index string) (*model.EngineIndexStats, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.statsErr != nil {
+		return nil, e.statsErr
+	}
+	stats, ok := e.stats[index]
+	if !ok {
+		return nil, engine.ErrIndexNotFound
+	}
+	dup := *stats
+	return &dup, nil
+}
+
+func (e *fakeEngine) HealthCheck(ctx context.Context) bool { return true }
+
+func (e *fakeEngine) Ping(ctx context.Context) (time.Duration, error) {
+	e.mu.Lock()
+	delay, err := e.pingDelay, e.pingErr
+	e.mu.Unlock()
+
+	start := time.Now()
+	time.Sleep(delay)
+	return time.Since(start), err
+}
+
+func (e *fakeEngine) Address() string { return e.name + ":50051" }
+
+func (e *fakeEngine) GetName() string { return e.name }
+
+func (e *fakeEngine) GetCircuitBreakerState() string {
+	if e.cbState == "" {
+		return "closed"
+	}
+	return e.cbState
+}
+
+func (e *fakeEngine) GetCircuitBreakerFailures() int { return e.failures }
+
+func (e *fakeEngine) addedCount() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return len(e.added)
+}
+
+func (e *fakeEngine) deletedCount() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return len(e.deleted)
+}
+
+func newTestLogger(t *testing.T) *util.Logger {
+	t.Helper()
+	logger, err := util.NewLogger("error", "json", "stdout")
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	return logger
+}
+
+func newFakeEngines() (map[string]engine.EngineClient, map[string]*fakeEngine) {
+	fakes := map[string]*fakeEngine{
+		"flexsearch": newFakeEngine("flexsearch"),
+		"bm25":       newFakeEngine("bm25"),
+		"vector":     newFakeEngine("vector"),
+	}
+	engines := make(map[string]engine.EngineClient, len(fakes))
+	for name, fake := range fakes {
+		engines[name] = fake
+	}
+	return engines, fakes
+}
+
+func TestDocumentServiceFanOutByIndexType(t *testing.T) {
+	tests := []struct {
+		indexType string
+		expected  map[string]int
+	}{
+		{model.IndexTypeFullText, map[string]int{"flexsearch": 1, "bm25": 1, "vector": 0}},
+		{model.IndexTypeKeyword, map[string]int{"flexsearch": 0, "bm25": 1, "vector": 0}},
+		{model.IndexTypeVector, map[string]int{"flexsearch": 0, "bm25": 0, "vector": 1}},
+		{model.IndexTypeHybrid, map[string]int{"flexsearch": 1, "bm25": 1, "vector": 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.indexType, func(t *testing.T) {
+			engines, fakes := newFakeEngines()
+			svc := NewDocumentService(&DocumentServiceConfig{
+				Logger:     newTestLogger(t),
+				Engines:    engines,
+				IndexTypes: map[string]string{"docs": tt.indexType},
+			})
+
+			resp, err := svc.AddDocument(context.Background(), &model.DocumentRequest{ID: "1", Index: "docs", Content: "hello"})
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if !resp.Success {
+				t.Error("Expected success response")
+			}
+
+			_, err = svc.DeleteDocument(context.Background(), &model.DeleteRequest{ID: "1", Index: "docs"})
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			for name, want := range tt.expected {
+				if got := fakes[name].addedCount(); got != want {
+					t.Errorf("Expected %s to receive %d adds, got %d", name, want, got)
+				}
+				if got := fakes[name].deletedCount(); got != want {
+					t.Errorf("Expected %s to receive %d deletes, got %d", name, want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestDocumentServiceUnknownIndexWritesAllEngines(t *testing.T) {
+	engines, fakes := newFakeEngines()
+	svc := NewDocumentService(&DocumentServiceConfig{
+		Logger:  newTestLogger(t),
+		Engines: engines,
+	})
+
+	if _, err := svc.AddDocument(context.Background(), &model.DocumentRequest{ID: "1", Index: "other"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for name, fake := range fakes {
+		if fake.addedCount() != 1 {
+			t.Errorf("Expected %s to receive the write, got %d", name, fake.addedCount())
+		}
+	}
+}
+
+func TestDocumentServiceWritesDropLocalCacheEntries(t *testing.T) {
+	engines, _ := newFakeEngines()
+	local := cache.NewLRUCache(10)
+	svc := NewDocumentService(&DocumentServiceConfig{
+		Logger:     newTestLogger(t),
+		Engines:    engines,
+		LocalCache: local,
+	})
+
+	ctx := context.Background()
+	local.Set(ctx, cache.IndexKeyPrefix("docs")+"abc", []byte("stale"), 0)
+	local.Set(ctx, cache.IndexKeyPrefix("other")+"abc", []byte("fresh"), 0)
+
+	if _, err := svc.AddDocument(ctx, &model.DocumentRequest{ID: "1", Index: "docs"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, ok := local.Get(ctx, cache.IndexKeyPrefix("docs")+"abc"); ok {
+		t.Error("Expected the write to drop the index's local cache entries")
+	}
+	if _, ok := local.Get(ctx, cache.IndexKeyPrefix("other")+"abc"); !ok {
+		t.Error("Expected other indexes to keep their local cache entries")
+	}
+}
+
+func TestDocumentServiceWritesDropCachedSearches(t *testing.T) {
+	redisCache, mr := newTestCache(t)
+	ctx := context.Background()
+
+	docsReq := &model.SearchRequest{Query: "hello", Index: "docs", Limit: 10}
+	tenantReq := &model.SearchRequest{Query: "hello", Index: "docs", Limit: 10, Tenant: "acme"}
+	otherReq := &model.SearchRequest{Query: "hello", Index: "other", Limit: 10}
+	for _, req := range []*model.SearchRequest{docsReq, tenantReq, otherReq} {
+		if err := redisCache.SetSearchResponse(ctx, req, &model.SearchResponse{Total: 1}, 0); err != nil {
+			t.Fatalf("Failed to seed cache: %v", err)
+		}
+	}
+
+	engines, _ := newFakeEngines()
+	svc := NewDocumentService(&DocumentServiceConfig{
+		Logger:  newTestLogger(t),
+		Engines: engines,
+		Cache:   redisCache,
+	})
+	if _, err := svc.AddDocument(ctx, &model.DocumentRequest{ID: "1", Index: "docs"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for _, req := range []*model.SearchRequest{docsReq, tenantReq} {
+		if mr.Exists(redisCache.GenerateCacheKey(req)) {
+			t.Errorf("Expected the write to drop %s", redisCache.GenerateCacheKey(req))
+		}
+		if _, found := redisCache.GetSearchResponse(ctx, req); found {
+			t.Errorf("Expected no local copy of %s to survive", redisCache.GenerateCacheKey(req))
+		}
+	}
+	if !mr.Exists(redisCache.GenerateCacheKey(otherReq)) {
+		t.Error("Expected cached search for other indexes to be kept")
+	}
+}
+
+func TestDocumentServiceWritesPublishInvalidations(t *testing.T) {
+	engines, _ := newFakeEngines()
+	redisCache, _ := newTestCache(t)
+	ctx := context.Background()
+
+	sub := redisCache.Client().Subscribe(ctx, cache.DefaultInvalidationChannel)
+	defer sub.Close()
+	if _, err := sub.Receive(ctx); err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+
+	svc := NewDocumentService(&DocumentServiceConfig{
+		Logger:  newTestLogger(t),
+		Engines: engines,
+		Bus:     cache.NewInvalidationBus(redisCache, cache.InvalidationBusConfig{}, newTestLogger(t)),
+	})
+	if _, err := svc.AddDocument(ctx, &model.DocumentRequest{ID: "1", Index: "docs"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	select {
+	case msg := <-sub.Channel():
+		if !strings.Contains(msg.Payload, `"index":"docs"`) {
+			t.Errorf("Expected an invalidation for docs, got %s", msg.Payload)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the write to publish an invalidation")
+	}
+}
+
+func TestDocumentServiceRegisterIndex(t *testing.T) {
+	engines, fakes := newFakeEngines()
+	svc := NewDocumentService(&DocumentServiceConfig{
+		Logger:  newTestLogger(t),
+		Engines: engines,
+	})
+
+	if err := svc.RegisterIndex("docs", "unknown"); err == nil {
+		t.Error("Expected error for unknown index type")
+	}
+	if err := svc.RegisterIndex("docs", model.IndexTypeVector); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, err := svc.AddDocument(context.Background(), &model.DocumentRequest{ID: "1", Index: "docs"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if fakes["vector"].addedCount() != 1 || fakes["bm25"].addedCount() != 0 {
+		t.Error("Expected only the vector engine to receive the write")
+	}
+}
+
+func TestDocumentServiceWriteFailure(t *testing.T) {
+	engines, fakes := newFakeEngines()
+	fakes["bm25"].writeErr = fmt.Errorf("bm25 unavailable")
+
+	svc := NewDocumentService(&DocumentServiceConfig{
+		Logger:     newTestLogger(t),
+		Engines:    engines,
+		IndexTypes: map[string]string{"docs": model.IndexTypeFullText},
+	})
+
+	resp, err := svc.AddDocument(context.Background(), &model.DocumentRequest{ID: "1", Index: "docs"})
+	if err == nil {
+		t.Fatal("Expected error when an engine write fails")
+	}
+	if resp.Success {
+		t.Error("Expected unsuccessful response")
+	}
+	if fakes["flexsearch"].addedCount() != 1 {
+		t.Error("Expected healthy engines to still receive the write")
+	}
+}
+
+func TestDocumentServiceWriteInvalidatesCache(t *testing.T) {
+	redisCache, mr := newTestCache(t)
+	ctx := context.Background()
+
+	docsReq := &model.SearchRequest{Query: "hello", Index: "docs", Limit: 10}
+	otherReq := &model.SearchRequest{Query: "hello", Index: "other", Limit: 10}
+	for _, req := range []*model.SearchRequest{docsReq, otherReq} {
+		if err := redisCache.SetSearchResponse(ctx, req, &model.SearchResponse{Total: 1}, 0); err != nil {
+			t.Fatalf("Failed to seed cache: %v", err)
+		}
+	}
+
+	var mu sync.Mutex
+	var invalidated []string
+
+	engines, _ := newFakeEngines()
+	svc := NewDocumentService(&DocumentServiceConfig{
+		Logger:      newTestLogger(t),
+		Engines:     engines,
+		Invalidator: sharedcache.NewCacheInvalidator(redisCache.Client()),
+		InvalidationRules: []sharedcache.InvalidationRule{
+			{
+				Pattern:  "search:*",
+				Strategy: sharedcache.InvalidationStrategyEvent,
+				Callback: func(ctx context.Context, key string) error {
+					mu.Lock()
+					invalidated = append(invalidated, key)
+					mu.Unlock()
+					return redisCache.Client().Del(ctx, key).Err()
+				},
+			},
+		},
+	})
+
+	if _, err := svc.AddDocument(ctx, &model.DocumentRequest{ID: "1", Index: "docs"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	docsKey := redisCache.GenerateCacheKey(docsReq)
+	if len(invalidated) != 1 || invalidated[0] != docsKey {
+		t.Errorf("Expected rule to invalidate %s, got %v", docsKey, invalidated)
+	}
+	if mr.Exists(docsKey) {
+		t.Error("Expected cached search for the written index to be removed")
+	}
+	if !mr.Exists(redisCache.GenerateCacheKey(otherReq)) {
+		t.Error("Expected cached search for other indexes to be kept")
+	}
+
+	invalidated = nil
+	if _, err := svc.DeleteDocument(ctx, &model.DeleteRequest{ID: "1", Index: "other"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(invalidated) != 1 || mr.Exists(redisCache.GenerateCacheKey(otherReq)) {
+		t.Errorf("Expected delete to invalidate the other index, got %v", invalidated)
+	}
+}
+
+func TestDocumentServiceGetDocuments(t *testing.T) {
+	engines, _ := newFakeEngines()
+	svc := NewDocumentService(&DocumentServiceConfig{
+		Logger:  newTestLogger(t),
+		Engines: engines,
+	})
+	ctx := context.Background()
+
+	for _, id := range []string{"1", "2", "3"} {
+		doc := &model.DocumentRequest{
+			ID:      id,
+			Index:   "docs",
+			Title:   "Doc " + id,
+			Content: "content " + id,
+			Fields:  map[string]interface{}{"author": "alice"},
+		}
+		if _, err := svc.AddDocument(ctx, doc); err != nil {
+			t.Fatalf("AddDocument(%s) failed: %v", id, err)
+		}
+	}
+	if _, err := svc.DeleteDocument(ctx, &model.DeleteRequest{ID: "2", Index: "docs"}); err != nil {
+		t.Fatalf("DeleteDocument failed: %v", err)
+	}
+
+	ids := []string{"3", "missing", "1", "2"}
+	docs, err := svc.GetDocuments(ctx, "docs", ids)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(docs) != len(ids) {
+		t.Fatalf("Expected %d responses, got %d", len(ids), len(docs))
+	}
+
+	for i, want := range []bool{true, false, true, false} {
+		doc := docs[i]
+		if doc.ID != ids[i] {
+			t.Errorf("Expected response %d for ID %s, got %s", i, ids[i], doc.ID)
+		}
+		if doc.Success != want {
+			t.Errorf("Expected %s success=%v, got %v", ids[i], want, doc.Success)
+		}
+		if !want && doc.Error == "" {
+			t.Errorf("Expected %s to carry an error", ids[i])
+		}
+	}
+	if docs[0].Fields["title"] != "Doc 3" || docs[0].Fields["author"] != "alice" {
+		t.Errorf("Expected stored fields for document 3, got %v", docs[0].Fields)
+	}
+}
+
+func TestDocumentServiceGetDocumentsLimits(t *testing.T) {
+	engines, _ := newFakeEngines()
+	svc := NewDocumentService(&DocumentServiceConfig{
+		Logger:  newTestLogger(t),
+		Engines: engines,
+	})
+
+	if _, err := svc.GetDocuments(context.Background(), "docs", nil); err == nil {
+		t.Error("Expected an error for an empty ID list")
+	}
+	if _, err := svc.GetDocuments(context.Background(), "docs", make([]string, MaxGetDocuments+1)); err == nil {
+		t.Error("Expected an error for too many IDs")
+	}
+}
+
+func TestDocumentServiceMergeDocument(t *testing.T) {
+	engines, fakes := newFakeEngines()
+	svc := NewDocumentService(&DocumentServiceConfig{
+		Logger:     newTestLogger(t),
+		Engines:    engines,
+		IndexTypes: map[string]string{"docs": model.IndexTypeKeyword},
+	})
+	ctx := context.Background()
+
+	if _, err := svc.AddDocument(ctx, &model.DocumentRequest{
+		ID:      "1",
+		Index:   "docs",
+		Title:   "Original",
+		Content: "body",
+		Fields:  map[string]interface{}{"author": "alice", "lang": "en", "draft": "yes"},
+	}); err != nil {
+		t.Fatalf("AddDocument failed: %v", err)
+	}
+
+	resp, err := svc.MergeDocument(ctx, &model.MergeDocumentRequest{
+		ID:    "1",
+		Index: "docs",
+		Fields: map[string]interface{}{
+			"title": "Renamed",
+			"lang":  "fr",
+			"draft": nil,
+		},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !resp.Success {
+		t.Fatalf("Expected successful merge, got %+v", resp)
+	}
+
+	want := map[string]interface{}{
+		"title":   "Renamed",
+		"content": "body",
+		"author":  "alice",
+		"lang":    "fr",
+	}
+	docs, _ := svc.GetDocuments(ctx, "docs", []string{"1"})
+	if !reflect.DeepEqual(docs[0].Fields, want) {
+		t.Errorf("Expected merged fields %v, got %v", want, docs[0].Fields)
+	}
+
+	bm25 := fakes["bm25"]
+	if bm25.addedCount() != 2 {
+		t.Fatalf("Expected the merged document to be rewritten, got %d writes", bm25.addedCount())
+	}
+	if written := bm25.added[1]; written.Title != "Renamed" || written.Fields["author"] != "alice" {
+		t.Errorf("Expected engines to receive the full merged document, got %+v", written)
+	}
+}
+
+func TestDocumentServiceMergeDocumentNotFound(t *testing.T) {
+	engines, fakes := newFakeEngines()
+	svc := NewDocumentService(&DocumentServiceConfig{
+		Logger:  newTestLogger(t),
+		Engines: engines,
+	})
+
+	_, err := svc.MergeDocument(context.Background(), &model.MergeDocumentRequest{
+		ID:     "missing",
+		Index:  "docs",
+		Fields: map[string]interface{}{"lang": "en"},
+	})
+
+	var appErr *util.AppError
+	if !errors.As(err, &appErr) || appErr.Code != 404 {
+		t.Fatalf("Expected a 404 AppError, got %v", err)
+	}
+	for name, fake := range fakes {
+		if fake.addedCount() != 0 {
+			t.Errorf("Expected no write to %s for a missing document", name)
+		}
+	}
+}
+
+func TestDocumentServiceDeleteSerializesWithMerge(t *testing.T) {
+	engines, fakes := newFakeEngines()
+	svc := NewDocumentService(&DocumentServiceConfig{
+		Logger:     newTestLogger(t),
+		Engines:    engines,
+		IndexTypes: map[string]string{"docs": model.IndexTypeKeyword},
+	})
+	ctx := context.Background()
+
+	if _, err := svc.AddDocument(ctx, &model.DocumentRequest{ID: "1", Index: "docs", Title: "Original"}); err != nil {
+		t.Fatalf("AddDocument failed: %v", err)
+	}
+
+	bm25 := fakes["bm25"]
+	bm25.deleteStarted = make(chan struct{})
+	bm25.deleteGate = make(chan struct{})
+	deleted := make(chan error, 1)
+	go func() {
+		_, err := svc.DeleteDocument(ctx, &model.DeleteRequest{ID: "1", Index: "docs"})
+		deleted <- err
+	}()
+	<-bm25.deleteStarted
+
+	merged := make(chan error, 1)
+	go func() {
+		_, err := svc.MergeDocument(ctx, &model.MergeDocumentRequest{
+			ID:     "1",
+			Index:  "docs",
+			Fields: map[string]interface{}{"title": "Renamed"},
+		})
+		merged <- err
+	}()
+
+	select {
+	case err := <-merged:
+		t.Fatalf("Expected the merge to wait for the in-flight delete, got %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(bm25.deleteGate)
+	if err := <-deleted; err != nil {
+		t.Fatalf("DeleteDocument failed: %v", err)
+	}
+	var appErr *util.AppError
+	if err := <-merged; !errors.As(err, &appErr) || appErr.Code != 404 {
+		t.Fatalf("Expected the merge after the delete to find no document, got %v", err)
+	}
+	if bm25.addedCount() != 1 {
+		t.Errorf("Expected no write to resurrect the deleted document, got %d writes", bm25.addedCount())
+	}
+}
+
+func TestDocumentServiceVersionedUpdate(t *testing.T) {
+	engines, _ := newFakeEngines()
+	svc := NewDocumentService(&DocumentServiceConfig{
+		Logger:  newTestLogger(t),
+		Engines: engines,
+	})
+	ctx := context.Background()
+
+	resp, err := svc.AddDocument(ctx, &model.DocumentRequest{ID: "1", Index: "docs", Title: "v1"})
+	if err != nil {
+		t.Fatalf("AddDocument failed: %v", err)
+	}
+	if resp.Version != 1 {
+		t.Fatalf("Expected a new document to start at version 1, got %d", resp.Version)
+	}
+
+	resp, err = svc.AddDocument(ctx, &model.DocumentRequest{ID: "1", Index: "docs", Title: "v2", Version: 1})
+	if err != nil {
+		t.Fatalf("Expected versioned update to succeed, got %v", err)
+	}
+	if resp.Version != 2 {
+		t.Errorf("Expected version 2 after update, got %d", resp.Version)
+	}
+
+	resp, err = svc.MergeDocument(ctx, &model.MergeDocumentRequest{
+		ID:      "1",
+		Index:   "docs",
+		Fields:  map[string]interface{}{"lang": "en"},
+		Version: 2,
+	})
+	if err != nil {
+		t.Fatalf("Expected versioned merge to succeed, got %v", err)
+	}
+	if resp.Version != 3 {
+		t.Errorf("Expected version 3 after merge, got %d", resp.Version)
+	}
+
+	docs, _ := svc.GetDocuments(ctx, "docs", []string{"1"})
+	if docs[0].Version != 3 {
+		t.Errorf("Expected stored version 3, got %d", docs[0].Version)
+	}
+}
+
+func TestDocumentServiceStaleVersionRejected(t *testing.T) {
+	engines, fakes := newFakeEngines()
+	svc := NewDocumentService(&DocumentServiceConfig{
+		Logger:     newTestLogger(t),
+		Engines:    engines,
+		IndexTypes: map[string]string{"docs": model.IndexTypeKeyword},
+	})
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, err := svc.AddDocument(ctx, &model.DocumentRequest{ID: "1", Index: "docs"}); err != nil {
+			t.Fatalf("AddDocument failed: %v", err)
+		}
+	}
+
+	_, err := svc.AddDocument(ctx, &model.DocumentRequest{ID: "1", Index: "docs", Title: "stale", Version: 1})
+	if status.Code(err) != codes.Aborted {
+		t.Errorf("Expected a stale update to fail with Aborted, got %v", err)
+	}
+	_, err = svc.MergeDocument(ctx, &model.MergeDocumentRequest{ID: "1", Index: "docs", Version: 5})
+	if status.Code(err) != codes.Aborted {
+		t.Errorf("Expected a stale merge to fail with Aborted, got %v", err)
+	}
+	if fakes["bm25"].addedCount() != 2 {
+		t.Errorf("Expected rejected updates not to reach the engines, got %d writes", fakes["bm25"].addedCount())
+	}
+
+	docs, _ := svc.GetDocuments(ctx, "docs", []string{"1"})
+	if docs[0].Version != 2 || docs[0].Fields["title"] != nil {
+		t.Errorf("Expected the stored document to be unchanged, got %+v", docs[0])
+	}
+}
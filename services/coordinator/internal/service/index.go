@@ -0,0 +1,248 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/flexsearch/coordinator/internal/engine"
+	"github.com/flexsearch/coordinator/internal/model"
+	"github.com/flexsearch/coordinator/internal/task"
+	"github.com/flexsearch/coordinator/internal/util"
+)
+
+var validFieldTypes = map[string]bool{
+	model.FieldTypeText:    true,
+	model.FieldTypeKeyword: true,
+	model.FieldTypeInteger: true,
+	model.FieldTypeFloat:   true,
+	model.FieldTypeBoolean: true,
+	model.FieldTypeDate:    true,
+	model.FieldTypeVector:  true,
+}
+
+var validAnalyzers = map[string]bool{
+	"standard":   true,
+	"simple":     true,
+	"whitespace": true,
+	"keyword":    true,
+	"english":    true,
+}
+
+// IndexService owns index metadata: the type that decides which engines
+// hold an index and the per-field mappings the router and engines consult.
+type IndexService struct {
+	logger    *util.Logger
+	documents *DocumentService
+	tasks     *task.Registry
+	mu        sync.RWMutex
+	indexes   map[string]*model.IndexMetadata
+}
+
+type IndexServiceConfig struct {
+	Logger *util.Logger
+	// Documents, when set, is told the type of every created index so
+	// writes fan out to the right engines.
+	Documents *DocumentService
+	// Tasks runs background work such as reconcile rebuilds.
+	Tasks *task.Registry
+}
+
+func NewIndexService(cfg *IndexServiceConfig) *IndexService {
+	return &IndexService{
+		logger:    cfg.Logger,
+		documents: cfg.Documents,
+		tasks:     cfg.Tasks,
+		indexes:   make(map[string]*model.IndexMetadata),
+	}
+}
+
+func (s *IndexService) CreateIndex(ctx context.Context, req *model.IndexRequest) (*model.IndexResponse, error) {
+	if strings.TrimSpace(req.Name) == "" {
+		return nil, util.NewAppError(400, "Invalid index", "index name is required")
+	}
+
+	indexType := req.Type
+	if indexType == "" {
+		indexType = model.IndexTypeFullText
+	}
+	if _, ok := model.IndexTypeEngines(indexType); !ok {
+		return nil, util.NewAppError(400, "Invalid index", fmt.Sprintf("unknown index type %q", indexType))
+	}
+	if err := validateMappings(req.Mappings); err != nil {
+		return nil, util.NewAppError(400, "Invalid mappings", err.Error())
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.indexes[req.Name]; exists {
+		return nil, util.NewAppError(409, "Index already exists", fmt.Sprintf("index %s already exists", req.Name))
+	}
+
+	if s.documents != nil {
+		if err := s.documents.RegisterIndex(req.Name, indexType); err != nil {
+			return nil, err
+		}
+	}
+
+	mappings := make(map[string]model.FieldMapping, len(req.Mappings))
+	for field, mapping := range req.Mappings {
+		mappings[field] = mapping
+	}
+	s.indexes[req.Name] = &model.IndexMetadata{
+		Name:      req.Name,
+		Type:      indexType,
+		Mappings:  mappings,
+		CreatedAt: time.Now(),
+	}
+
+	s.logger.Infow("Index created",
+		"index", req.Name,
+		"type", indexType,
+		"mapped_fields", len(mappings),
+	)
+
+	return &model.IndexResponse{
+		Name:    req.Name,
+		Success: true,
+		Fields:  sortedFields(mappings),
+	}, nil
+}
+
+// GetIndex returns a copy of the index metadata, or a 404 AppError.
+func (s *IndexService) GetIndex(ctx context.Context, name string) (*model.IndexMetadata, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	meta, ok := s.indexes[name]
+	if !ok {
+		return nil, util.NewAppError(404, "Index not found", fmt.Sprintf("index %s does not exist", name))
+	}
+
+	dup := *meta
+	dup.Mappings = make(map[string]model.FieldMapping, len(meta.Mappings))
+	for field, mapping := range meta.Mappings {
+		dup.Mappings[field] = mapping
+	}
+	return &dup, nil
+}
+
+// FieldMapping returns the mapping declared for field of index, if any.
+func (s *IndexService) FieldMapping(index, field string) (model.FieldMapping, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	meta, ok := s.indexes[index]
+	if !ok {
+		return model.FieldMapping{}, false
+	}
+	mapping, ok := meta.Mappings[field]
+	return mapping, ok
+}
+
+func (s *IndexService) DeleteIndex(ctx context.Context, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.indexes[name]; !ok {
+		return util.NewAppError(404, "Index not found", fmt.Sprintf("index %s does not exist", name))
+	}
+	delete(s.indexes, name)
+	if s.documents != nil {
+		s.documents.UnregisterIndex(name)
+	}
+	return nil
+}
+
+// GetIndexStats collects the stats of index from every engine that should
+// hold it. Engines that lack the index or fail to answer are skipped and
+// noted, marking the response partial. It returns a 404 AppError when no
+// engine has the index and a 503 when none could be reached.
+func (s *IndexService) GetIndexStats(ctx context.Context, index string) (*model.IndexStatsResponse, error) {
+	if s.documents == nil {
+		return nil, util.NewAppError(404, "Index not found", fmt.Sprintf("index %s does not exist", index))
+	}
+
+	resp := &model.IndexStatsResponse{Index: index}
+	var latest time.Time
+	missing := 0
+
+	names := s.documents.EnginesForIndex(index)
+	for _, name := range names {
+		provider, ok := s.documents.engines[name].(engine.IndexStatsProvider)
+		if !ok {
+			resp.Notes = append(resp.Notes, fmt.Sprintf("engine %s does not report index stats", name))
+			continue
+		}
+
+		stats, err := provider.IndexStats(ctx, index)
+		if errors.Is(err, engine.ErrIndexNotFound) {
+			missing++
+			resp.Notes = append(resp.Notes, fmt.Sprintf("index not present in engine %s", name))
+			continue
+		}
+		if err != nil {
+			s.logger.Warnw("Index stats failed", "index", index, "engine", name, "error", err)
+			resp.Notes = append(resp.Notes, fmt.Sprintf("engine %s: %v", name, err))
+			continue
+		}
+
+		resp.Engines = append(resp.Engines, *stats)
+		if stats.DocumentCount > resp.DocumentCount {
+			resp.DocumentCount = stats.DocumentCount
+		}
+		resp.IndexSize += stats.IndexSize
+		if t, err := time.Parse(time.RFC3339, stats.LastUpdated); err == nil && t.After(latest) {
+			latest = t
+			resp.LastUpdated = stats.LastUpdated
+		}
+	}
+
+	if len(resp.Engines) == 0 {
+		if missing == len(names) {
+			return nil, util.NewAppError(404, "Index not found", fmt.Sprintf("index %s does not exist", index))
+		}
+		return nil, util.NewAppError(503, "Index stats unavailable", strings.Join(resp.Notes, "; "))
+	}
+
+	resp.Partial = len(resp.Notes) > 0
+	return resp, nil
+}
+
+func validateMappings(mappings map[string]model.FieldMapping) error {
+	for _, field := range sortedFields(mappings) {
+		mapping := mappings[field]
+		if strings.TrimSpace(field) == "" {
+			return fmt.Errorf("field name cannot be empty")
+		}
+		if !validFieldTypes[mapping.Type] {
+			return fmt.Errorf("field %s: unknown type %q", field, mapping.Type)
+		}
+		if mapping.Analyzer != "" {
+			if mapping.Type != model.FieldTypeText {
+				return fmt.Errorf("field %s: analyzer only applies to text fields", field)
+			}
+			if !validAnalyzers[mapping.Analyzer] {
+				return fmt.Errorf("field %s: unknown analyzer %q", field, mapping.Analyzer)
+			}
+		}
+		if mapping.Boost < 0 {
+			return fmt.Errorf("field %s: boost cannot be negative", field)
+		}
+	}
+	return nil
+}
+
+func sortedFields(mappings map[string]model.FieldMapping) []string {
+	fields := make([]string, 0, len(mappings))
+	for field := range mappings {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
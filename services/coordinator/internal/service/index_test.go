@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/flexsearch/coordinator/internal/model"
+	"github.com/flexsearch/coordinator/internal/util"
+)
+
+func newTestIndexService(t *testing.T) (*IndexService, *DocumentService) {
+	t.Helper()
+	engines, _ := newFakeEngines()
+	docs := NewDocumentService(&DocumentServiceConfig{
+		Logger:  newTestLogger(t),
+		Engines: engines,
+	})
+	return NewIndexService(&IndexServiceConfig{
+		Logger:    newTestLogger(t),
+		Documents: docs,
+	}), docs
+}
+
+func TestIndexServiceCreateWithMappings(t *testing.T) {
+	svc, docs := newTestIndexService(t)
+	ctx := context.Background()
+
+	resp, err := svc.CreateIndex(ctx, &model.IndexRequest{
+		Name: "articles",
+		Type: model.IndexTypeKeyword,
+		Mappings: map[string]model.FieldMapping{
+			"title": {Type: model.FieldTypeText, Analyzer: "english", Boost: 2},
+			"year":  {Type: model.FieldTypeInteger},
+			"tags":  {Type: model.FieldTypeKeyword},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Expected a valid mapping to be accepted, got %v", err)
+	}
+	if !resp.Success || len(resp.Fields) != 3 || resp.Fields[0] != "tags" {
+		t.Errorf("Expected success listing the mapped fields, got %+v", resp)
+	}
+
+	mapping, ok := svc.FieldMapping("articles", "title")
+	if !ok || mapping.Analyzer != "english" || mapping.Boost != 2 {
+		t.Errorf("Expected the title mapping to be persisted, got %+v", mapping)
+	}
+	if engines := docs.EnginesForIndex("articles"); len(engines) != 1 || engines[0] != "bm25" {
+		t.Errorf("Expected the index type to reach the document service, got %v", engines)
+	}
+
+	meta, err := svc.GetIndex(ctx, "articles")
+	if err != nil || meta.Type != model.IndexTypeKeyword || len(meta.Mappings) != 3 {
+		t.Errorf("Expected stored metadata, got %+v (%v)", meta, err)
+	}
+}
+
+func TestIndexServiceRejectsInvalidMappings(t *testing.T) {
+	tests := []struct {
+		name     string
+		mappings map[string]model.FieldMapping
+	}{
+		{"unknown type", map[string]model.FieldMapping{"title": {Type: "string"}}},
+		{"missing type", map[string]model.FieldMapping{"title": {}}},
+		{"unknown analyzer", map[string]model.FieldMapping{"title": {Type: model.FieldTypeText, Analyzer: "klingon"}}},
+		{"analyzer on keyword", map[string]model.FieldMapping{"tags": {Type: model.FieldTypeKeyword, Analyzer: "standard"}}},
+		{"negative boost", map[string]model.FieldMapping{"title": {Type: model.FieldTypeText, Boost: -1}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, _ := newTestIndexService(t)
+
+			_, err := svc.CreateIndex(context.Background(), &model.IndexRequest{Name: "articles", Mappings: tt.mappings})
+			var appErr *util.AppError
+			if !errors.As(err, &appErr) || appErr.Code != 400 {
+				t.Fatalf("Expected a 400 AppError, got %v", err)
+			}
+			if _, err := svc.GetIndex(context.Background(), "articles"); err == nil {
+				t.Error("Expected a rejected index not to be stored")
+			}
+		})
+	}
+}
+
+func TestIndexServiceDuplicateAndDelete(t *testing.T) {
+	svc, _ := newTestIndexService(t)
+	ctx := context.Background()
+
+	if _, err := svc.CreateIndex(ctx, &model.IndexRequest{Name: "articles"}); err != nil {
+		t.Fatalf("CreateIndex failed: %v", err)
+	}
+
+	var appErr *util.AppError
+	if _, err := svc.CreateIndex(ctx, &model.IndexRequest{Name: "articles"}); !errors.As(err, &appErr) || appErr.Code != 409 {
+		t.Errorf("Expected a duplicate index to be rejected with 409, got %v", err)
+	}
+
+	if err := svc.DeleteIndex(ctx, "articles"); err != nil {
+		t.Fatalf("DeleteIndex failed: %v", err)
+	}
+	if _, err := svc.GetIndex(ctx, "articles"); !errors.As(err, &appErr) || appErr.Code != 404 {
+		t.Errorf("Expected a deleted index to be gone, got %v", err)
+	}
+}
+
+func newTestStatsService(t *testing.T) (*IndexService, map[string]*fakeEngine) {
+	t.Helper()
+	engines, fakes := newFakeEngines()
+	docs := NewDocumentService(&DocumentServiceConfig{
+		Logger:     newTestLogger(t),
+		Engines:    engines,
+		IndexTypes: map[string]string{"articles": model.IndexTypeHybrid},
+	})
+	return NewIndexService(&IndexServiceConfig{
+		Logger:    newTestLogger(t),
+		Documents: docs,
+	}), fakes
+}
+
+func TestIndexServiceGetIndexStats(t *testing.T) {
+	svc, fakes := newTestStatsService(t)
+	fakes["flexsearch"].stats = map[string]*model.EngineIndexStats{
+		"articles": {Engine: "flexsearch", DocumentCount: 120, IndexSize: 4096, LastUpdated: "2026-01-02T10:00:00Z"},
+	}
+	fakes["bm25"].stats = map[string]*model.EngineIndexStats{
+		"articles": {Engine: "bm25", DocumentCount: 118, IndexSize: 2048, LastUpdated: "2026-01-02T11:00:00Z"},
+	}
+	fakes["vector"].stats = map[string]*model.EngineIndexStats{
+		"articles": {Engine: "vector", DocumentCount: 120, IndexSize: 8192, LastUpdated: "2026-01-02T09:00:00Z"},
+	}
+
+	stats, err := svc.GetIndexStats(context.Background(), "articles")
+	if err != nil {
+		t.Fatalf("GetIndexStats returned error: %v", err)
+	}
+	if stats.DocumentCount != 120 || stats.IndexSize != 14336 {
+		t.Errorf("Expected 120 documents in 14336 bytes, got %d in %d", stats.DocumentCount, stats.IndexSize)
+	}
+	if stats.LastUpdated != "2026-01-02T11:00:00Z" {
+		t.Errorf("Expected the latest update time, got %s", stats.LastUpdated)
+	}
+	if stats.Partial || len(stats.Notes) != 0 || len(stats.Engines) != 3 {
+		t.Errorf("Expected complete stats from 3 engines, got %+v", stats)
+	}
+}
+
+func TestIndexServiceGetIndexStatsPartial(t *testing.T) {
+	svc, fakes := newTestStatsService(t)
+	fakes["flexsearch"].stats = map[string]*model.EngineIndexStats{
+		"articles": {Engine: "flexsearch", DocumentCount: 120, IndexSize: 4096},
+	}
+	fakes["vector"].statsErr = errors.New("connection refused")
+
+	stats, err := svc.GetIndexStats(context.Background(), "articles")
+	if err != nil {
+		t.Fatalf("GetIndexStats returned error: %v", err)
+	}
+	if !stats.Partial {
+		t.Error("Expected stats to be marked partial")
+	}
+	if len(stats.Engines) != 1 || stats.DocumentCount != 120 {
+		t.Errorf("Expected stats from flexsearch only, got %+v", stats)
+	}
+	if len(stats.Notes) != 2 {
+		t.Errorf("Expected notes for bm25 and vector, got %v", stats.Notes)
+	}
+}
+
+func TestIndexServiceGetIndexStatsNotFound(t *testing.T) {
+	svc, fakes := newTestStatsService(t)
+
+	_, err := svc.GetIndexStats(context.Background(), "articles")
+	var appErr *util.AppError
+	if !errors.As(err, &appErr) || appErr.Code != 404 {
+		t.Fatalf("Expected a 404 AppError, got %v", err)
+	}
+
+	fakes["bm25"].statsErr = errors.New("connection refused")
+	_, err = svc.GetIndexStats(context.Background(), "articles")
+	if !errors.As(err, &appErr) || appErr.Code != 503 {
+		t.Errorf("Expected a 503 AppError when an engine is unreachable, got %v", err)
+	}
+}
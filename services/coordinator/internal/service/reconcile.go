@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/flexsearch/coordinator/internal/engine"
+	"github.com/flexsearch/coordinator/internal/model"
+	"github.com/flexsearch/coordinator/internal/task"
+	"github.com/flexsearch/coordinator/internal/util"
+)
+
+const TaskTypeReconcile = "reconcile"
+
+// ReconcileIndex compares the document count every engine reports for index
+// with the number of documents in the store. With rebuild set and a task
+// registry configured, mismatched engines are re-fed every stored document
+// in a background task. A rebuild only restores missing documents; engines
+// holding extra documents stay flagged until they are cleared.
+func (s *IndexService) ReconcileIndex(ctx context.Context, index string, rebuild bool) (*model.ReconcileReport, error) {
+	if s.documents == nil {
+		return nil, util.NewAppError(404, "Index not found", fmt.Sprintf("index %s does not exist", index))
+	}
+
+	docs, err := s.documents.store.List(ctx, index)
+	if err != nil {
+		return nil, util.WrapError(err, "Failed to list stored documents")
+	}
+
+	report := &model.ReconcileReport{
+		Index:    index,
+		Expected: int64(len(docs)),
+		Counts:   make(map[string]int64),
+	}
+
+	names := s.documents.EnginesForIndex(index)
+	missing := 0
+	for _, name := range names {
+		provider, ok := s.documents.engines[name].(engine.IndexStatsProvider)
+		if !ok {
+			report.Unreachable = append(report.Unreachable, name)
+			continue
+		}
+
+		stats, err := provider.IndexStats(ctx, index)
+		switch {
+		case errors.Is(err, engine.ErrIndexNotFound):
+			missing++
+			report.Counts[name] = 0
+		case err != nil:
+			s.logger.Warnw("Reconcile count failed", "index", index, "engine", name, "error", err)
+			report.Unreachable = append(report.Unreachable, name)
+			continue
+		default:
+			report.Counts[name] = stats.DocumentCount
+		}
+
+		if report.Counts[name] != report.Expected {
+			report.Mismatched = append(report.Mismatched, name)
+		}
+	}
+
+	if report.Expected == 0 && missing == len(names) {
+		return nil, util.NewAppError(404, "Index not found", fmt.Sprintf("index %s does not exist", index))
+	}
+
+	report.Consistent = len(report.Mismatched) == 0 && len(report.Unreachable) == 0
+	if len(report.Mismatched) > 0 {
+		s.logger.Warnw("Index document counts diverge",
+			"index", index,
+			"expected", report.Expected,
+			"mismatched", report.Mismatched,
+		)
+	}
+
+	if rebuild && len(report.Mismatched) > 0 && s.tasks != nil {
+		targets := append([]string(nil), report.Mismatched...)
+		report.RebuildTaskID = s.tasks.Start(context.Background(), TaskTypeReconcile,
+			func(ctx context.Context, progress task.Progress) error {
+				return s.documents.replay(ctx, docs, targets, progress)
+			})
+	}
+
+	return report, nil
+}
+
+// replay writes docs to the named engines, reporting progress as it goes.
+func (s *DocumentService) replay(ctx context.Context, docs []*model.DocumentRequest, engines []string, progress task.Progress) error {
+	var failed int
+	for i, doc := range docs {
+		for _, name := range engines {
+			if err := s.engines[name].AddDocument(ctx, doc); err != nil {
+				failed++
+				s.logger.Warnw("Reconcile write failed",
+					"index", doc.Index,
+					"id", doc.ID,
+					"engine", name,
+					"error", err,
+				)
+			}
+		}
+		progress(float64(i+1) * 100 / float64(len(docs)))
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d document writes failed", failed)
+	}
+	return nil
+}
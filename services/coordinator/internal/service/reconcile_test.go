@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/flexsearch/coordinator/internal/model"
+	"github.com/flexsearch/coordinator/internal/task"
+)
+
+func newTestReconcileService(t *testing.T) (*IndexService, *DocumentService, map[string]*fakeEngine) {
+	t.Helper()
+	engines, fakes := newFakeEngines()
+	docs := NewDocumentService(&DocumentServiceConfig{
+		Logger:     newTestLogger(t),
+		Engines:    engines,
+		IndexTypes: map[string]string{"articles": model.IndexTypeHybrid},
+	})
+	svc := NewIndexService(&IndexServiceConfig{
+		Logger:    newTestLogger(t),
+		Documents: docs,
+		Tasks:     task.NewRegistry(time.Minute),
+	})
+
+	ctx := context.Background()
+	for _, id := range []string{"1", "2", "3"} {
+		if _, err := docs.AddDocument(ctx, &model.DocumentRequest{ID: id, Index: "articles", Title: "doc " + id}); err != nil {
+			t.Fatalf("AddDocument(%s) returned error: %v", id, err)
+		}
+	}
+	return svc, docs, fakes
+}
+
+func setCounts(fakes map[string]*fakeEngine, counts map[string]int64) {
+	for name, count := range counts {
+		fakes[name].stats = map[string]*model.EngineIndexStats{
+			"articles": {Engine: name, DocumentCount: count},
+		}
+	}
+}
+
+func TestReconcileIndexFlagsDivergentCounts(t *testing.T) {
+	svc, _, fakes := newTestReconcileService(t)
+	setCounts(fakes, map[string]int64{"flexsearch": 3, "bm25": 2, "vector": 5})
+
+	report, err := svc.ReconcileIndex(context.Background(), "articles", false)
+	if err != nil {
+		t.Fatalf("ReconcileIndex returned error: %v", err)
+	}
+	if report.Consistent {
+		t.Error("Expected the report to flag the discrepancy")
+	}
+	if report.Expected != 3 {
+		t.Errorf("Expected 3 stored documents, got %d", report.Expected)
+	}
+	if len(report.Mismatched) != 2 || report.Mismatched[0] != "bm25" || report.Mismatched[1] != "vector" {
+		t.Errorf("Expected bm25 and vector to be mismatched, got %v", report.Mismatched)
+	}
+	if report.Counts["bm25"] != 2 || report.Counts["vector"] != 5 {
+		t.Errorf("Expected engine counts in the report, got %v", report.Counts)
+	}
+	if report.RebuildTaskID != "" {
+		t.Errorf("Expected no rebuild without the flag, got task %s", report.RebuildTaskID)
+	}
+}
+
+func TestReconcileIndexConsistent(t *testing.T) {
+	svc, _, fakes := newTestReconcileService(t)
+	setCounts(fakes, map[string]int64{"flexsearch": 3, "bm25": 3, "vector": 3})
+
+	report, err := svc.ReconcileIndex(context.Background(), "articles", true)
+	if err != nil {
+		t.Fatalf("ReconcileIndex returned error: %v", err)
+	}
+	if !report.Consistent || len(report.Mismatched) != 0 || report.RebuildTaskID != "" {
+		t.Errorf("Expected a consistent report without a rebuild, got %+v", report)
+	}
+}
+
+func TestReconcileIndexRebuildsMismatchedEngines(t *testing.T) {
+	svc, _, fakes := newTestReconcileService(t)
+	setCounts(fakes, map[string]int64{"flexsearch": 3, "bm25": 1, "vector": 3})
+	before := map[string]int{}
+	for name, fake := range fakes {
+		before[name] = fake.addedCount()
+	}
+
+	report, err := svc.ReconcileIndex(context.Background(), "articles", true)
+	if err != nil {
+		t.Fatalf("ReconcileIndex returned error: %v", err)
+	}
+	if report.RebuildTaskID == "" {
+		t.Fatal("Expected a rebuild task to be started")
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		status, err := svc.tasks.GetTaskStatus(context.Background(), report.RebuildTaskID)
+		if err != nil {
+			t.Fatalf("GetTaskStatus returned error: %v", err)
+		}
+		if status.State == model.TaskStateCompleted {
+			break
+		}
+		if status.State == model.TaskStateFailed || time.Now().After(deadline) {
+			t.Fatalf("Expected the rebuild to complete, got %+v", status)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if got := fakes["bm25"].addedCount() - before["bm25"]; got != 3 {
+		t.Errorf("Expected bm25 to be re-fed 3 documents, got %d", got)
+	}
+	if got := fakes["flexsearch"].addedCount() - before["flexsearch"]; got != 0 {
+		t.Errorf("Expected flexsearch to be left alone, got %d writes", got)
+	}
+}
+
+func TestReconcileIndexUnknown(t *testing.T) {
+	svc, _, _ := newTestReconcileService(t)
+
+	if _, err := svc.ReconcileIndex(context.Background(), "missing", false); err == nil {
+		t.Error("Expected an unknown index to be rejected")
+	}
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -12,41 +13,145 @@ import (
 	"github.com/flexsearch/coordinator/internal/merger"
 	"github.com/flexsearch/coordinator/internal/model"
 	"github.com/flexsearch/coordinator/internal/router"
+	"github.com/flexsearch/coordinator/internal/suggest"
 	"github.com/flexsearch/coordinator/internal/util"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	defaultSearchTimeout       = 800 * time.Millisecond
+	defaultMultiSearchTimeout  = 2 * time.Second
+	MaxMultiSearchQueries      = 20
+	defaultDidYouMeanThreshold = 3
+	defaultSuggestLimit        = 10
+	MaxSuggestLimit            = 50
+	MaxPerEngineLimit          = 1000
 )
 
 type SearchService struct {
-	config        *config.Config
-	logger        *util.Logger
-	cache         *cache.RedisCache
-	router        *router.Router
-	optimizer     *router.Optimizer
-	merger        merger.Merger
-	engines       map[string]engine.EngineClient
-	metrics       *util.Metrics
+	config               *config.Config
+	logger               *util.Logger
+	cache                *cache.RedisCache
+	router               *router.Router
+	optimizer            *router.Optimizer
+	merger               merger.Merger
+	engines              map[string]engine.EngineClient
+	metrics              *util.Metrics
+	engineTimeouts       map[string]time.Duration
+	minSuccessfulEngines int
+	fallbackEngines      map[string][]string
+	perEngineLimit       int32
+	didYouMeanThreshold  int
+	highlighter          *merger.Highlighter
+	suggestions          *suggest.Trie
+	tracer               trace.Tracer
+	queryLogger          *util.QueryLogger
+	indexTypes           IndexTypeLookup
+	flights              *searchFlights
+	complexity           ComplexityLimits
+	wildcards            WildcardPolicy
+}
+
+// IndexTypeLookup resolves an index name to its registered index type.
+// DocumentService implements it.
+type IndexTypeLookup interface {
+	IndexType(index string) (string, bool)
 }
 
 type SearchServiceConfig struct {
-	Config       *config.Config
-	Logger       *util.Logger
-	Cache        *cache.RedisCache
-	Router       *router.Router
-	Optimizer    *router.Optimizer
-	Merger       merger.Merger
-	Engines      map[string]engine.EngineClient
-	Metrics      *util.Metrics
+	Config               *config.Config
+	Logger               *util.Logger
+	Cache                *cache.RedisCache
+	Router               *router.Router
+	Optimizer            *router.Optimizer
+	Merger               merger.Merger
+	Engines              map[string]engine.EngineClient
+	Metrics              *util.Metrics
+	EngineTimeouts       map[string]time.Duration
+	MinSuccessfulEngines int
+	// FallbackEngines maps a routing strategy to the engines tried when
+	// every engine it selected fails.
+	FallbackEngines map[string][]string
+	// PerEngineLimit is the default number of candidates asked of each
+	// engine for fusion. Engines are always asked for at least enough to
+	// fill the requested page. Zero asks for just that.
+	PerEngineLimit int
+	// DidYouMeanThreshold is the result count below which the top optimizer
+	// suggestion is returned as DidYouMean.
+	DidYouMeanThreshold int
+	Highlighter         *merger.Highlighter
+	// Suggestions is the prefix index behind Suggest. Queries that return
+	// results are recorded in it. It may be shared with DocumentService.
+	Suggestions *suggest.Trie
+	// Tracer records a child span per engine call. Defaults to the global
+	// provider's "coordinator" tracer.
+	Tracer trace.Tracer
+	// QueryLogger logs every executed query and flags slow ones. Defaults
+	// to a QueryLogger on Logger without a slow threshold.
+	QueryLogger *util.QueryLogger
+	// IndexTypes, when set, restricts routing to the engines that hold data
+	// for the searched index's type.
+	IndexTypes IndexTypeLookup
+	// Complexity rejects searches with too many terms, filters, indexes
+	// or wildcards before any work is done.
+	Complexity ComplexityLimits
+	// Wildcards rejects or rewrites wildcard terms with too short a
+	// literal prefix.
+	Wildcards WildcardPolicy
 }
 
 func NewSearchService(cfg *SearchServiceConfig) *SearchService {
+	didYouMeanThreshold := cfg.DidYouMeanThreshold
+	if didYouMeanThreshold <= 0 {
+		didYouMeanThreshold = defaultDidYouMeanThreshold
+	}
+
+	highlighter := cfg.Highlighter
+	if highlighter == nil {
+		highlighter = merger.NewHighlighter(merger.HighlighterConfig{})
+	}
+
+	suggestions := cfg.Suggestions
+	if suggestions == nil {
+		suggestions = suggest.NewTrie()
+	}
+
+	tracer := cfg.Tracer
+	if tracer == nil {
+		tracer = otel.Tracer("coordinator")
+	}
+
+	queryLogger := cfg.QueryLogger
+	if queryLogger == nil {
+		queryLogger = util.NewQueryLogger(cfg.Logger)
+	}
+
 	return &SearchService{
-		config:    cfg.Config,
-		logger:    cfg.Logger,
-		cache:     cfg.Cache,
-		router:    cfg.Router,
-		optimizer: cfg.Optimizer,
-		merger:    cfg.Merger,
-		engines:   cfg.Engines,
-		metrics:   cfg.Metrics,
+		config:               cfg.Config,
+		logger:               cfg.Logger,
+		cache:                cfg.Cache,
+		router:               cfg.Router,
+		optimizer:            cfg.Optimizer,
+		merger:               cfg.Merger,
+		engines:              cfg.Engines,
+		metrics:              cfg.Metrics,
+		engineTimeouts:       cfg.EngineTimeouts,
+		minSuccessfulEngines: cfg.MinSuccessfulEngines,
+		fallbackEngines:      cfg.FallbackEngines,
+		perEngineLimit:       int32(cfg.PerEngineLimit),
+		didYouMeanThreshold:  didYouMeanThreshold,
+		highlighter:          highlighter,
+		suggestions:          suggestions,
+		tracer:               tracer,
+		queryLogger:          queryLogger,
+		indexTypes:           cfg.IndexTypes,
+		flights:              newSearchFlights(),
+		complexity:           cfg.Complexity,
+		wildcards:            cfg.Wildcards,
 	}
 }
 
@@ -63,19 +168,172 @@ func (s *SearchService) Search(ctx context.Context, req *model.SearchRequest) (*
 		"index", req.Index,
 	)
 
+	if err := s.complexity.check(req); err != nil {
+		return s.handleError(ctx, req, err), err
+	}
+	query, err := s.wildcards.apply(req.Query)
+	if err != nil {
+		return s.handleError(ctx, req, err), err
+	}
+	if query != req.Query {
+		s.logger.Debugw("Rewrote short-prefixed wildcards",
+			"request_id", req.RequestID,
+			"original", req.Query,
+			"rewritten", query,
+		)
+		guarded := *req
+		guarded.Query = query
+		req = &guarded
+	}
+
+	searchReq, suggestions := s.rewrite(ctx, req)
+	if req.DryRun {
+		return s.dryRun(ctx, req, searchReq), nil
+	}
+	cacheReq := s.cacheRequest(req, searchReq)
+
 	if s.cache != nil && s.cache.IsEnabled() {
-		cached, found := s.cache.GetSearchResponse(ctx, req)
+		cached, stale, found := s.cache.LookupSearchResponse(ctx, cacheReq)
 		if found {
 			s.logger.Infow("Cache hit",
 				"request_id", req.RequestID,
+				"stale", stale,
 				"took_ms", time.Since(startTime).Milliseconds(),
 			)
 			s.metrics.RecordCacheHit()
+			if stale {
+				go s.revalidate(ctx, cacheReq, searchReq, suggestions)
+			}
+			cached.RequestID = req.RequestID
+			s.recordQuery(req.Query, cached)
 			return cached, nil
 		}
 		s.metrics.RecordCacheMiss()
 	}
 
+	// Identical searches already executing share that execution, and its
+	// response, instead of fanning out to the engines again.
+	shared, engineResults, coalesced, err := s.flights.do(ctx, cache.SearchKey(cacheReq),
+		s.searchAndCache(cacheReq, searchReq, suggestions))
+	if err != nil {
+		return s.handleError(ctx, req, err), err
+	}
+	if coalesced {
+		s.logger.Debugw("Joined identical in-flight search",
+			"request_id", req.RequestID,
+		)
+	}
+	response := new(model.SearchResponse)
+	*response = *shared
+	response.RequestID = req.RequestID
+	s.recordQuery(req.Query, response)
+
+	totalTime := time.Since(startTime)
+	s.queryLogger.LogQuery(req.Query, req.Filters, engineTimings(engineResults),
+		float64(totalTime.Milliseconds()), len(response.Results), req.RequestID)
+
+	s.metrics.RecordSearchDuration(float64(totalTime.Milliseconds()))
+	s.metrics.RecordSearchResults(len(response.Results))
+
+	return response, nil
+}
+
+// searchAndCache returns the flight that executes searchReq and caches a
+// complete response under cacheReq.
+func (s *SearchService) searchAndCache(cacheReq, searchReq *model.SearchRequest, suggestions []string) func(context.Context) (*model.SearchResponse, map[string]*model.EngineResult, error) {
+	return func(ctx context.Context) (*model.SearchResponse, map[string]*model.EngineResult, error) {
+		response, engineResults, err := s.executeWithResults(ctx, searchReq)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(response.Results) < s.didYouMeanThreshold && len(suggestions) > 0 {
+			response.DidYouMean = suggestions[0]
+		}
+		if s.cache != nil && s.cache.IsEnabled() && !response.Degraded {
+			go s.cache.SetSearchResponse(context.Background(), cacheReq, response, 0)
+		}
+		return response, engineResults, nil
+	}
+}
+
+// revalidate refreshes a stale cache entry after it was served. It joins
+// any search already in flight for the key, so a burst of stale hits
+// refreshes the entry once, and outlives the request that triggered it.
+func (s *SearchService) revalidate(ctx context.Context, cacheReq, searchReq *model.SearchRequest, suggestions []string) {
+	ctx = context.WithoutCancel(ctx)
+	if _, _, _, err := s.flights.do(ctx, cache.SearchKey(cacheReq), s.searchAndCache(cacheReq, searchReq, suggestions)); err != nil {
+		s.logger.Warnw("Failed to refresh stale cache entry",
+			"request_id", cacheReq.RequestID,
+			"error", err,
+		)
+	}
+}
+
+// MultiSearch runs the queries concurrently under one shared deadline and
+// returns their responses in input order. A failed query yields a response
+// with Error set rather than failing the batch.
+func (s *SearchService) MultiSearch(ctx context.Context, reqs []*model.SearchRequest) ([]*model.SearchResponse, error) {
+	if len(reqs) == 0 {
+		return nil, util.NewAppError(400, "Empty multi-search", "at least one query is required")
+	}
+	if len(reqs) > MaxMultiSearchQueries {
+		return nil, util.NewAppError(400, "Multi-search too large",
+			fmt.Sprintf("%d queries exceeds the maximum of %d", len(reqs), MaxMultiSearchQueries))
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, defaultMultiSearchTimeout)
+	defer cancel()
+
+	responses := make([]*model.SearchResponse, len(reqs))
+	var wg sync.WaitGroup
+
+	for i, req := range reqs {
+		wg.Add(1)
+		go func(i int, req *model.SearchRequest) {
+			defer wg.Done()
+
+			response, err := s.Search(ctx, req)
+			if err != nil {
+				if response == nil {
+					response = s.handleError(ctx, req, err)
+				}
+				response.Error = err.Error()
+			}
+			responses[i] = response
+		}(i, req)
+	}
+
+	wg.Wait()
+	return responses, nil
+}
+
+// Suggest returns up to limit popular queries and document titles that
+// start with prefix. An empty prefix returns no suggestions.
+func (s *SearchService) Suggest(ctx context.Context, prefix string, limit int) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 {
+		limit = defaultSuggestLimit
+	} else if limit > MaxSuggestLimit {
+		limit = MaxSuggestLimit
+	}
+
+	return s.suggestions.Suggest(prefix, limit), nil
+}
+
+// recordQuery counts a query towards suggestion popularity when it found
+// something, so typos that match nothing are never suggested.
+func (s *SearchService) recordQuery(query string, response *model.SearchResponse) {
+	if len(response.Results) > 0 {
+		s.suggestions.Add(query, 1)
+	}
+}
+
+// rewrite returns the optimized request along with the optimizer's
+// spelling and phrase suggestions for the original query.
+func (s *SearchService) rewrite(ctx context.Context, req *model.SearchRequest) (*model.SearchRequest, []string) {
 	optimized := s.optimizer.Optimize(ctx, req)
 	if optimized.Rewritten {
 		s.logger.Debugw("Query rewritten",
@@ -86,64 +344,251 @@ func (s *SearchService) Search(ctx context.Context, req *model.SearchRequest) (*
 
 	searchReq := *req
 	searchReq.Query = optimized.RewrittenQuery
+	return &searchReq, optimized.Suggestions
+}
 
-	decision := s.router.Route(ctx, &searchReq)
-	
-	results, err := s.executeSearch(ctx, &searchReq, decision)
+// execute routes an already rewritten request to the engines and merges
+// their results, bypassing the cache.
+func (s *SearchService) execute(ctx context.Context, req *model.SearchRequest) (*model.SearchResponse, error) {
+	response, _, err := s.executeWithResults(ctx, req)
+	return response, err
+}
+
+// executeWithResults is execute that also returns the per-engine results
+// the response was merged from.
+func (s *SearchService) executeWithResults(ctx context.Context, req *model.SearchRequest) (*model.SearchResponse, map[string]*model.EngineResult, error) {
+	filters, err := merger.ParseFilters(req.Filters)
+	if err != nil {
+		return nil, nil, util.NewAppError(400, "Invalid filter", err.Error())
+	}
+
+	boolean, err := model.ParseBooleanQuery(req.Query)
+	if err != nil {
+		return nil, nil, util.NewAppError(400, "Invalid query", err.Error())
+	}
+
+	if req.PerEngineLimit < 0 || req.PerEngineLimit > MaxPerEngineLimit {
+		return nil, nil, util.NewAppError(400, "Invalid per-engine limit",
+			fmt.Sprintf("per_engine_limit must be between 0 and %d, got %d", MaxPerEngineLimit, req.PerEngineLimit))
+	}
+
+	var after *merger.Cursor
+	if req.SearchAfter != "" {
+		after, err = merger.DecodeCursor(req.SearchAfter, req.SortBy, req.SortOrder)
+		if err != nil {
+			return nil, nil, util.NewAppError(400, "Invalid cursor", err.Error())
+		}
+	}
+
+	decision := s.route(ctx, req)
+
+	results, err := s.executeSearch(ctx, s.engineRequest(req, after, decision.QueryInfo, boolean), decision)
 	if err != nil {
 		s.logger.Errorf("Search execution failed: %v", err)
-		return s.handleError(ctx, req, err), nil
+		return nil, nil, err
+	}
+
+	// Engines ignore filters, so apply them before merging to keep
+	// filtered-out documents from taking top-k slots.
+	if len(filters) > 0 {
+		for _, result := range results {
+			if result != nil {
+				result.Results = merger.FilterResults(result.Results, filters)
+			}
+		}
 	}
 
-	response := s.merger.Merge(results)
+	response := s.merger.MergeWithExplain(results, req.Explain)
+	if len(req.Facets) > 0 {
+		response.Facets = merger.ComputeFacets(response.Results, req.Facets)
+	}
+	response.Results, response.NextCursor = merger.Paginate(response.Results, after,
+		int(req.Offset), int(req.Limit), req.SortBy, req.SortOrder)
+	if req.Highlight {
+		s.highlighter.Apply(response.Results, req.Query, req.HighlightField)
+	}
 	response.RequestID = req.RequestID
 	response.QueryInfo = decision.QueryInfo
 	response.CacheHit = false
+	response.FailedEngines = failedEngines(results)
+	response.SkippedEngines = skippedEngines(results)
+	response.Degraded = len(response.FailedEngines) > 0 || len(response.SkippedEngines) > 0
+	if req.Explain {
+		response.Routing = decision.Explain()
+	}
 
-	if s.cache != nil && s.cache.IsEnabled() {
-		go s.cache.SetSearchResponse(context.Background(), req, response, s.config.Cache.DefaultTTL)
+	if response.Degraded {
+		s.logger.Warnw("Returning degraded search response",
+			"request_id", req.RequestID,
+			"failed_engines", response.FailedEngines,
+		)
 	}
 
-	totalTime := time.Since(startTime)
-	s.logger.Infow("Search completed",
-		"request_id", req.RequestID,
-		"results", len(response.Results),
-		"engines", response.EnginesUsed,
-		"took_ms", totalTime.Milliseconds(),
-	)
+	return response, results, nil
+}
 
-	s.metrics.RecordSearchDuration(float64(totalTime.Milliseconds()))
-	s.metrics.RecordSearchResults(len(response.Results))
+// route routes req, restricted to the engines of its index's type when
+// that is known.
+func (s *SearchService) route(ctx context.Context, req *model.SearchRequest) *router.RoutingDecision {
+	var indexType string
+	if s.indexTypes != nil && req.Index != "" {
+		indexType, _ = s.indexTypes.IndexType(req.Index)
+	}
+	return s.router.RouteForIndexType(ctx, req, indexType)
+}
 
-	return response, nil
+// dryRun routes searchReq, the rewritten form of req, and returns only the
+// routing explanation, without searching any engine or touching the cache.
+func (s *SearchService) dryRun(ctx context.Context, req, searchReq *model.SearchRequest) *model.SearchResponse {
+	decision := s.route(ctx, searchReq)
+	return &model.SearchResponse{
+		RequestID:   req.RequestID,
+		Results:     []model.SearchResult{},
+		EnginesUsed: []string{},
+		QueryInfo:   decision.QueryInfo,
+		Routing:     decision.Explain(),
+	}
+}
+
+// engineRequest is req as sent to each engine. Its limit is the candidate
+// pool size, widened to cover every result up to the end of the requested
+// page, since engines rank from the top and know nothing of offsets or
+// cursors. Limit itself only bounds the merged response. It also carries
+// the phrases the router parsed from the query and its boolean structure.
+func (s *SearchService) engineRequest(req *model.SearchRequest, after *merger.Cursor, queryInfo *model.QueryInfo, boolean *model.QueryNode) *model.SearchRequest {
+	if queryInfo != nil && len(queryInfo.Phrases) > 0 && len(req.Phrases) == 0 {
+		phrased := *req
+		phrased.Phrases = queryInfo.Phrases
+		req = &phrased
+	}
+	if boolean != nil && req.Boolean == nil {
+		parsed := *req
+		parsed.Boolean = boolean
+		req = &parsed
+	}
+	if req.Limit <= 0 {
+		return req
+	}
+	skip := req.Offset
+	if after != nil {
+		skip = int32(after.Position)
+	}
+	limit := req.Limit + skip
+	pool := req.PerEngineLimit
+	if pool == 0 {
+		pool = s.perEngineLimit
+	}
+	if pool > limit {
+		limit = pool
+	}
+	if limit == req.Limit {
+		return req
+	}
+	widened := *req
+	widened.Limit = limit
+	return &widened
 }
 
 func (s *SearchService) executeSearch(ctx context.Context, req *model.SearchRequest, decision *router.RoutingDecision) (map[string]*model.EngineResult, error) {
-	timeout := 800 * time.Millisecond
-	if req.Timeout > 0 {
-		timeout = req.Timeout
+	results, hasError := s.searchEngines(ctx, req, decision.Engines)
+
+	if fallback := s.fallbackFor(decision.StrategyName, results); len(fallback) > 0 {
+		s.logger.Warnw("All selected engines failed, retrying with fallback engines",
+			"strategy", decision.StrategyName,
+			"failed_engines", failedEngines(results),
+			"fallback_engines", fallback,
+		)
+		fallbackResults, _ := s.searchEngines(ctx, req, fallback)
+		for name, result := range fallbackResults {
+			results[name] = result
+		}
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, timeout)
-	defer cancel()
+	if len(results) == 0 {
+		return nil, fmt.Errorf("no engines available")
+	}
 
-	results := make(map[string]*model.EngineResult)
+	successful := successfulEngines(results)
+	if s.minSuccessfulEngines > 0 && successful < s.minSuccessfulEngines {
+		return nil, util.NewAppError(503, "Insufficient engines succeeded",
+			fmt.Sprintf("%d of %d engines succeeded, %d required", successful, len(results), s.minSuccessfulEngines))
+	}
+
+	if hasError && successful > 0 {
+		s.logger.Warnw("Some engines failed, continuing with available results",
+			"total_engines", len(results),
+			"successful", successful,
+		)
+	}
+
+	return results, nil
+}
+
+// fallbackFor returns the fallback engines of strategy that were not
+// already tried, or nil while any selected engine succeeded.
+func (s *SearchService) fallbackFor(strategy string, results map[string]*model.EngineResult) []string {
+	if successfulEngines(results) > 0 {
+		return nil
+	}
+	var fallback []string
+	for _, name := range s.fallbackEngines[strategy] {
+		if _, tried := results[name]; !tried {
+			fallback = append(fallback, name)
+		}
+	}
+	return fallback
+}
+
+// searchEngines queries the named engines concurrently. A failed engine
+// gets a result with Error set; hasError reports whether any failed. An
+// engine whose circuit breaker is open is not queried and gets a result
+// with Skipped set instead, since the breaker would reject the call.
+func (s *SearchService) searchEngines(ctx context.Context, req *model.SearchRequest, names []string) (results map[string]*model.EngineResult, hasError bool) {
+	results = make(map[string]*model.EngineResult)
 	var mu sync.Mutex
 	var wg sync.WaitGroup
-	var hasError bool
 
-	for _, engineName := range decision.Engines {
+	for _, engineName := range names {
 		client, exists := s.engines[engineName]
 		if !exists {
 			s.logger.Warnf("Engine %s not configured", engineName)
 			continue
 		}
+		if client.GetCircuitBreakerState() == engine.StateOpen.String() {
+			s.logger.Warnw("Skipping engine with an open circuit breaker",
+				"engine", engineName,
+			)
+			mu.Lock()
+			results[engineName] = &model.EngineResult{
+				Engine:  engineName,
+				Results: []model.SearchResult{},
+				Skipped: true,
+			}
+			mu.Unlock()
+			continue
+		}
 
 		wg.Add(1)
 		go func(name string, client engine.EngineClient) {
 			defer wg.Done()
 
+			ctx, span := s.tracer.Start(ctx, "Engine.Search",
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(
+					attribute.String("engine", name),
+					attribute.String("index", req.Index),
+				))
+			defer span.End()
+
+			ctx, cancel := context.WithTimeout(ctx, s.engineTimeout(name, req))
+			defer cancel()
+
+			start := time.Now()
 			result, err := client.Search(ctx, req)
+			took := time.Since(start)
+			span.SetAttributes(attribute.Int64("took_ms", took.Milliseconds()))
+			s.metrics.RecordEngineLatency(name, "search", took)
+			s.metrics.RecordSearchRequest(name)
 			
 			mu.Lock()
 			defer mu.Unlock()
@@ -153,16 +598,25 @@ func (s *SearchService) executeSearch(ctx context.Context, req *model.SearchRequ
 					"engine", name,
 					"error", err,
 				)
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+				span.SetAttributes(attribute.Bool("timed_out", ctx.Err() == context.DeadlineExceeded))
+				errorType := "error"
+				if ctx.Err() == context.DeadlineExceeded {
+					errorType = "timeout"
+				}
+				s.metrics.RecordSearchError(name, errorType)
 				results[name] = &model.EngineResult{
 					Engine:   name,
 					Results:  []model.SearchResult{},
 					Total:    0,
-					Took:     0,
+					Took:     float64(took.Milliseconds()),
 					Error:    err.Error(),
 					TimedOut: ctx.Err() == context.DeadlineExceeded,
 				}
 				hasError = true
 			} else {
+				span.SetAttributes(attribute.Int("result_count", len(result.Results)))
 				results[name] = result
 			}
 		}(engineName, client)
@@ -170,18 +624,39 @@ func (s *SearchService) executeSearch(ctx context.Context, req *model.SearchRequ
 
 	wg.Wait()
 
-	if len(results) == 0 {
-		return nil, fmt.Errorf("no engines available")
+	return results, hasError
+}
+
+// cacheRequest returns the request used to derive the cache key. With
+// normalized keys enabled, queries that rewrite to the same canonical form
+// share one cache entry. Each tenant gets its own entries unless tenants
+// are configured to share them.
+func (s *SearchService) cacheRequest(original, rewritten *model.SearchRequest) *model.SearchRequest {
+	req := original
+	if s.config != nil && s.config.Cache.NormalizeKeys {
+		req = rewritten
+	}
+	if req.Tenant != "" && s.config != nil && s.config.Cache.SharedTenants {
+		shared := *req
+		shared.Tenant = ""
+		req = &shared
 	}
+	return req
+}
 
-	if hasError && len(results) > 1 {
-		s.logger.Warnw("Some engines failed, continuing with available results",
-			"total_engines", len(decision.Engines),
-			"successful", len(results),
-		)
+func (s *SearchService) engineTimeout(name string, req *model.SearchRequest) time.Duration {
+	timeout := defaultSearchTimeout
+	if req.Timeout > 0 {
+		timeout = req.Timeout
 	}
 
-	return results, nil
+	if engineTimeout, ok := s.engineTimeouts[name]; ok && engineTimeout > 0 {
+		if req.Timeout <= 0 || engineTimeout < req.Timeout {
+			timeout = engineTimeout
+		}
+	}
+
+	return timeout
 }
 
 func (s *SearchService) handleError(ctx context.Context, req *model.SearchRequest, err error) *model.SearchResponse {
@@ -203,13 +678,54 @@ func (s *SearchService) handleError(ctx context.Context, req *model.SearchReques
 	return response
 }
 
-func (s *SearchService) HealthCheck(ctx context.Context) map[string]bool {
-	health := make(map[string]bool)
-	
-	for name, client := range s.engines {
-		health[name] = client.HealthCheck(ctx)
+// HealthCheck pings every engine concurrently and reports each one's
+// round-trip latency and circuit breaker state, ordered by name. Engines
+// that cannot be pinged fall back to their connection state.
+func (s *SearchService) HealthCheck(ctx context.Context) []model.EngineHealth {
+	names := make([]string, 0, len(s.engines))
+	for name := range s.engines {
+		names = append(names, name)
 	}
-	
+	sort.Strings(names)
+
+	health := make([]model.EngineHealth, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, name string, client engine.EngineClient) {
+			defer wg.Done()
+			health[i] = engineHealth(ctx, name, client)
+		}(i, name, s.engines[name])
+	}
+	wg.Wait()
+
+	return health
+}
+
+func engineHealth(ctx context.Context, name string, client engine.EngineClient) model.EngineHealth {
+	health := model.EngineHealth{
+		Name:           name,
+		Status:         "unhealthy",
+		CircuitBreaker: client.GetCircuitBreakerState(),
+		FailureCount:   client.GetCircuitBreakerFailures(),
+	}
+
+	pinger, ok := client.(engine.HealthPinger)
+	if !ok {
+		if client.HealthCheck(ctx) {
+			health.Status = "healthy"
+		}
+		return health
+	}
+
+	health.Address = pinger.Address()
+	latency, err := pinger.Ping(ctx)
+	health.Latency = float64(latency.Microseconds()) / 1000
+	if err != nil {
+		health.Error = err.Error()
+		return health
+	}
+	health.Status = "healthy"
 	return health
 }
 
@@ -231,9 +747,77 @@ func (s *SearchService) WarmupCache(ctx context.Context, queries []string, index
 	if s.cache == nil {
 		return nil
 	}
-	return s.cache.Warmup(ctx, queries, index)
+
+	if s.config != nil && s.config.Cache.NormalizeKeys {
+		rewritten := make([]string, len(queries))
+		for i, query := range queries {
+			searchReq, _ := s.rewrite(ctx, &model.SearchRequest{Query: query, Index: index})
+			rewritten[i] = searchReq.Query
+		}
+		return s.cache.Warmup(ctx, rewritten, index, s.execute)
+	}
+
+	return s.cache.Warmup(ctx, queries, index, func(ctx context.Context, req *model.SearchRequest) (*model.SearchResponse, error) {
+		searchReq, _ := s.rewrite(ctx, req)
+		return s.execute(ctx, searchReq)
+	})
+}
+
+// engineTimings summarises results for the query logger, sorted by engine.
+func engineTimings(results map[string]*model.EngineResult) []util.EngineTiming {
+	timings := make([]util.EngineTiming, 0, len(results))
+	for name, result := range results {
+		if result == nil {
+			continue
+		}
+		timings = append(timings, util.EngineTiming{
+			Engine:   name,
+			TookMs:   result.Took,
+			Results:  len(result.Results),
+			Error:    result.Error,
+			TimedOut: result.TimedOut,
+		})
+	}
+	sort.Slice(timings, func(i, j int) bool { return timings[i].Engine < timings[j].Engine })
+	return timings
+}
+
+// skippedEngines lists the engines that were not queried, sorted.
+func skippedEngines(results map[string]*model.EngineResult) []string {
+	var skipped []string
+	for name, result := range results {
+		if result != nil && result.Skipped {
+			skipped = append(skipped, name)
+		}
+	}
+	sort.Strings(skipped)
+	return skipped
+}
+
+// successfulEngines counts the engines that answered.
+func successfulEngines(results map[string]*model.EngineResult) int {
+	n := 0
+	for _, result := range results {
+		if result != nil && !result.Skipped && result.Error == "" && !result.TimedOut {
+			n++
+		}
+	}
+	return n
+}
+
+func failedEngines(results map[string]*model.EngineResult) []string {
+	var failed []string
+	for name, result := range results {
+		if result != nil && (result.Error != "" || result.TimedOut) {
+			failed = append(failed, name)
+		}
+	}
+	sort.Strings(failed)
+	return failed
 }
 
+// generateRequestID returns a random UUIDv4-based ID. Callers that already
+// have an ID, such as one propagated from the gateway, set RequestID instead.
 func generateRequestID() string {
-	return fmt.Sprintf("req-%d", time.Now().UnixNano())
+	return "req-" + uuid.NewString()
 }
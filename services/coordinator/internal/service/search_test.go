@@ -0,0 +1,1554 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/flexsearch/coordinator/internal/cache"
+	"github.com/flexsearch/coordinator/internal/config"
+	"github.com/flexsearch/coordinator/internal/engine"
+	"github.com/flexsearch/coordinator/internal/merger"
+	"github.com/flexsearch/coordinator/internal/model"
+	"github.com/flexsearch/coordinator/internal/router"
+	"github.com/flexsearch/coordinator/internal/suggest"
+	"github.com/flexsearch/coordinator/internal/util"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+var testMetrics = util.NewMetrics("coordinator_test")
+
+func newTestSearchService(t *testing.T, engines map[string]engine.EngineClient, opts ...func(*SearchServiceConfig)) *SearchService {
+	t.Helper()
+
+	logger := newTestLogger(t)
+	cfg := &SearchServiceConfig{
+		Config:    &config.Config{},
+		Logger:    logger,
+		Router:    router.NewRouter(logger),
+		Optimizer: router.NewOptimizer(logger),
+		Merger:    merger.NewMerger("rrf", &merger.MergerConfig{RRFK: 60, TopK: 100}, logger),
+		Engines:   engines,
+		Metrics:   testMetrics,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return NewSearchService(cfg)
+}
+
+func newTestCache(t *testing.T) (*cache.RedisCache, *miniredis.Miniredis) {
+	t.Helper()
+	return newTestCacheWith(t, func(*cache.CacheConfig) {})
+}
+
+func newTestCacheWith(t *testing.T, configure func(*cache.CacheConfig)) (*cache.RedisCache, *miniredis.Miniredis) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	port, _ := strconv.Atoi(mr.Port())
+
+	config := &cache.CacheConfig{
+		Enabled:    true,
+		Host:       mr.Host(),
+		Port:       port,
+		DefaultTTL: time.Minute,
+	}
+	configure(config)
+
+	redisCache, err := cache.NewRedisCache(config, newTestLogger(t))
+	if err != nil {
+		t.Fatalf("Failed to create cache: %v", err)
+	}
+	t.Cleanup(func() { redisCache.Close() })
+
+	return redisCache, mr
+}
+
+func waitForKeys(t *testing.T, mr *miniredis.Miniredis, n int) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if len(mr.Keys()) >= n {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("Expected %d cache keys, got %d", n, len(mr.Keys()))
+}
+
+func fakeResults(prefix string, n int) []model.SearchResult {
+	results := make([]model.SearchResult, n)
+	for i := range results {
+		results[i] = model.SearchResult{
+			ID:           fmt.Sprintf("%s-%d", prefix, i),
+			Score:        1.0 - float64(i)*0.1,
+			EngineSource: prefix,
+		}
+	}
+	return results
+}
+
+func TestSearchServiceHealthCheckReportsBreakers(t *testing.T) {
+	engines, fakes := newFakeEngines()
+	fakes["vector"].cbState = "open"
+	fakes["vector"].failures = 5
+
+	svc := NewSearchService(&SearchServiceConfig{
+		Logger:  newTestLogger(t),
+		Engines: engines,
+	})
+
+	statuses := svc.HealthCheck(context.Background())
+	if len(statuses) != 3 {
+		t.Fatalf("Expected 3 engine statuses, got %d", len(statuses))
+	}
+
+	for _, status := range statuses {
+		switch status.Name {
+		case "vector":
+			if status.CircuitBreaker != "open" {
+				t.Errorf("Expected vector breaker to be open, got %s", status.CircuitBreaker)
+			}
+			if status.FailureCount != 5 {
+				t.Errorf("Expected 5 failures, got %d", status.FailureCount)
+			}
+		default:
+			if status.CircuitBreaker != "closed" {
+				t.Errorf("Expected %s breaker to be closed, got %s", status.Name, status.CircuitBreaker)
+			}
+		}
+	}
+}
+
+func TestSearchServiceHealthCheck(t *testing.T) {
+	engines, fakes := newFakeEngines()
+	fakes["bm25"].pingDelay = 20 * time.Millisecond
+	fakes["vector"].pingErr = fmt.Errorf("connection refused")
+
+	svc := newTestSearchService(t, engines)
+
+	health := svc.HealthCheck(context.Background())
+	if len(health) != 3 {
+		t.Fatalf("Expected 3 engine health entries, got %d", len(health))
+	}
+	if health[0].Name != "bm25" || health[1].Name != "flexsearch" || health[2].Name != "vector" {
+		t.Fatalf("Expected engines ordered by name, got %+v", health)
+	}
+
+	bm25 := health[0]
+	if bm25.Status != "healthy" || bm25.Latency < 20 {
+		t.Errorf("Expected bm25 healthy with latency >= 20ms, got %+v", bm25)
+	}
+	if bm25.Address != "bm25:50051" {
+		t.Errorf("Expected bm25 address, got %q", bm25.Address)
+	}
+	if health[1].Status != "healthy" || health[1].Error != "" {
+		t.Errorf("Expected flexsearch healthy, got %+v", health[1])
+	}
+
+	vector := health[2]
+	if vector.Status != "unhealthy" || vector.Error != "connection refused" {
+		t.Errorf("Expected vector unhealthy with its error, got %+v", vector)
+	}
+}
+
+func TestSearchServicePerEngineTimeouts(t *testing.T) {
+	engines, fakes := newFakeEngines()
+	fakes["bm25"].results = fakeResults("bm25", 3)
+	fakes["flexsearch"].results = fakeResults("flexsearch", 2)
+	fakes["vector"].results = fakeResults("vector", 3)
+	fakes["vector"].searchDelay = 300 * time.Millisecond
+
+	svc := newTestSearchService(t, engines, func(cfg *SearchServiceConfig) {
+		cfg.EngineTimeouts = map[string]time.Duration{
+			"bm25":   200 * time.Millisecond,
+			"vector": 50 * time.Millisecond,
+		}
+	})
+
+	start := time.Now()
+	resp, err := svc.Search(context.Background(), &model.SearchRequest{
+		Query:   "test query",
+		Limit:   10,
+		Engines: []string{"bm25", "vector", "flexsearch"},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 250*time.Millisecond {
+		t.Errorf("Expected vector to time out at its own deadline, took %v", elapsed)
+	}
+
+	if len(resp.Results) != 5 {
+		t.Errorf("Expected 5 results from the fast engines, got %d", len(resp.Results))
+	}
+	for _, result := range resp.Results {
+		if result.EngineSource == "vector" {
+			t.Error("Expected no results from the timed out vector engine")
+		}
+	}
+}
+
+func TestSearchServiceEngineTimeout(t *testing.T) {
+	svc := newTestSearchService(t, nil, func(cfg *SearchServiceConfig) {
+		cfg.EngineTimeouts = map[string]time.Duration{"vector": 2 * time.Second}
+	})
+
+	if got := svc.engineTimeout("bm25", &model.SearchRequest{}); got != defaultSearchTimeout {
+		t.Errorf("Expected default timeout for bm25, got %v", got)
+	}
+	if got := svc.engineTimeout("vector", &model.SearchRequest{}); got != 2*time.Second {
+		t.Errorf("Expected 2s for vector, got %v", got)
+	}
+	if got := svc.engineTimeout("vector", &model.SearchRequest{Timeout: time.Second}); got != time.Second {
+		t.Errorf("Expected request timeout to cap vector at 1s, got %v", got)
+	}
+	if got := svc.engineTimeout("bm25", &model.SearchRequest{Timeout: 3 * time.Second}); got != 3*time.Second {
+		t.Errorf("Expected request timeout for bm25, got %v", got)
+	}
+}
+
+func TestSearchServiceDegradedResponse(t *testing.T) {
+	engines, fakes := newFakeEngines()
+	fakes["bm25"].results = fakeResults("bm25", 3)
+	fakes["flexsearch"].results = fakeResults("flexsearch", 3)
+	fakes["vector"].searchErr = fmt.Errorf("vector unavailable")
+
+	svc := newTestSearchService(t, engines)
+
+	resp, err := svc.Search(context.Background(), &model.SearchRequest{
+		Query:   "test query",
+		Limit:   10,
+		Engines: []string{"bm25", "flexsearch", "vector"},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !resp.Degraded {
+		t.Error("Expected response to be marked degraded")
+	}
+	if len(resp.FailedEngines) != 1 || resp.FailedEngines[0] != "vector" {
+		t.Errorf("Expected failed engines [vector], got %v", resp.FailedEngines)
+	}
+	if len(resp.Results) != 6 {
+		t.Errorf("Expected 6 results from healthy engines, got %d", len(resp.Results))
+	}
+}
+
+func TestSearchServiceCompleteResponse(t *testing.T) {
+	engines, fakes := newFakeEngines()
+	for name, fake := range fakes {
+		fake.results = fakeResults(name, 2)
+	}
+
+	svc := newTestSearchService(t, engines)
+
+	resp, err := svc.Search(context.Background(), &model.SearchRequest{
+		Query:   "test query",
+		Limit:   10,
+		Engines: []string{"bm25", "flexsearch", "vector"},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if resp.Degraded {
+		t.Error("Expected complete response not to be degraded")
+	}
+	if len(resp.FailedEngines) != 0 {
+		t.Errorf("Expected no failed engines, got %v", resp.FailedEngines)
+	}
+}
+
+func TestSearchServiceFacets(t *testing.T) {
+	engines, fakes := newFakeEngines()
+	delete(engines, "vector")
+
+	authors := []string{"alice", "bob", "alice", "carol", "alice", "bob"}
+	results := make([]model.SearchResult, len(authors))
+	for i, author := range authors {
+		results[i] = model.SearchResult{
+			ID:     fmt.Sprintf("doc-%d", i),
+			Score:  1.0 - float64(i)*0.1,
+			Fields: map[string]interface{}{"author": author},
+		}
+	}
+	// Both engines return the same documents; facets count merged results.
+	fakes["bm25"].results = results
+	fakes["flexsearch"].results = results
+
+	svc := newTestSearchService(t, engines)
+
+	resp, err := svc.Search(context.Background(), &model.SearchRequest{
+		Query:  "test query",
+		Limit:  10,
+		Facets: []string{"author"},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	want := []model.FacetBucket{
+		{Value: "alice", Count: 3},
+		{Value: "bob", Count: 2},
+		{Value: "carol", Count: 1},
+	}
+	got := resp.Facets["author"]
+	if len(got) != len(want) {
+		t.Fatalf("Expected author buckets %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Expected bucket %d to be %v, got %v", i, want[i], got[i])
+		}
+	}
+}
+
+func TestSearchServiceFilters(t *testing.T) {
+	engines, fakes := newFakeEngines()
+	for name, fake := range fakes {
+		results := fakeResults(name, 4)
+		for i := range results {
+			lang := "en"
+			if i%2 == 1 {
+				lang = "fr"
+			}
+			results[i].Fields = map[string]interface{}{"lang": lang}
+		}
+		fake.results = results
+	}
+
+	svc := newTestSearchService(t, engines)
+
+	resp, err := svc.Search(context.Background(), &model.SearchRequest{
+		Query:   "test query",
+		Limit:   10,
+		Engines: []string{"bm25", "flexsearch", "vector"},
+		Filters: map[string]string{"lang": "fr"},
+		Facets:  []string{"lang"},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(resp.Results) != 6 {
+		t.Errorf("Expected 6 results matching the filter, got %d", len(resp.Results))
+	}
+	for _, result := range resp.Results {
+		if result.Fields["lang"] != "fr" {
+			t.Errorf("Expected filtered-out result %s not to appear", result.ID)
+		}
+	}
+	if buckets := resp.Facets["lang"]; len(buckets) != 1 || buckets[0].Value != "fr" {
+		t.Errorf("Expected facets over filtered results only, got %v", buckets)
+	}
+}
+
+func TestSearchServiceInvalidFilter(t *testing.T) {
+	engines, fakes := newFakeEngines()
+	svc := newTestSearchService(t, engines)
+
+	_, err := svc.Search(context.Background(), &model.SearchRequest{
+		Query:   "test query",
+		Filters: map[string]string{"price": "[10 TO"},
+	})
+
+	appErr, ok := err.(*util.AppError)
+	if !ok || appErr.Code != 400 {
+		t.Fatalf("Expected a 400 AppError, got %v", err)
+	}
+	for name, fake := range fakes {
+		if fake.searchCount() != 0 {
+			t.Errorf("Expected %s not to be queried for an invalid filter", name)
+		}
+	}
+}
+
+func TestSearchServiceSortByField(t *testing.T) {
+	engines, fakes := newFakeEngines()
+	years := []int{2001, 1999, 2024, 2010}
+	for name, fake := range fakes {
+		results := fakeResults(name, len(years))
+		for i := range results {
+			results[i].Fields = map[string]interface{}{"year": years[i]}
+		}
+		fake.results = results
+	}
+
+	svc := newTestSearchService(t, engines)
+
+	resp, err := svc.Search(context.Background(), &model.SearchRequest{
+		Query:     "test query",
+		Limit:     20,
+		Engines:   []string{"bm25", "flexsearch", "vector"},
+		SortBy:    "year",
+		SortOrder: "desc",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(resp.Results) != 12 {
+		t.Fatalf("Expected 12 results, got %d", len(resp.Results))
+	}
+	for i := 1; i < len(resp.Results); i++ {
+		prev, cur := resp.Results[i-1], resp.Results[i]
+		if prev.Fields["year"].(int) < cur.Fields["year"].(int) {
+			t.Fatalf("Expected results sorted by year descending, got %v before %v", prev.Fields["year"], cur.Fields["year"])
+		}
+		if cur.Rank != int32(i+1) {
+			t.Errorf("Expected result %d to have rank %d, got %d", i, i+1, cur.Rank)
+		}
+	}
+}
+
+func TestSearchServiceHighlightFallback(t *testing.T) {
+	engines, fakes := newFakeEngines()
+	delete(engines, "vector")
+	fakes["bm25"].results = []model.SearchResult{
+		{ID: "doc-1", Score: 0.9, Content: "Distributed search with Go"},
+		{ID: "doc-2", Score: 0.8, Content: "Nothing relevant here"},
+	}
+	fakes["flexsearch"].results = fakes["bm25"].results
+
+	svc := newTestSearchService(t, engines)
+
+	resp, err := svc.Search(context.Background(), &model.SearchRequest{
+		Query:     "distributed",
+		Limit:     10,
+		Highlight: true,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for _, result := range resp.Results {
+		got := result.Highlight["content"]
+		switch result.ID {
+		case "doc-1":
+			if got != "<em>Distributed</em> search with Go" {
+				t.Errorf("Expected generated highlight for doc-1, got %q", got)
+			}
+		case "doc-2":
+			if result.Highlight != nil {
+				t.Errorf("Expected no highlight for doc-2, got %v", result.Highlight)
+			}
+		}
+	}
+}
+
+func TestSearchServiceDidYouMean(t *testing.T) {
+	engines, fakes := newFakeEngines()
+	svc := newTestSearchService(t, engines)
+
+	resp, err := svc.Search(context.Background(), &model.SearchRequest{Query: "serch"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if resp.DidYouMean != "search" {
+		t.Errorf("Expected did-you-mean %q for a misspelled query, got %q", "search", resp.DidYouMean)
+	}
+
+	for name, fake := range fakes {
+		fake.results = fakeResults(name, 5)
+	}
+
+	resp, err = svc.Search(context.Background(), &model.SearchRequest{Query: "serch"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if resp.DidYouMean != "" {
+		t.Errorf("Expected no did-you-mean above the result threshold, got %q", resp.DidYouMean)
+	}
+
+	resp, err = svc.Search(context.Background(), &model.SearchRequest{Query: "database"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if resp.DidYouMean != "" {
+		t.Errorf("Expected no did-you-mean for a correctly spelled query, got %q", resp.DidYouMean)
+	}
+}
+
+func TestSearchServiceSuggest(t *testing.T) {
+	engines, fakes := newFakeEngines()
+	for name, fake := range fakes {
+		fake.results = fakeResults(name, 2)
+	}
+
+	trie := suggest.NewTrie()
+	svc := newTestSearchService(t, engines, func(cfg *SearchServiceConfig) {
+		cfg.Suggestions = trie
+	})
+	docs := NewDocumentService(&DocumentServiceConfig{
+		Logger:      newTestLogger(t),
+		Engines:     engines,
+		Suggestions: trie,
+	})
+
+	ctx := context.Background()
+	for _, query := range []string{"golang tutorial", "golang tutorial", "Go generics"} {
+		if _, err := svc.Search(ctx, &model.SearchRequest{Query: query}); err != nil {
+			t.Fatalf("Search(%q) failed: %v", query, err)
+		}
+	}
+	if _, err := docs.AddDocument(ctx, &model.DocumentRequest{ID: "1", Index: "docs", Title: "Golang Concurrency"}); err != nil {
+		t.Fatalf("AddDocument failed: %v", err)
+	}
+
+	got, err := svc.Suggest(ctx, "GO", 10)
+	if err != nil {
+		t.Fatalf("Suggest failed: %v", err)
+	}
+	want := []string{"golang tutorial", "Go generics", "Golang Concurrency"}
+	if len(got) != len(want) {
+		t.Fatalf("Expected suggestions %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Expected suggestions %v, got %v", want, got)
+		}
+	}
+
+	if got, _ := svc.Suggest(ctx, "go", 1); len(got) != 1 || got[0] != "golang tutorial" {
+		t.Errorf("Expected the limit to keep only the most popular suggestion, got %v", got)
+	}
+	if got, _ := svc.Suggest(ctx, "", 10); len(got) != 0 {
+		t.Errorf("Expected no suggestions for an empty prefix, got %v", got)
+	}
+}
+
+func TestSearchServiceMinSuccessfulEngines(t *testing.T) {
+	tests := []struct {
+		name      string
+		min       int
+		expectErr bool
+	}{
+		{"require two", 2, true},
+		{"require one", 1, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engines, fakes := newFakeEngines()
+			delete(engines, "flexsearch")
+			fakes["bm25"].results = fakeResults("bm25", 3)
+			fakes["vector"].searchErr = fmt.Errorf("vector unavailable")
+
+			svc := newTestSearchService(t, engines, func(cfg *SearchServiceConfig) {
+				cfg.MinSuccessfulEngines = tt.min
+			})
+
+			resp, err := svc.Search(context.Background(), &model.SearchRequest{
+				Query:   "hybrid query",
+				Limit:   10,
+				Engines: []string{"bm25", "vector"},
+			})
+
+			if tt.expectErr {
+				if err == nil {
+					t.Fatal("Expected error when too few engines succeed")
+				}
+				if len(resp.Results) != 0 {
+					t.Errorf("Expected no partial results, got %d", len(resp.Results))
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if len(resp.Results) != 3 {
+				t.Errorf("Expected 3 results from bm25, got %d", len(resp.Results))
+			}
+		})
+	}
+}
+
+func TestSearchServiceNormalizedCacheKeys(t *testing.T) {
+	tests := []struct {
+		name          string
+		normalizeKeys bool
+		expectHit     bool
+	}{
+		{"normalized", true, true},
+		{"raw", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engines, fakes := newFakeEngines()
+			fakes["bm25"].results = fakeResults("bm25", 3)
+			redisCache, mr := newTestCache(t)
+
+			svc := newTestSearchService(t, engines, func(cfg *SearchServiceConfig) {
+				cfg.Cache = redisCache
+				cfg.Config.Cache.NormalizeKeys = tt.normalizeKeys
+			})
+
+			ctx := context.Background()
+			first, err := svc.Search(ctx, &model.SearchRequest{Query: "the quick fox", Limit: 10, Engines: []string{"bm25"}})
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if first.CacheHit {
+				t.Error("Expected first search to miss the cache")
+			}
+			waitForKeys(t, mr, 1)
+
+			second, err := svc.Search(ctx, &model.SearchRequest{Query: "quick fox", Limit: 10, Engines: []string{"bm25"}, RequestID: "req-second"})
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if second.CacheHit != tt.expectHit {
+				t.Errorf("Expected cache hit %v, got %v", tt.expectHit, second.CacheHit)
+			}
+			if second.RequestID != "req-second" {
+				t.Errorf("Expected caller's request id, got %s", second.RequestID)
+			}
+
+			wantSearches := 2
+			if tt.expectHit {
+				wantSearches = 1
+			}
+			if got := fakes["bm25"].searchCount(); got != wantSearches {
+				t.Errorf("Expected %d engine searches, got %d", wantSearches, got)
+			}
+		})
+	}
+}
+
+func TestSearchServiceWarmupCache(t *testing.T) {
+	engines, fakes := newFakeEngines()
+	fakes["bm25"].results = fakeResults("bm25", 3)
+	redisCache, mr := newTestCache(t)
+
+	svc := newTestSearchService(t, engines, func(cfg *SearchServiceConfig) {
+		cfg.Cache = redisCache
+		cfg.Config.Cache.NormalizeKeys = true
+	})
+
+	ctx := context.Background()
+	if err := svc.WarmupCache(ctx, []string{"the quick fox"}, "docs"); err != nil {
+		t.Fatalf("WarmupCache failed: %v", err)
+	}
+	if !mr.Exists(redisCache.GenerateCacheKey(&model.SearchRequest{Query: "quick fox", Index: "docs", Limit: 10})) {
+		t.Fatal("Expected warmed entry under the normalized key")
+	}
+
+	searches := fakes["bm25"].searchCount() + fakes["flexsearch"].searchCount() + fakes["vector"].searchCount()
+
+	resp, err := svc.Search(ctx, &model.SearchRequest{Query: "quick fox", Index: "docs", Limit: 10})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !resp.CacheHit {
+		t.Error("Expected search to be served from the warmed cache")
+	}
+
+	after := fakes["bm25"].searchCount() + fakes["flexsearch"].searchCount() + fakes["vector"].searchCount()
+	if after != searches {
+		t.Errorf("Expected no engine searches after warmup, got %d", after-searches)
+	}
+}
+
+func setFakeResults(fake *fakeEngine, results []model.SearchResult) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	fake.results = results
+}
+
+func TestSearchServiceServesStaleWhileRevalidating(t *testing.T) {
+	engines, fakes := newFakeEngines()
+	fakes["bm25"].results = fakeResults("old", 2)
+	redisCache, _ := newTestCacheWith(t, func(config *cache.CacheConfig) {
+		config.SoftTTL = 20 * time.Millisecond
+		config.HardTTL = time.Minute
+	})
+	svc := newTestSearchService(t, engines, func(cfg *SearchServiceConfig) {
+		cfg.Cache = redisCache
+	})
+
+	ctx := context.Background()
+	req := func() *model.SearchRequest {
+		return &model.SearchRequest{Query: "quick fox", Limit: 10, Engines: []string{"bm25"}}
+	}
+	if _, err := svc.Search(ctx, req()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	deadline := time.Now().Add(time.Second)
+	for _, found := redisCache.GetSearchResponse(ctx, req()); !found; _, found = redisCache.GetSearchResponse(ctx, req()) {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the response to be cached")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	setFakeResults(fakes["bm25"], fakeResults("new", 2))
+
+	stale, err := svc.Search(ctx, req())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !stale.CacheHit || stale.Results[0].ID != "old-0" {
+		t.Fatalf("Expected the stale response to be served, got cache hit %v and %s", stale.CacheHit, stale.Results[0].ID)
+	}
+
+	deadline = time.Now().Add(time.Second)
+	for {
+		cached, found := redisCache.GetSearchResponse(ctx, req())
+		if found && cached.Results[0].ID == "new-0" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the background refresh to update the cached response")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := fakes["bm25"].searchCount(); got != 2 {
+		t.Errorf("Expected one search plus one refresh, got %d engine searches", got)
+	}
+}
+
+func TestSearchServiceRecomputesHardExpiredEntries(t *testing.T) {
+	engines, fakes := newFakeEngines()
+	fakes["bm25"].results = fakeResults("old", 2)
+	redisCache, mr := newTestCacheWith(t, func(config *cache.CacheConfig) {
+		config.SoftTTL = time.Minute
+		config.HardTTL = 10 * time.Minute
+	})
+	svc := newTestSearchService(t, engines, func(cfg *SearchServiceConfig) {
+		cfg.Cache = redisCache
+	})
+
+	ctx := context.Background()
+	req := &model.SearchRequest{Query: "quick fox", Limit: 10, Engines: []string{"bm25"}}
+	if _, err := svc.Search(ctx, req); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	waitForKeys(t, mr, 1)
+
+	mr.FastForward(11 * time.Minute)
+	setFakeResults(fakes["bm25"], fakeResults("new", 2))
+
+	resp, err := svc.Search(ctx, &model.SearchRequest{Query: "quick fox", Limit: 10, Engines: []string{"bm25"}})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if resp.CacheHit || resp.Results[0].ID != "new-0" {
+		t.Errorf("Expected a synchronous recompute, got cache hit %v and %s", resp.CacheHit, resp.Results[0].ID)
+	}
+	if got := fakes["bm25"].searchCount(); got != 2 {
+		t.Errorf("Expected 2 engine searches, got %d", got)
+	}
+}
+
+func TestSearchServiceKeepsTenantsApartInCache(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		shared    bool
+		expectHit bool
+	}{
+		{"isolated", false, false},
+		{"shared", true, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			engines, fakes := newFakeEngines()
+			fakes["bm25"].results = fakeResults("bm25", 2)
+			redisCache, mr := newTestCache(t)
+			svc := newTestSearchService(t, engines, func(cfg *SearchServiceConfig) {
+				cfg.Cache = redisCache
+				cfg.Config.Cache.SharedTenants = tt.shared
+			})
+
+			ctx := context.Background()
+			search := func(tenant string) *model.SearchResponse {
+				resp, err := svc.Search(ctx, &model.SearchRequest{Query: "quick fox", Index: "docs", Limit: 10, Engines: []string{"bm25"}, Tenant: tenant})
+				if err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+				return resp
+			}
+
+			search("acme")
+			waitForKeys(t, mr, 1)
+
+			if resp := search("globex"); resp.CacheHit != tt.expectHit {
+				t.Errorf("Expected cache hit %v for another tenant, got %v", tt.expectHit, resp.CacheHit)
+			}
+		})
+	}
+}
+
+func TestSearchServiceMultiSearch(t *testing.T) {
+	engines, fakes := newFakeEngines()
+	for name, fake := range fakes {
+		fake.results = fakeResults(name, 2)
+		fake.queryDelays = map[string]time.Duration{"alpha": 50 * time.Millisecond, "beta": 20 * time.Millisecond}
+		fake.queryErrs = map[string]error{"broken": fmt.Errorf("%s unavailable", name)}
+	}
+
+	svc := newTestSearchService(t, engines, func(cfg *SearchServiceConfig) {
+		cfg.MinSuccessfulEngines = 1
+	})
+
+	queries := []string{"alpha", "beta", "broken", "gamma"}
+	reqs := make([]*model.SearchRequest, len(queries))
+	for i, q := range queries {
+		reqs[i] = &model.SearchRequest{Query: q, Limit: 10}
+	}
+
+	responses, err := svc.MultiSearch(context.Background(), reqs)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(responses) != len(queries) {
+		t.Fatalf("Expected %d responses, got %d", len(queries), len(responses))
+	}
+
+	for i, q := range queries {
+		resp := responses[i]
+		if resp.QueryInfo == nil || resp.QueryInfo.Query != q {
+			t.Errorf("Expected response %d to be for %q, got %+v", i, q, resp.QueryInfo)
+		}
+		if q == "broken" {
+			if resp.Error == "" {
+				t.Error("Expected failed query to carry an error")
+			}
+			continue
+		}
+		if resp.Error != "" || len(resp.Results) == 0 {
+			t.Errorf("Expected %q to succeed, got error %q with %d results", q, resp.Error, len(resp.Results))
+		}
+	}
+}
+
+func TestSearchServiceMultiSearchLimits(t *testing.T) {
+	engines, fakes := newFakeEngines()
+	svc := newTestSearchService(t, engines)
+
+	if _, err := svc.MultiSearch(context.Background(), nil); err == nil {
+		t.Error("Expected error for an empty batch")
+	}
+
+	reqs := make([]*model.SearchRequest, MaxMultiSearchQueries+1)
+	for i := range reqs {
+		reqs[i] = &model.SearchRequest{Query: "q", Limit: 10}
+	}
+	if _, err := svc.MultiSearch(context.Background(), reqs); err == nil {
+		t.Error("Expected error for an oversized batch")
+	}
+	for name, fake := range fakes {
+		if fake.searchCount() != 0 {
+			t.Errorf("Expected no searches on %s for a rejected batch", name)
+		}
+	}
+}
+
+func TestSearchServiceEngineSpans(t *testing.T) {
+	engines, fakes := newFakeEngines()
+	fakes["bm25"].results = fakeResults("bm25", 3)
+	fakes["flexsearch"].results = fakeResults("flexsearch", 2)
+	fakes["vector"].searchErr = fmt.Errorf("vector unavailable")
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	svc := newTestSearchService(t, engines, func(cfg *SearchServiceConfig) {
+		cfg.Tracer = tp.Tracer("test")
+	})
+
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "incoming")
+	_, err := svc.Search(ctx, &model.SearchRequest{
+		Query:   "test query",
+		Index:   "docs",
+		Limit:   10,
+		Engines: []string{"bm25", "flexsearch", "vector"},
+	})
+	parent.End()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	spans := make(map[string]sdktrace.ReadOnlySpan)
+	for _, span := range recorder.Ended() {
+		if span.Name() != "Engine.Search" {
+			continue
+		}
+		attrs := spanAttributes(span)
+		spans[attrs["engine"].AsString()] = span
+	}
+	if len(spans) != 3 {
+		t.Fatalf("Expected one span per engine, got %d", len(spans))
+	}
+
+	wantCounts := map[string]int64{"bm25": 3, "flexsearch": 2}
+	for name, want := range wantCounts {
+		span := spans[name]
+		attrs := spanAttributes(span)
+		if got := attrs["result_count"].AsInt64(); got != want {
+			t.Errorf("Expected %s span result_count %d, got %d", name, want, got)
+		}
+		if _, ok := attrs["took_ms"]; !ok {
+			t.Errorf("Expected %s span to record took_ms", name)
+		}
+		if attrs["index"].AsString() != "docs" {
+			t.Errorf("Expected %s span index docs, got %q", name, attrs["index"].AsString())
+		}
+		if span.Status().Code == codes.Error {
+			t.Errorf("Expected %s span not to be an error", name)
+		}
+		if span.Parent().SpanID() != parent.SpanContext().SpanID() {
+			t.Errorf("Expected %s span to be a child of the incoming span", name)
+		}
+	}
+
+	failed := spans["vector"]
+	if failed.Status().Code != codes.Error {
+		t.Errorf("Expected vector span to have error status, got %v", failed.Status().Code)
+	}
+	if len(failed.Events()) == 0 || failed.Events()[0].Name != "exception" {
+		t.Errorf("Expected vector span to record the error, got events %v", failed.Events())
+	}
+}
+
+func spanAttributes(span sdktrace.ReadOnlySpan) map[string]attribute.Value {
+	attrs := make(map[string]attribute.Value)
+	for _, kv := range span.Attributes() {
+		attrs[string(kv.Key)] = kv.Value
+	}
+	return attrs
+}
+
+func TestSearchServiceEngineMetrics(t *testing.T) {
+	engines, fakes := newFakeEngines()
+	fakes["bm25"].results = fakeResults("bm25", 3)
+	fakes["flexsearch"].results = fakeResults("flexsearch", 2)
+	fakes["vector"].searchErr = fmt.Errorf("vector unavailable")
+
+	reg := prometheus.NewRegistry()
+	svc := newTestSearchService(t, engines, func(cfg *SearchServiceConfig) {
+		cfg.Metrics = util.NewMetricsWithRegistry("engine_metrics_test", reg)
+	})
+
+	if _, err := svc.Search(context.Background(), &model.SearchRequest{
+		Query:   "test query",
+		Limit:   10,
+		Engines: []string{"bm25", "flexsearch", "vector"},
+	}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("Failed to gather metrics: %v", err)
+	}
+	series := make(map[string]map[string]*dto.Metric)
+	for _, family := range families {
+		byLabels := make(map[string]*dto.Metric)
+		for _, metric := range family.GetMetric() {
+			var labels []string
+			for _, label := range metric.GetLabel() {
+				labels = append(labels, label.GetName()+"="+label.GetValue())
+			}
+			byLabels[strings.Join(labels, ",")] = metric
+		}
+		series[family.GetName()] = byLabels
+	}
+
+	for _, name := range []string{"bm25", "flexsearch", "vector"} {
+		latency := series["engine_metrics_test_engine_latency_seconds"]["engine="+name+",operation=search"]
+		if latency == nil || latency.GetHistogram().GetSampleCount() != 1 {
+			t.Errorf("Expected one latency observation for %s, got %v", name, latency)
+		}
+		requests := series["engine_metrics_test_search_requests_total"]["engine="+name]
+		if requests == nil || requests.GetCounter().GetValue() != 1 {
+			t.Errorf("Expected one search request for %s, got %v", name, requests)
+		}
+	}
+
+	errors := series["engine_metrics_test_search_errors_total"]
+	if len(errors) != 1 || errors["engine=vector,error_type=error"].GetCounter().GetValue() != 1 {
+		t.Errorf("Expected one search error for vector only, got %v", errors)
+	}
+}
+
+func TestSearchServiceRecordsSlowQueries(t *testing.T) {
+	redisCache, mr := newTestCache(t)
+	engines := map[string]engine.EngineClient{
+		"bm25": &fakeEngine{name: "bm25", results: fakeResults("b", 2)},
+	}
+	svc := newTestSearchService(t, engines, func(cfg *SearchServiceConfig) {
+		cfg.QueryLogger = util.NewQueryLoggerWithConfig(cfg.Logger, util.QueryLoggerConfig{
+			SlowThreshold: 20 * time.Millisecond,
+			SlowSink:      redisCache.SlowQueryList(10),
+		})
+	})
+	slowList := redisCache.SlowQueryList(10)
+
+	if _, err := svc.Search(context.Background(), &model.SearchRequest{Query: "fast", Index: "docs", Limit: 10}); err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+
+	engines["bm25"].(*fakeEngine).searchDelay = 50 * time.Millisecond
+	if _, err := svc.Search(context.Background(), &model.SearchRequest{Query: "slow", Index: "docs", Limit: 10, RequestID: "req-slow"}); err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for !mr.Exists(cache.SlowQueryKey) && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	recent, err := slowList.Recent(context.Background(), 10)
+	if err != nil {
+		t.Fatalf("Recent failed: %v", err)
+	}
+	if len(recent) != 1 {
+		t.Fatalf("Expected only the slow query to be recorded, got %d", len(recent))
+	}
+	if recent[0].Query != "slow" || recent[0].RequestID != "req-slow" {
+		t.Errorf("Unexpected slow query %+v", recent[0])
+	}
+	if len(recent[0].Engines) != 1 || recent[0].Engines[0].Engine != "bm25" || recent[0].Engines[0].Results != 2 {
+		t.Errorf("Expected the bm25 breakdown, got %+v", recent[0].Engines)
+	}
+}
+
+func TestGenerateRequestIDUnique(t *testing.T) {
+	const workers, perWorker = 16, 1000
+
+	var mu sync.Mutex
+	seen := make(map[string]bool, workers*perWorker)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ids := make([]string, perWorker)
+			for i := range ids {
+				ids[i] = generateRequestID()
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			for _, id := range ids {
+				if seen[id] {
+					t.Errorf("Duplicate request ID %s", id)
+				}
+				seen[id] = true
+			}
+		}()
+	}
+	wg.Wait()
+
+	for id := range seen {
+		if !strings.HasPrefix(id, "req-") {
+			t.Fatalf("Expected req- prefix, got %s", id)
+		}
+		break
+	}
+}
+
+func TestSearchServiceKeepsCallerRequestID(t *testing.T) {
+	engines := map[string]engine.EngineClient{
+		"bm25": &fakeEngine{name: "bm25", results: fakeResults("b", 1)},
+	}
+	svc := newTestSearchService(t, engines)
+
+	resp, err := svc.Search(context.Background(), &model.SearchRequest{Query: "q", Index: "docs", Limit: 10, RequestID: "gw-123"})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if resp.RequestID != "gw-123" {
+		t.Errorf("Expected caller request ID to be kept, got %s", resp.RequestID)
+	}
+
+	resp, err = svc.Search(context.Background(), &model.SearchRequest{Query: "q", Index: "docs", Limit: 10})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if !strings.HasPrefix(resp.RequestID, "req-") || len(resp.RequestID) != len("req-")+36 {
+		t.Errorf("Expected a generated req-<uuid> ID, got %s", resp.RequestID)
+	}
+}
+
+func TestSearchServiceCursorPagination(t *testing.T) {
+	engines, fakes := newFakeEngines()
+	for name, fake := range fakes {
+		// The shared documents fuse to distinct scores; each engine's own
+		// documents tie with the other engines' at the same rank.
+		fake.results = append(fakeResults("shared", 5), fakeResults(name, 4)...)
+	}
+	svc := newTestSearchService(t, engines)
+
+	var ids []string
+	cursor := ""
+	for pages := 0; ; pages++ {
+		if pages > 10 {
+			t.Fatal("Expected paging to finish")
+		}
+		resp, err := svc.Search(context.Background(), &model.SearchRequest{
+			Query:       "test query",
+			Limit:       4,
+			Engines:     []string{"bm25", "flexsearch", "vector"},
+			SearchAfter: cursor,
+		})
+		if err != nil {
+			t.Fatalf("Search page %d failed: %v", pages, err)
+		}
+		if len(resp.Results) > 4 {
+			t.Fatalf("Expected at most 4 results per page, got %d", len(resp.Results))
+		}
+		for _, r := range resp.Results {
+			ids = append(ids, r.ID)
+		}
+		if resp.NextCursor == "" {
+			break
+		}
+		cursor = resp.NextCursor
+	}
+
+	seen := make(map[string]bool)
+	for _, id := range ids {
+		if seen[id] {
+			t.Fatalf("Result %s was returned on two pages: %v", id, ids)
+		}
+		seen[id] = true
+	}
+	if len(seen) != 5+3*4 {
+		t.Fatalf("Expected %d distinct results across pages, got %d: %v", 5+3*4, len(seen), ids)
+	}
+
+	searches := fakes["bm25"].searches
+	if last := searches[len(searches)-1]; last.Limit <= 4 {
+		t.Errorf("Expected later pages to widen the engine limit past 4, got %d", last.Limit)
+	}
+}
+
+func TestSearchServiceCursorRejectsSortChange(t *testing.T) {
+	engines, fakes := newFakeEngines()
+	for name, fake := range fakes {
+		fake.results = fakeResults(name, 6)
+	}
+	svc := newTestSearchService(t, engines)
+
+	first, err := svc.Search(context.Background(), &model.SearchRequest{Query: "test query", Limit: 2, SortBy: "year"})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if first.NextCursor == "" {
+		t.Fatal("Expected a cursor for a full page")
+	}
+
+	_, err = svc.Search(context.Background(), &model.SearchRequest{
+		Query:       "test query",
+		Limit:       2,
+		SortBy:      "year",
+		SortOrder:   "desc",
+		SearchAfter: first.NextCursor,
+	})
+	appErr, ok := err.(*util.AppError)
+	if !ok || appErr.Code != 400 {
+		t.Fatalf("Expected a 400 AppError for a cursor from another sort, got %v", err)
+	}
+}
+
+func TestSearchServiceCoalescesIdenticalSearches(t *testing.T) {
+	engines, fakes := newFakeEngines()
+	for name, fake := range fakes {
+		fake.results = fakeResults(name, 3)
+		fake.searchDelay = 50 * time.Millisecond
+	}
+	svc := newTestSearchService(t, engines)
+
+	const callers = 20
+	var wg sync.WaitGroup
+	responses := make([]*model.SearchResponse, callers)
+	errs := make([]error, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			responses[i], errs[i] = svc.Search(context.Background(), &model.SearchRequest{
+				RequestID: fmt.Sprintf("req-%d", i),
+				Query:     "same query",
+				Limit:     10,
+				Engines:   []string{"bm25", "flexsearch", "vector"},
+			})
+		}(i)
+	}
+	wg.Wait()
+
+	for name, fake := range fakes {
+		if got := fake.searchCount(); got != 1 {
+			t.Errorf("Expected %s to be searched once, got %d", name, got)
+		}
+	}
+	for i, resp := range responses {
+		if errs[i] != nil {
+			t.Fatalf("Caller %d failed: %v", i, errs[i])
+		}
+		if len(resp.Results) != 9 {
+			t.Errorf("Expected caller %d to get 9 results, got %d", i, len(resp.Results))
+		}
+		if want := fmt.Sprintf("req-%d", i); resp.RequestID != want {
+			t.Errorf("Expected caller %d to keep request ID %s, got %s", i, want, resp.RequestID)
+		}
+	}
+}
+
+func TestSearchServiceCoalescedCallerCancellation(t *testing.T) {
+	engines, fakes := newFakeEngines()
+	for name, fake := range fakes {
+		fake.results = fakeResults(name, 3)
+		fake.searchDelay = 100 * time.Millisecond
+	}
+	svc := newTestSearchService(t, engines)
+	req := func() *model.SearchRequest {
+		return &model.SearchRequest{Query: "same query", Limit: 10, Engines: []string{"bm25", "flexsearch", "vector"}}
+	}
+
+	// The first caller starts the shared execution, then gives up on it.
+	ctx, cancel := context.WithCancel(context.Background())
+	firstErr := make(chan error, 1)
+	go func() {
+		_, err := svc.Search(ctx, req())
+		firstErr <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	secondDone := make(chan *model.SearchResponse, 1)
+	go func() {
+		resp, err := svc.Search(context.Background(), req())
+		if err != nil {
+			t.Errorf("Expected the second caller to succeed, got %v", err)
+		}
+		secondDone <- resp
+	}()
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+
+	if err := <-firstErr; err != context.Canceled {
+		t.Errorf("Expected the cancelled caller to get context.Canceled, got %v", err)
+	}
+	if resp := <-secondDone; resp == nil || len(resp.Results) != 9 {
+		t.Errorf("Expected the second caller to get the shared results, got %+v", resp)
+	}
+	for name, fake := range fakes {
+		if got := fake.searchCount(); got != 1 {
+			t.Errorf("Expected %s to be searched once, got %d", name, got)
+		}
+	}
+}
+
+func TestSearchServiceFallbackEngines(t *testing.T) {
+	semantic := &router.RoutingDecision{StrategyName: "semantic_search", Engines: []string{"vector"}}
+	withFallback := func(cfg *SearchServiceConfig) {
+		cfg.MinSuccessfulEngines = 1
+		cfg.FallbackEngines = map[string][]string{"semantic_search": {"bm25"}}
+	}
+
+	t.Run("fallback answers", func(t *testing.T) {
+		engines, fakes := newFakeEngines()
+		fakes["vector"].searchErr = fmt.Errorf("vector unavailable")
+		fakes["bm25"].results = fakeResults("bm25", 3)
+		svc := newTestSearchService(t, engines, withFallback)
+
+		results, err := svc.executeSearch(context.Background(), &model.SearchRequest{Query: "what is a fox", Limit: 10}, semantic)
+		if err != nil {
+			t.Fatalf("executeSearch() error = %v", err)
+		}
+		if got := len(results["bm25"].Results); got != 3 {
+			t.Errorf("Expected 3 fallback results from bm25, got %d", got)
+		}
+		if failed := failedEngines(results); len(failed) != 1 || failed[0] != "vector" {
+			t.Errorf("Expected vector to be reported failed, got %v", failed)
+		}
+		if fakes["flexsearch"].searchCount() != 0 {
+			t.Error("Expected only the configured fallback to be searched")
+		}
+	})
+
+	t.Run("fallback also fails", func(t *testing.T) {
+		engines, fakes := newFakeEngines()
+		fakes["vector"].searchErr = fmt.Errorf("vector unavailable")
+		fakes["bm25"].searchErr = fmt.Errorf("bm25 unavailable")
+		svc := newTestSearchService(t, engines, withFallback)
+
+		_, err := svc.executeSearch(context.Background(), &model.SearchRequest{Query: "what is a fox", Limit: 10}, semantic)
+		appErr, ok := err.(*util.AppError)
+		if !ok || appErr.Code != 503 {
+			t.Fatalf("Expected a 503 AppError, got %v", err)
+		}
+		if fakes["bm25"].searchCount() != 1 {
+			t.Errorf("Expected bm25 to be tried once as the fallback, got %d", fakes["bm25"].searchCount())
+		}
+	})
+
+	t.Run("no fallback while an engine succeeds", func(t *testing.T) {
+		engines, fakes := newFakeEngines()
+		fakes["vector"].results = fakeResults("vector", 2)
+		svc := newTestSearchService(t, engines, withFallback)
+
+		if _, err := svc.executeSearch(context.Background(), &model.SearchRequest{Query: "what is a fox", Limit: 10}, semantic); err != nil {
+			t.Fatalf("executeSearch() error = %v", err)
+		}
+		if fakes["bm25"].searchCount() != 0 {
+			t.Error("Expected the fallback to be skipped")
+		}
+	})
+}
+
+func TestSearchServicePerEngineLimit(t *testing.T) {
+	engines, fakes := newFakeEngines()
+	for name, fake := range fakes {
+		fake.results = fakeResults(name, 30)
+	}
+	svc := newTestSearchService(t, engines, func(cfg *SearchServiceConfig) {
+		cfg.PerEngineLimit = 25
+	})
+	search := func(req *model.SearchRequest) *model.SearchResponse {
+		t.Helper()
+		req.Query = "test query"
+		req.Engines = []string{"bm25", "flexsearch", "vector"}
+		resp, err := svc.Search(context.Background(), req)
+		if err != nil {
+			t.Fatalf("Search failed: %v", err)
+		}
+		return resp
+	}
+	lastLimit := func() int32 {
+		searches := fakes["bm25"].searches
+		return searches[len(searches)-1].Limit
+	}
+
+	resp := search(&model.SearchRequest{Limit: 10})
+	if got := lastLimit(); got != 25 {
+		t.Errorf("Expected engines to be asked for the default pool of 25, got %d", got)
+	}
+	if len(resp.Results) != 10 {
+		t.Errorf("Expected the response to honor Limit 10, got %d results", len(resp.Results))
+	}
+
+	resp = search(&model.SearchRequest{Limit: 5, PerEngineLimit: 40})
+	if got := lastLimit(); got != 40 {
+		t.Errorf("Expected engines to be asked for the requested pool of 40, got %d", got)
+	}
+	if len(resp.Results) != 5 {
+		t.Errorf("Expected the response to honor Limit 5, got %d results", len(resp.Results))
+	}
+
+	// A page past the pool still gets enough candidates to fill it.
+	search(&model.SearchRequest{Limit: 10, Offset: 20})
+	if got := lastLimit(); got != 30 {
+		t.Errorf("Expected the engine limit to cover the page end of 30, got %d", got)
+	}
+
+	if _, err := svc.Search(context.Background(), &model.SearchRequest{Query: "q", Limit: 10, PerEngineLimit: MaxPerEngineLimit + 1}); err == nil {
+		t.Error("Expected a per-engine limit above the maximum to be rejected")
+	}
+}
+
+func TestSearchServiceExplainRouting(t *testing.T) {
+	engines, fakes := newFakeEngines()
+	for name, fake := range fakes {
+		fake.results = fakeResults(name, 3)
+	}
+	svc := newTestSearchService(t, engines)
+
+	resp, err := svc.Search(context.Background(), &model.SearchRequest{
+		Query:   "golang",
+		Limit:   10,
+		Engines: []string{"bm25"},
+		DryRun:  true,
+	})
+	if err != nil {
+		t.Fatalf("Dry run failed: %v", err)
+	}
+	for name, fake := range fakes {
+		if fake.searchCount() != 0 {
+			t.Errorf("Expected a dry run not to search %s", name)
+		}
+	}
+	if resp.Routing == nil || resp.Routing.Strategy != "auto_routing" || len(resp.Routing.Engines) == 0 {
+		t.Fatalf("Expected the dry run to explain the routing, got %+v", resp.Routing)
+	}
+	if len(resp.Results) != 0 {
+		t.Errorf("Expected a dry run to return no results, got %d", len(resp.Results))
+	}
+
+	resp, err = svc.Search(context.Background(), &model.SearchRequest{
+		Query:   "golang",
+		Limit:   10,
+		Engines: []string{"bm25"},
+		Explain: true,
+	})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if resp.Routing == nil || resp.Routing.Strategy != "auto_routing" {
+		t.Errorf("Expected explain to attach the routing, got %+v", resp.Routing)
+	}
+	if len(resp.Results) == 0 {
+		t.Error("Expected explain to still return results")
+	}
+
+	resp, _ = svc.Search(context.Background(), &model.SearchRequest{Query: "golang", Limit: 10, Engines: []string{"bm25"}})
+	if resp.Routing != nil {
+		t.Errorf("Expected no routing without explain, got %+v", resp.Routing)
+	}
+}
+
+func TestSearchServiceExplainScores(t *testing.T) {
+	engines, fakes := newFakeEngines()
+	for name, fake := range fakes {
+		fake.results = fakeResults("shared", 2)
+		fake.results[0].EngineSource = name
+	}
+	svc := newTestSearchService(t, engines)
+
+	resp, err := svc.Search(context.Background(), &model.SearchRequest{
+		Query:   "test query",
+		Limit:   10,
+		Engines: []string{"bm25", "flexsearch", "vector"},
+		Explain: true,
+	})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	top := resp.Results[0]
+	for _, name := range []string{"bm25", "flexsearch", "vector"} {
+		if _, ok := top.Explain[name+"_rrf"]; !ok {
+			t.Errorf("Expected %s to contribute to %s's explanation, got %v", name, top.ID, top.Explain)
+		}
+	}
+	if top.Explain["final"] != top.Score {
+		t.Errorf("Expected final %v to equal the score %v", top.Explain["final"], top.Score)
+	}
+}
+
+func TestSearchServiceRoutesToIndexTypeEngines(t *testing.T) {
+	engines, fakes := newFakeEngines()
+	for name, fake := range fakes {
+		fake.results = fakeResults(name, 3)
+	}
+	docs := NewDocumentService(&DocumentServiceConfig{
+		Logger:     newTestLogger(t),
+		Engines:    engines,
+		IndexTypes: map[string]string{"articles": model.IndexTypeKeyword},
+	})
+	svc := newTestSearchService(t, engines, func(cfg *SearchServiceConfig) {
+		cfg.IndexTypes = docs
+	})
+
+	resp, err := svc.Search(context.Background(), &model.SearchRequest{
+		Query: "how does a search engine rank all these pages",
+		Index: "articles",
+		Limit: 10,
+	})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if fakes["vector"].searchCount() != 0 {
+		t.Error("Expected the vector engine to be skipped for a keyword index")
+	}
+	if fakes["bm25"].searchCount() != 1 || len(resp.Results) != 3 {
+		t.Errorf("Expected bm25 to answer the semantic query, got %d searches and %d results",
+			fakes["bm25"].searchCount(), len(resp.Results))
+	}
+}
+
+func TestSearchServiceSkipsOpenCircuitEngines(t *testing.T) {
+	engines, fakes := newFakeEngines()
+	for name, fake := range fakes {
+		fake.results = fakeResults(name, 3)
+	}
+	fakes["vector"].cbState = "open"
+	svc := newTestSearchService(t, engines)
+
+	resp, err := svc.Search(context.Background(), &model.SearchRequest{
+		Query:   "test query",
+		Limit:   10,
+		Engines: []string{"bm25", "flexsearch", "vector"},
+	})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if fakes["vector"].searchCount() != 0 {
+		t.Error("Expected the open-breaker engine to be left out of the fan-out")
+	}
+	if len(resp.SkippedEngines) != 1 || resp.SkippedEngines[0] != "vector" {
+		t.Errorf("Expected vector to be reported skipped, got %v", resp.SkippedEngines)
+	}
+	if len(resp.FailedEngines) != 0 {
+		t.Errorf("Expected a skipped engine not to count as failed, got %v", resp.FailedEngines)
+	}
+	if !resp.Degraded || len(resp.Results) != 6 {
+		t.Errorf("Expected a degraded response with the other engines' 6 results, got degraded=%v and %d results",
+			resp.Degraded, len(resp.Results))
+	}
+}
+
+func TestSearchServicePassesPhrasesToEngines(t *testing.T) {
+	engines, fakes := newFakeEngines()
+	svc := newTestSearchService(t, engines)
+
+	req := &model.SearchRequest{Query: `jump "quick fox"~2`, Engines: []string{"bm25"}, Limit: 5}
+	if _, err := svc.Search(context.Background(), req); err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+
+	searches := fakes["bm25"].searches
+	if len(searches) != 1 {
+		t.Fatalf("Expected one bm25 search, got %d", len(searches))
+	}
+	want := []model.PhraseClause{{Terms: []string{"quick", "fox"}, Distance: 2}}
+	if got := searches[0].Phrases; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected the engine to receive phrases %+v, got %+v", want, got)
+	}
+	if req.Phrases != nil {
+		t.Error("Expected the caller's request to be left unchanged")
+	}
+}
+
+func TestSearchServiceParsesBooleanQueries(t *testing.T) {
+	engines, fakes := newFakeEngines()
+	svc := newTestSearchService(t, engines)
+
+	req := &model.SearchRequest{Query: "rust AND (go OR NOT java)", Engines: []string{"bm25"}, Limit: 5}
+	if _, err := svc.Search(context.Background(), req); err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	searches := fakes["bm25"].searches
+	if len(searches) != 1 || searches[0].Boolean == nil {
+		t.Fatalf("Expected the engine to receive the parsed query, got %+v", searches)
+	}
+	if got, want := searches[0].Boolean.String(), "(and rust (or go (not java)))"; got != want {
+		t.Errorf("Expected the engine to receive %s, got %s", want, got)
+	}
+
+	_, err := svc.Search(context.Background(), &model.SearchRequest{Query: "rust AND (go", Engines: []string{"bm25"}, Limit: 5})
+	appErr, ok := err.(*util.AppError)
+	if !ok || appErr.Code != 400 || !strings.Contains(appErr.Details, "unmatched (") {
+		t.Errorf("Expected a 400 naming the unmatched parenthesis, got %v", err)
+	}
+	if len(fakes["bm25"].searches) != 1 {
+		t.Error("Expected a malformed query not to reach the engines")
+	}
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/flexsearch/coordinator/internal/model"
+)
+
+// DocumentStore keeps the source of every written document so it can be
+// read back by ID; the engines only answer searches.
+type DocumentStore interface {
+	Get(ctx context.Context, index, id string) (*model.DocumentRequest, bool, error)
+	Put(ctx context.Context, doc *model.DocumentRequest) error
+	Delete(ctx context.Context, index, id string) error
+	// List returns every document of index ordered by ID.
+	List(ctx context.Context, index string) ([]*model.DocumentRequest, error)
+}
+
+type MemoryDocumentStore struct {
+	mu   sync.RWMutex
+	docs map[string]map[string]*model.DocumentRequest
+}
+
+func NewMemoryDocumentStore() *MemoryDocumentStore {
+	return &MemoryDocumentStore{
+		docs: make(map[string]map[string]*model.DocumentRequest),
+	}
+}
+
+func (m *MemoryDocumentStore) Get(ctx context.Context, index, id string) (*model.DocumentRequest, bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	doc, ok := m.docs[index][id]
+	if !ok {
+		return nil, false, nil
+	}
+	return copyDocument(doc), true, nil
+}
+
+func (m *MemoryDocumentStore) Put(ctx context.Context, doc *model.DocumentRequest) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	docs, ok := m.docs[doc.Index]
+	if !ok {
+		docs = make(map[string]*model.DocumentRequest)
+		m.docs[doc.Index] = docs
+	}
+	docs[doc.ID] = copyDocument(doc)
+	return nil
+}
+
+func (m *MemoryDocumentStore) Delete(ctx context.Context, index, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.docs[index], id)
+	return nil
+}
+
+func (m *MemoryDocumentStore) List(ctx context.Context, index string) ([]*model.DocumentRequest, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	docs := make([]*model.DocumentRequest, 0, len(m.docs[index]))
+	for _, doc := range m.docs[index] {
+		docs = append(docs, copyDocument(doc))
+	}
+	sort.Slice(docs, func(i, j int) bool { return docs[i].ID < docs[j].ID })
+	return docs, nil
+}
+
+func copyDocument(doc *model.DocumentRequest) *model.DocumentRequest {
+	dup := *doc
+	if doc.Fields != nil {
+		dup.Fields = make(map[string]interface{}, len(doc.Fields))
+		for k, v := range doc.Fields {
+			dup.Fields[k] = v
+		}
+	}
+	if doc.Vector != nil {
+		dup.Vector = append([]float64(nil), doc.Vector...)
+	}
+	return &dup
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/flexsearch/coordinator/internal/util"
+)
+
+const (
+	WildcardActionReject  = "reject"
+	WildcardActionRewrite = "rewrite"
+)
+
+// WildcardPolicy guards against wildcard terms that make engines scan the
+// whole index. A term needs MinPrefix literal characters before its first
+// * or ?, so a MinPrefix of 1 blocks leading wildcards like *term and 0
+// allows every wildcard.
+type WildcardPolicy struct {
+	MinPrefix int
+	// Action is reject, the default, which fails the search with a 400,
+	// or rewrite, which drops the wildcards of short-prefixed terms and
+	// searches their literal characters.
+	Action string
+}
+
+var wildcardTermPattern = regexp.MustCompile(`[^\s()"]+`)
+
+// apply returns query with the policy enforced.
+func (p WildcardPolicy) apply(query string) (string, error) {
+	if p.MinPrefix <= 0 || !strings.ContainsAny(query, "*?") {
+		return query, nil
+	}
+
+	var rejected string
+	rewritten := wildcardTermPattern.ReplaceAllStringFunc(query, func(term string) string {
+		core := strings.TrimPrefix(term, "!")
+		at := strings.IndexAny(core, "*?")
+		if at < 0 || utf8.RuneCountInString(core[:at]) >= p.MinPrefix {
+			return term
+		}
+		if p.Action != WildcardActionRewrite {
+			if rejected == "" {
+				rejected = term
+			}
+			return term
+		}
+		return strings.NewReplacer("*", "", "?", "").Replace(term)
+	})
+
+	if rejected != "" {
+		return "", util.NewAppError(400, "Wildcard prefix too short",
+			fmt.Sprintf("%q needs at least %d literal characters before its first wildcard", rejected, p.MinPrefix))
+	}
+	return strings.Join(strings.Fields(rewritten), " "), nil
+}
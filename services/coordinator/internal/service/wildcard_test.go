@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/flexsearch/coordinator/internal/model"
+	"github.com/flexsearch/coordinator/internal/util"
+)
+
+func TestWildcardPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  WildcardPolicy
+		query   string
+		want    string
+		wantErr bool
+	}{
+		{"trailing wildcard allowed", WildcardPolicy{MinPrefix: 1}, "data*", "data*", false},
+		{"leading wildcard rejected", WildcardPolicy{MinPrefix: 1}, "big *data", "", true},
+		{"negated leading wildcard rejected", WildcardPolicy{MinPrefix: 1}, "big AND !*data", "", true},
+		{"no policy allows leading", WildcardPolicy{}, "*data", "*data", false},
+		{"prefix at minimum", WildcardPolicy{MinPrefix: 3}, "dat*", "dat*", false},
+		{"prefix under minimum", WildcardPolicy{MinPrefix: 3}, "da?a", "", true},
+		{"rewrite drops wildcards", WildcardPolicy{MinPrefix: 2, Action: WildcardActionRewrite}, `(*data OR d*) te*t`, "(data OR d) te*t", false},
+		{"rewrite drops bare wildcard", WildcardPolicy{MinPrefix: 1, Action: WildcardActionRewrite}, "big * data", "big data", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.policy.apply(tt.query)
+			if tt.wantErr {
+				if appErr, ok := err.(*util.AppError); !ok || appErr.Code != 400 {
+					t.Errorf("apply(%q) error = %v, want a 400", tt.query, err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("apply(%q) = %q, %v; want %q", tt.query, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchServiceAppliesWildcardPolicy(t *testing.T) {
+	engines, fakes := newFakeEngines()
+	svc := newTestSearchService(t, engines, func(cfg *SearchServiceConfig) {
+		cfg.Wildcards = WildcardPolicy{MinPrefix: 1}
+	})
+
+	_, err := svc.Search(context.Background(), &model.SearchRequest{Query: "*ology", Engines: []string{"bm25"}, Limit: 5})
+	if appErr, ok := err.(*util.AppError); !ok || appErr.Code != 400 {
+		t.Fatalf("Expected a 400 for a leading wildcard, got %v", err)
+	}
+	if n := len(fakes["bm25"].searches); n != 0 {
+		t.Errorf("Expected the rejected query not to reach the engines, got %d searches", n)
+	}
+
+	if _, err := svc.Search(context.Background(), &model.SearchRequest{Query: "bio*", Engines: []string{"bm25"}, Limit: 5}); err != nil {
+		t.Errorf("Expected a trailing wildcard to be searched, got %v", err)
+	}
+}
@@ -0,0 +1,117 @@
+package suggest
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Trie is a case-insensitive prefix index of terms weighted by popularity.
+// Suggestions keep the casing the term was first added with.
+type Trie struct {
+	mu   sync.RWMutex
+	root *node
+	size int
+}
+
+type node struct {
+	children map[rune]*node
+	term     string
+	weight   int64
+}
+
+type entry struct {
+	term   string
+	weight int64
+}
+
+func NewTrie() *Trie {
+	return &Trie{root: newNode()}
+}
+
+func newNode() *node {
+	return &node{children: make(map[rune]*node)}
+}
+
+// Add records weight more uses of term. Blank terms are ignored.
+func (t *Trie) Add(term string, weight int64) {
+	term = strings.Join(strings.Fields(term), " ")
+	if term == "" || weight <= 0 {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	n := t.root
+	for _, r := range strings.ToLower(term) {
+		child, ok := n.children[r]
+		if !ok {
+			child = newNode()
+			n.children[r] = child
+		}
+		n = child
+	}
+
+	if n.term == "" {
+		n.term = term
+		t.size++
+	}
+	n.weight += weight
+}
+
+// Suggest returns up to limit terms starting with prefix, most popular
+// first and alphabetically among equals. An empty prefix returns nothing.
+func (t *Trie) Suggest(prefix string, limit int) []string {
+	prefix = strings.ToLower(strings.Join(strings.Fields(prefix), " "))
+	if prefix == "" || limit <= 0 {
+		return nil
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	n := t.root
+	for _, r := range prefix {
+		child, ok := n.children[r]
+		if !ok {
+			return nil
+		}
+		n = child
+	}
+
+	var entries []entry
+	collect(n, &entries)
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].weight != entries[j].weight {
+			return entries[i].weight > entries[j].weight
+		}
+		return strings.ToLower(entries[i].term) < strings.ToLower(entries[j].term)
+	})
+
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+	terms := make([]string, len(entries))
+	for i, e := range entries {
+		terms[i] = e.term
+	}
+	return terms
+}
+
+// Len is the number of distinct terms.
+func (t *Trie) Len() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.size
+}
+
+func collect(n *node, entries *[]entry) {
+	if n.term != "" {
+		*entries = append(*entries, entry{term: n.term, weight: n.weight})
+	}
+	for _, child := range n.children {
+		collect(child, entries)
+	}
+}
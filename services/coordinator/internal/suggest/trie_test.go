@@ -0,0 +1,63 @@
+package suggest
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTrieSuggestPrefix(t *testing.T) {
+	trie := NewTrie()
+	trie.Add("golang", 1)
+	trie.Add("go modules", 1)
+	trie.Add("google", 1)
+	trie.Add("rust", 1)
+
+	got := trie.Suggest("go", 10)
+	want := []string{"go modules", "golang", "google"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Suggest(go) = %v, want %v", got, want)
+	}
+
+	if got := trie.Suggest("gol", 10); !reflect.DeepEqual(got, []string{"golang"}) {
+		t.Errorf("Suggest(gol) = %v, want [golang]", got)
+	}
+	if got := trie.Suggest("python", 10); len(got) != 0 {
+		t.Errorf("Expected no suggestions for an unknown prefix, got %v", got)
+	}
+	if got := trie.Suggest("  ", 10); got != nil {
+		t.Errorf("Expected no suggestions for an empty prefix, got %v", got)
+	}
+}
+
+func TestTrieSuggestPopularityAndLimit(t *testing.T) {
+	trie := NewTrie()
+	trie.Add("search engine", 1)
+	trie.Add("search api", 5)
+	trie.Add("search index", 3)
+	trie.Add("search engine", 3)
+
+	got := trie.Suggest("search", 2)
+	want := []string{"search api", "search engine"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Suggest(search, 2) = %v, want %v", got, want)
+	}
+
+	if got := trie.Suggest("search", 0); got != nil {
+		t.Errorf("Expected no suggestions for a zero limit, got %v", got)
+	}
+}
+
+func TestTrieSuggestCaseInsensitive(t *testing.T) {
+	trie := NewTrie()
+	trie.Add("FlexSearch Guide", 1)
+	trie.Add("flexsearch guide", 1)
+
+	if trie.Len() != 1 {
+		t.Errorf("Expected case variants to share one entry, got %d", trie.Len())
+	}
+
+	got := trie.Suggest("FLEX", 10)
+	if !reflect.DeepEqual(got, []string{"FlexSearch Guide"}) {
+		t.Errorf("Suggest(FLEX) = %v, want the first-seen casing", got)
+	}
+}
@@ -0,0 +1,144 @@
+package task
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/flexsearch/coordinator/internal/model"
+	"github.com/flexsearch/coordinator/internal/util"
+)
+
+const defaultRetention = time.Hour
+
+// Progress reports how far a running task has got, as a percentage.
+type Progress func(percent float64)
+
+// Registry tracks long-running background tasks such as index rebuilds so
+// clients can poll their status. Finished tasks are kept for the retention
+// period and then dropped.
+type Registry struct {
+	mu        sync.RWMutex
+	tasks     map[string]*model.TaskStatus
+	retention time.Duration
+	now       func() time.Time
+}
+
+func NewRegistry(retention time.Duration) *Registry {
+	if retention <= 0 {
+		retention = defaultRetention
+	}
+	return &Registry{
+		tasks:     make(map[string]*model.TaskStatus),
+		retention: retention,
+		now:       time.Now,
+	}
+}
+
+// Start registers a task of the given type and runs fn in the background.
+// The task completes when fn returns nil and fails with its error otherwise.
+func (r *Registry) Start(ctx context.Context, taskType string, fn func(ctx context.Context, progress Progress) error) string {
+	id := r.create(taskType)
+
+	go func() {
+		err := fn(ctx, func(percent float64) {
+			r.update(id, func(status *model.TaskStatus) {
+				status.Percent = clampPercent(percent)
+			})
+		})
+		r.finish(id, err)
+	}()
+
+	return id
+}
+
+// GetTaskStatus returns a snapshot of the task, or a 404 AppError when the
+// ID is unknown or has expired.
+func (r *Registry) GetTaskStatus(ctx context.Context, taskID string) (*model.TaskStatus, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	status, ok := r.tasks[taskID]
+	if !ok {
+		return nil, util.NewAppError(404, "Task not found", fmt.Sprintf("no task with id %s", taskID))
+	}
+
+	snapshot := *status
+	if status.FinishedAt != nil {
+		finished := *status.FinishedAt
+		snapshot.FinishedAt = &finished
+	}
+	return &snapshot, nil
+}
+
+func (r *Registry) create(taskType string) string {
+	id := newTaskID()
+	now := r.now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.prune(now)
+	r.tasks[id] = &model.TaskStatus{
+		ID:        id,
+		Type:      taskType,
+		State:     model.TaskStateRunning,
+		StartedAt: now,
+	}
+	return id
+}
+
+func (r *Registry) finish(id string, err error) {
+	now := r.now()
+	r.update(id, func(status *model.TaskStatus) {
+		status.FinishedAt = &now
+		if err != nil {
+			status.State = model.TaskStateFailed
+			status.Error = err.Error()
+			return
+		}
+		status.State = model.TaskStateCompleted
+		status.Percent = 100
+	})
+}
+
+func (r *Registry) update(id string, apply func(*model.TaskStatus)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if status, ok := r.tasks[id]; ok {
+		apply(status)
+	}
+}
+
+// prune drops finished tasks past the retention period. Callers must hold
+// the write lock.
+func (r *Registry) prune(now time.Time) {
+	for id, status := range r.tasks {
+		if status.FinishedAt != nil && now.Sub(*status.FinishedAt) > r.retention {
+			delete(r.tasks, id)
+		}
+	}
+}
+
+func clampPercent(percent float64) float64 {
+	switch {
+	case percent < 0:
+		return 0
+	case percent > 100:
+		return 100
+	default:
+		return percent
+	}
+}
+
+func newTaskID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("task-%d", time.Now().UnixNano())
+	}
+	return "task-" + hex.EncodeToString(b)
+}
@@ -0,0 +1,102 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/flexsearch/coordinator/internal/model"
+	"github.com/flexsearch/coordinator/internal/util"
+)
+
+func waitForState(t *testing.T, r *Registry, id, state string) *model.TaskStatus {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for {
+		status, err := r.GetTaskStatus(context.Background(), id)
+		if err != nil {
+			t.Fatalf("GetTaskStatus(%s) failed: %v", id, err)
+		}
+		if status.State == state {
+			return status
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Task %s stayed %s, want %s", id, status.State, state)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestRegistryRunningTask(t *testing.T) {
+	r := NewRegistry(time.Minute)
+	reported := make(chan struct{})
+	release := make(chan struct{})
+
+	id := r.Start(context.Background(), "rebuild", func(ctx context.Context, progress Progress) error {
+		progress(40)
+		close(reported)
+		<-release
+		return nil
+	})
+	defer close(release)
+	<-reported
+
+	status := waitForState(t, r, id, model.TaskStateRunning)
+	if status.Type != "rebuild" || status.Percent != 40 {
+		t.Errorf("Expected a rebuild at 40%%, got %+v", status)
+	}
+	if status.StartedAt.IsZero() || status.FinishedAt != nil {
+		t.Errorf("Expected a start time and no finish time, got %+v", status)
+	}
+}
+
+func TestRegistryCompletedTask(t *testing.T) {
+	r := NewRegistry(time.Minute)
+
+	done := r.Start(context.Background(), "rebuild", func(ctx context.Context, progress Progress) error {
+		progress(50)
+		return nil
+	})
+	status := waitForState(t, r, done, model.TaskStateCompleted)
+	if status.Percent != 100 || status.FinishedAt == nil {
+		t.Errorf("Expected a finished task at 100%%, got %+v", status)
+	}
+	if status.FinishedAt.Before(status.StartedAt) {
+		t.Errorf("Expected finish after start, got %+v", status)
+	}
+
+	failed := r.Start(context.Background(), "rebuild", func(ctx context.Context, progress Progress) error {
+		return errors.New("engine unavailable")
+	})
+	status = waitForState(t, r, failed, model.TaskStateFailed)
+	if status.Error != "engine unavailable" || status.FinishedAt == nil {
+		t.Errorf("Expected the failure to be recorded, got %+v", status)
+	}
+}
+
+func TestRegistryUnknownTask(t *testing.T) {
+	r := NewRegistry(time.Minute)
+
+	_, err := r.GetTaskStatus(context.Background(), "task-missing")
+	var appErr *util.AppError
+	if !errors.As(err, &appErr) || appErr.Code != 404 {
+		t.Errorf("Expected a 404 AppError, got %v", err)
+	}
+}
+
+func TestRegistryPrunesExpiredTasks(t *testing.T) {
+	r := NewRegistry(time.Minute)
+	now := time.Now()
+	r.now = func() time.Time { return now }
+
+	id := r.Start(context.Background(), "rebuild", func(ctx context.Context, progress Progress) error { return nil })
+	waitForState(t, r, id, model.TaskStateCompleted)
+
+	now = now.Add(2 * time.Minute)
+	r.Start(context.Background(), "rebuild", func(ctx context.Context, progress Progress) error { return nil })
+
+	if _, err := r.GetTaskStatus(context.Background(), id); err == nil {
+		t.Error("Expected the expired task to be pruned")
+	}
+}
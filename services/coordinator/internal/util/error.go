@@ -1,6 +1,11 @@
 package util
 
-import "fmt"
+import (
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
 
 type AppError struct {
 	Code    int    `json:"code"`
@@ -12,6 +17,35 @@ func (e *AppError) Error() string {
 	return e.Message
 }
 
+// GRPCStatus lets gRPC handlers return an AppError directly; the HTTP-style
+// code is translated to the matching gRPC code.
+func (e *AppError) GRPCStatus() *status.Status {
+	return status.New(grpcCode(e.Code), e.Message)
+}
+
+func grpcCode(code int) codes.Code {
+	switch code {
+	case 400:
+		return codes.InvalidArgument
+	case 401:
+		return codes.Unauthenticated
+	case 403:
+		return codes.PermissionDenied
+	case 404:
+		return codes.NotFound
+	case 409:
+		return codes.Aborted
+	case 429:
+		return codes.ResourceExhausted
+	case 503:
+		return codes.Unavailable
+	case 504:
+		return codes.DeadlineExceeded
+	default:
+		return codes.Internal
+	}
+}
+
 func NewAppError(code int, message, details string) *AppError {
 	return &AppError{
 		Code:    code,
@@ -33,6 +67,7 @@ var (
 	ErrQueryInvalid       = &AppError{Code: 400, Message: "Invalid query"}
 	ErrCacheError         = &AppError{Code: 500, Message: "Cache error"}
 	ErrMergerError        = &AppError{Code: 500, Message: "Merger error"}
+	ErrVersionConflict    = &AppError{Code: 409, Message: "Version conflict"}
 )
 
 func WrapError(err error, message string) *AppError {
@@ -1,16 +1,23 @@
 package util
 
 import (
+	"context"
+	"sync"
+	"time"
+
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
-	"sync"
 )
 
 type Logger struct {
 	*zap.Logger
 	sugar *zap.SugaredLogger
 	mu    sync.RWMutex
-	level zapcore.Level
+	// level is shared with the zap core, so SetLevel takes effect on the
+	// running logger.
+	level zap.AtomicLevel
+	// redactor masks sensitive fields; nil unless WithRedaction was used.
+	redactor *Redactor
 }
 
 var (
@@ -18,7 +25,36 @@ var (
 	once          sync.Once
 )
 
+// SamplingConfig caps repeated log entries. Within each Tick the first
+// Initial entries with the same level and message are written, then only
+// every Thereafter-th one; Thereafter 0 drops the rest of the tick. Initial
+// 0 turns sampling off.
+type SamplingConfig struct {
+	Initial    int
+	Thereafter int
+	Tick       time.Duration
+}
+
+func (s *SamplingConfig) wrap(core zapcore.Core) zapcore.Core {
+	if s.Initial <= 0 {
+		return core
+	}
+	tick := s.Tick
+	if tick <= 0 {
+		tick = time.Second
+	}
+	return zapcore.NewSamplerWithOptions(core, tick, s.Initial, s.Thereafter)
+}
+
+// NewLogger builds a logger with zap's default sampling for the format:
+// 100 initial and 100 thereafter per second for json, none otherwise.
 func NewLogger(level string, format string, output string) (*Logger, error) {
+	return NewLoggerWithSampling(level, format, output, nil)
+}
+
+// NewLoggerWithSampling is NewLogger with explicit sampling; nil keeps the
+// format's default.
+func NewLoggerWithSampling(level string, format string, output string, sampling *SamplingConfig) (*Logger, error) {
 	var zapLevel zapcore.Level
 	switch level {
 	case "debug":
@@ -43,22 +79,29 @@ func NewLogger(level string, format string, output string) (*Logger, error) {
 		config.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
 	}
 
-	config.Level = zap.NewAtomicLevelAt(zapLevel)
+	atomicLevel := zap.NewAtomicLevelAt(zapLevel)
+	config.Level = atomicLevel
 	config.OutputPaths = []string{output}
 	config.ErrorOutputPaths = []string{output}
 
 	config.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
 	config.EncoderConfig.EncodeDuration = zapcore.StringDurationEncoder
 
-	logger, err := config.Build()
+	var opts []zap.Option
+	if sampling != nil {
+		config.Sampling = nil
+		opts = append(opts, zap.WrapCore(sampling.wrap))
+	}
+
+	logger, err := config.Build(opts...)
 	if err != nil {
 		return nil, err
 	}
 
 	l := &Logger{
 		Logger: logger,
-		sugar: logger.Sugar(),
-		level: zapLevel,
+		sugar:  logger.Sugar(),
+		level:  atomicLevel,
 	}
 
 	return l, nil
@@ -153,10 +196,17 @@ func (l *Logger) SetLevel(level string) error {
 		return nil
 	}
 
-	l.level = zapLevel
+	l.level.SetLevel(zapLevel)
 	return nil
 }
 
+// Level returns the name of the current log level, e.g. "info".
+func (l *Logger) Level() string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.level.Level().String()
+}
+
 func GetDefaultLogger() *Logger {
 	once.Do(func() {
 		var err error
@@ -169,23 +219,77 @@ func GetDefaultLogger() *Logger {
 }
 
 type QueryLogger struct {
-	logger *Logger
+	logger        *Logger
+	slowThreshold time.Duration
+	slowSink      SlowQuerySink
 }
 
 func NewQueryLogger(logger *Logger) *QueryLogger {
-	return &QueryLogger{logger: logger}
+	return NewQueryLoggerWithConfig(logger, QueryLoggerConfig{})
 }
 
-func (ql *QueryLogger) LogQuery(query string, engines []string, latency float64, resultCount int, requestID string) {
+func NewQueryLoggerWithConfig(logger *Logger, config QueryLoggerConfig) *QueryLogger {
+	return &QueryLogger{
+		logger:        logger,
+		slowThreshold: config.SlowThreshold,
+		slowSink:      config.SlowSink,
+	}
+}
+
+// LogQuery records a finished query at info, or at warn with the per-engine
+// breakdown when latency exceeds the slow threshold. Slow queries are also
+// handed to the sink, if any, in the background. Filter values for field
+// names the logger redacts are masked.
+func (ql *QueryLogger) LogQuery(query string, filters map[string]string, engines []EngineTiming, latency float64, resultCount int, requestID string) {
+	if ql.slowThreshold > 0 && latency > float64(ql.slowThreshold.Milliseconds()) {
+		ql.logger.Warnw("Slow query",
+			"query", query,
+			"filters", filters,
+			"engines", engines,
+			"latency_ms", latency,
+			"threshold_ms", ql.slowThreshold.Milliseconds(),
+			"result_count", resultCount,
+			"request_id", requestID,
+		)
+		ql.recordSlow(&SlowQuery{
+			Query:       query,
+			Filters:     ql.logger.Redactor().StringMap(filters),
+			Engines:     engines,
+			LatencyMs:   latency,
+			ResultCount: resultCount,
+			RequestID:   requestID,
+			Timestamp:   time.Now(),
+		})
+		return
+	}
+
+	names := make([]string, len(engines))
+	for i, e := range engines {
+		names[i] = e.Engine
+	}
 	ql.logger.Infow("Query completed",
 		"query", query,
-		"engines", engines,
+		"filters", filters,
+		"engines", names,
 		"latency_ms", latency,
 		"result_count", resultCount,
 		"request_id", requestID,
 	)
 }
 
+func (ql *QueryLogger) recordSlow(entry *SlowQuery) {
+	if ql.slowSink == nil {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), slowQuerySinkTimeout)
+		defer cancel()
+		if err := ql.slowSink.RecordSlowQuery(ctx, entry); err != nil {
+			ql.logger.Warnf("Failed to record slow query: %v", err)
+		}
+	}()
+}
+
 func (ql *QueryLogger) LogError(query string, engines []string, err error, requestID string) {
 	ql.logger.Errorw("Query error",
 		"query", query,
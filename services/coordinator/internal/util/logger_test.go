@@ -0,0 +1,192 @@
+package util
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func newObservedLogger(level zapcore.Level) (*Logger, *observer.ObservedLogs) {
+	atomicLevel := zap.NewAtomicLevelAt(level)
+	core, logs := observer.New(atomicLevel)
+	logger := zap.New(core)
+	return &Logger{Logger: logger, sugar: logger.Sugar(), level: atomicLevel}, logs
+}
+
+func TestSetLevelChangesRunningLogger(t *testing.T) {
+	logger, logs := newObservedLogger(zapcore.InfoLevel)
+
+	logger.Debug("suppressed")
+	if logs.Len() != 0 {
+		t.Fatalf("expected debug log to be suppressed at info level, got %d entries", logs.Len())
+	}
+
+	if err := logger.SetLevel("debug"); err != nil {
+		t.Fatalf("SetLevel() error = %v", err)
+	}
+	logger.Debug("visible")
+	logger.Debugw("visible with fields", "key", "value")
+
+	if got := logs.FilterMessage("visible").Len(); got != 1 {
+		t.Errorf("expected debug log after SetLevel(debug), got %d", got)
+	}
+	if got := logs.FilterMessage("visible with fields").Len(); got != 1 {
+		t.Errorf("expected sugared debug log after SetLevel(debug), got %d", got)
+	}
+	if got := logger.Level(); got != "debug" {
+		t.Errorf("Level() = %q, want debug", got)
+	}
+}
+
+func TestSetLevelRaisesThreshold(t *testing.T) {
+	logger, logs := newObservedLogger(zapcore.DebugLevel)
+
+	if err := logger.SetLevel("error"); err != nil {
+		t.Fatalf("SetLevel() error = %v", err)
+	}
+	logger.Info("dropped")
+	logger.Warnf("dropped %s", "too")
+	logger.Error("kept")
+
+	if logs.Len() != 1 || logs.All()[0].Message != "kept" {
+		t.Errorf("expected only the error entry, got %v", logs.All())
+	}
+}
+
+func TestNewLoggerSharesLevel(t *testing.T) {
+	logger, err := NewLogger("warn", "json", "stderr")
+	if err != nil {
+		t.Fatalf("NewLogger() error = %v", err)
+	}
+	if logger.Core().Enabled(zapcore.InfoLevel) {
+		t.Fatal("expected info to be disabled at warn level")
+	}
+
+	logger.SetLevel("info")
+	if !logger.Core().Enabled(zapcore.InfoLevel) {
+		t.Error("expected SetLevel(info) to enable info on the zap core")
+	}
+}
+
+func newSampledLogger(sampling *SamplingConfig) (*Logger, *observer.ObservedLogs) {
+	atomicLevel := zap.NewAtomicLevelAt(zapcore.DebugLevel)
+	core, logs := observer.New(atomicLevel)
+	logger := zap.New(sampling.wrap(core))
+	return &Logger{Logger: logger, sugar: logger.Sugar(), level: atomicLevel}, logs
+}
+
+func TestSamplingDropsRepeatedDebugEntries(t *testing.T) {
+	logger, logs := newSampledLogger(&SamplingConfig{Initial: 3, Thereafter: 5, Tick: time.Minute})
+
+	for i := 0; i < 20; i++ {
+		logger.Debugw("engine call", "attempt", i)
+	}
+	logger.Debug("different message")
+
+	// 3 initial entries, then every 5th of the remaining 17.
+	if got := logs.FilterMessage("engine call").Len(); got != 6 {
+		t.Errorf("expected 6 sampled entries out of 20, got %d", got)
+	}
+	if got := logs.FilterMessage("different message").Len(); got != 1 {
+		t.Errorf("expected a distinct message to be sampled separately, got %d", got)
+	}
+}
+
+func TestSamplingDisabled(t *testing.T) {
+	logger, logs := newSampledLogger(&SamplingConfig{})
+
+	for i := 0; i < 20; i++ {
+		logger.Debug("engine call")
+	}
+	if logs.Len() != 20 {
+		t.Errorf("expected every entry with sampling off, got %d", logs.Len())
+	}
+}
+
+func TestSamplingAppliesThroughRedaction(t *testing.T) {
+	base, logs := newSampledLogger(&SamplingConfig{Initial: 1, Thereafter: 0, Tick: time.Minute})
+	logger := base.WithRedaction([]string{"email"})
+
+	for i := 0; i < 5; i++ {
+		logger.Debugw("lookup", "email", "jane@example.com")
+	}
+
+	if logs.Len() != 1 {
+		t.Fatalf("expected sampling to keep one entry, got %d", logs.Len())
+	}
+	if got := logs.All()[0].ContextMap()["email"]; got != RedactedValue {
+		t.Errorf("expected email to be redacted, got %v", got)
+	}
+}
+
+type fakeSlowQuerySink struct {
+	recorded chan *SlowQuery
+}
+
+func (s *fakeSlowQuerySink) RecordSlowQuery(ctx context.Context, query *SlowQuery) error {
+	s.recorded <- query
+	return nil
+}
+
+func TestQueryLoggerSlowQueries(t *testing.T) {
+	base, logs := newObservedLogger(zapcore.InfoLevel)
+	sink := &fakeSlowQuerySink{recorded: make(chan *SlowQuery, 1)}
+	ql := NewQueryLoggerWithConfig(base.WithRedaction([]string{"email"}), QueryLoggerConfig{
+		SlowThreshold: 100 * time.Millisecond,
+		SlowSink:      sink,
+	})
+
+	engines := []EngineTiming{
+		{Engine: "bm25", TookMs: 40, Results: 5},
+		{Engine: "vector", TookMs: 240, Error: "deadline exceeded", TimedOut: true},
+	}
+	filters := map[string]string{"email": "jane@example.com", "lang": "en"}
+
+	ql.LogQuery("fast query", nil, engines[:1], 50, 5, "req-fast")
+	ql.LogQuery("slow query", filters, engines, 250, 5, "req-slow")
+
+	fast := logs.FilterMessage("Query completed").All()
+	if len(fast) != 1 || fast[0].Level != zapcore.InfoLevel {
+		t.Fatalf("expected one info entry for the fast query, got %v", fast)
+	}
+	if got := fast[0].ContextMap()["request_id"]; got != "req-fast" {
+		t.Errorf("expected fast entry for req-fast, got %v", got)
+	}
+
+	slow := logs.FilterMessage("Slow query").All()
+	if len(slow) != 1 || slow[0].Level != zapcore.WarnLevel {
+		t.Fatalf("expected one warn entry for the slow query, got %v", slow)
+	}
+	fields := slow[0].ContextMap()
+	if fields["threshold_ms"] != int64(100) {
+		t.Errorf("expected threshold_ms 100, got %v", fields["threshold_ms"])
+	}
+	if breakdown, ok := fields["engines"].([]EngineTiming); !ok || len(breakdown) != 2 {
+		t.Errorf("expected the engine breakdown on the slow entry, got %#v", fields["engines"])
+	}
+
+	select {
+	case recorded := <-sink.recorded:
+		if recorded.RequestID != "req-slow" || recorded.LatencyMs != 250 || len(recorded.Engines) != 2 {
+			t.Errorf("unexpected slow query %+v", recorded)
+		}
+		if !recorded.Engines[1].TimedOut {
+			t.Errorf("expected vector timing to be marked timed out")
+		}
+		if recorded.Filters["email"] != RedactedValue || recorded.Filters["lang"] != "en" {
+			t.Errorf("expected sink filters to be redacted, got %v", recorded.Filters)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("slow query was not sent to the sink")
+	}
+
+	select {
+	case extra := <-sink.recorded:
+		t.Errorf("fast query should not reach the sink, got %+v", extra)
+	default:
+	}
+}
@@ -17,6 +17,8 @@ type Metrics struct {
 	mergerLatency        *prometheus.HistogramVec
 	cacheHits            prometheus.Counter
 	cacheMisses          prometheus.Counter
+	cacheHitRate         prometheus.Gauge
+	cacheSize            prometheus.Gauge
 	searchRequestsTotal   *prometheus.CounterVec
 	searchResultsTotal    *prometheus.CounterVec
 	searchErrorsTotal     *prometheus.CounterVec
@@ -25,8 +27,15 @@ type Metrics struct {
 }
 
 func NewMetrics(namespace string) *Metrics {
+	return NewMetricsWithRegistry(namespace, prometheus.DefaultRegisterer)
+}
+
+// NewMetricsWithRegistry registers the collectors with reg instead of the
+// default registry, so tests can inspect them in isolation.
+func NewMetricsWithRegistry(namespace string, reg prometheus.Registerer) *Metrics {
+	factory := promauto.With(reg)
 	m := &Metrics{
-		grpcRequestsTotal: promauto.NewCounterVec(
+		grpcRequestsTotal: factory.NewCounterVec(
 			prometheus.CounterOpts{
 				Namespace: namespace,
 				Name:      "grpc_requests_total",
@@ -34,7 +43,7 @@ func NewMetrics(namespace string) *Metrics {
 			},
 			[]string{"method", "status"},
 		),
-		grpcRequestsDuration: promauto.NewHistogramVec(
+		grpcRequestsDuration: factory.NewHistogramVec(
 			prometheus.HistogramOpts{
 				Namespace: namespace,
 				Name:      "grpc_request_duration_seconds",
@@ -43,14 +52,14 @@ func NewMetrics(namespace string) *Metrics {
 			},
 			[]string{"method"},
 		),
-		grpcRequestsInFlight: promauto.NewGauge(
+		grpcRequestsInFlight: factory.NewGauge(
 			prometheus.GaugeOpts{
 				Namespace: namespace,
 				Name:      "grpc_requests_in_flight",
 				Help:      "Number of gRPC requests currently being processed",
 			},
 		),
-		queryLatency: promauto.NewHistogramVec(
+		queryLatency: factory.NewHistogramVec(
 			prometheus.HistogramOpts{
 				Namespace: namespace,
 				Name:      "query_latency_seconds",
@@ -59,7 +68,7 @@ func NewMetrics(namespace string) *Metrics {
 			},
 			[]string{"query_type"},
 		),
-		engineLatency: promauto.NewHistogramVec(
+		engineLatency: factory.NewHistogramVec(
 			prometheus.HistogramOpts{
 				Namespace: namespace,
 				Name:      "engine_latency_seconds",
@@ -68,7 +77,7 @@ func NewMetrics(namespace string) *Metrics {
 			},
 			[]string{"engine", "operation"},
 		),
-		mergerLatency: promauto.NewHistogramVec(
+		mergerLatency: factory.NewHistogramVec(
 			prometheus.HistogramOpts{
 				Namespace: namespace,
 				Name:      "merger_latency_seconds",
@@ -77,21 +86,35 @@ func NewMetrics(namespace string) *Metrics {
 			},
 			[]string{"strategy"},
 		),
-		cacheHits: promauto.NewCounter(
+		cacheHits: factory.NewCounter(
 			prometheus.CounterOpts{
 				Namespace: namespace,
 				Name:      "cache_hits_total",
 				Help:      "Total number of cache hits",
 			},
 		),
-		cacheMisses: promauto.NewCounter(
+		cacheMisses: factory.NewCounter(
 			prometheus.CounterOpts{
 				Namespace: namespace,
 				Name:      "cache_misses_total",
 				Help:      "Total number of cache misses",
 			},
 		),
-		searchRequestsTotal: promauto.NewCounterVec(
+		cacheHitRate: factory.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "cache_hit_rate",
+				Help:      "Cache hits as a fraction of all cache lookups",
+			},
+		),
+		cacheSize: factory.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "cache_size",
+				Help:      "Number of entries written to the cache",
+			},
+		),
+		searchRequestsTotal: factory.NewCounterVec(
 			prometheus.CounterOpts{
 				Namespace: namespace,
 				Name:      "search_requests_total",
@@ -99,7 +122,7 @@ func NewMetrics(namespace string) *Metrics {
 			},
 			[]string{"engine"},
 		),
-		searchResultsTotal: promauto.NewCounterVec(
+		searchResultsTotal: factory.NewCounterVec(
 			prometheus.CounterOpts{
 				Namespace: namespace,
 				Name:      "search_results_total",
@@ -107,7 +130,7 @@ func NewMetrics(namespace string) *Metrics {
 			},
 			[]string{"engine"},
 		),
-		searchErrorsTotal: promauto.NewCounterVec(
+		searchErrorsTotal: factory.NewCounterVec(
 			prometheus.CounterOpts{
 				Namespace: namespace,
 				Name:      "search_errors_total",
@@ -153,6 +176,12 @@ func (m *Metrics) RecordCacheMiss() {
 	m.cacheMisses.Inc()
 }
 
+// SetCacheStats publishes the cache's derived hit rate and size.
+func (m *Metrics) SetCacheStats(hitRate float64, size int64) {
+	m.cacheHitRate.Set(hitRate)
+	m.cacheSize.Set(float64(size))
+}
+
 func (m *Metrics) RecordMergerLatency(strategy string, duration time.Duration) {
 	m.mergerLatency.WithLabelValues(strategy).Observe(duration.Seconds())
 }
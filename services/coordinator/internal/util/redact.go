@@ -0,0 +1,41 @@
+package util
+
+import (
+	"github.com/flexsearch/shared/redact"
+	"go.uber.org/zap"
+)
+
+// RedactedValue replaces the value of every sensitive field in log output.
+const RedactedValue = redact.Value
+
+// Redactor masks the values of sensitive field names, matched
+// case-insensitively. A nil Redactor leaves everything untouched.
+type Redactor = redact.Redactor
+
+// NewRedactor returns nil when fields is empty so callers can skip the
+// redaction hook entirely.
+func NewRedactor(fields []string) *Redactor {
+	return redact.New(fields)
+}
+
+// WithRedaction returns a logger that masks the given field names in all
+// structured output. The level stays shared with l. With no fields it
+// returns l unchanged.
+func (l *Logger) WithRedaction(fields []string) *Logger {
+	r := NewRedactor(fields)
+	if r == nil {
+		return l
+	}
+	logger := l.Logger.WithOptions(zap.WrapCore(r.WrapCore))
+	return &Logger{
+		Logger:   logger,
+		sugar:    logger.Sugar(),
+		level:    l.level,
+		redactor: r,
+	}
+}
+
+// Redactor returns the logger's redactor, nil when redaction is off.
+func (l *Logger) Redactor() *Redactor {
+	return l.redactor
+}
@@ -0,0 +1,53 @@
+package util
+
+import (
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestQueryLoggerRedactsSensitiveFilters(t *testing.T) {
+	base, logs := newObservedLogger(zapcore.InfoLevel)
+	ql := NewQueryLogger(base.WithRedaction([]string{"email", "ssn"}))
+
+	ql.LogQuery("order status", map[string]string{
+		"email":  "jane@example.com",
+		"status": "shipped",
+	}, []EngineTiming{{Engine: "bm25", TookMs: 12}}, 12.5, 3, "req-1")
+
+	entries := logs.FilterMessage("Query completed").All()
+	if len(entries) != 1 {
+		t.Fatalf("expected one query entry, got %d", len(entries))
+	}
+
+	fields := entries[0].ContextMap()
+	filters, ok := fields["filters"].(map[string]string)
+	if !ok {
+		t.Fatalf("expected filters map, got %T", fields["filters"])
+	}
+	if filters["email"] != RedactedValue {
+		t.Errorf("expected email filter to be redacted, got %q", filters["email"])
+	}
+	if filters["status"] != "shipped" {
+		t.Errorf("expected status filter to pass through, got %q", filters["status"])
+	}
+	if fields["query"] != "order status" || fields["request_id"] != "req-1" {
+		t.Errorf("expected other fields to pass through, got %v", fields)
+	}
+}
+
+func TestWithRedactionSharesLevel(t *testing.T) {
+	base, logs := newObservedLogger(zapcore.InfoLevel)
+	logger := base.WithRedaction([]string{"ssn"})
+
+	base.SetLevel("debug")
+	logger.Debugw("lookup", "ssn", "123-45-6789")
+
+	entries := logs.All()
+	if len(entries) != 1 {
+		t.Fatalf("expected debug entry after SetLevel on the base logger, got %d", len(entries))
+	}
+	if got := entries[0].ContextMap()["ssn"]; got != RedactedValue {
+		t.Errorf("expected ssn to be redacted, got %v", got)
+	}
+}
@@ -0,0 +1,38 @@
+package util
+
+import (
+	"math"
+	"sync/atomic"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// RatioSampler samples a fraction of traces by trace ID. Unlike
+// sdktrace.TraceIDRatioBased the fraction can be changed while the tracer
+// provider is running.
+type RatioSampler struct {
+	rate atomic.Uint64
+}
+
+func NewRatioSampler(rate float64) *RatioSampler {
+	s := &RatioSampler{}
+	s.SetRate(rate)
+	return s
+}
+
+// SetRate clamps rate to [0, 1] and applies it to traces started from now on.
+func (s *RatioSampler) SetRate(rate float64) {
+	s.rate.Store(math.Float64bits(math.Max(0, math.Min(1, rate))))
+}
+
+func (s *RatioSampler) Rate() float64 {
+	return math.Float64frombits(s.rate.Load())
+}
+
+func (s *RatioSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	return sdktrace.TraceIDRatioBased(s.Rate()).ShouldSample(p)
+}
+
+func (s *RatioSampler) Description() string {
+	return "RatioSampler"
+}
@@ -0,0 +1,39 @@
+package util
+
+import (
+	"context"
+	"time"
+)
+
+const slowQuerySinkTimeout = time.Second
+
+type QueryLoggerConfig struct {
+	// SlowThreshold is the latency above which a query is logged as slow.
+	// Zero disables slow-query logging.
+	SlowThreshold time.Duration
+	// SlowSink, when set, receives every slow query for offline analysis.
+	SlowSink SlowQuerySink
+}
+
+type SlowQuerySink interface {
+	RecordSlowQuery(ctx context.Context, query *SlowQuery) error
+}
+
+// EngineTiming is one engine's share of a query.
+type EngineTiming struct {
+	Engine   string  `json:"engine"`
+	TookMs   float64 `json:"took_ms"`
+	Results  int     `json:"results"`
+	Error    string  `json:"error,omitempty"`
+	TimedOut bool    `json:"timed_out,omitempty"`
+}
+
+type SlowQuery struct {
+	Query       string            `json:"query"`
+	Filters     map[string]string `json:"filters,omitempty"`
+	Engines     []EngineTiming    `json:"engines"`
+	LatencyMs   float64           `json:"latency_ms"`
+	ResultCount int               `json:"result_count"`
+	RequestID   string            `json:"request_id"`
+	Timestamp   time.Time         `json:"timestamp"`
+}
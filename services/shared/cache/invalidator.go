@@ -120,22 +120,72 @@ func (ci *CacheInvalidator) applyManualInvalidation(ctx context.Context, key str
 	return ci.client.Del(ctx, key).Err()
 }
 
+// matchPattern reports whether key matches a Redis-style glob pattern.
+// '*' matches any run of characters, '?' matches a single character and a
+// backslash escapes the following character. Unlike filepath.Match, no
+// character is treated as a separator.
 func matchPattern(key, pattern string) bool {
-	if pattern == "*" {
-		return true
+	if prefix, ok := plainPrefix(pattern); ok {
+		return strings.HasPrefix(key, prefix)
 	}
+	return globMatch(key, pattern)
+}
 
-	if strings.HasSuffix(pattern, "*") {
-		prefix := strings.TrimSuffix(pattern, "*")
-		return strings.HasPrefix(key, prefix)
+// plainPrefix returns the literal prefix of patterns of the form "prefix*"
+// so they can be matched without backtracking.
+func plainPrefix(pattern string) (string, bool) {
+	if !strings.HasSuffix(pattern, "*") {
+		return "", false
+	}
+	prefix := pattern[:len(pattern)-1]
+	if strings.ContainsAny(prefix, "*?\\") {
+		return "", false
 	}
+	return prefix, true
+}
+
+func globMatch(key, pattern string) bool {
+	k, p := 0, 0
+	starP, starK := -1, 0
 
-	if strings.HasPrefix(pattern, "*") {
-		suffix := strings.TrimPrefix(pattern, "*")
-		return strings.HasSuffix(key, suffix)
+	for k < len(key) {
+		if p < len(pattern) {
+			switch c := pattern[p]; c {
+			case '*':
+				starP, starK = p, k
+				p++
+				continue
+			case '?':
+				k++
+				p++
+				continue
+			case '\\':
+				if p+1 < len(pattern) && pattern[p+1] == key[k] {
+					k++
+					p += 2
+					continue
+				}
+			default:
+				if c == key[k] {
+					k++
+					p++
+					continue
+				}
+			}
+		}
+
+		if starP < 0 {
+			return false
+		}
+		starK++
+		k = starK
+		p = starP + 1
 	}
 
-	return key == pattern
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+	return p == len(pattern)
 }
 
 func (ci *CacheInvalidator) ClearRules() {
@@ -0,0 +1,90 @@
+package cache
+
+import "testing"
+
+func TestMatchPattern(t *testing.T) {
+	tests := []struct {
+		key     string
+		pattern string
+		want    bool
+	}{
+		{"search:abc", "*", true},
+		{"search:abc", "search:*", true},
+		{"other:abc", "search:*", false},
+		{"search:abc:index", "*:index", true},
+		{"search:abc:index", "*:cache", false},
+		{"search:abc", "search:abc", true},
+		{"search:abc", "search:ab", false},
+
+		{"search:docs:index", "search:*:index", true},
+		{"search:a:b:index", "search:*:index", true},
+		{"search:docs:cache", "search:*:index", false},
+		{"search::index", "search:*:index", true},
+		{"a:b:c", "*:*:*", true},
+		{"a:b", "*:*:*", false},
+		{"search:docs/1:index", "search:*:index", true},
+
+		{"user:1:cache", "user:?:cache", true},
+		{"user:12:cache", "user:?:cache", false},
+		{"user::cache", "user:?:cache", false},
+		{"user:12:cache", "user:??:cache", true},
+		{"user:123:cache", "user:?*:cache", true},
+
+		{"search*", `search\*`, true},
+		{"searchx", `search\*`, false},
+		{"what?", `what\?`, true},
+		{"whatx", `what\?`, false},
+		{`a\b`, `a\\b`, true},
+		{"key*:1", `key\**`, true},
+		{"keyx:1", `key\**`, false},
+	}
+
+	for _, tt := range tests {
+		if got := matchPattern(tt.key, tt.pattern); got != tt.want {
+			t.Errorf("matchPattern(%q, %q) = %v, want %v", tt.key, tt.pattern, got, tt.want)
+		}
+	}
+}
+
+func TestPlainPrefix(t *testing.T) {
+	tests := []struct {
+		pattern string
+		prefix  string
+		ok      bool
+	}{
+		{"search:*", "search:", true},
+		{"*", "", true},
+		{"search:*:index", "", false},
+		{"search:?*", "", false},
+		{`search\*`, "", false},
+		{"search", "", false},
+	}
+
+	for _, tt := range tests {
+		prefix, ok := plainPrefix(tt.pattern)
+		if prefix != tt.prefix || ok != tt.ok {
+			t.Errorf("plainPrefix(%q) = (%q, %v), want (%q, %v)", tt.pattern, prefix, ok, tt.prefix, tt.ok)
+		}
+	}
+}
+
+func BenchmarkMatchPatternPrefix(b *testing.B) {
+	key := "search:docs:5d41402abc4b2a76b9719d911017c592"
+	for i := 0; i < b.N; i++ {
+		matchPattern(key, "search:docs:*")
+	}
+}
+
+func BenchmarkMatchPatternGlobPrefix(b *testing.B) {
+	key := "search:docs:5d41402abc4b2a76b9719d911017c592"
+	for i := 0; i < b.N; i++ {
+		globMatch(key, "search:docs:*")
+	}
+}
+
+func BenchmarkMatchPatternMiddleWildcard(b *testing.B) {
+	key := "search:docs:5d41402abc4b2a76b9719d911017c592:index"
+	for i := 0; i < b.N; i++ {
+		matchPattern(key, "search:*:index")
+	}
+}
@@ -0,0 +1,31 @@
+// Package codec holds the gRPC codec the gateway and coordinator speak.
+package codec
+
+import (
+	"encoding/json"
+
+	"google.golang.org/grpc/encoding"
+)
+
+// Name is the content subtype of the coordinator services. Their messages
+// are plain JSON-tagged structs rather than generated protobuf types, so
+// the gateway dials with grpc.CallContentSubtype(Name) and the coordinator
+// answers in kind. Importing the package registers the codec.
+const Name = "json"
+
+func init() {
+	encoding.RegisterCodec(JSON{})
+}
+
+// JSON marshals gRPC messages with encoding/json.
+type JSON struct{}
+
+func (JSON) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (JSON) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+func (JSON) Name() string { return Name }
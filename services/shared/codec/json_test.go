@@ -0,0 +1,36 @@
+package codec
+
+import (
+	"testing"
+
+	"google.golang.org/grpc/encoding"
+)
+
+type message struct {
+	Query   string   `json:"query"`
+	Indexes []string `json:"indexes"`
+}
+
+func TestJSONIsRegistered(t *testing.T) {
+	if encoding.GetCodec(Name) == nil {
+		t.Fatalf("Expected a codec registered as %q", Name)
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	data, err := JSON{}.Marshal(&message{Query: "golang", Indexes: []string{"books"}})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if string(data) != `{"query":"golang","indexes":["books"]}` {
+		t.Errorf("Expected the JSON tags on the wire, got %s", data)
+	}
+
+	var out message
+	if err := (JSON{}).Unmarshal(data, &out); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if out.Query != "golang" || len(out.Indexes) != 1 || out.Indexes[0] != "books" {
+		t.Errorf("Expected the message back, got %+v", out)
+	}
+}
@@ -0,0 +1,171 @@
+// Package redact masks sensitive fields in the gateway and coordinator logs.
+package redact
+
+import (
+	"net/url"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Value replaces the value of every sensitive field in log output.
+const Value = "[REDACTED]"
+
+// Redactor masks the values of sensitive field names, matched
+// case-insensitively. A nil Redactor leaves everything untouched.
+type Redactor struct {
+	fields map[string]bool
+}
+
+// New returns nil when fields is empty so callers can skip the redaction
+// hook entirely.
+func New(fields []string) *Redactor {
+	r := &Redactor{fields: make(map[string]bool, len(fields))}
+	for _, field := range fields {
+		if field = strings.TrimSpace(field); field != "" {
+			r.fields[strings.ToLower(field)] = true
+		}
+	}
+	if len(r.fields) == 0 {
+		return nil
+	}
+	return r
+}
+
+func (r *Redactor) IsSensitive(key string) bool {
+	return r != nil && r.fields[strings.ToLower(key)]
+}
+
+// Map returns a copy of m with sensitive keys masked at any depth.
+func (r *Redactor) Map(m map[string]interface{}) map[string]interface{} {
+	if r == nil || m == nil {
+		return m
+	}
+	out := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		switch {
+		case r.IsSensitive(k):
+			out[k] = Value
+		default:
+			out[k] = r.value(v)
+		}
+	}
+	return out
+}
+
+// StringMap returns a copy of m with sensitive keys masked.
+func (r *Redactor) StringMap(m map[string]string) map[string]string {
+	if r == nil || m == nil {
+		return m
+	}
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		if r.IsSensitive(k) {
+			v = Value
+		}
+		out[k] = v
+	}
+	return out
+}
+
+func (r *Redactor) value(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		return r.Map(v)
+	case map[string]string:
+		return r.StringMap(v)
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = r.value(item)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// Query masks sensitive parameters of a raw URL query string, leaving the
+// rest of it byte-for-byte as it was.
+func (r *Redactor) Query(raw string) string {
+	if r == nil || raw == "" {
+		return raw
+	}
+	params := strings.Split(raw, "&")
+	for i, param := range params {
+		key, _, _ := strings.Cut(param, "=")
+		if unescaped, err := url.QueryUnescape(key); err == nil {
+			key = unescaped
+		}
+		if r.IsSensitive(key) {
+			params[i] = key + "=" + Value
+		}
+	}
+	return strings.Join(params, "&")
+}
+
+func (r *Redactor) redactFields(fields []zapcore.Field) []zapcore.Field {
+	var out []zapcore.Field
+	for i, f := range fields {
+		redacted, changed := r.redactField(f)
+		if !changed {
+			if out != nil {
+				out = append(out, f)
+			}
+			continue
+		}
+		if out == nil {
+			out = append(make([]zapcore.Field, 0, len(fields)), fields[:i]...)
+		}
+		out = append(out, redacted)
+	}
+	if out == nil {
+		return fields
+	}
+	return out
+}
+
+func (r *Redactor) redactField(f zapcore.Field) (zapcore.Field, bool) {
+	if r.IsSensitive(f.Key) {
+		return zap.String(f.Key, Value), true
+	}
+	if f.Type != zapcore.ReflectType {
+		return f, false
+	}
+	switch f.Interface.(type) {
+	case map[string]interface{}, map[string]string, []interface{}:
+		return zap.Any(f.Key, r.value(f.Interface)), true
+	}
+	return f, false
+}
+
+// redactingCore masks sensitive fields before they reach the wrapped core,
+// both for per-entry fields and for fields attached with With.
+type redactingCore struct {
+	zapcore.Core
+	redactor *Redactor
+}
+
+func (c *redactingCore) With(fields []zapcore.Field) zapcore.Core {
+	return &redactingCore{Core: c.Core.With(c.redactor.redactFields(fields)), redactor: c.redactor}
+}
+
+// Check defers to the wrapped core so decisions made there, such as
+// sampling, still apply; the entry is then written through c.
+func (c *redactingCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Core.Check(ent, nil) == nil {
+		return ce
+	}
+	return ce.AddCore(ent, c)
+}
+
+func (c *redactingCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	return c.Core.Write(ent, c.redactor.redactFields(fields))
+}
+
+// WrapCore returns core with sensitive fields masked before they reach
+// it, for use with zap.WrapCore.
+func (r *Redactor) WrapCore(core zapcore.Core) zapcore.Core {
+	return &redactingCore{Core: core, redactor: r}
+}
@@ -0,0 +1,55 @@
+package redact
+
+import (
+	"testing"
+)
+
+func TestRedactorMaps(t *testing.T) {
+	r := New([]string{"email"})
+
+	flat := r.StringMap(map[string]string{"Email": "jane@example.com", "lang": "en"})
+	if flat["Email"] != Value || flat["lang"] != "en" {
+		t.Errorf("unexpected string map %v", flat)
+	}
+
+	nested := r.Map(map[string]interface{}{
+		"owners": []interface{}{map[string]interface{}{"email": "owner@example.com"}},
+		"tags":   map[string]string{"email": "tag@example.com"},
+	})
+	owner := nested["owners"].([]interface{})[0].(map[string]interface{})
+	if owner["email"] != Value {
+		t.Errorf("expected the email inside the list to be redacted, got %v", owner)
+	}
+	if tags := nested["tags"].(map[string]string); tags["email"] != Value {
+		t.Errorf("expected the email in the string map to be redacted, got %v", tags)
+	}
+
+	if New([]string{" ", ""}) != nil {
+		t.Error("expected New with no field names to return nil")
+	}
+}
+
+func TestRedactorQuery(t *testing.T) {
+	r := New([]string{"email"})
+
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{"", ""},
+		{"q=shoes&limit=10", "q=shoes&limit=10"},
+		{"q=shoes&email=jane%40example.com", "q=shoes&email=" + Value},
+		{"EMAIL=jane&q=a+b", "EMAIL=" + Value + "&q=a+b"},
+		{"em%61il=jane", "email=" + Value},
+	}
+	for _, tt := range tests {
+		if got := r.Query(tt.raw); got != tt.want {
+			t.Errorf("Query(%q) = %q, want %q", tt.raw, got, tt.want)
+		}
+	}
+
+	var none *Redactor
+	if got := none.Query("email=jane"); got != "email=jane" {
+		t.Errorf("nil Redactor changed the query: %q", got)
+	}
+}